@@ -3,10 +3,12 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 
 	"github.com/fastschema/fastschema"
 	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/codegen"
 	"github.com/fastschema/fastschema/pkg/utils"
 	toolservice "github.com/fastschema/fastschema/services/tool"
 	"github.com/google/uuid"
@@ -104,6 +106,69 @@ func main() {
 					)
 				},
 			},
+			{
+				Name:  "generate",
+				Usage: "Generate code from the schemas",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "go",
+						Usage: "Generate Go structs, field names and predicates from the schemas",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:    "schemas",
+								Aliases: []string{"s"},
+								Usage:   "Schema directory (default: <app dir>/data/schemas)",
+							},
+							&cli.StringFlag{
+								Name:    "out",
+								Aliases: []string{"o"},
+								Usage:   "Output directory",
+								Value:   "models",
+							},
+							&cli.StringFlag{
+								Name:    "package",
+								Aliases: []string{"p"},
+								Usage:   "Package name of the generated structs",
+								Value:   "models",
+							},
+							&cli.StringFlag{
+								Name:  "import",
+								Usage: "Import path of the output directory",
+							},
+						},
+						Action: func(c *cli.Context) error {
+							schemasDir := c.String("schemas")
+							if schemasDir == "" {
+								schemasDir = filepath.Join(c.Args().Get(0), "data", "schemas")
+							}
+
+							return toolservice.GenerateGo(schemasDir, c.String("out"), &codegen.GoConfig{
+								Package: c.String("package"),
+								Import:  c.String("import"),
+							})
+						},
+					},
+					{
+						Name:  "ts",
+						Usage: "Generate TypeScript types of the schemas and a fetch client of the resources",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:    "out",
+								Aliases: []string{"o"},
+								Usage:   "Output file",
+								Value:   "fastschema.ts",
+							},
+						},
+						Action: func(c *cli.Context) error {
+							app := utils.Must(fastschema.New(&fs.Config{
+								Dir: c.Args().Get(0),
+							}))
+
+							return toolservice.GenerateTS(app.SchemaBuilder(), app.Resources(), c.String("out"))
+						},
+					},
+				},
+			},
 			{
 				Name:  "migration",
 				Usage: "Manage database migrations",
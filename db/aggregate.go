@@ -0,0 +1,274 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+)
+
+// AggregateFunc is the type of an aggregate function
+type AggregateFunc int
+
+const (
+	AggregateInvalid AggregateFunc = iota
+	AggregateCount
+	AggregateSum
+	AggregateAvg
+	AggregateMin
+	AggregateMax
+	endAggregateFuncs
+)
+
+var (
+	aggregateFuncToStrings = [...]string{
+		AggregateInvalid: "invalid",
+		AggregateCount:   "count",
+		AggregateSum:     "sum",
+		AggregateAvg:     "avg",
+		AggregateMin:     "min",
+		AggregateMax:     "max",
+	}
+
+	stringToAggregateFuncs = map[string]AggregateFunc{
+		"invalid": AggregateInvalid,
+		"count":   AggregateCount,
+		"sum":     AggregateSum,
+		"avg":     AggregateAvg,
+		"min":     AggregateMin,
+		"max":     AggregateMax,
+	}
+)
+
+// String returns the string representation of an aggregate function.
+func (f AggregateFunc) String() string {
+	if f < endAggregateFuncs {
+		return aggregateFuncToStrings[f]
+	}
+	return aggregateFuncToStrings[AggregateInvalid]
+}
+
+// Valid reports if the given function is a known aggregate function.
+func (f AggregateFunc) Valid() bool {
+	return f > AggregateInvalid && f < endAggregateFuncs
+}
+
+// MarshalJSON marshal an enum value to the quoted json string value
+func (f AggregateFunc) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(f.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON unmashals a quoted json string to the enum value
+func (f *AggregateFunc) UnmarshalJSON(b []byte) error {
+	var j string
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	*f = stringToAggregateFuncs[j] // If the string can't be found, it will be set to the zero value: 'invalid'
+	return nil
+}
+
+// Aggregation represents an aggregate function applied to a field.
+// The result of the aggregation is returned under the Alias key.
+// An empty Field is only allowed for count, which counts all rows.
+type Aggregation struct {
+	Func  AggregateFunc `json:"func"`
+	Field string        `json:"field,omitempty"`
+	Alias string        `json:"alias"`
+}
+
+// Clone returns a copy of the aggregation.
+func (a *Aggregation) Clone() *Aggregation {
+	return &Aggregation{
+		Func:  a.Func,
+		Field: a.Field,
+		Alias: a.Alias,
+	}
+}
+
+// newAggregation creates an aggregation with a default alias
+// in the form of <func>_<field> (e.g. "sum_price") or <func> if the field is empty.
+func newAggregation(fn AggregateFunc, field string, alias ...string) *Aggregation {
+	a := &Aggregation{Func: fn, Field: field}
+	if len(alias) > 0 && alias[0] != "" {
+		a.Alias = alias[0]
+	} else if field == "" {
+		a.Alias = fn.String()
+	} else {
+		a.Alias = fn.String() + "_" + field
+	}
+
+	return a
+}
+
+// AggCount creates a count aggregation.
+// If the field is empty, all rows are counted.
+func AggCount(field string, alias ...string) *Aggregation {
+	return newAggregation(AggregateCount, field, alias...)
+}
+
+// AggSum creates a sum aggregation.
+func AggSum(field string, alias ...string) *Aggregation {
+	return newAggregation(AggregateSum, field, alias...)
+}
+
+// AggAvg creates an average aggregation.
+func AggAvg(field string, alias ...string) *Aggregation {
+	return newAggregation(AggregateAvg, field, alias...)
+}
+
+// AggMin creates a min aggregation.
+func AggMin(field string, alias ...string) *Aggregation {
+	return newAggregation(AggregateMin, field, alias...)
+}
+
+// AggMax creates a max aggregation.
+func AggMax(field string, alias ...string) *Aggregation {
+	return newAggregation(AggregateMax, field, alias...)
+}
+
+// ParseAggregations parses a comma separated list of aggregations.
+// Each aggregation has the format func[:field[:alias]], e.g.
+//
+//	count,sum:price,avg:price:average_price
+func ParseAggregations(s string) ([]*Aggregation, error) {
+	aggregations := []*Aggregation{}
+	for _, raw := range strings.Split(s, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		parts := strings.Split(raw, ":")
+		if len(parts) > 3 {
+			return nil, fmt.Errorf("invalid aggregation %q", raw)
+		}
+
+		fn := stringToAggregateFuncs[strings.ToLower(parts[0])]
+		if !fn.Valid() {
+			return nil, fmt.Errorf("invalid aggregate function %q", parts[0])
+		}
+
+		parts = append(parts, "", "")
+		if fn != AggregateCount && parts[1] == "" {
+			return nil, fmt.Errorf("aggregate function %q requires a field", fn)
+		}
+
+		aggregations = append(aggregations, newAggregation(fn, parts[1], parts[2]))
+	}
+
+	return aggregations, nil
+}
+
+// CreateHavingPredicatesFromFilterObject creates predicates for the having clause
+// of an aggregate query. The filter object uses the same format as the
+// query filter, but the keys must be aliases of the given aggregations.
+// E.g.
+//
+//	{ "total": { "$gt": 10 }, "$or": [{ "avg_price": 1 }, { "avg_price": 2 }] }
+func CreateHavingPredicatesFromFilterObject(
+	aggregations []*Aggregation,
+	filterObject string,
+) ([]*Predicate, error) {
+	if filterObject == "" {
+		return []*Predicate{}, nil
+	}
+
+	filterEntity, err := entity.NewEntityFromJSON(filterObject)
+	if err != nil {
+		return nil, filterError(err)
+	}
+
+	aliases := utils.Map(aggregations, func(a *Aggregation) string {
+		return a.Alias
+	})
+
+	return createHavingPredicates(aliases, filterEntity)
+}
+
+func createHavingPredicates(aliases []string, filterObject *entity.Entity) ([]*Predicate, error) {
+	var predicates = make([]*Predicate, 0)
+
+	for pair := filterObject.First(); pair != nil; pair = pair.Next() {
+		if pair.Key == "$or" || pair.Key == "$and" {
+			opEntities, ok := pair.Value.([]*entity.Entity)
+			if !ok {
+				return nil, errors.New("invalid $or/$and value")
+			}
+
+			var opPredicates []*Predicate
+			for _, opEntity := range opEntities {
+				objectPredicates, err := createHavingPredicates(aliases, opEntity)
+				if err != nil {
+					return nil, err
+				}
+
+				if len(objectPredicates) > 1 {
+					opPredicates = append(opPredicates, And(objectPredicates...))
+				} else {
+					opPredicates = append(opPredicates, objectPredicates...)
+				}
+			}
+
+			op := utils.If(pair.Key == "$or", Or, And)
+			predicates = append(predicates, op(opPredicates...))
+			continue
+		}
+
+		if !utils.Contains(aliases, pair.Key) {
+			return nil, filterError(fmt.Errorf("%s is not an aggregation alias", pair.Key))
+		}
+
+		aliasPredicates, err := createPredicatesFromValue(pair.Key, pair.Value, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(aliasPredicates) > 1 {
+			predicates = append(predicates, And(aliasPredicates...))
+		} else {
+			predicates = append(predicates, aliasPredicates...)
+		}
+	}
+
+	return predicates, nil
+}
+
+/** Aggregate related methods **/
+
+// GroupBy sets the columns to group the aggregate query by.
+func (q *QueryBuilder[T]) GroupBy(columns ...string) *QueryBuilder[T] {
+	q.groupBy = append(q.groupBy, columns...)
+	return q
+}
+
+// Having adds predicates on the aggregation aliases of the aggregate query.
+func (q *QueryBuilder[T]) Having(predicates ...*Predicate) *QueryBuilder[T] {
+	q.having = append(q.having, predicates...)
+	return q
+}
+
+// Aggregate runs the given aggregations over the entities that match the query.
+// Each returned entity contains the group by columns and the aggregation aliases.
+func (q *QueryBuilder[T]) Aggregate(ctx context.Context, aggregations ...*Aggregation) ([]*entity.Entity, error) {
+	model, err := q.model()
+	if err != nil {
+		return nil, err
+	}
+
+	return model.Query(q.predicates...).
+		GroupBy(q.groupBy...).
+		Having(q.having...).
+		Limit(q.limit).
+		Offset(q.offset).
+		Order(q.order...).
+		Aggregate(ctx, aggregations...)
+}
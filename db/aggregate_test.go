@@ -0,0 +1,132 @@
+package db_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateFunc(t *testing.T) {
+	assert.Equal(t, "sum", db.AggregateSum.String())
+	assert.Equal(t, "invalid", db.AggregateFunc(100).String())
+	assert.True(t, db.AggregateMax.Valid())
+	assert.False(t, db.AggregateInvalid.Valid())
+
+	data, err := json.Marshal(db.AggregateAvg)
+	assert.NoError(t, err)
+	assert.Equal(t, `"avg"`, string(data))
+
+	var fn db.AggregateFunc
+	assert.NoError(t, json.Unmarshal([]byte(`"min"`), &fn))
+	assert.Equal(t, db.AggregateMin, fn)
+	assert.NoError(t, json.Unmarshal([]byte(`"unknown"`), &fn))
+	assert.Equal(t, db.AggregateInvalid, fn)
+	assert.Error(t, json.Unmarshal([]byte(`1`), &fn))
+}
+
+func TestAggregationHelpers(t *testing.T) {
+	assert.Equal(t, &db.Aggregation{Func: db.AggregateCount, Alias: "count"}, db.AggCount(""))
+	assert.Equal(t, &db.Aggregation{Func: db.AggregateSum, Field: "price", Alias: "sum_price"}, db.AggSum("price"))
+	assert.Equal(t, &db.Aggregation{Func: db.AggregateAvg, Field: "price", Alias: "avg"}, db.AggAvg("price", "avg"))
+	assert.Equal(t, "min_price", db.AggMin("price").Alias)
+	assert.Equal(t, "max_price", db.AggMax("price").Alias)
+
+	aggregation := db.AggSum("price")
+	assert.Equal(t, aggregation, aggregation.Clone())
+}
+
+func TestParseAggregations(t *testing.T) {
+	aggregations, err := db.ParseAggregations("count, sum:price, avg:price:average,COUNT:id:")
+	assert.NoError(t, err)
+	assert.Equal(t, []*db.Aggregation{
+		db.AggCount(""),
+		db.AggSum("price"),
+		db.AggAvg("price", "average"),
+		db.AggCount("id"),
+	}, aggregations)
+
+	_, err = db.ParseAggregations("median:price")
+	assert.ErrorContains(t, err, `invalid aggregate function "median"`)
+
+	_, err = db.ParseAggregations("sum")
+	assert.ErrorContains(t, err, `aggregate function "sum" requires a field`)
+
+	_, err = db.ParseAggregations("sum:price:total:extra")
+	assert.ErrorContains(t, err, `invalid aggregation "sum:price:total:extra"`)
+}
+
+func TestCreateHavingPredicatesFromFilterObject(t *testing.T) {
+	aggregations := []*db.Aggregation{db.AggCount(""), db.AggSum("price", "total")}
+
+	predicates, err := db.CreateHavingPredicatesFromFilterObject(aggregations, "")
+	assert.NoError(t, err)
+	assert.Empty(t, predicates)
+
+	_, err = db.CreateHavingPredicatesFromFilterObject(aggregations, "invalid")
+	assert.Error(t, err)
+
+	_, err = db.CreateHavingPredicatesFromFilterObject(aggregations, `{"price": 1}`)
+	assert.ErrorContains(t, err, "price is not an aggregation alias")
+
+	_, err = db.CreateHavingPredicatesFromFilterObject(aggregations, `{"$or": 1}`)
+	assert.Error(t, err)
+
+	predicates, err = db.CreateHavingPredicatesFromFilterObject(
+		aggregations,
+		`{"count": 2, "total": {"$gt": 10, "$lt": 100}, "$or": [{"count": 1}, {"total": {"$gte": 5}}]}`,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []*db.Predicate{
+		db.EQ("count", float64(2)),
+		db.And(db.GT("total", float64(10)), db.LT("total", float64(100))),
+		db.Or(db.EQ("count", float64(1)), db.GTE("total", float64(5))),
+	}, predicates)
+}
+
+func TestQueryBuilderAggregate(t *testing.T) {
+	client, ctx := prepareTest()
+
+	for i := 1; i <= 5; i++ {
+		_, err := db.Create[TestCategory](ctx, client, fs.Map{
+			"name": fmt.Sprintf("category %d", i),
+		})
+		assert.NoError(t, err)
+	}
+
+	// Case 1: Aggregate invalid model.
+	_, err := db.Builder[testPost](client).Aggregate(ctx, db.AggCount(""))
+	assert.Error(t, err)
+
+	// Case 2: Aggregate non filterable column.
+	_, err = db.Builder[TestCategory](client).Aggregate(ctx, db.AggMax("name"))
+	assert.ErrorContains(t, err, `column "name" is not filterable`)
+
+	// Case 3: Aggregate success.
+	results, err := db.Builder[TestCategory](client).
+		Where(db.GTE("id", 2)).
+		Aggregate(ctx, db.AggCount(""), db.AggSum("id"), db.AggMin("id"), db.AggMax("id", "last"))
+	assert.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, int64(4), results[0].Get("count"))
+	assert.Equal(t, int64(14), results[0].Get("sum_id"))
+	assert.Equal(t, int64(2), results[0].Get("min_id"))
+	assert.Equal(t, int64(5), results[0].Get("last"))
+
+	// Case 4: Aggregate with group by, having, order, limit and offset.
+	results, err = db.Builder[TestCategory](client).
+		GroupBy("id").
+		Having(db.GT("count", 0)).
+		Order("-id").
+		Limit(2).
+		Offset(1).
+		Aggregate(ctx, db.AggCount(""))
+	assert.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, int64(4), results[0].ID())
+	assert.Equal(t, int64(3), results[1].ID())
+}
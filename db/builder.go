@@ -19,6 +19,12 @@ type QueryBuilder[T any] struct {
 	offset uint
 	fields []string
 	order  []string
+	after  string
+	before string
+
+	// Aggregate specific fields
+	groupBy []string
+	having  []*Predicate
 }
 
 func Builder[T any](client Client, schemas ...string) *QueryBuilder[T] {
@@ -0,0 +1,249 @@
+package db
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/schema"
+)
+
+// Cache stores the query results of the schemas that have a cache TTL.
+//
+//	The cached results are invalidated by the hooks created by CacheHooks
+//	when a record of the schema or of a related schema is created, updated or deleted.
+//	Cache backends handle their own errors, a failing Get is treated as a cache miss.
+type Cache interface {
+	// Get returns the cached entities of the key and whether the key is found.
+	Get(ctx context.Context, key string) ([]*entity.Entity, bool)
+	// Set caches the entities of the query on the schema for the given ttl.
+	Set(ctx context.Context, key, schemaName string, entities []*entity.Entity, ttl time.Duration)
+	// Invalidate removes the cached results of the given schemas.
+	Invalidate(ctx context.Context, schemaNames ...string)
+}
+
+// CacheTTL returns the time to live of the cached query results of the schema.
+// Returns 0 if the results of the schema are not cached.
+func CacheTTL(s *schema.Schema) time.Duration {
+	if s == nil || s.Settings == nil || s.Settings.Cache == nil || s.Settings.Cache.TTL <= 0 {
+		return 0
+	}
+
+	return time.Duration(s.Settings.Cache.TTL) * time.Second
+}
+
+// CacheKey returns the cache key of the query.
+// The key is the schema name followed by the hash of the normalized query options.
+func CacheKey(option *QueryOption, relationOptions RelationOptions) (string, error) {
+	key := struct {
+		Limit           uint            `json:"limit"`
+		Offset          uint            `json:"offset"`
+		Columns         []string        `json:"columns"`
+		Order           []string        `json:"order"`
+		Predicates      []*Predicate    `json:"predicates"`
+		After           string          `json:"after"`
+		Before          string          `json:"before"`
+		RelationOptions RelationOptions `json:"relation_options"`
+	}{
+		Limit:           option.Limit,
+		Offset:          option.Offset,
+		Order:           option.Order,
+		After:           option.After,
+		Before:          option.Before,
+		RelationOptions: relationOptions,
+	}
+
+	if option.Columns != nil {
+		key.Columns = *option.Columns
+	}
+
+	if option.Predicates != nil {
+		key.Predicates = *option.Predicates
+	}
+
+	data, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+	return option.Schema.Name + ":" + hex.EncodeToString(hash[:]), nil
+}
+
+// RelatedSchemaNames returns the names of the schemas that have a relation with the schema.
+func RelatedSchemaNames(s *schema.Schema) []string {
+	names := []string{}
+	for _, field := range s.Fields {
+		if field.Type.IsRelationType() && field.Relation != nil {
+			names = append(names, field.Relation.TargetSchemaName)
+		}
+	}
+
+	return names
+}
+
+// CacheHooks creates the hooks that invalidate the cached results of the mutated schema
+// and of its related schemas, since the related results may contain the mutated records.
+// The mutations of a transaction invalidate the cached results after the transaction is committed,
+// so that the results read before the commit are not cached again.
+func CacheHooks(cache Cache) *Hooks {
+	invalidate := func(ctx context.Context, s *schema.Schema) {
+		schemaNames := append([]string{s.Name}, RelatedSchemaNames(s)...)
+		AfterCommit(ctx, func() {
+			cache.Invalidate(context.WithoutCancel(ctx), schemaNames...)
+		})
+	}
+
+	return &Hooks{
+		PostDBCreate: []PostDBCreate{
+			func(ctx context.Context, s *schema.Schema, _ *entity.Entity, _ any) error {
+				invalidate(ctx, s)
+				return nil
+			},
+		},
+		PostDBUpdate: []PostDBUpdate{
+			func(
+				ctx context.Context,
+				s *schema.Schema,
+				_ *[]*Predicate,
+				_ *entity.Entity,
+				_ []*entity.Entity,
+				_ int,
+			) error {
+				invalidate(ctx, s)
+				return nil
+			},
+		},
+		PostDBDelete: []PostDBDelete{
+			func(
+				ctx context.Context,
+				s *schema.Schema,
+				_ *[]*Predicate,
+				_ []*entity.Entity,
+				_ int,
+			) error {
+				invalidate(ctx, s)
+				return nil
+			},
+		},
+	}
+}
+
+type lruCacheItem struct {
+	key        string
+	schemaName string
+	entities   []*entity.Entity
+	expiresAt  time.Time
+}
+
+// LRUCache is an in-memory Cache that evicts the least recently used results
+// when the number of cached results reaches the capacity.
+// The entities are cloned when they are cached and returned,
+// so that the callers can modify the returned entities.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	recent   *list.List
+	schemas  map[string]map[string]struct{}
+}
+
+// NewLRUCache creates a new LRUCache that holds at most capacity query results.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: max(capacity, 1),
+		items:    map[string]*list.Element{},
+		recent:   list.New(),
+		schemas:  map[string]map[string]struct{}{},
+	}
+}
+
+// Get returns the cached entities of the key if they are not expired.
+func (c *LRUCache) Get(_ context.Context, key string) ([]*entity.Entity, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	item := element.Value.(*lruCacheItem)
+	if time.Now().After(item.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.recent.MoveToFront(element)
+	return cloneEntities(item.entities), true
+}
+
+// Set caches the entities of the key, the least recently used results are evicted if the cache is full.
+func (c *LRUCache) Set(_ context.Context, key, schemaName string, entities []*entity.Entity, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+
+	c.items[key] = c.recent.PushFront(&lruCacheItem{
+		key:        key,
+		schemaName: schemaName,
+		entities:   cloneEntities(entities),
+		expiresAt:  time.Now().Add(ttl),
+	})
+
+	if c.schemas[schemaName] == nil {
+		c.schemas[schemaName] = map[string]struct{}{}
+	}
+	c.schemas[schemaName][key] = struct{}{}
+
+	for c.recent.Len() > c.capacity {
+		c.remove(c.recent.Back())
+	}
+}
+
+// Invalidate removes the cached results of the schemas.
+func (c *LRUCache) Invalidate(_ context.Context, schemaNames ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, schemaName := range schemaNames {
+		for key := range c.schemas[schemaName] {
+			c.remove(c.items[key])
+		}
+	}
+}
+
+// Len returns the number of cached results, including the expired ones that are not removed yet.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.recent.Len()
+}
+
+func (c *LRUCache) remove(element *list.Element) {
+	item := element.Value.(*lruCacheItem)
+	c.recent.Remove(element)
+	delete(c.items, item.key)
+	delete(c.schemas[item.schemaName], item.key)
+	if len(c.schemas[item.schemaName]) == 0 {
+		delete(c.schemas, item.schemaName)
+	}
+}
+
+func cloneEntities(entities []*entity.Entity) []*entity.Entity {
+	clones := make([]*entity.Entity, len(entities))
+	for i, e := range entities {
+		clones[i] = e.Clone()
+	}
+
+	return clones
+}
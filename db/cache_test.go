@@ -0,0 +1,148 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache(t *testing.T) {
+	ctx := context.Background()
+	cache := db.NewLRUCache(2)
+
+	// Case 1: Miss
+	_, ok := cache.Get(ctx, "missing")
+	assert.False(t, ok)
+
+	// Case 2: Hit returns a copy of the cached entities
+	entities := []*entity.Entity{entity.New(1).Set("name", "a")}
+	cache.Set(ctx, "a", "post", entities, time.Minute)
+	entities[0].Set("name", "changed")
+
+	cached, ok := cache.Get(ctx, "a")
+	require.True(t, ok)
+	assert.Equal(t, "a", cached[0].GetString("name"))
+	cached[0].Set("name", "changed")
+	cached, _ = cache.Get(ctx, "a")
+	assert.Equal(t, "a", cached[0].GetString("name"))
+
+	// Case 3: The least recently used result is evicted
+	cache.Set(ctx, "b", "tag", nil, time.Minute)
+	_, _ = cache.Get(ctx, "a")
+	cache.Set(ctx, "c", "post", nil, time.Minute)
+	assert.Equal(t, 2, cache.Len())
+	_, ok = cache.Get(ctx, "b")
+	assert.False(t, ok)
+	_, ok = cache.Get(ctx, "a")
+	assert.True(t, ok)
+
+	// Case 4: Replace an existing key
+	cache.Set(ctx, "a", "tag", []*entity.Entity{entity.New(2)}, time.Minute)
+	assert.Equal(t, 2, cache.Len())
+	cached, _ = cache.Get(ctx, "a")
+	assert.Equal(t, 2, cached[0].ID())
+
+	// Case 5: Invalidate the results of the schemas
+	cache.Invalidate(ctx, "post", "invalid")
+	_, ok = cache.Get(ctx, "c")
+	assert.False(t, ok)
+	_, ok = cache.Get(ctx, "a")
+	assert.True(t, ok)
+	cache.Invalidate(ctx, "tag")
+	assert.Equal(t, 0, cache.Len())
+
+	// Case 6: Expired results are removed
+	cache.Set(ctx, "d", "post", nil, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = cache.Get(ctx, "d")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+
+	// Case 7: The capacity is at least 1
+	cache = db.NewLRUCache(0)
+	cache.Set(ctx, "a", "post", nil, time.Minute)
+	_, ok = cache.Get(ctx, "a")
+	assert.True(t, ok)
+}
+
+func TestCacheTTL(t *testing.T) {
+	assert.Equal(t, time.Duration(0), db.CacheTTL(nil))
+	assert.Equal(t, time.Duration(0), db.CacheTTL(&schema.Schema{}))
+	assert.Equal(t, time.Duration(0), db.CacheTTL(&schema.Schema{Settings: &schema.SchemaSettings{}}))
+	assert.Equal(t, time.Duration(0), db.CacheTTL(&schema.Schema{Settings: &schema.SchemaSettings{
+		Cache: &schema.SchemaCacheSettings{TTL: -1},
+	}}))
+	assert.Equal(t, 30*time.Second, db.CacheTTL(&schema.Schema{Settings: &schema.SchemaSettings{
+		Cache: &schema.SchemaCacheSettings{TTL: 30},
+	}}))
+}
+
+func TestCacheKey(t *testing.T) {
+	sb := createJSONTestSchemaBuilder(t)
+	product := utils.Must(sb.Schema("product"))
+	option := func(limit uint, predicates ...*db.Predicate) *db.QueryOption {
+		return &db.QueryOption{
+			Schema:     product,
+			Limit:      limit,
+			Columns:    &[]string{"name"},
+			Order:      []string{"-id"},
+			Predicates: &predicates,
+		}
+	}
+
+	key := utils.Must(db.CacheKey(option(10, db.EQ("name", "a")), nil))
+	assert.Regexp(t, "^product:[0-9a-f]{64}$", key)
+	assert.Equal(t, key, utils.Must(db.CacheKey(option(10, db.EQ("name", "a")), nil)))
+	assert.NotEqual(t, key, utils.Must(db.CacheKey(option(20, db.EQ("name", "a")), nil)))
+	assert.NotEqual(t, key, utils.Must(db.CacheKey(option(10, db.EQ("name", "b")), nil)))
+	assert.NotEqual(t, key, utils.Must(db.CacheKey(option(10, db.EQ("name", "a")), db.RelationOptions{
+		"vendor": {Limit: 1},
+	})))
+	assert.Regexp(t, "^product:", utils.Must(db.CacheKey(&db.QueryOption{Schema: product}, nil)))
+
+	_, err := db.CacheKey(option(10, db.EQ("name", func() {})), nil)
+	assert.Error(t, err)
+}
+
+type testCache struct {
+	invalidated []string
+}
+
+func (c *testCache) Get(context.Context, string) ([]*entity.Entity, bool) {
+	return nil, false
+}
+
+func (c *testCache) Set(context.Context, string, string, []*entity.Entity, time.Duration) {}
+
+func (c *testCache) Invalidate(_ context.Context, schemaNames ...string) {
+	c.invalidated = append(c.invalidated, schemaNames...)
+}
+
+func TestCacheHooks(t *testing.T) {
+	ctx := context.Background()
+	sb := createJSONTestSchemaBuilder(t)
+	product := utils.Must(sb.Schema("product"))
+	vendor := utils.Must(sb.Schema("vendor"))
+
+	assert.Equal(t, []string{"vendor"}, db.RelatedSchemaNames(product))
+
+	cache := &testCache{}
+	hooks := db.CacheHooks(cache)
+	assert.NoError(t, hooks.PostDBCreate[0](ctx, product, entity.New(), 1))
+	assert.Equal(t, []string{"product", "vendor"}, cache.invalidated)
+
+	cache.invalidated = nil
+	assert.NoError(t, hooks.PostDBUpdate[0](ctx, vendor, nil, entity.New(), nil, 1))
+	assert.Equal(t, []string{"vendor", "product"}, cache.invalidated)
+
+	cache.invalidated = nil
+	assert.NoError(t, hooks.PostDBDelete[0](ctx, product, nil, nil, 1))
+	assert.Equal(t, []string{"product", "vendor"}, cache.invalidated)
+}
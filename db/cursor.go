@@ -0,0 +1,233 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+)
+
+// Cursor represents a position in a result set used for keyset pagination.
+// It holds the keyset order (the sort columns followed by the primary key)
+// and the values of these columns for the record at the position, nil for the NULL values.
+//
+//	Cursors are passed around as opaque base64 encoded strings.
+type Cursor struct {
+	Order  []string  `json:"o"`
+	Values []*string `json:"v"`
+}
+
+// KeysetOrder returns the order used for keyset pagination.
+// It contains the given order columns followed by the primary key
+// of the schema, so that the order is always deterministic.
+// If the primary key is not present in the order, it is appended in ascending order.
+func KeysetOrder(s *schema.Schema, order []string) []string {
+	keysetOrder := []string{}
+	pkName := s.PrimaryKeyName()
+	hasPK := false
+
+	for _, o := range order {
+		for _, column := range strings.Split(o, ",") {
+			column = strings.TrimSpace(column)
+			if column == "" || slices.Contains(keysetOrder, column) {
+				continue
+			}
+
+			if strings.TrimPrefix(column, "-") == pkName {
+				hasPK = true
+			}
+
+			keysetOrder = append(keysetOrder, column)
+		}
+	}
+
+	if !hasPK {
+		keysetOrder = append(keysetOrder, pkName)
+	}
+
+	return keysetOrder
+}
+
+// NewCursor creates a cursor for the given entity using the keyset order.
+// The missing columns are NULL since the NULL values are not set when the records are read,
+// the primary key must not be missing or null.
+func NewCursor(s *schema.Schema, order []string, e *entity.Entity) (*Cursor, error) {
+	cursor := &Cursor{Order: KeysetOrder(s, order)}
+
+	for _, o := range cursor.Order {
+		column := strings.TrimPrefix(o, "-")
+		value := e.Get(column)
+		if value == nil && column == s.PrimaryKeyName() {
+			return nil, fmt.Errorf("cursor column %q is missing or null", column)
+		}
+
+		if value == nil {
+			cursor.Values = append(cursor.Values, nil)
+			continue
+		}
+
+		stringValue := cursorValueToString(value)
+		cursor.Values = append(cursor.Values, &stringValue)
+	}
+
+	return cursor, nil
+}
+
+// CreateCursor creates an encoded cursor for the given entity using the keyset order.
+func CreateCursor(s *schema.Schema, order []string, e *entity.Entity) (string, error) {
+	cursor, err := NewCursor(s, order, e)
+	if err != nil {
+		return "", err
+	}
+
+	return cursor.Encode(), nil
+}
+
+// DecodeCursor decodes an encoded cursor.
+func DecodeCursor(encoded string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	cursor := &Cursor{}
+	if err := json.Unmarshal(data, cursor); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	if len(cursor.Order) == 0 || len(cursor.Order) != len(cursor.Values) {
+		return nil, errors.New("invalid cursor: order and values mismatch")
+	}
+
+	return cursor, nil
+}
+
+// Encode returns the opaque string representation of the cursor.
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Predicates creates the keyset predicates that select the records after the cursor
+// or before the cursor if before is true. E.g. for the order [name, -id]:
+//
+//	name > ? OR (name = ? AND id < ?)
+//
+// nullsFirst reports whether the database orders the NULL values before the other values
+// in ascending order (SQLite, MySQL) or after them (Postgres).
+func (c *Cursor) Predicates(s *schema.Schema, order []string, before, nullsFirst bool) ([]*Predicate, error) {
+	keysetOrder := KeysetOrder(s, order)
+	if !slices.Equal(keysetOrder, c.Order) {
+		return nil, fmt.Errorf(
+			"cursor order %q does not match the query order %q",
+			strings.Join(c.Order, ","),
+			strings.Join(keysetOrder, ","),
+		)
+	}
+
+	values := make([]any, len(c.Order))
+	nullables := make([]bool, len(c.Order))
+	for i, o := range c.Order {
+		column := strings.TrimPrefix(o, "-")
+		field := s.Field(column)
+		if field == nil {
+			return nil, schema.ErrFieldNotFound(s.Name, column)
+		}
+
+		nullables[i] = field.Optional
+
+		if c.Values[i] == nil {
+			continue
+		}
+
+		value, err := schema.StringToFieldValue[any](field, *c.Values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = value
+	}
+
+	orPredicates := []*Predicate{}
+	for i, o := range c.Order {
+		column, desc := strings.CutPrefix(o, "-")
+		andPredicates := []*Predicate{}
+		for j := 0; j < i; j++ {
+			andPredicates = append(andPredicates, cursorEQ(strings.TrimPrefix(c.Order[j], "-"), values[j]))
+		}
+
+		var predicate *Predicate
+		if desc != before {
+			predicate = cursorLT(column, values[i], nullables[i], nullsFirst)
+		} else {
+			predicate = cursorGT(column, values[i], nullables[i], nullsFirst)
+		}
+
+		// No value is beyond NULL in this direction
+		if predicate == nil {
+			continue
+		}
+
+		andPredicates = append(andPredicates, predicate)
+		orPredicates = append(orPredicates, And(andPredicates...))
+	}
+
+	return []*Predicate{Or(orPredicates...)}, nil
+}
+
+// cursorEQ matches the values equal to the cursor value, NULL is equal to NULL.
+func cursorEQ(column string, value any) *Predicate {
+	if value == nil {
+		return Null(column, true)
+	}
+
+	return EQ(column, value)
+}
+
+// cursorGT matches the values greater than the cursor value.
+// It returns nil if the cursor value is NULL and NULL is the greatest value.
+func cursorGT(column string, value any, nullable, nullsFirst bool) *Predicate {
+	if value == nil {
+		return utils.If(nullsFirst, Null(column, false), nil)
+	}
+
+	if !nullable || nullsFirst {
+		return GT(column, value)
+	}
+
+	return Or(GT(column, value), Null(column, true))
+}
+
+// cursorLT matches the values less than the cursor value.
+// It returns nil if the cursor value is NULL and NULL is the least value.
+func cursorLT(column string, value any, nullable, nullsFirst bool) *Predicate {
+	if value == nil {
+		return utils.If(nullsFirst, nil, Null(column, false))
+	}
+
+	if !nullable || !nullsFirst {
+		return LT(column, value)
+	}
+
+	return Or(LT(column, value), Null(column, true))
+}
+
+func cursorValueToString(value any) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case *time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,215 @@
+package db_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createCursorTestSchema(t *testing.T) *schema.Schema {
+	s := &schema.Schema{
+		Name:           "post",
+		Namespace:      "posts",
+		LabelFieldName: "title",
+		Fields: []*schema.Field{
+			{Name: "id", Type: schema.TypeUint64},
+			{Name: "title", Type: schema.TypeString, Sortable: true, Optional: true},
+			{Name: "published_at", Type: schema.TypeTime, Sortable: true},
+		},
+	}
+	require.NoError(t, s.Init(false))
+	return s
+}
+
+func cursorValues(values ...string) []*string {
+	return utils.Map(values, func(value string) *string { return &value })
+}
+
+func TestKeysetOrder(t *testing.T) {
+	s := createCursorTestSchema(t)
+	assert.Equal(t, []string{"id"}, db.KeysetOrder(s, nil))
+	assert.Equal(t, []string{"-id"}, db.KeysetOrder(s, []string{"-id"}))
+	assert.Equal(t, []string{"title", "-published_at", "id"}, db.KeysetOrder(s, []string{"title, -published_at", "title", ""}))
+	assert.Equal(t, []string{"-id", "title"}, db.KeysetOrder(s, []string{"-id", "title"}))
+}
+
+func TestCursorEncodeDecode(t *testing.T) {
+	s := createCursorTestSchema(t)
+	publishedAt := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	e := entity.New(uint64(10)).Set("title", "hello").Set("published_at", publishedAt)
+
+	cursor, err := db.NewCursor(s, []string{"-published_at"}, e)
+	assert.NoError(t, err)
+	assert.Equal(t, &db.Cursor{
+		Order:  []string{"-published_at", "id"},
+		Values: cursorValues("2024-01-02T03:04:05.000000006Z", "10"),
+	}, cursor)
+
+	encoded, err := db.CreateCursor(s, []string{"-published_at"}, e)
+	assert.NoError(t, err)
+	assert.Equal(t, cursor.Encode(), encoded)
+
+	decoded, err := db.DecodeCursor(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	// Missing column value is NULL
+	cursor, err = db.NewCursor(s, []string{"title"}, entity.New(uint64(1)))
+	assert.NoError(t, err)
+	assert.Equal(t, []*string{nil, cursorValues("1")[0]}, cursor.Values)
+
+	// Invalid cursors
+	_, err = db.DecodeCursor("!invalid")
+	assert.ErrorContains(t, err, "invalid cursor")
+
+	_, err = db.DecodeCursor("aW52YWxpZA")
+	assert.ErrorContains(t, err, "invalid cursor")
+
+	_, err = db.DecodeCursor((&db.Cursor{Order: []string{"id"}}).Encode())
+	assert.ErrorContains(t, err, "invalid cursor: order and values mismatch")
+
+	// Null values are encoded, the primary key must not be null
+	cursor, err = db.NewCursor(s, []string{"title"}, entity.New(uint64(1)).Set("title", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []*string{nil, cursorValues("1")[0]}, cursor.Values)
+	decoded, err = db.DecodeCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	_, err = db.NewCursor(s, nil, entity.New().Set("id", nil))
+	assert.ErrorContains(t, err, `cursor column "id" is missing or null`)
+}
+
+func TestCursorPredicates(t *testing.T) {
+	s := createCursorTestSchema(t)
+	cursor := &db.Cursor{
+		Order:  []string{"title", "-id"},
+		Values: cursorValues("hello", "10"),
+	}
+
+	// Order mismatch
+	_, err := cursor.Predicates(s, []string{"title"}, false, true)
+	assert.ErrorContains(t, err, `cursor order "title,-id" does not match the query order "title,id"`)
+
+	// After
+	predicates, err := cursor.Predicates(s, []string{"title", "-id"}, false, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []*db.Predicate{db.Or(
+		db.And(db.GT("title", "hello")),
+		db.And(db.EQ("title", "hello"), db.LT("id", uint64(10))),
+	)}, predicates)
+
+	// Null value, NULL is ordered before the other values in ascending order
+	nullCursor := &db.Cursor{Order: []string{"title", "-id"}, Values: []*string{nil, cursorValues("10")[0]}}
+	predicates, err = nullCursor.Predicates(s, []string{"title", "-id"}, false, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []*db.Predicate{db.Or(
+		db.And(db.Null("title", false)),
+		db.And(db.Null("title", true), db.LT("id", uint64(10))),
+	)}, predicates)
+
+	predicates, err = nullCursor.Predicates(s, []string{"title", "-id"}, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []*db.Predicate{db.Or(
+		db.And(db.Null("title", true), db.GT("id", uint64(10))),
+	)}, predicates)
+
+	// Before, the NULL values are before any value
+	predicates, err = cursor.Predicates(s, []string{"title", "-id"}, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []*db.Predicate{db.Or(
+		db.And(db.Or(db.LT("title", "hello"), db.Null("title", true))),
+		db.And(db.EQ("title", "hello"), db.GT("id", uint64(10))),
+	)}, predicates)
+
+	// NULL is ordered after the other values in ascending order (Postgres)
+	predicates, err = cursor.Predicates(s, []string{"title", "-id"}, false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []*db.Predicate{db.Or(
+		db.And(db.Or(db.GT("title", "hello"), db.Null("title", true))),
+		db.And(db.EQ("title", "hello"), db.LT("id", uint64(10))),
+	)}, predicates)
+
+	predicates, err = nullCursor.Predicates(s, []string{"title", "-id"}, false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []*db.Predicate{db.Or(
+		db.And(db.Null("title", true), db.LT("id", uint64(10))),
+	)}, predicates)
+
+	predicates, err = nullCursor.Predicates(s, []string{"title", "-id"}, true, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []*db.Predicate{db.Or(
+		db.And(db.Null("title", false)),
+		db.And(db.Null("title", true), db.GT("id", uint64(10))),
+	)}, predicates)
+
+	// Invalid value
+	cursor.Values[1] = cursorValues("invalid")[0]
+	_, err = cursor.Predicates(s, []string{"title", "-id"}, false, true)
+	assert.Error(t, err)
+
+	// Invalid field
+	cursor = &db.Cursor{Order: []string{"invalid", "id"}, Values: cursorValues("1", "1")}
+	_, err = cursor.Predicates(s, []string{"invalid"}, false, true)
+	assert.Error(t, err)
+}
+
+func TestQueryBuilderCursor(t *testing.T) {
+	client, ctx := prepareTest()
+
+	for i := 1; i <= 5; i++ {
+		_, err := db.Create[TestCategory](ctx, client, fs.Map{
+			"name": fmt.Sprintf("category %d", i),
+		})
+		assert.NoError(t, err)
+	}
+
+	categorySchema := utils.Must(client.SchemaBuilder().Schema("category"))
+	cursor := func(id uint64) string {
+		return utils.Must(db.CreateCursor(categorySchema, []string{"-id"}, entity.New(id)))
+	}
+
+	// Case 1: After
+	categories, err := db.Builder[TestCategory](client).
+		Order("-id").
+		After(cursor(4)).
+		Limit(2).
+		Get(ctx)
+	assert.NoError(t, err)
+	require.Len(t, categories, 2)
+	assert.Equal(t, uint64(3), categories[0].ID)
+	assert.Equal(t, uint64(2), categories[1].ID)
+
+	// Case 2: Before
+	categories, err = db.Builder[TestCategory](client).
+		Order("-id").
+		Before(cursor(2)).
+		Limit(2).
+		Get(ctx)
+	assert.NoError(t, err)
+	require.Len(t, categories, 2)
+	assert.Equal(t, uint64(4), categories[0].ID)
+	assert.Equal(t, uint64(3), categories[1].ID)
+
+	// Case 3: Cursor order mismatch
+	_, err = db.Builder[TestCategory](client).
+		After(cursor(2)).
+		Get(ctx)
+	assert.ErrorContains(t, err, "does not match the query order")
+
+	// Case 4: After and before together
+	_, err = db.Builder[TestCategory](client).
+		Order("-id").
+		After(cursor(4)).
+		Before(cursor(2)).
+		Get(ctx)
+	assert.ErrorContains(t, err, "after and before cursors cannot be used together")
+}
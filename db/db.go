@@ -148,6 +148,18 @@ type Config struct {
 	DisableForeignKeys bool          `json:"disable_foreign_keys"`
 	UseSoftDeletes     bool          `json:"use_soft_deletes"`
 	Hooks              func() *Hooks `json:"-"`
+	// Cache caches the query results of the schemas that have a cache TTL in their settings.
+	Cache Cache `json:"-"`
+	// Replicas are the read replicas of the database, the queries are routed to the healthy replicas.
+	Replicas []*ReplicaConfig `json:"replicas,omitempty"`
+	// ReplicaHealthCheck is the interval in seconds between the replica health checks, default to 10.
+	ReplicaHealthCheck int `json:"replica_health_check,omitempty"`
+	// SlowQueryThreshold is the duration in milliseconds from which a query is logged as a slow query.
+	// The slow query log is disabled if the threshold is not set.
+	SlowQueryThreshold int `json:"slow_query_threshold,omitempty"`
+	// TrashRetentionDays is the number of days the soft deleted records are kept in the trash,
+	// the older ones are permanently deleted. The trash is kept forever if it is not set.
+	TrashRetentionDays int `json:"trash_retention_days,omitempty"`
 }
 
 func (c *Config) Clone() *Config {
@@ -163,7 +175,13 @@ func (c *Config) Clone() *Config {
 		MigrationDir:       c.MigrationDir,
 		MigrationMode:      c.MigrationMode,
 		DisableForeignKeys: c.DisableForeignKeys,
+		UseSoftDeletes:     c.UseSoftDeletes,
 		Hooks:              c.Hooks,
+		Cache:              c.Cache,
+		Replicas:           cloneReplicaConfigs(c.Replicas),
+		ReplicaHealthCheck: c.ReplicaHealthCheck,
+		SlowQueryThreshold: c.SlowQueryThreshold,
+		TrashRetentionDays: c.TrashRetentionDays,
 	}
 }
 
@@ -214,6 +232,7 @@ type Model interface {
 	Schema() *schema.Schema
 	CreateFromJSON(ctx context.Context, json string) (id any, err error)
 	Create(ctx context.Context, e *entity.Entity) (id any, err error)
+	CreateMany(ctx context.Context, entities []*entity.Entity) (ids []any, err error)
 	SetClient(client Client) Model
 	Clone() Model
 }
@@ -221,6 +240,7 @@ type Model interface {
 // QueryOption is a struct that contains query options
 //
 //	Column and Unique are used for count query.
+//	GroupBy, Aggregations and Having are used for aggregate query.
 type QueryOption struct {
 	Schema     *schema.Schema `json:"schema"`
 	Limit      uint           `json:"limit"`
@@ -230,9 +250,16 @@ type QueryOption struct {
 	Predicates *[]*Predicate  `json:"predicates"`
 	Query      string         `json:"query"`
 	Args       any            `json:"args"`
+	// For keyset pagination
+	After  string `json:"after,omitempty"`
+	Before string `json:"before,omitempty"`
 	// For count query
 	Column string `json:"column"`
 	Unique bool   `json:"unique"`
+	// For aggregate query
+	GroupBy      []string       `json:"group_by,omitempty"`
+	Aggregations []*Aggregation `json:"aggregations,omitempty"`
+	Having       []*Predicate   `json:"having,omitempty"`
 }
 
 type Querier interface {
@@ -245,14 +272,33 @@ type Querier interface {
 	Offset(offset uint) Querier
 	Select(columns ...string) Querier
 	Order(order ...string) Querier
+	// After sets the cursor to return the records after, using keyset pagination.
+	// The records are ordered by the query order followed by the primary key.
+	After(cursor string) Querier
+	// Before sets the cursor to return the records before, using keyset pagination.
+	// The records are returned in the query order.
+	Before(cursor string) Querier
 	// WithRelationOptions sets options for loading relation records.
 	// This allows limiting, offsetting, sorting, filtering, and selecting
 	// specific fields when loading relation records per entity.
 	WithRelationOptions(options RelationOptions) Querier
 	Count(ctx context.Context, options ...*QueryOption) (int, error)
+	// GroupBy sets the columns to group the aggregate query by.
+	GroupBy(columns ...string) Querier
+	// Having adds predicates on the aggregation aliases of the aggregate query.
+	Having(predicates ...*Predicate) Querier
+	// Aggregate runs the given aggregations over the records that match the query.
+	// Each returned entity contains the group by columns and the aggregation aliases.
+	Aggregate(ctx context.Context, aggregations ...*Aggregation) ([]*entity.Entity, error)
 	Get(ctx context.Context) ([]*entity.Entity, error)
+	// Each streams the records that match the query to fn without loading all of them in memory.
+	// The relations are loaded and the PostDBQuery hooks are run per batch of records.
+	// The iteration stops at the first error returned by fn.
+	Each(ctx context.Context, fn func(*entity.Entity) error) error
 	First(ctx context.Context) (*entity.Entity, error)
 	Only(ctx context.Context) (*entity.Entity, error)
+	// SQL returns the statement and the arguments of the query without running it.
+	SQL(ctx context.Context) (string, []any, error)
 	Options() *QueryOption
 }
 
@@ -260,6 +306,13 @@ type Mutator interface {
 	Where(predicates ...*Predicate) Mutator
 	GetRelationEntityIDs(fieldName string, fieldValue any) ([]driver.Value, error)
 	Create(ctx context.Context, e *entity.Entity) (id any, err error)
+	// CreateMany creates the entities using batched inserts in a single transaction
+	// and returns the created IDs in the same order as the entities.
+	CreateMany(ctx context.Context, entities []*entity.Entity) (ids []any, err error)
 	Update(ctx context.Context, e *entity.Entity) (affected int, err error)
+	// Upsert creates the entity or updates the existing one that has the same conflict column values.
+	Upsert(ctx context.Context, e *entity.Entity, conflictColumns, updateColumns []string) (id any, err error)
 	Delete(ctx context.Context) (affected int, err error)
+	// Restore restores the soft deleted records and their m2m junction rows from the trash.
+	Restore(ctx context.Context) (affected int, err error)
 }
@@ -65,6 +65,17 @@ func TestDBConfigClone(t *testing.T) {
 	assert.Equal(t, c.MigrationDir, clone.MigrationDir)
 }
 
+func TestDBConfigCloneTrash(t *testing.T) {
+	c := &db.Config{
+		UseSoftDeletes:     true,
+		TrashRetentionDays: 30,
+	}
+
+	clone := c.Clone()
+	assert.True(t, clone.UseSoftDeletes)
+	assert.Equal(t, 30, clone.TrashRetentionDays)
+}
+
 func TestHooksClone(t *testing.T) {
 	hooks := &db.Hooks{
 		PostDBQuery: []db.PostDBQuery{func(ctx context.Context, option *db.QueryOption, entities []*entity.Entity) ([]*entity.Entity, error) {
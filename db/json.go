@@ -0,0 +1,70 @@
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/fastschema/fastschema/schema"
+)
+
+// jsonPathSegmentRegex matches a segment of a JSON path: an object key or an array index.
+var jsonPathSegmentRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// JSONPath is a dot notation field path that points to a value inside a JSON field.
+// E.g. "owner.metadata.color" is parsed to:
+//
+//	JSONPath{Relations: ["owner"], Field: "metadata", Path: ["color"]}
+type JSONPath struct {
+	Relations []string // The relation fields to traverse before reaching the JSON field
+	Field     string   // The JSON field name
+	Path      []string // The object keys or array indexes inside the JSON value
+}
+
+// ParseJSONPath parses a field path that points to a value inside a JSON field.
+// The path may start with relation fields, e.g. "owner.metadata.color".
+// The relation fields are only resolved when the schema builder is provided.
+// Returns nil if the field path does not point to a JSON field.
+func ParseJSONPath(sb *schema.Builder, s *schema.Schema, fieldPath string) (*JSONPath, error) {
+	if s == nil || !strings.Contains(fieldPath, ".") {
+		return nil, nil
+	}
+
+	parts := strings.Split(fieldPath, ".")
+	currentSchema := s
+	for i, part := range parts[:len(parts)-1] {
+		field := currentSchema.Field(part)
+		if field == nil {
+			return nil, nil
+		}
+
+		if field.Type == schema.TypeJSON {
+			path := parts[i+1:]
+			for _, segment := range path {
+				if !jsonPathSegmentRegex.MatchString(segment) {
+					return nil, fmt.Errorf("invalid JSON path %q", fieldPath)
+				}
+			}
+
+			return &JSONPath{Relations: parts[:i], Field: part, Path: path}, nil
+		}
+
+		if !field.Type.IsRelationType() || sb == nil {
+			return nil, nil
+		}
+
+		targetSchema, err := sb.Schema(field.Relation.TargetSchemaName)
+		if err != nil {
+			return nil, nil
+		}
+
+		currentSchema = targetSchema
+	}
+
+	return nil, nil
+}
+
+// FieldPath returns the JSON field path without the relation fields, e.g. "metadata.color".
+func (p *JSONPath) FieldPath() string {
+	return p.Field + "." + strings.Join(p.Path, ".")
+}
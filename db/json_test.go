@@ -0,0 +1,146 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createJSONTestSchemaBuilder(t *testing.T) *schema.Builder {
+	productSchema := &schema.Schema{
+		Name:           "product",
+		Namespace:      "products",
+		LabelFieldName: "name",
+		Fields: []*schema.Field{
+			{Name: "name", Type: schema.TypeString},
+			{Name: "metadata", Type: schema.TypeJSON, Optional: true},
+			{
+				Name: "vendor",
+				Type: schema.TypeRelation,
+				Relation: &schema.Relation{
+					Type:             schema.O2M,
+					TargetSchemaName: "vendor",
+					TargetFieldName:  "products",
+				},
+			},
+		},
+	}
+
+	vendorSchema := &schema.Schema{
+		Name:           "vendor",
+		Namespace:      "vendors",
+		LabelFieldName: "name",
+		Fields: []*schema.Field{
+			{Name: "name", Type: schema.TypeString},
+			{Name: "settings", Type: schema.TypeJSON, Optional: true},
+			{
+				Name: "products",
+				Type: schema.TypeRelation,
+				Relation: &schema.Relation{
+					Type:             schema.O2M,
+					Owner:            true,
+					TargetSchemaName: "product",
+					TargetFieldName:  "vendor",
+				},
+			},
+		},
+	}
+
+	sb, err := schema.NewBuilderFromSchemas("", map[string]*schema.Schema{
+		productSchema.Name: productSchema,
+		vendorSchema.Name:  vendorSchema,
+	})
+	require.NoError(t, err)
+
+	return sb
+}
+
+func TestParseJSONPath(t *testing.T) {
+	sb := createJSONTestSchemaBuilder(t)
+	product := utils.Must(sb.Schema("product"))
+
+	tests := []struct {
+		name        string
+		sb          *schema.Builder
+		field       string
+		expect      *db.JSONPath
+		expectError string
+	}{
+		{name: "simple field", sb: sb, field: "name"},
+		{name: "json field", sb: sb, field: "metadata"},
+		{name: "non json field", sb: sb, field: "name.first"},
+		{name: "invalid field", sb: sb, field: "invalid.color"},
+		{name: "relation field", sb: sb, field: "vendor.name"},
+		{
+			name:   "json path",
+			sb:     sb,
+			field:  "metadata.color",
+			expect: &db.JSONPath{Relations: []string{}, Field: "metadata", Path: []string{"color"}},
+		},
+		{
+			name:   "nested json path",
+			sb:     sb,
+			field:  "metadata.sizes.0.value",
+			expect: &db.JSONPath{Relations: []string{}, Field: "metadata", Path: []string{"sizes", "0", "value"}},
+		},
+		{
+			name:   "relation json path",
+			sb:     sb,
+			field:  "vendor.settings.currency",
+			expect: &db.JSONPath{Relations: []string{"vendor"}, Field: "settings", Path: []string{"currency"}},
+		},
+		{name: "relation json path without schema builder", field: "vendor.settings.currency"},
+		{name: "empty segment", sb: sb, field: "metadata..color", expectError: `invalid JSON path "metadata..color"`},
+		{name: "invalid segment", sb: sb, field: "metadata.color'", expectError: `invalid JSON path "metadata.color'"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonPath, err := db.ParseJSONPath(tt.sb, product, tt.field)
+			if tt.expectError != "" {
+				assert.EqualError(t, err, tt.expectError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, jsonPath)
+		})
+	}
+
+	assert.Equal(t, "metadata.sizes.0", (&db.JSONPath{Field: "metadata", Path: []string{"sizes", "0"}}).FieldPath())
+}
+
+func TestCreateJSONPredicatesFromFilterObject(t *testing.T) {
+	sb := createJSONTestSchemaBuilder(t)
+	product := utils.Must(sb.Schema("product"))
+
+	predicates, err := db.CreatePredicatesFromFilterObject(sb, product, `{
+		"metadata.color": "red",
+		"metadata.size": {"$gt": 1, "$lte": 10},
+		"metadata.tags.0": {"$in": ["a", "b"]},
+		"vendor.settings.currency": {"$null": false}
+	}`)
+	require.NoError(t, err)
+	assert.Equal(t, []*db.Predicate{
+		db.EQ("metadata.color", "red"),
+		db.And(db.GT("metadata.size", float64(1)), db.LTE("metadata.size", float64(10))),
+		db.In("metadata.tags.0", []any{"a", "b"}),
+		db.Null("vendor.settings.currency", false),
+	}, predicates)
+
+	_, err = db.CreatePredicatesFromFilterObject(sb, product, `{"metadata.color": {"$invalid": 1}}`)
+	assert.ErrorContains(t, err, "invalid operator $invalid for field metadata.color")
+
+	_, err = db.CreatePredicatesFromFilterObject(sb, product, `{"metadata.color": {"$search": "red"}}`)
+	assert.ErrorContains(t, err, "field metadata.color is not searchable")
+
+	_, err = db.CreatePredicatesFromFilterObject(sb, product, `{"metadata.color": {}}`)
+	assert.ErrorContains(t, err, "invalid field predicates")
+
+	_, err = db.CreatePredicatesFromFilterObject(sb, product, `{"metadata.co lor": "red"}`)
+	assert.ErrorContains(t, err, `filter error: invalid JSON path "metadata.co lor"`)
+}
@@ -8,6 +8,14 @@ import (
 	"github.com/fastschema/fastschema/pkg/errors"
 )
 
+// ErrVersionConflict is returned when updating the records of a schema with optimistic lock
+// and the expected version does not match the current version of the records.
+var ErrVersionConflict = errors.Conflict("the record has been modified by another request, version conflict")
+
+// ErrUpsertTrashed is returned when the record that conflicts with an upsert is in the trash,
+// the record must be restored before it can be upserted.
+var ErrUpsertTrashed = errors.Conflict("the conflicting record is in the trash, restore it before upserting")
+
 /** Mutation related methods **/
 
 // Create creates a new entity and return the newly created entity
@@ -65,6 +73,34 @@ func (m *QueryBuilder[T]) Update(ctx context.Context, updateData any) (ts []T, e
 	return q.Where(m.predicates...).Get(ctx)
 }
 
+// Upsert creates the entity or updates the existing one that has the same conflict column values,
+// then returns the created or updated entity
+func (m *QueryBuilder[T]) Upsert(
+	ctx context.Context,
+	data any,
+	conflictColumns []string,
+	updateColumns []string,
+) (t T, err error) {
+	model, err := m.model()
+	if err != nil {
+		return t, err
+	}
+
+	entityUpsert, err := typesToEntity(data)
+	if err != nil {
+		return t, fmt.Errorf("cannot create entity: %w", err)
+	}
+
+	id, err := model.Mutation().Upsert(ctx, entityUpsert, conflictColumns, updateColumns)
+	if err != nil {
+		return t, err
+	}
+
+	q := Builder[T](m.client, m.schemaName)
+
+	return q.Where(EQ(model.Schema().PrimaryKeyName(), id)).First(ctx)
+}
+
 // Delete deletes entities from the database
 func (m *QueryBuilder[T]) Delete(ctx context.Context) (affected int, err error) {
 	model, err := m.model()
@@ -106,6 +142,18 @@ func Update[T any](
 	return Builder[T](client, schemas...).Where(predicates...).Update(ctx, dataUpdate)
 }
 
+// Upsert is a shortcut to mutation.Upsert
+func Upsert[T any](
+	ctx context.Context,
+	client Client,
+	data any,
+	conflictColumns []string,
+	updateColumns []string,
+	schemas ...string,
+) (T, error) {
+	return Builder[T](client, schemas...).Upsert(ctx, data, conflictColumns, updateColumns)
+}
+
 // Delete is a shortcut to mutation.Delete
 func Delete[T any](
 	ctx context.Context,
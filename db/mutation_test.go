@@ -97,3 +97,47 @@ func TestMutation(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, affected)
 }
+
+func TestUpsert(t *testing.T) {
+	client, ctx := prepareTest()
+
+	// Case 1: Invalid model
+	_, err := db.Upsert[testPost](ctx, client, fs.Map{"title": "tutorial"}, []string{"id"}, nil)
+	assert.ErrorContains(t, err, "model testPost not found")
+
+	// Case 2: Invalid data
+	_, err = db.Upsert[TestCategory](ctx, client, "invalid", []string{"id"}, nil)
+	assert.ErrorContains(t, err, "cannot create entity")
+
+	// Case 3: Conflict column is not unique
+	_, err = db.Upsert[TestCategory](ctx, client, fs.Map{"name": "Tutorial"}, []string{"name"}, nil)
+	assert.ErrorContains(t, err, "must be the primary key or a unique key of category")
+
+	// Case 4: Create
+	category, err := db.Upsert[TestCategory](ctx, client, fs.Map{"id": 10, "name": "Tutorial"}, []string{"id"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), category.ID)
+	assert.Equal(t, "Tutorial", category.Name)
+
+	// Case 5: Update
+	category, err = db.Upsert[TestCategory](ctx, client, fs.Map{"id": 10, "name": "Tutorial 2"}, []string{"id"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), category.ID)
+	assert.Equal(t, "Tutorial 2", category.Name)
+
+	// Case 6: Upsert with entity.Entity
+	cat, err := db.Upsert[*entity.Entity](
+		ctx,
+		client,
+		fs.Map{"id": 10, "name": "Tutorial 3"},
+		[]string{"id"},
+		[]string{"name"},
+		"category",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "Tutorial 3", cat.Get("name"))
+
+	categories, err := db.Builder[TestCategory](client).Get(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, categories, 1)
+}
@@ -25,6 +25,16 @@ const (
 	OpIN
 	OpNIN
 	OpNULL
+	OpSearch
+	OpBetween
+	OpStartsWith
+	OpEndsWith
+	OpRegex
+	OpExists
+	OpSome
+	OpNone
+	OpEvery
+	OpCount
 	endOperatorTypes
 )
 
@@ -46,6 +56,16 @@ var (
 		OpIN:              "$in",
 		OpNIN:             "$nin",
 		OpNULL:            "$null",
+		OpSearch:          "$search",
+		OpBetween:         "$between",
+		OpStartsWith:      "$startswith",
+		OpEndsWith:        "$endswith",
+		OpRegex:           "$regex",
+		OpExists:          "$exists",
+		OpSome:            "$some",
+		OpNone:            "$none",
+		OpEvery:           "$every",
+		OpCount:           "$count",
 	}
 
 	stringToOperatorTypes = map[string]OperatorType{
@@ -65,6 +85,16 @@ var (
 		"$in":              OpIN,
 		"$nin":             OpNIN,
 		"$null":            OpNULL,
+		"$search":          OpSearch,
+		"$between":         OpBetween,
+		"$startswith":      OpStartsWith,
+		"$endswith":        OpEndsWith,
+		"$regex":           OpRegex,
+		"$exists":          OpExists,
+		"$some":            OpSome,
+		"$none":            OpNone,
+		"$every":           OpEvery,
+		"$count":           OpCount,
 	}
 )
 
@@ -81,6 +111,11 @@ func (t OperatorType) Valid() bool {
 	return t > OpInvalid && t < endOperatorTypes
 }
 
+// IsRelationQuantifier reports if the operator filters a relation field by its related records.
+func (t OperatorType) IsRelationQuantifier() bool {
+	return t == OpSome || t == OpNone || t == OpEvery || t == OpCount
+}
+
 // MarshalJSON marshal an enum value to the quoted json string value
 func (t OperatorType) MarshalJSON() ([]byte, error) {
 	buffer := bytes.NewBufferString(`"`)
@@ -227,6 +262,99 @@ func Null(field string, value bool) *Predicate {
 	return &Predicate{Field: field, Operator: OpNULL, Value: value}
 }
 
+// Search creates a full-text search predicate.
+// The field must be a searchable field of the schema, relation fields are not supported.
+// The query is matched using the native full-text search engine of the database:
+// FTS5 for SQLite, tsvector for Postgres and FULLTEXT indexes for MySQL.
+func Search(field string, query string) *Predicate {
+	return &Predicate{Field: field, Operator: OpSearch, Value: query}
+}
+
+// Between creates a range predicate that matches the values from min to max (inclusive).
+// The field can be a simple field name (e.g., "age") or a dot notation path
+// for relation fields (e.g., "teams.rank" where "teams" is the relation field
+// and "rank" is the target field in the related schema).
+func Between(field string, min, max any) *Predicate {
+	return &Predicate{Field: field, Operator: OpBetween, Value: []any{min, max}}
+}
+
+// StartsWith creates a prefix match predicate.
+// Unlike Like, the LIKE wildcards (%, _) in the value are escaped and matched literally.
+// The field can be a simple field name (e.g., "name") or a dot notation path
+// for relation fields (e.g., "teams.slug" where "teams" is the relation field
+// and "slug" is the target field in the related schema).
+func StartsWith(field string, value string) *Predicate {
+	return &Predicate{Field: field, Operator: OpStartsWith, Value: value}
+}
+
+// EndsWith creates a suffix match predicate.
+// Unlike Like, the LIKE wildcards (%, _) in the value are escaped and matched literally.
+// The field can be a simple field name (e.g., "name") or a dot notation path
+// for relation fields (e.g., "teams.slug" where "teams" is the relation field
+// and "slug" is the target field in the related schema).
+func EndsWith(field string, value string) *Predicate {
+	return &Predicate{Field: field, Operator: OpEndsWith, Value: value}
+}
+
+// Regex creates a regular expression match predicate.
+// The pattern is matched using REGEXP for MySQL and SQLite, and the ~ operator for Postgres.
+// The field can be a simple field name (e.g., "name") or a dot notation path
+// for relation fields (e.g., "teams.slug" where "teams" is the relation field
+// and "slug" is the target field in the related schema).
+func Regex(field string, pattern string) *Predicate {
+	return &Predicate{Field: field, Operator: OpRegex, Value: pattern}
+}
+
+// Exists creates a predicate that checks if an optional field has a value.
+// For a path inside a JSON field (e.g., "metadata.color"), it checks if the key exists,
+// even if the value of the key is null.
+// The field can be a simple field name (e.g., "bio") or a dot notation path
+// for relation fields (e.g., "teams.bio" where "teams" is the relation field
+// and "bio" is the target field in the related schema).
+func Exists(field string, value bool) *Predicate {
+	return &Predicate{Field: field, Operator: OpExists, Value: value}
+}
+
+// Some creates a predicate that matches the records having at least one related record
+// of the relation field that matches all the predicates.
+// The predicates are applied to the related schema, e.g.
+//
+//	db.Some("comments", db.EQ("approved", true))
+//
+// Without predicates, it matches the records having any related record.
+func Some(field string, predicates ...*Predicate) *Predicate {
+	return &Predicate{Field: field, Operator: OpSome, Value: predicates}
+}
+
+// None creates a predicate that matches the records having no related record
+// of the relation field that matches all the predicates.
+// Without predicates, it matches the records having no related record, e.g.
+//
+//	db.None("comments")
+func None(field string, predicates ...*Predicate) *Predicate {
+	return &Predicate{Field: field, Operator: OpNone, Value: predicates}
+}
+
+// Every creates a predicate that matches the records whose related records
+// of the relation field all match the predicates, e.g.
+//
+//	db.Every("orders", db.EQ("status", "paid"))
+//
+// Records without related records are matched as well.
+func Every(field string, predicates ...*Predicate) *Predicate {
+	return &Predicate{Field: field, Operator: OpEvery, Value: predicates}
+}
+
+// Count creates a predicate that compares the number of related records of the relation field.
+// The predicates compare the count, their field is ignored, e.g.
+//
+//	db.Count("posts", db.GT("posts", 5))
+//
+// Only the $eq, $neq, $gt, $gte, $lt, $lte, $in, $nin and $between operators are supported.
+func Count(field string, predicates ...*Predicate) *Predicate {
+	return &Predicate{Field: field, Operator: OpCount, Value: predicates}
+}
+
 // IsFalse creates a predicate that checks if a boolean field is false.
 // The field can be a simple field name (e.g., "active") or a dot notation path
 // for relation fields (e.g., "teams.active" where "teams" is the relation field
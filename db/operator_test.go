@@ -441,6 +441,27 @@ func TestNull(t *testing.T) {
 	}
 }
 
+func TestRangeAndPatternOperators(t *testing.T) {
+	assert.Equal(t, &Predicate{Field: "age", Operator: OpBetween, Value: []any{1, 10}}, Between("age", 1, 10))
+	assert.Equal(t, &Predicate{Field: "name", Operator: OpStartsWith, Value: "jo"}, StartsWith("name", "jo"))
+	assert.Equal(t, &Predicate{Field: "name", Operator: OpEndsWith, Value: "hn"}, EndsWith("name", "hn"))
+	assert.Equal(t, &Predicate{Field: "name", Operator: OpRegex, Value: "^j"}, Regex("name", "^j"))
+	assert.Equal(t, &Predicate{Field: "bio", Operator: OpExists, Value: false}, Exists("bio", false))
+}
+
+func TestRelationQuantifiers(t *testing.T) {
+	approved := EQ("approved", true)
+	assert.Equal(t, &Predicate{Field: "comments", Operator: OpSome, Value: []*Predicate{approved}}, Some("comments", approved))
+	assert.Equal(t, &Predicate{Field: "comments", Operator: OpNone, Value: []*Predicate(nil)}, None("comments"))
+	assert.Equal(t, &Predicate{Field: "comments", Operator: OpEvery, Value: []*Predicate{approved}}, Every("comments", approved))
+	assert.Equal(t, &Predicate{Field: "posts", Operator: OpCount, Value: []*Predicate{GT("posts", 5)}}, Count("posts", GT("posts", 5)))
+
+	for op := OpEQ; op < endOperatorTypes; op++ {
+		expected := op == OpSome || op == OpNone || op == OpEvery || op == OpCount
+		assert.Equal(t, expected, op.IsRelationQuantifier(), op.String())
+	}
+}
+
 func TestIsFalse(t *testing.T) {
 	values := map[bool]func(field string) *Predicate{
 		true:  IsTrue,
@@ -3,6 +3,8 @@ package db
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/fastschema/fastschema/entity"
@@ -14,6 +16,7 @@ import (
 // The Field can be a simple field name (e.g., "name") or a dot notation path
 // for relation fields (e.g., "teams.slug" where "teams" is the relation field
 // and "slug" is the target field in the related schema).
+// A dot notation path can also point to a value inside a JSON field (e.g., "metadata.color").
 type Predicate struct {
 	Field    string       `json:"field"`
 	Operator OperatorType `json:"operator"`
@@ -29,6 +32,13 @@ func (p *Predicate) Clone() *Predicate {
 		Value:    p.Value,
 	}
 
+	// The relation quantifiers hold the predicates of the related records
+	if predicates, ok := p.Value.([]*Predicate); ok {
+		cloned.Value = utils.Map(predicates, func(vp *Predicate) *Predicate {
+			return vp.Clone()
+		})
+	}
+
 	if p.And != nil {
 		cloned.And = utils.Map(p.And, func(ap *Predicate) *Predicate {
 			return ap.Clone()
@@ -44,6 +54,23 @@ func (p *Predicate) Clone() *Predicate {
 	return cloned
 }
 
+// PredicateFields returns the fields of the predicates and their nested and/or predicates.
+// The predicates of the relation quantifiers filter the related records and are not included.
+func PredicateFields(predicates []*Predicate) []string {
+	fields := []string{}
+	for _, p := range predicates {
+		if p == nil {
+			continue
+		}
+
+		fields = append(fields, p.Field)
+		fields = append(fields, PredicateFields(p.And)...)
+		fields = append(fields, PredicateFields(p.Or)...)
+	}
+
+	return fields
+}
+
 func filterError(err error) error {
 	return fmt.Errorf("filter error: %w", err)
 }
@@ -171,6 +198,21 @@ func createObjectPredicates(
 	var predicates = make([]*Predicate, 0)
 
 	for pair := filterObject.First(); pair != nil; pair = pair.Next() {
+		if pair.Key == "$search" {
+			query, ok := pair.Value.(string)
+			if !ok {
+				return nil, filterError(errors.New("$search operator must be a string"))
+			}
+
+			searchPredicate, err := SearchFields(s, query)
+			if err != nil {
+				return nil, filterError(err)
+			}
+
+			predicates = append(predicates, searchPredicate)
+			continue
+		}
+
 		if pair.Key == "$or" || pair.Key == "$and" {
 			opEntities, ok := pair.Value.([]*entity.Entity)
 			if !ok {
@@ -201,8 +243,33 @@ func createObjectPredicates(
 			continue
 		}
 
+		// If the field path points to a value inside a JSON field,
+		// the value type is not validated since the JSON value is not typed by the schema.
+		// E.g. "metadata.color": { "$eq": "red" }
+		jsonPath, err := ParseJSONPath(sb, s, pair.Key)
+		if err != nil {
+			return nil, filterError(err)
+		}
+
+		if jsonPath != nil {
+			jsonPredicates, err := createPredicatesFromValue(pair.Key, pair.Value, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(jsonPredicates) == 0 {
+				return nil, errors.New("invalid field predicates")
+			}
+
+			if len(jsonPredicates) > 1 {
+				predicates = append(predicates, And(jsonPredicates...))
+			} else {
+				predicates = append(predicates, jsonPredicates[0])
+			}
+			continue
+		}
+
 		var fieldPredicates []*Predicate
-		var err error
 		var fieldPath string // The full field path (e.g., "teams.slug" or "name")
 
 		// If the field contains ".", it is a relation filter
@@ -251,7 +318,12 @@ func createObjectPredicates(
 
 			// Store the full dot notation path (e.g., "teams.slug")
 			fieldPath = pair.Key
-			if fieldPredicates, err = createFieldPredicate(
+			if lastRelationField.Type.IsRelationType() && hasRelationQuantifier(pair.Value) {
+				// E.g. "owner.posts": { "$none": {} }
+				if fieldPredicates, err = createRelationPredicates(sb, lastRelationField, pair.Value); err != nil {
+					return nil, err
+				}
+			} else if fieldPredicates, err = createFieldPredicate(
 				lastRelationField,
 				pair.Value,
 			); err != nil {
@@ -269,7 +341,7 @@ func createObjectPredicates(
 			// The transformation to target schema's PK happens in createEntPredicates.
 			// E.g. {"country": 1} or {"country": {"$in": [1, 2, 3]}}
 			if f.Type.IsRelationType() {
-				if fieldPredicates, err = createRelationFieldPredicates(f.Name, pair.Value); err != nil {
+				if fieldPredicates, err = createRelationPredicates(sb, f, pair.Value); err != nil {
 					return nil, err
 				}
 			} else {
@@ -309,6 +381,121 @@ func createObjectPredicates(
 	return predicates, nil
 }
 
+// createRelationPredicates creates predicates for a relation field.
+// The relation quantifiers filter the records by their related records, e.g.
+// { "comments": { "$none": {} } } or { "posts": { "$count": { "$gt": 5 } } }
+// The other operators filter the primary key of the related records, see createRelationFieldPredicates.
+func createRelationPredicates(sb *schema.Builder, field *schema.Field, value any) ([]*Predicate, error) {
+	fieldValue, ok := value.(*entity.Entity)
+	if !ok {
+		return createRelationFieldPredicates(field.Name, value)
+	}
+
+	predicates := []*Predicate{}
+	for p := fieldValue.First(); p != nil; p = p.Next() {
+		op := stringToOperatorTypes[p.Key]
+		if !op.IsRelationQuantifier() {
+			operatorPredicates, err := createRelationFieldPredicates(field.Name, entity.New().Set(p.Key, p.Value))
+			if err != nil {
+				return nil, err
+			}
+
+			predicates = append(predicates, operatorPredicates...)
+			continue
+		}
+
+		predicate, err := createRelationQuantifierPredicate(sb, field, op, p.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		predicates = append(predicates, predicate)
+	}
+
+	return predicates, nil
+}
+
+// hasRelationQuantifier reports if the filter value contains a relation quantifier operator.
+func hasRelationQuantifier(value any) bool {
+	fieldValue, ok := value.(*entity.Entity)
+	if !ok {
+		return false
+	}
+
+	for p := fieldValue.First(); p != nil; p = p.Next() {
+		if stringToOperatorTypes[p.Key].IsRelationQuantifier() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// countPredicateOperators are the operators that can compare the number of related records.
+var countPredicateOperators = []OperatorType{OpEQ, OpNEQ, OpGT, OpGTE, OpLT, OpLTE, OpIN, OpNIN, OpBetween}
+
+// createRelationQuantifierPredicate creates a $some, $none, $every or $count predicate for a relation field.
+// The value of $some, $none and $every is a filter object of the related schema.
+// The value of $count is a number or an object of the count comparison operators.
+func createRelationQuantifierPredicate(
+	sb *schema.Builder,
+	field *schema.Field,
+	op OperatorType,
+	value any,
+) (*Predicate, error) {
+	if op == OpCount {
+		countField := &schema.Field{Name: field.Name, Type: schema.TypeUint64}
+		countPredicates, err := createPredicatesFromValue(field.Name, value, countField)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, countPredicate := range countPredicates {
+			if !slices.Contains(countPredicateOperators, countPredicate.Operator) {
+				return nil, filterError(fmt.Errorf(
+					"operator %s is not supported by $count of field %s",
+					countPredicate.Operator,
+					field.Name,
+				))
+			}
+		}
+
+		return Count(field.Name, countPredicates...), nil
+	}
+
+	filterObject, ok := value.(*entity.Entity)
+	if !ok {
+		return nil, filterError(fmt.Errorf("%s operator must be a filter object", op))
+	}
+
+	if sb == nil {
+		return nil, filterError(fmt.Errorf("%s operator requires a schema builder", op))
+	}
+
+	targetSchema, err := sb.Schema(field.Relation.TargetSchemaName)
+	if err != nil {
+		return nil, filterError(fmt.Errorf("invalid relation schema %s: %w", field.Name, err))
+	}
+
+	predicates, err := createObjectPredicates(sb, targetSchema, filterObject)
+	if err != nil {
+		return nil, err
+	}
+
+	if op == OpEvery && len(predicates) == 0 {
+		return nil, filterError(errors.New("$every operator requires at least one predicate"))
+	}
+
+	switch op {
+	case OpSome:
+		return Some(field.Name, predicates...), nil
+	case OpNone:
+		return None(field.Name, predicates...), nil
+	default:
+		return Every(field.Name, predicates...), nil
+	}
+}
+
 // createRelationFieldPredicates creates predicates for a relation field without dot notation.
 // This handles implicit PK filtering like {"country": 1} or {"country": {"$in": [1, 2, 3]}}.
 // Unlike createFieldPredicate, this doesn't validate the value type since the actual
@@ -349,8 +536,16 @@ func createPredicatesFromValue(fieldName string, value any, field *schema.Field)
 				return nil, filterError(fmt.Errorf("invalid operator %s for field %s", p.Key, fieldName))
 			}
 
+			if op.IsRelationQuantifier() {
+				return nil, filterError(fmt.Errorf("operator %s is only supported on relation field, got %s", op, fieldName))
+			}
+
+			if op == OpSearch && (field == nil || !field.Searchable) {
+				return nil, filterError(fmt.Errorf("field %s is not searchable", fieldName))
+			}
+
 			// Validate value type if field is provided (skip for relation fields)
-			if field != nil && op != OpNULL && !field.IsValidValue(p.Value) {
+			if field != nil && op != OpNULL && op != OpExists && !field.IsValidValue(p.Value) {
 				return nil, filterError(fmt.Errorf(
 					"invalid value for field %s.%s (%s) = %v (%T)",
 					fieldName,
@@ -454,6 +649,45 @@ func createOperatorPredicate(fieldName string, op OperatorType, value any) (*Pre
 			return nil, filterError(errors.New("$null operator must be a boolean"))
 		}
 		return Null(fieldName, boolVal), nil
+	case OpSearch:
+		stringVal, ok := value.(string)
+		if !ok {
+			return nil, filterError(errors.New("$search operator must be a string"))
+		}
+		return Search(fieldName, stringVal), nil
+	case OpBetween:
+		arrayVal, ok := value.([]any)
+		if !ok || len(arrayVal) != 2 {
+			return nil, filterError(errors.New("$between operator must be an array of two values"))
+		}
+		return Between(fieldName, arrayVal[0], arrayVal[1]), nil
+	case OpStartsWith:
+		stringVal, ok := value.(string)
+		if !ok {
+			return nil, filterError(errors.New("$startswith operator must be a string"))
+		}
+		return StartsWith(fieldName, stringVal), nil
+	case OpEndsWith:
+		stringVal, ok := value.(string)
+		if !ok {
+			return nil, filterError(errors.New("$endswith operator must be a string"))
+		}
+		return EndsWith(fieldName, stringVal), nil
+	case OpRegex:
+		stringVal, ok := value.(string)
+		if !ok {
+			return nil, filterError(errors.New("$regex operator must be a string"))
+		}
+		if _, err := regexp.Compile(stringVal); err != nil {
+			return nil, filterError(fmt.Errorf("invalid $regex pattern: %w", err))
+		}
+		return Regex(fieldName, stringVal), nil
+	case OpExists:
+		boolVal, ok := value.(bool)
+		if !ok {
+			return nil, filterError(errors.New("$exists operator must be a boolean"))
+		}
+		return Exists(fieldName, boolVal), nil
 	default:
 		return nil, filterError(fmt.Errorf("unsupported operator %s", op))
 	}
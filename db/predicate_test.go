@@ -123,6 +123,12 @@ func TestClonePredicate(t *testing.T) {
 	)
 	p2 := p.Clone()
 	assert.Equal(t, p, p2)
+
+	quantifier := Some("comments", EQ("approved", true))
+	clonedQuantifier := quantifier.Clone()
+	assert.Equal(t, quantifier, clonedQuantifier)
+	clonedQuantifier.Value.([]*Predicate)[0].Value = false
+	assert.Equal(t, true, quantifier.Value.([]*Predicate)[0].Value)
 }
 
 func TestCreateFieldPredicate(t *testing.T) {
@@ -279,6 +285,66 @@ func TestCreateFieldPredicate(t *testing.T) {
 			value:       utils.Must(entity.NewEntityFromJSON(`{"$null": "ok"}`)),
 			expectError: "filter error: $null operator must be a boolean",
 		},
+		{
+			field:        "age",
+			value:        utils.Must(entity.NewEntityFromJSON(`{"$between": [1, 10]}`)),
+			expectResult: []*Predicate{Between("age", float64(1), float64(10))},
+		},
+		{
+			field:       "age",
+			value:       utils.Must(entity.NewEntityFromJSON(`{"$between": [1]}`)),
+			expectError: "filter error: $between operator must be an array of two values",
+		},
+		{
+			field:       "age",
+			value:       utils.Must(entity.NewEntityFromJSON(`{"$between": [1, "ten"]}`)),
+			expectError: "filter error: invalid value for field age.$between (uint) = [1 ten] ([]interface {})",
+		},
+		{
+			field:        "name",
+			value:        utils.Must(entity.NewEntityFromJSON(`{"$startswith": "50%_"}`)),
+			expectResult: []*Predicate{StartsWith("name", "50%_")},
+		},
+		{
+			field:       "name",
+			value:       utils.Must(entity.NewEntityFromJSON(`{"$startswith": ["a"]}`)),
+			expectError: "filter error: $startswith operator must be a string",
+		},
+		{
+			field:        "name",
+			value:        utils.Must(entity.NewEntityFromJSON(`{"$endswith": "son"}`)),
+			expectResult: []*Predicate{EndsWith("name", "son")},
+		},
+		{
+			field:       "name",
+			value:       utils.Must(entity.NewEntityFromJSON(`{"$endswith": ["a"]}`)),
+			expectError: "filter error: $endswith operator must be a string",
+		},
+		{
+			field:        "name",
+			value:        utils.Must(entity.NewEntityFromJSON(`{"$regex": "^jo(hn)?$"}`)),
+			expectResult: []*Predicate{Regex("name", "^jo(hn)?$")},
+		},
+		{
+			field:       "name",
+			value:       utils.Must(entity.NewEntityFromJSON(`{"$regex": ["a"]}`)),
+			expectError: "filter error: $regex operator must be a string",
+		},
+		{
+			field:       "name",
+			value:       utils.Must(entity.NewEntityFromJSON(`{"$regex": "(a"}`)),
+			expectError: "filter error: invalid $regex pattern: error parsing regexp: missing closing ): `(a`",
+		},
+		{
+			field:        "name",
+			value:        utils.Must(entity.NewEntityFromJSON(`{"$exists": true}`)),
+			expectResult: []*Predicate{Exists("name", true)},
+		},
+		{
+			field:       "name",
+			value:       utils.Must(entity.NewEntityFromJSON(`{"$exists": "yes"}`)),
+			expectError: "filter error: $exists operator must be a boolean",
+		},
 		{
 			field:        "age",
 			value:        5,
@@ -674,3 +740,121 @@ func TestCreateRelationFieldPredicates(t *testing.T) {
 		assert.Equal(t, OpIN, result[0].Operator)
 	})
 }
+
+func TestCreateRelationQuantifierPredicates(t *testing.T) {
+	b := &schema.Builder{}
+
+	modelSchema := &schema.Schema{}
+	assert.Nil(t, json.Unmarshal([]byte(modelSchemaJSON), modelSchema))
+	assert.NoError(t, modelSchema.Init(false))
+	b.AddSchema(modelSchema)
+
+	carSchema := &schema.Schema{}
+	assert.Nil(t, json.Unmarshal([]byte(carSchemaJSON), carSchema))
+	assert.NoError(t, carSchema.Init(false))
+	b.AddSchema(carSchema)
+
+	userSchema := &schema.Schema{}
+	assert.Nil(t, json.Unmarshal([]byte(userSchemaJSON), userSchema))
+	assert.NoError(t, userSchema.Init(false))
+	b.AddSchema(userSchema)
+
+	assert.NoError(t, b.Init())
+
+	tests := []struct {
+		name         string
+		filter       string
+		expectResult []*Predicate
+		expectError  string
+	}{
+		{
+			name:         "none",
+			filter:       `{"cars": {"$none": {}}}`,
+			expectResult: []*Predicate{None("cars", []*Predicate{}...)},
+		},
+		{
+			name:   "some with operators",
+			filter: `{"cars": {"$some": {"name": {"$like": "a%"}}, "$in": [1, 2]}}`,
+			expectResult: []*Predicate{And(
+				Some("cars", Like("name", "a%")),
+				In("cars", []any{float64(1), float64(2)}),
+			)},
+		},
+		{
+			name:         "every",
+			filter:       `{"cars": {"$every": {"name": "a", "model.name": "b"}}}`,
+			expectResult: []*Predicate{Every("cars", EQ("name", "a"), EQ("model.name", "b"))},
+		},
+		{
+			name:         "count",
+			filter:       `{"cars": {"$count": {"$gt": 2, "$between": [3, 4]}}}`,
+			expectResult: []*Predicate{Count("cars", GT("cars", float64(2)), Between("cars", float64(3), float64(4)))},
+		},
+		{
+			name:         "count value",
+			filter:       `{"cars": {"$count": 2}}`,
+			expectResult: []*Predicate{Count("cars", EQ("cars", float64(2)))},
+		},
+		{
+			name:         "nested relation",
+			filter:       `{"cars.model": {"$none": {"name": "a"}}}`,
+			expectResult: []*Predicate{None("cars.model", EQ("name", "a"))},
+		},
+		{
+			name:        "count invalid value",
+			filter:      `{"cars": {"$count": {"$gt": "one"}}}`,
+			expectError: "filter error: invalid value for field cars.$gt (uint64) = one (string)",
+		},
+		{
+			name:        "count unsupported operator",
+			filter:      `{"cars": {"$count": {"$null": true}}}`,
+			expectError: "filter error: operator $null is not supported by $count of field cars",
+		},
+		{
+			name:        "count like operator",
+			filter:      `{"cars": {"$count": {"$like": "1"}}}`,
+			expectError: "filter error: operator $like is not supported by $count of field cars",
+		},
+		{
+			name:        "count negative",
+			filter:      `{"cars": {"$count": -1}}`,
+			expectError: "filter error: invalid value for field cars (uint64) = -1 (float64)",
+		},
+		{
+			name:        "some invalid value",
+			filter:      `{"cars": {"$some": 1}}`,
+			expectError: "filter error: $some operator must be a filter object",
+		},
+		{
+			name:        "every without predicates",
+			filter:      `{"cars": {"$every": {}}}`,
+			expectError: "filter error: $every operator requires at least one predicate",
+		},
+		{
+			name:        "invalid target filter",
+			filter:      `{"cars": {"$some": {"invalid": 1}}}`,
+			expectError: "field 'invalid' is not defined in schema 'car'",
+		},
+		{
+			name:        "not relation field",
+			filter:      `{"name": {"$some": {}}}`,
+			expectError: "filter error: operator $some is only supported on relation field, got name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CreatePredicatesFromFilterObject(b, userSchema, tt.filter)
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectResult, result)
+		})
+	}
+
+	_, err := CreatePredicatesFromFilterObject(nil, userSchema, `{"cars": {"$some": {}}}`)
+	assert.EqualError(t, err, "filter error: $some operator requires a schema builder")
+}
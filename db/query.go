@@ -4,12 +4,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"reflect"
 
 	"github.com/fastschema/fastschema/entity"
 	"github.com/mitchellh/mapstructure"
 )
 
+type rawReadContextKey struct{}
+
+// WithRawRead returns a context that reads the records as they are stored:
+// the query hooks, the field getters and the cache are skipped, the tenant and the row filter are still applied.
+// It is used by the mutations and their hooks to compare the records before and after a write.
+//
+//	originals, err := model.Query(db.EQ("id", 1)).Get(db.WithRawRead(ctx))
+func WithRawRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rawReadContextKey{}, true)
+}
+
+// UseRawRead reports whether the queries of the context read the records as they are stored.
+func UseRawRead(ctx context.Context) bool {
+	rawRead, _ := ctx.Value(rawReadContextKey{}).(bool)
+	return rawRead
+}
+
 /** Query related methods **/
 
 // Limit sets the limit of the query.
@@ -30,6 +48,18 @@ func (q *QueryBuilder[T]) Order(order ...string) *QueryBuilder[T] {
 	return q
 }
 
+// After sets the cursor to return the entities after, using keyset pagination.
+func (q *QueryBuilder[T]) After(cursor string) *QueryBuilder[T] {
+	q.after = cursor
+	return q
+}
+
+// Before sets the cursor to return the entities before, using keyset pagination.
+func (q *QueryBuilder[T]) Before(cursor string) *QueryBuilder[T] {
+	q.before = cursor
+	return q
+}
+
 // Select sets the columns of the query.
 func (q *QueryBuilder[T]) Select(fields ...string) *QueryBuilder[T] {
 	q.fields = append(q.fields, fields...)
@@ -51,6 +81,25 @@ func (q *QueryBuilder[T]) Count(ctx context.Context, options ...*QueryOption) (i
 	return count, nil
 }
 
+// query creates the querier of the model with the builder options.
+func (q *QueryBuilder[T]) query(model Model) Querier {
+	query := model.Query(q.predicates...).
+		Limit(q.limit).
+		Offset(q.offset).
+		Order(q.order...).
+		Select(q.fields...)
+
+	if q.after != "" {
+		query = query.After(q.after)
+	}
+
+	if q.before != "" {
+		query = query.Before(q.before)
+	}
+
+	return query
+}
+
 // Get returns the list of entities that match the query.
 func (q *QueryBuilder[T]) Get(ctx context.Context) ([]T, error) {
 	model, err := q.model()
@@ -58,32 +107,15 @@ func (q *QueryBuilder[T]) Get(ctx context.Context) ([]T, error) {
 		return nil, err
 	}
 
-	entities, err := model.Query(q.predicates...).
-		Limit(q.limit).
-		Offset(q.offset).
-		Order(q.order...).
-		Select(q.fields...).
-		Get(ctx)
+	entities, err := q.query(model).Get(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var t T
-	_, tIsEntity := any(t).(*entity.Entity)
-
 	result := make([]T, 0)
 	for _, e := range entities {
-		if tIsEntity {
-			if converted, ok := any(e).(T); ok {
-				result = append(result, converted)
-			} else {
-				return nil, fmt.Errorf("failed to convert entity to type %T", t)
-			}
-			continue
-		}
-
-		var record T
-		if err := BindStruct(e, &record); err != nil {
+		record, err := convertEntity[T](e)
+		if err != nil {
 			return nil, err
 		}
 
@@ -93,6 +125,65 @@ func (q *QueryBuilder[T]) Get(ctx context.Context) ([]T, error) {
 	return result, nil
 }
 
+// errStopIteration stops the streaming of the entities when the iteration is stopped.
+var errStopIteration = errors.New("iteration stopped")
+
+// Iter returns an iterator over the entities that match the query.
+// The entities are streamed from the database instead of being loaded in memory at once (see Querier.Each).
+// If an error occurs, it is yielded with the zero value of T and the iteration stops.
+//
+//	for post, err := range db.Builder[Post](client).Iter(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//	}
+func (q *QueryBuilder[T]) Iter(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		model, err := q.model()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		err = q.query(model).Each(ctx, func(e *entity.Entity) error {
+			record, err := convertEntity[T](e)
+			if err != nil {
+				return err
+			}
+
+			if !yield(record, nil) {
+				return errStopIteration
+			}
+
+			return nil
+		})
+
+		if err != nil && !errors.Is(err, errStopIteration) {
+			yield(zero, err)
+		}
+	}
+}
+
+// convertEntity converts the entity to the type T of the builder.
+func convertEntity[T any](e *entity.Entity) (T, error) {
+	var record T
+	if _, tIsEntity := any(record).(*entity.Entity); tIsEntity {
+		converted, ok := any(e).(T)
+		if !ok {
+			return record, fmt.Errorf("failed to convert entity to type %T", record)
+		}
+
+		return converted, nil
+	}
+
+	if err := BindStruct(e, &record); err != nil {
+		return record, err
+	}
+
+	return record, nil
+}
+
 // First returns the first entity that matches the query.
 func (q *QueryBuilder[T]) First(ctx context.Context) (t T, err error) {
 	q.Limit(1)
@@ -121,6 +121,55 @@ func TestQuery(t *testing.T) {
 	assert.Equal(t, "category 1", cats[0].Get("name"))
 }
 
+func TestQueryIter(t *testing.T) {
+	client, ctx := prepareTest()
+
+	for i := 1; i <= 5; i++ {
+		_, err := db.Create[TestCategory](ctx, client, fs.Map{
+			"name": fmt.Sprintf("category %d", i),
+		})
+		assert.NoError(t, err)
+	}
+
+	// Case 1: Invalid model.
+	for _, err := range db.Builder[testPost](client).Iter(ctx) {
+		assert.Error(t, err)
+	}
+
+	// Case 2: Query error.
+	iterations := 0
+	for category, err := range db.Builder[TestCategory](client).
+		Where(db.EQ("invalid_column", 1)).
+		Iter(ctx) {
+		iterations++
+		assert.Error(t, err)
+		assert.Equal(t, TestCategory{}, category)
+	}
+	assert.Equal(t, 1, iterations)
+
+	// Case 3: Iterate all matched entities.
+	names := []string{}
+	for category, err := range db.Builder[TestCategory](client).
+		Where(db.GTE("id", 2)).
+		Order("-id").
+		Iter(ctx) {
+		assert.NoError(t, err)
+		names = append(names, category.Name)
+	}
+	assert.Equal(t, []string{"category 5", "category 4", "category 3", "category 2"}, names)
+
+	// Case 4: Break the iteration.
+	names = []string{}
+	for category, err := range db.Builder[*entity.Entity](client, "category").Order("id").Iter(ctx) {
+		assert.NoError(t, err)
+		names = append(names, category.GetString("name"))
+		if len(names) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"category 1", "category 2"}, names)
+}
+
 type TestData struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
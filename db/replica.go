@@ -0,0 +1,75 @@
+package db
+
+import "context"
+
+// ReplicaConfig is the connection config of a read replica.
+// The empty fields default to the value of the primary database config.
+type ReplicaConfig struct {
+	Name string `json:"name,omitempty"`
+	Host string `json:"host,omitempty"`
+	Port string `json:"port,omitempty"`
+	User string `json:"user,omitempty"`
+	Pass string `json:"pass,omitempty"`
+}
+
+// Clone returns a copy of the replica config.
+func (r *ReplicaConfig) Clone() *ReplicaConfig {
+	if r == nil {
+		return nil
+	}
+
+	clone := *r
+	return &clone
+}
+
+// Config returns the database config of the replica based on the primary config.
+func (r *ReplicaConfig) Config(primary *Config) *Config {
+	config := primary.Clone()
+	config.Replicas = nil
+	if r.Name != "" {
+		config.Name = r.Name
+	}
+	if r.Host != "" {
+		config.Host = r.Host
+	}
+	if r.Port != "" {
+		config.Port = r.Port
+	}
+	if r.User != "" {
+		config.User = r.User
+	}
+	if r.Pass != "" {
+		config.Pass = r.Pass
+	}
+
+	return config
+}
+
+func cloneReplicaConfigs(replicas []*ReplicaConfig) []*ReplicaConfig {
+	if replicas == nil {
+		return nil
+	}
+
+	clones := make([]*ReplicaConfig, len(replicas))
+	for i, replica := range replicas {
+		clones[i] = replica.Clone()
+	}
+
+	return clones
+}
+
+type primaryContextKey struct{}
+
+// WithPrimary returns a context that routes the queries to the primary database instead of the replicas.
+// It is used to read the data right after a write, before it is replicated.
+//
+//	posts, err := model.Query().Get(db.WithPrimary(ctx))
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+// UsePrimary reports whether the queries of the context must be routed to the primary database.
+func UsePrimary(ctx context.Context) bool {
+	usePrimary, _ := ctx.Value(primaryContextKey{}).(bool)
+	return usePrimary
+}
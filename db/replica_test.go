@@ -0,0 +1,54 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplicaConfig(t *testing.T) {
+	primary := &db.Config{
+		Driver:     "pgx",
+		Name:       "app",
+		Host:       "primary",
+		Port:       "5432",
+		User:       "user",
+		Pass:       "pass",
+		LogQueries: true,
+		Replicas:   []*db.ReplicaConfig{{Host: "replica1"}, {Host: "replica2", Port: "5433", User: "reader", Pass: "secret", Name: "app_replica"}},
+	}
+
+	// The empty fields default to the primary config
+	config := primary.Replicas[0].Config(primary)
+	assert.Equal(t, "pgx", config.Driver)
+	assert.Equal(t, "app", config.Name)
+	assert.Equal(t, "replica1", config.Host)
+	assert.Equal(t, "5432", config.Port)
+	assert.Equal(t, "user", config.User)
+	assert.Equal(t, "pass", config.Pass)
+	assert.True(t, config.LogQueries)
+	assert.Nil(t, config.Replicas)
+
+	config = primary.Replicas[1].Config(primary)
+	assert.Equal(t, "app_replica", config.Name)
+	assert.Equal(t, "replica2", config.Host)
+	assert.Equal(t, "5433", config.Port)
+	assert.Equal(t, "reader", config.User)
+	assert.Equal(t, "secret", config.Pass)
+
+	// The replicas are deep copied
+	clone := primary.Clone()
+	assert.Equal(t, primary.Replicas, clone.Replicas)
+	clone.Replicas[0].Host = "changed"
+	assert.Equal(t, "replica1", primary.Replicas[0].Host)
+	assert.Nil(t, (&db.Config{}).Clone().Replicas)
+	assert.Nil(t, (*db.ReplicaConfig)(nil).Clone())
+}
+
+func TestWithPrimary(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, db.UsePrimary(ctx))
+	assert.True(t, db.UsePrimary(db.WithPrimary(ctx)))
+}
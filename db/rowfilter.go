@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+
+	"github.com/fastschema/fastschema/pkg/errors"
+)
+
+// RowFilterKey is the key of the row filter of a request in the context values.
+//
+//	The request contexts expose their locals as context values,
+//	so that the row filter of a request is set with:
+//		c.Local(db.RowFilterKey, &db.RowFilter{...})
+//	Other contexts set the row filter with WithRowFilter.
+const RowFilterKey = "row_filter"
+
+// RowFilter restricts the records of a schema that a context can access.
+// The predicates are added to all the queries, updates and deletes of the schema.
+type RowFilter struct {
+	Schema     string
+	Predicates []*Predicate
+}
+
+// ErrRowFilterMismatch is returned when a written record does not match the row filter of the context.
+var ErrRowFilterMismatch = errors.Forbidden("the record does not match the row filter")
+
+type rowFilterContextKey struct{}
+
+// WithRowFilter returns a context that restricts the records of the filter schema to the filter predicates.
+//
+//	posts, err := model.Query().Get(db.WithRowFilter(ctx, &db.RowFilter{
+//		Schema:     "post",
+//		Predicates: []*db.Predicate{db.EQ("owner_id", userID)},
+//	}))
+func WithRowFilter(ctx context.Context, filter *RowFilter) context.Context {
+	return context.WithValue(ctx, rowFilterContextKey{}, filter)
+}
+
+// RowFilterFromContext returns the row filter predicates of the given schema,
+// or nil if the context does not restrict the records of the schema.
+func RowFilterFromContext(ctx context.Context, schemaName string) []*Predicate {
+	filter, ok := ctx.Value(rowFilterContextKey{}).(*RowFilter)
+	if !ok {
+		filter, _ = ctx.Value(RowFilterKey).(*RowFilter)
+	}
+
+	if filter == nil || filter.Schema != schemaName {
+		return nil
+	}
+
+	return filter.Predicates
+}
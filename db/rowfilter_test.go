@@ -0,0 +1,24 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowFilterContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, db.RowFilterFromContext(ctx, "post"))
+
+	predicates := []*db.Predicate{db.EQ("owner_id", 1)}
+	local := context.WithValue(ctx, db.RowFilterKey, &db.RowFilter{Schema: "post", Predicates: predicates})
+	assert.Equal(t, predicates, db.RowFilterFromContext(local, "post"))
+	assert.Nil(t, db.RowFilterFromContext(local, "comment"))
+
+	override := []*db.Predicate{db.EQ("owner_id", 2)}
+	filtered := db.WithRowFilter(local, &db.RowFilter{Schema: "comment", Predicates: override})
+	assert.Equal(t, override, db.RowFilterFromContext(filtered, "comment"))
+	assert.Nil(t, db.RowFilterFromContext(filtered, "post"))
+}
@@ -0,0 +1,33 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+)
+
+// SearchRelevance is the order column that sorts the records by their full-text search relevance.
+// The relevance is computed from the $search predicates of the query, e.g.
+//
+//	Query(db.Search("title", "hello")).Order("-_relevance")
+const SearchRelevance = "_relevance"
+
+// SearchFields creates a predicate that matches the records
+// having any of the searchable fields of the schema matching the query.
+func SearchFields(s *schema.Schema, query string) (*Predicate, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.New("search query is required")
+	}
+
+	fields := s.SearchableFields()
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("schema %s has no searchable fields", s.Name)
+	}
+
+	return Or(utils.Map(fields, func(f *schema.Field) *Predicate {
+		return Search(f.Name, query)
+	})...), nil
+}
@@ -0,0 +1,68 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createSearchTestSchema(t *testing.T) *schema.Schema {
+	s := &schema.Schema{
+		Name:           "article",
+		Namespace:      "articles",
+		LabelFieldName: "title",
+		Fields: []*schema.Field{
+			{Name: "title", Type: schema.TypeString, Searchable: true},
+			{Name: "body", Type: schema.TypeText, Searchable: true},
+			{Name: "slug", Type: schema.TypeString},
+		},
+	}
+	require.NoError(t, s.Init(false))
+	return s
+}
+
+func TestSearchFields(t *testing.T) {
+	s := createSearchTestSchema(t)
+
+	_, err := db.SearchFields(s, " ")
+	assert.ErrorContains(t, err, "search query is required")
+
+	predicate, err := db.SearchFields(s, "hello")
+	assert.NoError(t, err)
+	assert.Equal(t, db.Or(db.Search("title", "hello"), db.Search("body", "hello")), predicate)
+
+	s.Field("title").Searchable = false
+	s.Field("body").Searchable = false
+	_, err = db.SearchFields(s, "hello")
+	assert.ErrorContains(t, err, "schema article has no searchable fields")
+}
+
+func TestCreateSearchPredicatesFromFilterObject(t *testing.T) {
+	s := createSearchTestSchema(t)
+
+	predicates, err := db.CreatePredicatesFromFilterObject(nil, s, `{"$search": "hello world", "slug": "a"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []*db.Predicate{
+		db.Or(db.Search("title", "hello world"), db.Search("body", "hello world")),
+		db.EQ("slug", "a"),
+	}, predicates)
+
+	predicates, err = db.CreatePredicatesFromFilterObject(nil, s, `{"title": {"$search": "hello"}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []*db.Predicate{db.Search("title", "hello")}, predicates)
+
+	_, err = db.CreatePredicatesFromFilterObject(nil, s, `{"$search": 1}`)
+	assert.ErrorContains(t, err, "$search operator must be a string")
+
+	_, err = db.CreatePredicatesFromFilterObject(nil, s, `{"$search": ""}`)
+	assert.ErrorContains(t, err, "search query is required")
+
+	_, err = db.CreatePredicatesFromFilterObject(nil, s, `{"title": {"$search": 1}}`)
+	assert.Error(t, err)
+
+	_, err = db.CreatePredicatesFromFilterObject(nil, s, `{"slug": {"$search": "hello"}}`)
+	assert.ErrorContains(t, err, "field slug is not searchable")
+}
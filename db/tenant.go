@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+
+	"github.com/fastschema/fastschema/pkg/errors"
+)
+
+// TenantKey is the key of the tenant of a request in the context values.
+//
+//	The request contexts expose their locals as context values,
+//	so that the tenant of a request is set with:
+//		c.Local(db.TenantKey, tenantID)
+//	Other contexts set the tenant with WithTenant.
+const TenantKey = "tenant"
+
+// ErrTenantRequired is returned when the records of a tenant schema are accessed without a tenant.
+var ErrTenantRequired = errors.Forbidden("tenant is required to access the records of a tenant schema")
+
+type tenantContextKey struct{}
+
+type allTenantsContextKey struct{}
+
+// WithTenant returns a context that scopes the records of the tenant schemas to the given tenant.
+//
+//	posts, err := model.Query().Get(db.WithTenant(ctx, "acme"))
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// WithAllTenants returns a context that accesses the records of all the tenants.
+// It is used by the system tasks that are not run for a tenant, such as migrations and backups.
+// The created records of the tenant schemas must have their tenant set.
+func WithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allTenantsContextKey{}, true)
+}
+
+// TenantFromContext returns the tenant of the context, or an empty string if there is none.
+func TenantFromContext(ctx context.Context) string {
+	if tenantID, ok := ctx.Value(tenantContextKey{}).(string); ok {
+		return tenantID
+	}
+
+	tenantID, _ := ctx.Value(TenantKey).(string)
+	return tenantID
+}
+
+// UseAllTenants reports whether the context accesses the records of all the tenants.
+func UseAllTenants(ctx context.Context) bool {
+	allTenants, _ := ctx.Value(allTenantsContextKey{}).(bool)
+	return allTenants
+}
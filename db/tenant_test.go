@@ -0,0 +1,25 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "", db.TenantFromContext(ctx))
+	assert.False(t, db.UseAllTenants(ctx))
+
+	assert.Equal(t, "acme", db.TenantFromContext(context.WithValue(ctx, db.TenantKey, "acme")))
+	assert.Equal(t, "globex", db.TenantFromContext(db.WithTenant(
+		context.WithValue(ctx, db.TenantKey, "acme"),
+		"globex",
+	)))
+
+	allTenants := db.WithAllTenants(ctx)
+	assert.True(t, db.UseAllTenants(allTenants))
+	assert.Equal(t, "", db.TenantFromContext(allTenants))
+}
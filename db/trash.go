@@ -0,0 +1,21 @@
+package db
+
+import (
+	"context"
+)
+
+type forceDeleteContextKey struct{}
+
+// WithForceDelete returns a context that permanently deletes the records, even if soft deletes are enabled.
+// It is used to purge the records from the trash.
+//
+//	affected, err := model.Mutation().Where(db.EQ("id", 1)).Delete(db.WithForceDelete(ctx))
+func WithForceDelete(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceDeleteContextKey{}, true)
+}
+
+// UseForceDelete reports whether the deletes of the context permanently delete the records.
+func UseForceDelete(ctx context.Context) bool {
+	forceDelete, _ := ctx.Value(forceDeleteContextKey{}).(bool)
+	return forceDelete
+}
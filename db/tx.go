@@ -7,6 +7,11 @@ import (
 	"github.com/fastschema/fastschema/pkg/errors"
 )
 
+// WithTx runs fn in a transaction, the transaction is committed if fn succeeds and rolled back otherwise.
+//
+//	If the client is a transaction, fn runs in a nested transaction so that
+//	its failure only rolls back the changes made by fn, the outer transaction can continue.
+//	If fn panics, the transaction is rolled back and the panic is re-raised.
 func WithTx(client Client, c context.Context, fn func(tx Client) error) (err error) {
 	var tx Client
 
@@ -15,6 +20,11 @@ func WithTx(client Client, c context.Context, fn func(tx Client) error) (err err
 	}
 
 	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+
 		if err != nil {
 			if e := tx.Rollback(); e != nil {
 				err = fmt.Errorf("error while rolling back transaction: %w, original error: %w", e, err)
@@ -30,3 +40,45 @@ func WithTx(client Client, c context.Context, fn func(tx Client) error) (err err
 
 	return
 }
+
+type txContextKey struct{}
+
+// txCommitter is implemented by the transaction clients that can run callbacks after they are committed.
+type txCommitter interface {
+	OnCommit(fn func())
+}
+
+// ContextWithTx returns a context that carries the transaction client.
+// The mutations set the transaction of their client to the context of their hooks,
+// so that the hooks can write in the same transaction:
+//
+//	client := db.TxFromContext(ctx, s.DB())
+func ContextWithTx(ctx context.Context, tx Client) context.Context {
+	if tx == nil || !tx.IsTx() {
+		return ctx
+	}
+
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// TxFromContext returns the transaction client of the context,
+// or the given client if the context does not carry a transaction.
+func TxFromContext(ctx context.Context, client Client) Client {
+	if tx, ok := ctx.Value(txContextKey{}).(Client); ok && tx != nil {
+		return tx
+	}
+
+	return client
+}
+
+// AfterCommit runs fn after the transaction of the context is committed.
+// fn is discarded if the transaction is rolled back,
+// it runs immediately if the context does not carry a transaction.
+func AfterCommit(ctx context.Context, fn func()) {
+	if tx, ok := ctx.Value(txContextKey{}).(txCommitter); ok {
+		tx.OnCommit(fn)
+		return
+	}
+
+	fn()
+}
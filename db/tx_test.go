@@ -5,6 +5,7 @@ import (
 
 	"github.com/fastschema/fastschema/db"
 	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -90,3 +91,84 @@ func TestWithTxReturnErrorRollbackTwice(t *testing.T) {
 	})
 	assert.Error(t, err)
 }
+
+func TestWithTxNested(t *testing.T) {
+	client, ctx := prepareTest()
+	categoryNames := func(client db.Client) []string {
+		categories := utils.Must(db.Builder[TestCategory](client).Order("id").Get(ctx))
+		return utils.Map(categories, func(c TestCategory) string { return c.Name })
+	}
+
+	err := db.WithTx(client, ctx, func(tx db.Client) error {
+		_ = utils.Must(db.Create[TestCategory](ctx, tx, fs.Map{"name": "category 1"}))
+
+		// The failed nested transaction only rolls back its own changes
+		err := db.WithTx(tx, ctx, func(nestedTx db.Client) error {
+			_ = utils.Must(db.Create[TestCategory](ctx, nestedTx, fs.Map{"name": "category 2"}))
+			return assert.AnError
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, []string{"category 1"}, categoryNames(tx))
+
+		// The committed nested transaction is committed with the outer transaction
+		assert.NoError(t, db.WithTx(tx, ctx, func(nestedTx db.Client) error {
+			_, err := db.Create[TestCategory](ctx, nestedTx, fs.Map{"name": "category 3"})
+			return err
+		}))
+
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"category 1", "category 3"}, categoryNames(client))
+
+	// The outer transaction rolls back the committed nested transactions
+	err = db.WithTx(client, ctx, func(tx db.Client) error {
+		assert.NoError(t, db.WithTx(tx, ctx, func(nestedTx db.Client) error {
+			_, err := db.Create[TestCategory](ctx, nestedTx, fs.Map{"name": "category 4"})
+			return err
+		}))
+
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, []string{"category 1", "category 3"}, categoryNames(client))
+}
+
+func TestWithTxPanic(t *testing.T) {
+	client, ctx := prepareTest()
+
+	assert.PanicsWithValue(t, "panic in tx", func() {
+		_ = db.WithTx(client, ctx, func(tx db.Client) error {
+			_ = utils.Must(db.Create[TestCategory](ctx, tx, fs.Map{"name": "category 1"}))
+			panic("panic in tx")
+		})
+	})
+
+	// The panic of a nested transaction only rolls back the nested transaction if it is recovered
+	err := db.WithTx(client, ctx, func(tx db.Client) error {
+		_ = utils.Must(db.Create[TestCategory](ctx, tx, fs.Map{"name": "category 2"}))
+		assert.Panics(t, func() {
+			_ = db.WithTx(tx, ctx, func(nestedTx db.Client) error {
+				_ = utils.Must(db.Create[TestCategory](ctx, nestedTx, fs.Map{"name": "category 3"}))
+				panic("panic in nested tx")
+			})
+		})
+
+		return nil
+	})
+	assert.NoError(t, err)
+
+	categories := utils.Must(db.Builder[TestCategory](client).Get(ctx))
+	assert.Len(t, categories, 1)
+	assert.Equal(t, "category 2", categories[0].Name)
+}
+
+func TestAfterCommitWithoutTx(t *testing.T) {
+	client, ctx := prepareTest()
+	assert.Same(t, client, db.TxFromContext(ctx, client))
+	assert.Equal(t, ctx, db.ContextWithTx(ctx, client))
+
+	called := false
+	db.AfterCommit(ctx, func() { called = true })
+	assert.True(t, called)
+}
@@ -11,6 +11,8 @@ const FieldID = "id"
 const FieldCreatedAt = "created_at"
 const FieldUpdatedAt = "updated_at"
 const FieldDeletedAt = "deleted_at"
+const FieldVersion = "version"
+const FieldTenantID = "tenant_id"
 
 type Entity struct {
 	data    *orderedmap.OrderedMap[string, any]
@@ -154,6 +156,48 @@ func (e *Entity) Delete(name string) *Entity {
 	return e
 }
 
+// Clone returns a deep copy of the entity.
+// The nested entities, maps and slices are copied, the other values are shared.
+func (e *Entity) Clone() *Entity {
+	clone := New()
+	clone.idField = e.idField
+	for pair := e.data.Oldest(); pair != nil; pair = pair.Next() {
+		clone.data.Set(pair.Key, cloneValue(pair.Value))
+	}
+
+	return clone
+}
+
+func cloneValue(value any) any {
+	switch v := value.(type) {
+	case *Entity:
+		if v == nil {
+			return v
+		}
+		return v.Clone()
+	case []*Entity:
+		entities := make([]*Entity, len(v))
+		for i, e := range v {
+			entities[i] = cloneValue(e).(*Entity)
+		}
+		return entities
+	case map[string]any:
+		values := make(map[string]any, len(v))
+		for key, value := range v {
+			values[key] = cloneValue(value)
+		}
+		return values
+	case []any:
+		values := make([]any, len(v))
+		for i, value := range v {
+			values[i] = cloneValue(value)
+		}
+		return values
+	default:
+		return value
+	}
+}
+
 // GetString returns a string value from the entity.
 func (e *Entity) GetString(name string, defaultValues ...string) string {
 	if value, present := e.data.Get(name); present {
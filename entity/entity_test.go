@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/buger/jsonparser"
@@ -106,6 +107,12 @@ func TestEntity(t *testing.T) {
 	assert.Equal(t, float64(4), entity3.ID())
 	assert.Equal(t, "John", entity3.Get("name"))
 
+	entities := []*Entity{}
+	assert.NoError(t, json.Unmarshal([]byte(`[{"id":1,"name":"John"},{"name":"Jane"}]`), &entities))
+	assert.Len(t, entities, 2)
+	assert.Equal(t, float64(1), entities[0].ID())
+	assert.Equal(t, "Jane", entities[1].Get("name"))
+
 	value, err := UnmarshalJSONValue(nil, []byte("name"), jsonparser.String, 0)
 	assert.NoError(t, err)
 	assert.Equal(t, "name", value)
@@ -926,3 +933,27 @@ func TestGetStringNonStringValue(t *testing.T) {
 	result = entity.GetString("count", "default")
 	assert.Equal(t, "default", result)
 }
+
+func TestEntityClone(t *testing.T) {
+	tag := New(2).Set("name", "tag")
+	e := NewWithIDField("key", 1).
+		Set("name", "entity").
+		Set("author", New(3)).
+		Set("tags", []*Entity{tag}).
+		Set("metadata", map[string]any{"colors": []any{"red"}}).
+		Set("empty", (*Entity)(nil))
+
+	clone := e.Clone()
+	assert.Equal(t, e, clone)
+	assert.Equal(t, "key", clone.GetIDField())
+
+	clone.Set("name", "clone")
+	clone.Get("author").(*Entity).Set("name", "author")
+	clone.Get("tags").([]*Entity)[0].Set("name", "clone tag")
+	clone.Get("metadata").(map[string]any)["colors"].([]any)[0] = "blue"
+
+	assert.Equal(t, "entity", e.Get("name"))
+	assert.Nil(t, e.Get("author").(*Entity).Get("name"))
+	assert.Equal(t, "tag", tag.Get("name"))
+	assert.Equal(t, map[string]any{"colors": []any{"red"}}, e.Get("metadata"))
+}
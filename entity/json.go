@@ -14,6 +14,11 @@ func (e *Entity) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
 func (e *Entity) UnmarshalJSON(data []byte) (err error) {
+	// The zero value entity is created when decoding into a slice of entities
+	if e.data == nil {
+		*e = *New()
+	}
+
 	return jsonparser.ObjectEach(
 		data,
 		func(keyData []byte, valueData []byte, dataType jsonparser.ValueType, offset int) error {
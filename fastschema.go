@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/fastschema/fastschema/db"
 	"github.com/fastschema/fastschema/entity"
@@ -21,6 +22,9 @@ import (
 var (
 	_       fs.App = (*App)(nil)
 	Version string = "0.0.0"
+
+	// trashPurgeInterval is the interval between the purges of the expired records in the trash.
+	trashPurgeInterval = time.Hour
 )
 
 type App struct {
@@ -54,6 +58,7 @@ type App struct {
 	openAPISpec         []byte
 	authProviders       map[string]fs.AuthProvider
 	jwtCustomClaimsFunc fs.JwtCustomClaimsFunc
+	stopTrashPurge      chan struct{}
 }
 
 func New(config *fs.Config) (_ *App, err error) {
@@ -76,6 +81,8 @@ func New(config *fs.Config) (_ *App, err error) {
 		return nil, err
 	}
 
+	a.startTrashPurge()
+
 	return a, nil
 }
 
@@ -341,6 +348,9 @@ func (a *App) UpdateCache(ctx context.Context) (err error) {
 		}
 	}
 
+	// The field permissions of the roles are documented in the openapi spec
+	a.openAPISpec = nil
+
 	return nil
 }
 
@@ -369,6 +379,7 @@ func (a *App) applyPermissionOverrides() {
 					// Override existing permission
 					targetRole.Permissions[i].Value = permConfig.Value
 					targetRole.Permissions[i].Modifier = permConfig.Modifier
+					targetRole.Permissions[i].Fields = permConfig.Fields
 					overridden = true
 					break
 				}
@@ -380,6 +391,7 @@ func (a *App) applyPermissionOverrides() {
 					Resource: permConfig.Resource,
 					Value:    permConfig.Value,
 					Modifier: permConfig.Modifier,
+					Fields:   permConfig.Fields,
 				})
 			}
 		}
@@ -395,6 +407,7 @@ func (a *App) CreateOpenAPISpec(overrides ...bool) ([]byte, error) {
 			BaseURL:       a.config.BaseURL,
 			Resources:     a.Resources(),
 			SchemaBuilder: a.schemaBuilder,
+			Roles:         a.roles,
 		})
 
 		if err != nil {
@@ -458,7 +471,48 @@ func (a *App) HTTPAdaptor() (http.HandlerFunc, error) {
 	return a.restResolver.HTTPAdaptor()
 }
 
+// startTrashPurge periodically purges the records that are in the trash for longer than the trash retention.
+func (a *App) startTrashPurge() {
+	dbConfig := a.config.DBConfig
+	if dbConfig == nil || !dbConfig.UseSoftDeletes || dbConfig.TrashRetentionDays <= 0 {
+		return
+	}
+
+	purge := func() {
+		before := time.Now().AddDate(0, 0, -dbConfig.TrashRetentionDays)
+		purged, err := a.services.Content().PurgeTrash(context.Background(), before)
+		if err != nil {
+			a.Logger().Errorf("purge trash: %v", err)
+			return
+		}
+
+		if purged > 0 {
+			a.Logger().Infof("purged %d records from the trash", purged)
+		}
+	}
+
+	a.stopTrashPurge = make(chan struct{})
+	go func(stop chan struct{}) {
+		purge()
+		ticker := time.NewTicker(trashPurgeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				purge()
+			}
+		}
+	}(a.stopTrashPurge)
+}
+
 func (a *App) Shutdown() error {
+	if a.stopTrashPurge != nil {
+		close(a.stopTrashPurge)
+		a.stopTrashPurge = nil
+	}
+
 	if a.DB() != nil {
 		if err := a.DB().Close(); err != nil {
 			return err
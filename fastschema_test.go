@@ -609,11 +609,12 @@ func TestFastschemaResources(t *testing.T) {
 	hooks := a.Hooks()
 	assert.NotNil(t, hooks)
 	assert.NotNil(t, hooks.DBHooks)
-	// All db hooks expected 2 includes: the default one and the one we added in the test
+	// All db hooks expected 2 includes: the default one and the one we added in the test,
+	// the mutation hooks also include the audit hooks
 	assert.Len(t, hooks.DBHooks.PostDBQuery, 2)
-	assert.Len(t, hooks.DBHooks.PostDBCreate, 2)
-	assert.Len(t, hooks.DBHooks.PostDBUpdate, 2)
-	assert.Len(t, hooks.DBHooks.PostDBDelete, 2)
+	assert.Len(t, hooks.DBHooks.PostDBCreate, 3)
+	assert.Len(t, hooks.DBHooks.PostDBUpdate, 4)
+	assert.Len(t, hooks.DBHooks.PostDBDelete, 4)
 
 	a.AddResource(fs.NewResource("test", func(c fs.Context, _ any) (any, error) {
 		return "test", nil
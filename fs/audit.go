@@ -0,0 +1,65 @@
+package fs
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// AuditMaskedValue replaces the values of the masked fields in the audit logs
+const AuditMaskedValue = "******"
+
+// AuditChange holds the value of a field before and after a change
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditLog is the schema for storing the changes of the records of the audited schemas.
+// The creation time is set by the audit service so that the logs can be filtered and sorted by it.
+type AuditLog struct {
+	_         any                     `json:"-" fs:"namespace=audit_logs;label_field=action;disable_timestamp"`
+	ID        uuid.UUID               `json:"id,omitempty" fs:"type=uuid"`
+	Action    string                  `json:"action,omitempty" fs:"size=20;filterable;sortable"`
+	Schema    string                  `json:"schema,omitempty" fs:"filterable;sortable"`
+	RecordID  string                  `json:"record_id,omitempty" fs:"filterable"`
+	UserID    *uuid.UUID              `json:"user_id,omitempty" fs:"type=uuid;optional;filterable"`
+	Username  string                  `json:"username,omitempty" fs:"optional;filterable"`
+	TraceID   string                  `json:"trace_id,omitempty" fs:"optional;filterable"`
+	Changes   map[string]*AuditChange `json:"changes,omitempty" fs:"type=json;optional"`
+	CreatedAt *time.Time              `json:"created_at,omitempty" fs:"optional;filterable;sortable"`
+}
+
+// AuditConfig holds the audit log configuration
+//
+//	The changes of the schemas that have the audit setting enabled are recorded in the audit logs.
+type AuditConfig struct {
+	MaskFields []string `json:"mask_fields"` // default: password
+}
+
+// Clone creates a deep copy of AuditConfig
+func (ac *AuditConfig) Clone() *AuditConfig {
+	if ac == nil {
+		return nil
+	}
+
+	return &AuditConfig{
+		MaskFields: slices.Clone(ac.MaskFields),
+	}
+}
+
+// GetMaskFields returns the fields that are masked in the audit logs with default value
+func (ac *AuditConfig) GetMaskFields() []string {
+	if ac == nil || len(ac.MaskFields) == 0 {
+		return []string{"password"}
+	}
+
+	return ac.MaskFields
+}
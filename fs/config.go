@@ -21,6 +21,8 @@ type Config struct {
 	StorageConfig          *StorageConfig                `json:"storage_config"`
 	AuthConfig             *AuthConfig                   `json:"auth_config"`
 	MailConfig             *MailConfig                   `json:"mail_config"`
+	TenantConfig           *TenantConfig                 `json:"tenant_config"` // If not set, the tenant of the requests is not resolved
+	AuditConfig            *AuditConfig                  `json:"audit_config"`
 	RolePermissionSettings *RolePermissionSettingsConfig `json:"role_permission_settings"`
 	SystemSchemas          []any                         `json:"-"` // types to build the system schemas
 	Hooks                  *Hooks                        `json:"-"`
@@ -61,6 +63,14 @@ func (ac *Config) Clone() *Config {
 		c.MailConfig = ac.MailConfig.Clone()
 	}
 
+	if ac.TenantConfig != nil {
+		c.TenantConfig = ac.TenantConfig.Clone()
+	}
+
+	if ac.AuditConfig != nil {
+		c.AuditConfig = ac.AuditConfig.Clone()
+	}
+
 	if ac.LoggerConfig != nil {
 		c.LoggerConfig = ac.LoggerConfig.Clone()
 	}
@@ -3,6 +3,7 @@ package fs
 import (
 	"bytes"
 	"context"
+	"io"
 	"net/http"
 
 	"github.com/fastschema/fastschema/entity"
@@ -46,6 +47,9 @@ type HTTPResponse struct {
 	Header     http.Header
 	File       string
 	Stream     *bytes.Buffer
+	// BodyWriter writes the response body after the handler has returned,
+	// so that large bodies are sent to the client without being buffered in memory.
+	BodyWriter func(w io.Writer) error
 }
 
 // Result is a struct that contains the result of a resolver
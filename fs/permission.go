@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,11 +22,18 @@ type Permission struct {
 	Resource    string                           `json:"resource,omitempty"`
 	Value       string                           `json:"value,omitempty"`
 	Modifier    string                           `json:"modifier,omitempty" fs:"type=json;optional"`
+	Fields      string                           `json:"fields,omitempty" fs:"type=json;optional"`
 	Role        *Role                            `json:"role,omitempty" fs.relation:"{'type':'o2m','schema':'role','field':'permissions','owner':false,'source_column':'role_id'}"`
 	CreatedAt   *time.Time                       `json:"created_at,omitempty"`
 	UpdatedAt   *time.Time                       `json:"updated_at,omitempty"`
 	DeletedAt   *time.Time                       `json:"deleted_at,omitempty"`
 	RuleProgram *expr.Program[*Permission, bool] `json:"-"` // The compiled rule program
+
+	// modifierFilter is the compiled modifier filter template.
+	// Its leaves are either literal values or compiled expression programs.
+	modifierFilter map[string]any
+	// fields is the parsed fields access of the permission.
+	fields *PermissionFields
 }
 
 func (p *Permission) IsAllowed() bool {
@@ -36,14 +44,31 @@ func (p *Permission) IsDenied() bool {
 	return p.Value == "" || p.Value == PermissionTypeDeny.String()
 }
 
+// HasModifier reports whether the permission restricts the rows it grants access to.
+//
+//	Only the JSON filter objects restrict the rows. The modifiers of the previous format,
+//	such as "$user.ID == $entity.author_id", were never enforced, they are kept as is and ignored.
+func (p *Permission) HasModifier() bool {
+	modifier := strings.TrimSpace(p.Modifier)
+	return strings.HasPrefix(modifier, "{") && modifier != "{}"
+}
+
 func (p *Permission) Compile() (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.compileModifier(); err != nil {
+		return err
+	}
+
+	if err := p.compileFields(); err != nil {
+		return err
+	}
+
 	if p.Value == "" || p.Value == "allow" || p.Value == "deny" {
 		return nil
 	}
 
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
 	if p.RuleProgram, err = expr.Compile[*Permission, bool](p.Value); err != nil {
 		return err
 	}
@@ -51,6 +76,122 @@ func (p *Permission) Compile() (err error) {
 	return nil
 }
 
+// compileModifier parses the modifier filter template.
+// The modifier is a JSON filter object, string values that start with "$" are
+// compiled as expressions and evaluated against the request context by Filter:
+//
+//	{"owner_id": "$context.User().ID"}
+func (p *Permission) compileModifier() error {
+	p.modifierFilter = nil
+	if !p.HasModifier() {
+		return nil
+	}
+
+	filter := map[string]any{}
+	if err := json.Unmarshal([]byte(p.Modifier), &filter); err != nil {
+		return fmt.Errorf("invalid permission modifier, a JSON filter object is expected: %w", err)
+	}
+
+	compiled, err := compileModifierValue(filter)
+	if err != nil {
+		return err
+	}
+
+	p.modifierFilter = compiled.(map[string]any)
+	return nil
+}
+
+func compileModifierValue(value any) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		compiled := make(map[string]any, len(v))
+		for key, item := range v {
+			compiledItem, err := compileModifierValue(item)
+			if err != nil {
+				return nil, err
+			}
+			compiled[key] = compiledItem
+		}
+		return compiled, nil
+	case []any:
+		compiled := make([]any, len(v))
+		for i, item := range v {
+			compiledItem, err := compileModifierValue(item)
+			if err != nil {
+				return nil, err
+			}
+			compiled[i] = compiledItem
+		}
+		return compiled, nil
+	case string:
+		if !strings.HasPrefix(v, "$") {
+			return v, nil
+		}
+		program, err := expr.Compile[*Permission, any](v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid permission modifier expression %q: %w", v, err)
+		}
+		return program, nil
+	default:
+		return v, nil
+	}
+}
+
+// Filter evaluates the modifier filter template against the given context.
+// It returns nil if the permission has no modifier.
+func (p *Permission) Filter(c context.Context, config expr.Config) (map[string]any, error) {
+	if !p.HasModifier() {
+		return nil, nil
+	}
+
+	if p.modifierFilter == nil {
+		return nil, errors.InternalServerError("permission modifier is not compiled for permission: %s", p.Resource)
+	}
+
+	filter, err := p.runModifierValue(c, config, p.modifierFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter.(map[string]any), nil
+}
+
+func (p *Permission) runModifierValue(c context.Context, config expr.Config, value any) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		filter := make(map[string]any, len(v))
+		for key, item := range v {
+			result, err := p.runModifierValue(c, config, item)
+			if err != nil {
+				return nil, err
+			}
+			filter[key] = result
+		}
+		return filter, nil
+	case []any:
+		filter := make([]any, len(v))
+		for i, item := range v {
+			result, err := p.runModifierValue(c, config, item)
+			if err != nil {
+				return nil, err
+			}
+			filter[i] = result
+		}
+		return filter, nil
+	case *expr.Program[*Permission, any]:
+		result, err := v.Run(c, p, config)
+		if err != nil {
+			return nil, fmt.Errorf("error running permission modifier: %w", err)
+		}
+		if result.IsUndefined() {
+			return nil, nil
+		}
+		return result.Raw(), nil
+	default:
+		return v, nil
+	}
+}
+
 func (p *Permission) Check(c context.Context, config expr.Config) error {
 	if p.IsAllowed() {
 		return nil
@@ -0,0 +1,197 @@
+package fs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/schema"
+)
+
+// FieldPermissionsKey is the key of the field permissions of a request in the context values.
+//
+//	The field permissions are set by the authorization of the content resources:
+//		c.Local(fs.FieldPermissionsKey, &fs.FieldPermissions{...})
+const FieldPermissionsKey = "field_permissions"
+
+// PermissionFields contains the fields that a permission grants access to.
+//
+//	Each list contains the field names, "*" for all the fields or "-name" to exclude a field:
+//		{"read": ["*", "-internal_notes"], "write": ["title", "body"]}
+//	A missing list grants access to all the fields.
+type PermissionFields struct {
+	Read  []string `json:"read,omitempty"`
+	Write []string `json:"write,omitempty"`
+}
+
+// fieldAllowed reports whether the field is allowed by the fields list.
+func fieldAllowed(fields []string, name string) bool {
+	if fields == nil {
+		return true
+	}
+
+	if slices.Contains(fields, "-"+name) {
+		return false
+	}
+
+	return slices.Contains(fields, "*") || slices.Contains(fields, name)
+}
+
+// HasFields reports whether the permission restricts the fields it grants access to.
+func (p *Permission) HasFields() bool {
+	fields := strings.TrimSpace(p.Fields)
+	return fields != "" && fields != "null" && fields != "{}"
+}
+
+// compileFields parses the fields access of the permission.
+func (p *Permission) compileFields() error {
+	p.fields = nil
+	if !p.HasFields() {
+		return nil
+	}
+
+	fields := &PermissionFields{}
+	if err := json.Unmarshal([]byte(p.Fields), fields); err != nil {
+		return fmt.Errorf("invalid permission fields, a JSON object with read and write lists is expected: %w", err)
+	}
+
+	p.fields = fields
+	return nil
+}
+
+// CanReadField reports whether the permission grants read access to the field.
+// The fields of a permission that is not compiled are not readable.
+func (p *Permission) CanReadField(name string) bool {
+	if !p.HasFields() {
+		return true
+	}
+
+	return p.fields != nil && fieldAllowed(p.fields.Read, name)
+}
+
+// CanWriteField reports whether the permission grants write access to the field.
+// The fields of a permission that is not compiled are not writable.
+func (p *Permission) CanWriteField(name string) bool {
+	if !p.HasFields() {
+		return true
+	}
+
+	return p.fields != nil && fieldAllowed(p.fields.Write, name)
+}
+
+// FieldPermissions contains the permissions that allowed a request on a content schema.
+// A field is accessible if any of the permissions grants access to it.
+type FieldPermissions struct {
+	Schema      string
+	Permissions []*Permission
+}
+
+// CanRead reports whether the field is readable.
+func (f *FieldPermissions) CanRead(name string) bool {
+	return slices.ContainsFunc(f.Permissions, func(p *Permission) bool {
+		return p.CanReadField(name)
+	})
+}
+
+// CanWrite reports whether the field is writable.
+func (f *FieldPermissions) CanWrite(name string) bool {
+	return slices.ContainsFunc(f.Permissions, func(p *Permission) bool {
+		return p.CanWriteField(name)
+	})
+}
+
+// Strip removes the fields that are not readable from the entities of the schema.
+// The primary key and the fields managed by the system are always kept.
+func (f *FieldPermissions) Strip(s *schema.Schema, entities ...*entity.Entity) {
+	for _, e := range entities {
+		for _, key := range e.Keys() {
+			if !f.readable(s, key) {
+				e.Delete(key)
+			}
+		}
+	}
+}
+
+// UnreadableField returns the first of the fields of the schema that is not readable, or an empty string.
+// The paths of the relation and JSON fields are checked by their first part: "tags.name" checks "tags".
+// The primary key, the fields managed by the system and the names that are not fields of the schema
+// such as the aggregation aliases are not checked.
+func (f *FieldPermissions) UnreadableField(s *schema.Schema, fields ...string) string {
+	if f == nil || s == nil {
+		return ""
+	}
+
+	for _, field := range fields {
+		name, _, _ := strings.Cut(field, ".")
+		if !f.readable(s, name) {
+			return name
+		}
+	}
+
+	return ""
+}
+
+func (f *FieldPermissions) readable(s *schema.Schema, name string) bool {
+	if name == s.PrimaryKeyName() || slices.Contains(systemFields, name) || s.Field(name) == nil {
+		return true
+	}
+
+	return f.CanRead(name)
+}
+
+// UnwritableField returns the first field of the payload that is not writable, or an empty string.
+// The update blocks contain the fields as keys: {"$set": {"title": "..."}}.
+// The fields managed by the system (version, deleted_at and tenant_id) are not checked.
+func (f *FieldPermissions) UnwritableField(data *entity.Entity) string {
+	if f == nil || data == nil {
+		return ""
+	}
+
+	for pair := data.First(); pair != nil; pair = pair.Next() {
+		fields := []string{pair.Key}
+		if strings.HasPrefix(pair.Key, "$") {
+			fields = blockFields(pair.Value)
+		}
+
+		for _, field := range fields {
+			if !slices.Contains(systemFields, field) && !f.CanWrite(field) {
+				return field
+			}
+		}
+	}
+
+	return ""
+}
+
+// systemFields are the fields that are managed by the system and not restricted by the field permissions.
+var systemFields = []string{entity.FieldVersion, entity.FieldDeletedAt, entity.FieldTenantID}
+
+func blockFields(block any) []string {
+	switch v := block.(type) {
+	case *entity.Entity:
+		return v.Keys()
+	case map[string]any:
+		fields := make([]string, 0, len(v))
+		for field := range v {
+			fields = append(fields, field)
+		}
+		slices.Sort(fields)
+		return fields
+	default:
+		return nil
+	}
+}
+
+// FieldPermissionsFromContext returns the field permissions of the given schema,
+// or nil if the context does not restrict the fields of the schema.
+func FieldPermissionsFromContext(ctx context.Context, schemaName string) *FieldPermissions {
+	fieldPermissions, _ := ctx.Value(FieldPermissionsKey).(*FieldPermissions)
+	if fieldPermissions == nil || fieldPermissions.Schema != schemaName {
+		return nil
+	}
+
+	return fieldPermissions
+}
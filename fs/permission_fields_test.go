@@ -0,0 +1,126 @@
+package fs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   string
+		readable []string
+		hidden   []string
+		writable []string
+		readonly []string
+	}{
+		{
+			name:     "no fields",
+			fields:   "",
+			readable: []string{"title", "featured"},
+			writable: []string{"title", "featured"},
+		},
+		{
+			name:     "empty object",
+			fields:   "{}",
+			readable: []string{"title", "featured"},
+			writable: []string{"title", "featured"},
+		},
+		{
+			name:     "exclusions",
+			fields:   `{"read": ["*", "-internal_notes"], "write": ["title", "body"]}`,
+			readable: []string{"title", "featured"},
+			hidden:   []string{"internal_notes"},
+			writable: []string{"title", "body"},
+			readonly: []string{"featured", "internal_notes"},
+		},
+		{
+			name:     "write only list",
+			fields:   `{"write": []}`,
+			readable: []string{"title", "featured"},
+			readonly: []string{"title", "featured"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &fs.Permission{Value: "allow", Fields: tt.fields}
+			require.NoError(t, p.Compile())
+			assert.Equal(t, tt.hidden != nil || tt.readonly != nil, p.HasFields())
+			for _, field := range tt.readable {
+				assert.True(t, p.CanReadField(field), field)
+			}
+			for _, field := range tt.hidden {
+				assert.False(t, p.CanReadField(field), field)
+			}
+			for _, field := range tt.writable {
+				assert.True(t, p.CanWriteField(field), field)
+			}
+			for _, field := range tt.readonly {
+				assert.False(t, p.CanWriteField(field), field)
+			}
+		})
+	}
+
+	// The fields of a permission that is not compiled are not accessible
+	p := &fs.Permission{Value: "allow", Fields: `{"read": ["*"]}`}
+	assert.False(t, p.CanReadField("title"))
+	assert.False(t, p.CanWriteField("title"))
+}
+
+func TestFieldPermissions(t *testing.T) {
+	editor := &fs.Permission{Value: "allow", Fields: `{"read": ["title"], "write": ["title"]}`}
+	reviewer := &fs.Permission{Value: "allow", Fields: `{"read": ["title", "notes"], "write": []}`}
+	require.NoError(t, editor.Compile())
+	require.NoError(t, reviewer.Compile())
+
+	fieldPermissions := &fs.FieldPermissions{
+		Schema:      "blog",
+		Permissions: []*fs.Permission{editor, reviewer},
+	}
+	assert.True(t, fieldPermissions.CanRead("title"))
+	assert.True(t, fieldPermissions.CanRead("notes"))
+	assert.False(t, fieldPermissions.CanRead("featured"))
+	assert.True(t, fieldPermissions.CanWrite("title"))
+	assert.False(t, fieldPermissions.CanWrite("notes"))
+
+	blogSchema := &schema.Schema{Name: "blog", Fields: []*schema.Field{
+		{Name: "id", Type: schema.TypeUint64, IsSystemField: true, Unique: true},
+		{Name: "title", Type: schema.TypeString},
+		{Name: "notes", Type: schema.TypeString},
+		{Name: "featured", Type: schema.TypeBool},
+		{Name: "metadata", Type: schema.TypeJSON},
+	}}
+	blog := entity.New(1).Set("title", "Hello").Set("notes", "draft").Set("featured", true).Set("version", 1)
+	fieldPermissions.Strip(blogSchema, blog)
+	assert.Equal(t, []string{"id", "title", "notes", "version"}, blog.Keys())
+
+	// The paths are checked by their first part, the names that are not fields are not checked
+	assert.Equal(t, "", fieldPermissions.UnreadableField(blogSchema, "id", "title", "notes", "deleted_at", "count"))
+	assert.Equal(t, "featured", fieldPermissions.UnreadableField(blogSchema, "title", "featured"))
+	assert.Equal(t, "metadata", fieldPermissions.UnreadableField(blogSchema, "metadata.color"))
+	assert.Equal(t, "", (*fs.FieldPermissions)(nil).UnreadableField(blogSchema, "featured"))
+
+	// The system fields are not checked, the update blocks are checked by their keys
+	assert.Equal(t, "", fieldPermissions.UnwritableField(entity.New().
+		Set("title", "Hello").
+		Set(entity.FieldVersion, 1).
+		Set(entity.FieldDeletedAt, nil).
+		Set(entity.FieldTenantID, 1).
+		Set("$set", entity.New().Set("title", "Hello"))))
+	assert.Equal(t, "notes", fieldPermissions.UnwritableField(entity.New().Set("title", "Hello").Set("notes", "")))
+	assert.Equal(t, "notes", fieldPermissions.UnwritableField(entity.New().Set("$expr", map[string]any{"notes": "title"})))
+	assert.Equal(t, "featured", fieldPermissions.UnwritableField(entity.New().Set("$clear", entity.New().Set("featured", true))))
+	assert.Equal(t, "", (*fs.FieldPermissions)(nil).UnwritableField(entity.New().Set("notes", "")))
+
+	ctx := context.WithValue(context.Background(), fs.FieldPermissionsKey, fieldPermissions)
+	assert.Equal(t, fieldPermissions, fs.FieldPermissionsFromContext(ctx, "blog"))
+	assert.Nil(t, fs.FieldPermissionsFromContext(ctx, "post"))
+	assert.Nil(t, fs.FieldPermissionsFromContext(context.Background(), "blog"))
+}
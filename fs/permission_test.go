@@ -336,6 +336,46 @@ func TestPermissionCompile(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "ValidModifier",
+			permission: fs.Permission{
+				Value:    "allow",
+				Modifier: `{"owner_id": "$context.Value('user_id')", "$or": [{"status": "published"}, {"views": {"$gt": 10}}]}`,
+			},
+			expectError: false,
+		},
+		{
+			name: "LegacyModifier",
+			permission: fs.Permission{
+				Value:    "allow",
+				Modifier: "$user.ID == $entity.author_id",
+			},
+			expectError: false,
+		},
+		{
+			name: "InvalidModifierJSON",
+			permission: fs.Permission{
+				Value:    "allow",
+				Modifier: `{"owner_id": `,
+			},
+			expectError: true,
+		},
+		{
+			name: "InvalidFields",
+			permission: fs.Permission{
+				Value:  "allow",
+				Fields: `["title"]`,
+			},
+			expectError: true,
+		},
+		{
+			name: "InvalidModifierExpression",
+			permission: fs.Permission{
+				Value:    "allow",
+				Modifier: `{"owner_id": "$context.Value("}`,
+			},
+			expectError: true,
+		},
 	}
 
 	for i := range tests {
@@ -408,3 +448,60 @@ func TestCheck(t *testing.T) {
 		assert.NoError(t, p.Check(ctx, expr.Config{}))
 	}
 }
+
+func TestPermissionFilter(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "user_id", 5)
+
+	// Permission without modifier
+	{
+		p := &fs.Permission{Value: "allow", Modifier: "{}"}
+		assert.False(t, p.HasModifier())
+		filter, err := p.Filter(ctx, expr.Config{})
+		assert.NoError(t, err)
+		assert.Nil(t, filter)
+	}
+
+	// Legacy modifier is not enforced
+	{
+		p := &fs.Permission{Value: "allow", Modifier: "$user.ID == $entity.author_id"}
+		assert.NoError(t, p.Compile())
+		assert.False(t, p.HasModifier())
+		filter, err := p.Filter(ctx, expr.Config{})
+		assert.NoError(t, err)
+		assert.Nil(t, filter)
+	}
+
+	// Modifier is not compiled
+	{
+		p := &fs.Permission{Value: "allow", Modifier: `{"owner_id": 1}`}
+		_, err := p.Filter(ctx, expr.Config{})
+		assert.Error(t, err)
+	}
+
+	// Modifier run error
+	{
+		p := &fs.Permission{Value: "allow", Modifier: `{"owner_id": "$context.Get('invalid')"}`}
+		assert.NoError(t, p.Compile())
+		_, err := p.Filter(ctx, expr.Config{})
+		assert.Error(t, err)
+	}
+
+	// Modifier is evaluated
+	{
+		p := &fs.Permission{
+			Value:    "allow",
+			Modifier: `{"owner_id": "$context.Value('user_id')", "$or": [{"status": "published"}, {"views": {"$gt": "$context.Value('user_id') * 2"}}]}`,
+		}
+		assert.NoError(t, p.Compile())
+		assert.True(t, p.HasModifier())
+		filter, err := p.Filter(ctx, expr.Config{})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"owner_id": 5,
+			"$or": []any{
+				map[string]any{"status": "published"},
+				map[string]any{"views": map[string]any{"$gt": 10}},
+			},
+		}, filter)
+	}
+}
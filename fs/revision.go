@@ -0,0 +1,39 @@
+package fs
+
+import (
+	"time"
+
+	"github.com/fastschema/fastschema/schema"
+	"github.com/google/uuid"
+)
+
+// Revision is the schema for storing the previous versions of the records of the schemas with revisions.
+//
+//	A revision is created with the data of the record before each update and delete,
+//	the revisions of a record are numbered from 1.
+type Revision struct {
+	_          any            `json:"-" fs:"namespace=revisions;label_field=number;disable_timestamp"`
+	ID         uuid.UUID      `json:"id,omitempty" fs:"type=uuid"`
+	SchemaName string         `json:"schema,omitempty" fs:"filterable"`
+	RecordID   string         `json:"record_id,omitempty" fs:"filterable"`
+	Number     uint64         `json:"number,omitempty" fs:"filterable;sortable"`
+	Action     string         `json:"action,omitempty" fs:"size=20;filterable"` // the action that replaced the revision: update or delete
+	TenantID   string         `json:"tenant_id,omitempty" fs:"optional;filterable"`
+	Data       map[string]any `json:"data,omitempty" fs:"type=json;optional"`
+	CreatedAt  *time.Time     `json:"created_at,omitempty" fs:"optional;filterable;sortable"`
+}
+
+func (r Revision) Schema() *schema.Schema {
+	return &schema.Schema{
+		Fields: []*schema.Field{},
+		DB: &schema.SchemaDB{
+			Indexes: []*schema.SchemaDBIndex{
+				{
+					Name:    "idx_revision_schema_record",
+					Unique:  true,
+					Columns: []string{"schema", "record_id", "number"},
+				},
+			},
+		},
+	}
+}
@@ -59,6 +59,7 @@ func (c *RolePermissionSettingsConfig) Clone() *RolePermissionSettingsConfig {
 				Resource: perm.Resource,
 				Value:    perm.Value,
 				Modifier: perm.Modifier,
+				Fields:   perm.Fields,
 			}
 		}
 		clone.Roles[i] = clonedRole
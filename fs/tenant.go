@@ -0,0 +1,39 @@
+package fs
+
+// TenantConfig holds the configuration to resolve the tenant of the requests.
+//
+//	The user is bound to the tenants of the JWT claim of the access token,
+//	the claim is either a tenant or the list of the tenants that the user is a member of.
+//	The tenant is requested by the first source that is set:
+//		- The request header, if Header is set.
+//		- The subdomain of the request host, if Subdomain is set.
+//	The requests for a tenant that the user is not bound to are rejected.
+//	Without a requested tenant, the tenant of the claim is used if it is a single tenant.
+//	The records of the tenant schemas are scoped to the resolved tenant.
+type TenantConfig struct {
+	Claim     string `json:"claim"`     // default: tenant_id, a tenant or a list of tenants
+	Header    string `json:"header"`    // for example: X-Tenant-ID
+	Subdomain string `json:"subdomain"` // the base domain, for example: example.com resolves acme.example.com to acme
+}
+
+// Clone creates a deep copy of TenantConfig
+func (tc *TenantConfig) Clone() *TenantConfig {
+	if tc == nil {
+		return nil
+	}
+
+	return &TenantConfig{
+		Claim:     tc.Claim,
+		Header:    tc.Header,
+		Subdomain: tc.Subdomain,
+	}
+}
+
+// GetClaim returns the JWT claim of the tenant with default value
+func (tc *TenantConfig) GetClaim() string {
+	if tc == nil || tc.Claim == "" {
+		return "tenant_id"
+	}
+
+	return tc.Claim
+}
@@ -33,6 +33,8 @@ var SystemSchemaTypes = []any{
 	File{},
 	Session{},
 	Migration{},
+	AuditLog{},
+	Revision{},
 }
 
 type Arg struct {
@@ -217,6 +217,24 @@ func (a *App) prepareConfig() (err error) {
 		}
 	}
 
+	// Parse TENANT from environment variable, the tenant of the requests is not resolved if it is not set
+	if a.config.TenantConfig == nil {
+		if envValue := utils.Env("TENANT"); envValue != "" {
+			if err := json.Unmarshal([]byte(envValue), &a.config.TenantConfig); err != nil {
+				return fmt.Errorf("failed to parse TENANT: %w", err)
+			}
+		}
+	}
+
+	// Parse AUDIT from environment variable, the password fields are masked if it is not set
+	if a.config.AuditConfig == nil {
+		if envValue := utils.Env("AUDIT"); envValue != "" {
+			if err := json.Unmarshal([]byte(envValue), &a.config.AuditConfig); err != nil {
+				return fmt.Errorf("failed to parse AUDIT: %w", err)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -495,6 +513,20 @@ func (a *App) createDBClient() (err error) {
 			LogQueries:         utils.Env("DB_LOGGING", "false") == "true",
 			DisableForeignKeys: utils.Env("DB_DISABLE_FOREIGN_KEYS", "false") == "true",
 			UseSoftDeletes:     utils.Env("DB_USE_SOFT_DELETES", "false") == "true",
+			TrashRetentionDays: utils.EnvInt("DB_TRASH_RETENTION_DAYS", 0),
+			SlowQueryThreshold: utils.EnvInt("DB_SLOW_QUERY_THRESHOLD", 0),
+		}
+
+		// The query cache holds at most DB_CACHE_SIZE query results, it is disabled by default
+		if cacheSize := utils.EnvInt("DB_CACHE_SIZE", 0); cacheSize > 0 {
+			a.config.DBConfig.Cache = db.NewLRUCache(cacheSize)
+		}
+
+		if replicas := utils.Env("DB_REPLICAS"); replicas != "" {
+			if err := json.Unmarshal([]byte(replicas), &a.config.DBConfig.Replicas); err != nil {
+				return fmt.Errorf("invalid DB_REPLICAS: %w", err)
+			}
+			a.config.DBConfig.ReplicaHealthCheck = utils.EnvInt("DB_REPLICA_HEALTH_CHECK", 10)
 		}
 	}
 
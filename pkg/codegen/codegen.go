@@ -0,0 +1,145 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/fastschema/fastschema/schema"
+)
+
+// Header is the first line of the generated files.
+// The files that start with the header are replaced or removed when the code is generated again.
+const Header = "// Code generated by fastschema. DO NOT EDIT."
+
+// initialisms are the words that are written in upper case in the generated names.
+var initialisms = []string{"id", "ids", "url", "uri", "uuid", "api", "http", "https", "html", "json", "sql", "ip", "ui", "xml"}
+
+// Schemas returns the schemas that code is generated for, sorted by name.
+// The system schemas have their own types and the junction schemas are not accessed directly.
+func Schemas(sb *schema.Builder) []*schema.Schema {
+	schemas := []*schema.Schema{}
+	for _, s := range sb.Schemas() {
+		if s.IsSystemSchema || s.IsJunctionSchema {
+			continue
+		}
+
+		schemas = append(schemas, s)
+	}
+
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Name < schemas[j].Name
+	})
+
+	return schemas
+}
+
+// PascalName converts a snake case name to a pascal case name, for example: "author_id" -> "AuthorID".
+func PascalName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.' || r == ' '
+	}) {
+		if slices.Contains(initialisms, strings.ToLower(part)) {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return b.String()
+}
+
+// WriteFiles writes the generated files to the directory.
+//
+//	The previously generated files of the directory that are not generated anymore are removed,
+//	so that the directory matches the schemas after the schemas are renamed or deleted.
+//	The files that are not generated are kept.
+func WriteFiles(dir string, files map[string][]byte) error {
+	if err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := files[filepath.ToSlash(relPath)]; ok {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if bytes.HasPrefix(content, []byte(Header)) {
+			return os.Remove(path)
+		}
+
+		return nil
+	}); err != nil {
+		return fmt.Errorf("clean generated files: %w", err)
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return removeEmptyDirs(dir)
+}
+
+// removeEmptyDirs removes the empty sub directories of the directory,
+// they are left by the removed files of the deleted schemas.
+func removeEmptyDirs(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		subDir := filepath.Join(dir, entry.Name())
+		if err := removeEmptyDirs(subDir); err != nil {
+			return err
+		}
+
+		subEntries, err := os.ReadDir(subDir)
+		if err != nil {
+			return err
+		}
+
+		if len(subEntries) == 0 {
+			if err := os.Remove(subDir); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
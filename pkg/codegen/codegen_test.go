@@ -0,0 +1,135 @@
+package codegen_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/codegen"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createSchemaBuilder(t *testing.T) *schema.Builder {
+	schemaDir := t.TempDir()
+	utils.WriteFile(schemaDir+"/blog_post.json", `{
+		"name": "blog_post",
+		"namespace": "blog_posts",
+		"label_field": "title",
+		"fields": [
+			{
+				"type": "uint64",
+				"name": "id",
+				"label": "ID",
+				"db": {"attr": "UNSIGNED", "key": "PRIMARY", "increment": true}
+			},
+			{ "type": "string", "name": "title", "label": "Title" },
+			{ "type": "json", "name": "meta", "label": "Meta", "optional": true },
+			{ "type": "bool", "name": "published", "label": "Published" },
+			{ "type": "int", "name": "views", "label": "Views", "optional": true },
+			{
+				"type": "enum",
+				"name": "status",
+				"label": "Status",
+				"default": "draft",
+				"enums": [{ "value": "draft", "label": "Draft" }, { "value": "published", "label": "Published" }]
+			},
+			{
+				"type": "relation",
+				"name": "tags",
+				"label": "Tags",
+				"optional": true,
+				"relation": { "schema": "tag", "field": "posts", "type": "m2m", "owner": true }
+			},
+			{
+				"type": "relation",
+				"name": "author",
+				"label": "Author",
+				"optional": true,
+				"relation": { "schema": "user", "field": "posts", "type": "o2m", "optional": true }
+			}
+		]
+	}`)
+	utils.WriteFile(schemaDir+"/tag.json", `{
+		"name": "tag",
+		"namespace": "tags",
+		"label_field": "name",
+		"fields": [
+			{ "type": "string", "name": "name", "label": "Name" },
+			{
+				"type": "relation",
+				"name": "posts",
+				"label": "Posts",
+				"optional": true,
+				"relation": { "schema": "blog_post", "field": "tags", "type": "m2m" }
+			}
+		]
+	}`)
+	utils.WriteFile(schemaDir+"/user.json", `{
+		"name": "user",
+		"namespace": "users",
+		"label_field": "username",
+		"fields": [
+			{
+				"type": "relation",
+				"name": "posts",
+				"label": "Posts",
+				"optional": true,
+				"relation": { "schema": "blog_post", "field": "author", "type": "o2m", "owner": true }
+			}
+		]
+	}`)
+
+	return utils.Must(schema.NewBuilderFromDir(schemaDir, fs.SystemSchemaTypes...))
+}
+
+func TestSchemas(t *testing.T) {
+	schemas := codegen.Schemas(createSchemaBuilder(t))
+	assert.Equal(t, []string{"blog_post", "tag"}, utils.Map(schemas, func(s *schema.Schema) string {
+		return s.Name
+	}))
+}
+
+func TestPascalName(t *testing.T) {
+	tests := map[string]string{
+		"title":        "Title",
+		"id":           "ID",
+		"author_id":    "AuthorID",
+		"blog_post":    "BlogPost",
+		"avatar_url":   "AvatarURL",
+		"api-key":      "APIKey",
+		"publishedAt":  "PublishedAt",
+		"html_content": "HTMLContent",
+	}
+
+	for name, expected := range tests {
+		assert.Equal(t, expected, codegen.PascalName(name), name)
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, codegen.WriteFiles(dir, map[string][]byte{
+		"post.go":      []byte(codegen.Header + "\n\npackage models\n"),
+		"post/post.go": []byte(codegen.Header + "\n\npackage post\n"),
+		"tag.go":       []byte(codegen.Header + "\n\npackage models\n"),
+		"tag/tag.go":   []byte(codegen.Header + "\n\npackage tag\n"),
+	}))
+	utils.WriteFile(filepath.Join(dir, "custom.go"), "package models\n")
+
+	// The files of the deleted schemas are removed, the files that are not generated are kept
+	require.NoError(t, codegen.WriteFiles(dir, map[string][]byte{
+		"post.go":      []byte(codegen.Header + "\n\npackage models\n\n// updated\n"),
+		"post/post.go": []byte(codegen.Header + "\n\npackage post\n"),
+	}))
+
+	assert.Equal(t, codegen.Header+"\n\npackage models\n\n// updated\n", string(utils.Must(os.ReadFile(filepath.Join(dir, "post.go")))))
+	assert.True(t, utils.IsFileExists(filepath.Join(dir, "post", "post.go")))
+	assert.True(t, utils.IsFileExists(filepath.Join(dir, "custom.go")))
+	assert.False(t, utils.IsFileExists(filepath.Join(dir, "tag.go")))
+	_, err := os.Stat(filepath.Join(dir, "tag"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,438 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+)
+
+const (
+	dbImport   = "github.com/fastschema/fastschema/db"
+	timeImport = "time"
+	uuidImport = "github.com/google/uuid"
+)
+
+// goFieldTypes are the Go types of the field values.
+var goFieldTypes = map[schema.FieldType]string{
+	schema.TypeBool:    "bool",
+	schema.TypeTime:    "time.Time",
+	schema.TypeJSON:    "any",
+	schema.TypeUUID:    "uuid.UUID",
+	schema.TypeBytes:   "[]byte",
+	schema.TypeEnum:    "string",
+	schema.TypeString:  "string",
+	schema.TypeText:    "string",
+	schema.TypeInt8:    "int8",
+	schema.TypeInt16:   "int16",
+	schema.TypeInt32:   "int32",
+	schema.TypeInt:     "int",
+	schema.TypeInt64:   "int64",
+	schema.TypeUint8:   "uint8",
+	schema.TypeUint16:  "uint16",
+	schema.TypeUint32:  "uint32",
+	schema.TypeUint:    "uint",
+	schema.TypeUint64:  "uint64",
+	schema.TypeFloat32: "float32",
+	schema.TypeFloat64: "float64",
+}
+
+// GoConfig is the configuration of the Go code generation.
+type GoConfig struct {
+	// Package is the name of the package of the generated structs, default to "models".
+	Package string
+	// Import is the import path of the package of the generated structs.
+	// The predicate packages of the schemas are generated in sub packages,
+	// their import paths are shown in the package documentation if it is set.
+	Import string
+}
+
+type goField struct {
+	Name       string
+	GoName     string
+	Type       string
+	ParamType  string
+	Optional   bool
+	Comparable bool
+	Ordered    bool
+	Text       bool
+	Relation   *goRelation
+}
+
+type goRelation struct {
+	TargetSchema string
+	TargetType   string
+	BackField    string
+	Many         bool
+}
+
+type goSchema struct {
+	Name         string
+	GoName       string
+	Receiver     string
+	Package      string
+	ModelPackage string
+	ModelImport  string
+	PrimaryKey   string
+	PrimaryField *goField
+	Fields       []*goField
+	Imports      []string
+}
+
+// GenerateGo generates the Go code of the schemas.
+//
+//	For each schema, the package of the config contains a struct that is bound to the records
+//	with db.Builder, a query function and the accessors of the relations:
+//		posts, err := models.QueryBlog(client).Where(blog.TitleEQ("x")).Get(ctx)
+//		tags, err := posts[0].QueryTags(client).Get(ctx)
+//	The sub package of each schema contains the field names and the typed predicates.
+//	The returned files are keyed by their path relative to the output directory.
+func GenerateGo(sb *schema.Builder, config *GoConfig) (map[string][]byte, error) {
+	if config == nil {
+		config = &GoConfig{}
+	}
+
+	modelPackage := config.Package
+	if modelPackage == "" {
+		modelPackage = "models"
+	}
+
+	files := map[string][]byte{}
+	for _, s := range Schemas(sb) {
+		gs, err := newGoSchema(sb, s, modelPackage, config.Import)
+		if err != nil {
+			return nil, err
+		}
+
+		modelFile, err := renderGo(goModelTemplate, gs, gs.Imports)
+		if err != nil {
+			return nil, fmt.Errorf("generate %s model: %w", s.Name, err)
+		}
+
+		predicateFile, err := renderGo(goPredicateTemplate, gs, gs.predicateImports())
+		if err != nil {
+			return nil, fmt.Errorf("generate %s predicates: %w", s.Name, err)
+		}
+
+		files[s.Name+".go"] = modelFile
+		files[path.Join(gs.Package, gs.Package+".go")] = predicateFile
+	}
+
+	return files, nil
+}
+
+func newGoSchema(sb *schema.Builder, s *schema.Schema, modelPackage, modelImport string) (*goSchema, error) {
+	gs := &goSchema{
+		Name:         s.Name,
+		GoName:       PascalName(s.Name),
+		Package:      goPackageName(s.Name),
+		ModelPackage: modelPackage,
+		PrimaryKey:   s.PrimaryKeyName(),
+	}
+	gs.Receiver = strings.ToLower(gs.GoName[:1])
+	if modelImport != "" {
+		gs.ModelImport = path.Join(modelImport, gs.Package)
+	}
+
+	imports := map[string]bool{}
+	for _, f := range s.Fields {
+		field := &goField{
+			Name:     f.Name,
+			GoName:   PascalName(f.Name),
+			Optional: f.Optional,
+		}
+
+		if f.Type.IsRelationType() {
+			relation, err := newGoRelation(sb, f, imports)
+			if err != nil {
+				return nil, fmt.Errorf("generate %s.%s: %w", s.Name, f.Name, err)
+			}
+
+			field.Relation = relation
+			field.Type = utils.If(relation.Many, "[]"+relation.TargetType, relation.TargetType)
+			gs.Fields = append(gs.Fields, field)
+			continue
+		}
+
+		goType, ok := goFieldTypes[f.Type]
+		if !ok {
+			return nil, fmt.Errorf("generate %s.%s: unsupported field type %s", s.Name, f.Name, f.Type)
+		}
+
+		// The time and the optional fields are pointers, so that the fields without value are omitted
+		field.ParamType = goType
+		field.Type = goType
+		if f.Type == schema.TypeTime || (f.Optional && f.Type != schema.TypeJSON && f.Type != schema.TypeBytes) {
+			field.Type = "*" + goType
+		}
+
+		switch f.Type {
+		case schema.TypeTime:
+			imports[timeImport] = true
+		case schema.TypeUUID:
+			imports[uuidImport] = true
+		}
+
+		field.Comparable = f.Type != schema.TypeJSON && f.Type != schema.TypeBytes
+		field.Ordered = f.Type.IsNumeric() || f.Type == schema.TypeTime ||
+			f.Type == schema.TypeString || f.Type == schema.TypeText
+		field.Text = f.Type == schema.TypeString || f.Type == schema.TypeText
+		if f.Name == gs.PrimaryKey {
+			gs.PrimaryField = field
+		}
+
+		gs.Fields = append(gs.Fields, field)
+	}
+
+	imports[dbImport] = true
+	for importPath := range imports {
+		gs.Imports = append(gs.Imports, importPath)
+	}
+
+	sort.Strings(gs.Imports)
+	return gs, nil
+}
+
+// newGoRelation returns the relation of a field, the relations to the system schemas use their types.
+func newGoRelation(sb *schema.Builder, f *schema.Field, imports map[string]bool) (*goRelation, error) {
+	if f.Relation == nil {
+		return nil, fmt.Errorf("relation is not set")
+	}
+
+	target, err := sb.Schema(f.Relation.TargetSchemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	relation := &goRelation{
+		TargetSchema: target.Name,
+		TargetType:   "*" + PascalName(target.Name),
+		BackField:    f.Relation.TargetFieldName,
+		Many:         f.Relation.Type.IsM2M() || (f.Relation.Type.IsO2M() && f.Relation.Owner),
+	}
+
+	if target.IsSystemSchema && target.SystemSchema != nil && target.SystemSchema.RType != nil {
+		rType := target.SystemSchema.RType
+		imports[rType.PkgPath()] = true
+		relation.TargetType = "*" + path.Base(rType.PkgPath()) + "." + rType.Name()
+	}
+
+	return relation, nil
+}
+
+func (gs *goSchema) predicateImports() []string {
+	imports := []string{dbImport}
+	for _, importPath := range []string{timeImport, uuidImport} {
+		for _, f := range gs.Fields {
+			if f.Relation == nil && f.Comparable && strings.HasPrefix(f.ParamType, path.Base(importPath)+".") {
+				imports = append(imports, importPath)
+				break
+			}
+		}
+	}
+
+	sort.Strings(imports)
+	return imports
+}
+
+// goPackageName returns the name of the predicate package of a schema, for example: "blog_post" -> "blogpost".
+func goPackageName(name string) string {
+	packageName := strings.ToLower(strings.NewReplacer("_", "", "-", "", ".", "").Replace(name))
+	if token.IsKeyword(packageName) {
+		packageName += "schema"
+	}
+
+	return packageName
+}
+
+// renderGo renders a Go file, the standard library imports are grouped before the other imports.
+func renderGo(tmpl *template.Template, gs *goSchema, imports []string) ([]byte, error) {
+	stdImports := utils.Filter(imports, func(importPath string) bool {
+		return !strings.Contains(strings.Split(importPath, "/")[0], ".")
+	})
+	otherImports := utils.Filter(imports, func(importPath string) bool {
+		return strings.Contains(strings.Split(importPath, "/")[0], ".")
+	})
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]any{
+		"Header":       Header,
+		"Schema":       gs,
+		"StdImports":   stdImports,
+		"OtherImports": otherImports,
+	}); err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+var goModelTemplate = template.Must(template.New("model").Parse(`{{ .Header }}
+
+package {{ .Schema.ModelPackage }}
+
+import (
+{{- range .StdImports }}
+	"{{ . }}"
+{{- end }}
+{{- if .StdImports }}
+{{ end }}
+{{- range .OtherImports }}
+	"{{ . }}"
+{{- end }}
+)
+
+{{ $s := .Schema -}}
+// {{ $s.GoName }} is the model of the {{ $s.Name }} schema.
+type {{ $s.GoName }} struct {
+{{- range $s.Fields }}
+	{{ .GoName }} {{ .Type }} ` + "`" + `json:"{{ .Name }},omitempty"` + "`" + `
+{{- end }}
+}
+
+// Query{{ $s.GoName }} returns a query builder of the {{ $s.Name }} records.
+func Query{{ $s.GoName }}(client db.Client) *db.QueryBuilder[*{{ $s.GoName }}] {
+	return db.Builder[*{{ $s.GoName }}](client, "{{ $s.Name }}")
+}
+{{- range $s.Fields }}
+{{- if and .Relation .Relation.BackField $s.PrimaryField }}
+
+// Query{{ .GoName }} returns a query builder of the {{ .Relation.TargetSchema }} records of the {{ .Name }} relation.
+func ({{ $s.Receiver }} *{{ $s.GoName }}) Query{{ .GoName }}(client db.Client) *db.QueryBuilder[{{ .Relation.TargetType }}] {
+	return db.Builder[{{ .Relation.TargetType }}](client, "{{ .Relation.TargetSchema }}").
+		Where(db.EQ("{{ .Relation.BackField }}.{{ $s.PrimaryKey }}", {{ $s.Receiver }}.{{ $s.PrimaryField.GoName }}))
+}
+{{- end }}
+{{- end }}
+`))
+
+var goPredicateTemplate = template.Must(template.New("predicate").Parse(`{{ .Header }}
+
+{{ $s := .Schema -}}
+// Package {{ $s.Package }} contains the field names and the predicates of the {{ $s.Name }} schema.
+package {{ $s.Package }}{{ if $s.ModelImport }} // import "{{ $s.ModelImport }}"{{ end }}
+
+import (
+{{- range .StdImports }}
+	"{{ . }}"
+{{- end }}
+{{- if .StdImports }}
+{{ end }}
+{{- range .OtherImports }}
+	"{{ . }}"
+{{- end }}
+)
+
+const (
+	// Schema is the name of the {{ $s.Name }} schema.
+	Schema = "{{ $s.Name }}"
+{{- range $s.Fields }}
+	// Field{{ .GoName }} is the name of the {{ .Name }} field.
+	Field{{ .GoName }} = "{{ .Name }}"
+{{- end }}
+)
+{{- range $s.Fields }}
+{{- if .Relation }}
+{{- if .Relation.Many }}
+
+// Has{{ .GoName }}With returns a predicate that matches the records having a related {{ .Relation.TargetSchema }} record that matches the predicates.
+func Has{{ .GoName }}With(predicates ...*db.Predicate) *db.Predicate {
+	return db.Some(Field{{ .GoName }}, predicates...)
+}
+
+// HasNo{{ .GoName }} returns a predicate that matches the records without related {{ .Relation.TargetSchema }} records.
+func HasNo{{ .GoName }}() *db.Predicate {
+	return db.None(Field{{ .GoName }})
+}
+{{- end }}
+{{- else }}
+{{- if .Comparable }}
+
+// {{ .GoName }}EQ returns a predicate that matches the records whose {{ .Name }} equals the value.
+func {{ .GoName }}EQ(value {{ .ParamType }}) *db.Predicate {
+	return db.EQ(Field{{ .GoName }}, value)
+}
+
+// {{ .GoName }}NEQ returns a predicate that matches the records whose {{ .Name }} does not equal the value.
+func {{ .GoName }}NEQ(value {{ .ParamType }}) *db.Predicate {
+	return db.NEQ(Field{{ .GoName }}, value)
+}
+{{- if ne .ParamType "bool" }}
+
+// {{ .GoName }}In returns a predicate that matches the records whose {{ .Name }} is one of the values.
+func {{ .GoName }}In(values ...{{ .ParamType }}) *db.Predicate {
+	return db.In(Field{{ .GoName }}, values)
+}
+
+// {{ .GoName }}NotIn returns a predicate that matches the records whose {{ .Name }} is none of the values.
+func {{ .GoName }}NotIn(values ...{{ .ParamType }}) *db.Predicate {
+	return db.NotIn(Field{{ .GoName }}, values)
+}
+{{- end }}
+{{- end }}
+{{- if .Ordered }}
+
+// {{ .GoName }}GT returns a predicate that matches the records whose {{ .Name }} is greater than the value.
+func {{ .GoName }}GT(value {{ .ParamType }}) *db.Predicate {
+	return db.GT(Field{{ .GoName }}, value)
+}
+
+// {{ .GoName }}GTE returns a predicate that matches the records whose {{ .Name }} is greater than or equal to the value.
+func {{ .GoName }}GTE(value {{ .ParamType }}) *db.Predicate {
+	return db.GTE(Field{{ .GoName }}, value)
+}
+
+// {{ .GoName }}LT returns a predicate that matches the records whose {{ .Name }} is less than the value.
+func {{ .GoName }}LT(value {{ .ParamType }}) *db.Predicate {
+	return db.LT(Field{{ .GoName }}, value)
+}
+
+// {{ .GoName }}LTE returns a predicate that matches the records whose {{ .Name }} is less than or equal to the value.
+func {{ .GoName }}LTE(value {{ .ParamType }}) *db.Predicate {
+	return db.LTE(Field{{ .GoName }}, value)
+}
+{{- end }}
+{{- if .Text }}
+
+// {{ .GoName }}Contains returns a predicate that matches the records whose {{ .Name }} contains the value.
+func {{ .GoName }}Contains(value string) *db.Predicate {
+	return db.Contains(Field{{ .GoName }}, value)
+}
+
+// {{ .GoName }}ContainsFold returns a predicate that matches the records whose {{ .Name }} contains the value, case-insensitively.
+func {{ .GoName }}ContainsFold(value string) *db.Predicate {
+	return db.ContainsFold(Field{{ .GoName }}, value)
+}
+
+// {{ .GoName }}HasPrefix returns a predicate that matches the records whose {{ .Name }} starts with the value.
+func {{ .GoName }}HasPrefix(value string) *db.Predicate {
+	return db.StartsWith(Field{{ .GoName }}, value)
+}
+
+// {{ .GoName }}HasSuffix returns a predicate that matches the records whose {{ .Name }} ends with the value.
+func {{ .GoName }}HasSuffix(value string) *db.Predicate {
+	return db.EndsWith(Field{{ .GoName }}, value)
+}
+{{- end }}
+{{- if .Optional }}
+
+// {{ .GoName }}IsNull returns a predicate that matches the records without {{ .Name }}.
+func {{ .GoName }}IsNull() *db.Predicate {
+	return db.Null(Field{{ .GoName }}, true)
+}
+
+// {{ .GoName }}NotNull returns a predicate that matches the records with {{ .Name }}.
+func {{ .GoName }}NotNull() *db.Predicate {
+	return db.Null(Field{{ .GoName }}, false)
+}
+{{- end }}
+{{- end }}
+{{- end }}
+`))
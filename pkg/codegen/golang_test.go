@@ -0,0 +1,72 @@
+package codegen_test
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/fastschema/fastschema/pkg/codegen"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateGo(t *testing.T) {
+	sb := createSchemaBuilder(t)
+	files := utils.Must(codegen.GenerateGo(sb, &codegen.GoConfig{
+		Package: "content",
+		Import:  "example.com/app/content",
+	}))
+	assert.Len(t, files, 4)
+	for name, content := range files {
+		_, err := parser.ParseFile(token.NewFileSet(), name, content, parser.AllErrors)
+		require.NoError(t, err, name)
+		assert.Contains(t, string(content), codegen.Header)
+	}
+
+	model := string(files["blog_post.go"])
+	assert.Contains(t, model, "package content")
+	assert.Contains(t, model, "\t\"github.com/fastschema/fastschema/fs\"")
+	assert.Contains(t, model, "type BlogPost struct {")
+	assert.Contains(t, model, "\tID        uint64     `json:\"id,omitempty\"`")
+	assert.Contains(t, model, "\tMeta      any        `json:\"meta,omitempty\"`")
+	assert.Contains(t, model, "\tViews     *int       `json:\"views,omitempty\"`")
+	assert.Contains(t, model, "\tTags      []*Tag     `json:\"tags,omitempty\"`")
+	assert.Contains(t, model, "\tAuthor    *fs.User   `json:\"author,omitempty\"`")
+	assert.Contains(t, model, "\tAuthorID  *uuid.UUID `json:\"author_id,omitempty\"`")
+	assert.Contains(t, model, "\tCreatedAt *time.Time `json:\"created_at,omitempty\"`")
+	assert.Contains(t, model, "func QueryBlogPost(client db.Client) *db.QueryBuilder[*BlogPost] {")
+	assert.Contains(t, model, `return db.Builder[*BlogPost](client, "blog_post")`)
+	assert.Contains(t, model, "func (b *BlogPost) QueryTags(client db.Client) *db.QueryBuilder[*Tag] {")
+	assert.Contains(t, model, `Where(db.EQ("posts.id", b.ID))`)
+	assert.Contains(t, model, "func (b *BlogPost) QueryAuthor(client db.Client) *db.QueryBuilder[*fs.User] {")
+
+	predicates := string(files["blogpost/blogpost.go"])
+	assert.Contains(t, predicates, `package blogpost // import "example.com/app/content/blogpost"`)
+	assert.Contains(t, predicates, `Schema = "blog_post"`)
+	assert.Contains(t, predicates, `FieldAuthorID = "author_id"`)
+	assert.Contains(t, predicates, "func TitleEQ(value string) *db.Predicate {")
+	assert.Contains(t, predicates, "func TitleContains(value string) *db.Predicate {")
+	assert.Contains(t, predicates, "func IDIn(values ...uint64) *db.Predicate {")
+	assert.Contains(t, predicates, "func ViewsGT(value int) *db.Predicate {")
+	assert.Contains(t, predicates, "func ViewsIsNull() *db.Predicate {")
+	assert.Contains(t, predicates, "func PublishedEQ(value bool) *db.Predicate {")
+	assert.Contains(t, predicates, "func AuthorIDEQ(value uuid.UUID) *db.Predicate {")
+	assert.Contains(t, predicates, "func CreatedAtLT(value time.Time) *db.Predicate {")
+	assert.Contains(t, predicates, "func HasTagsWith(predicates ...*db.Predicate) *db.Predicate {")
+	assert.NotContains(t, predicates, "func PublishedIn(")
+	assert.NotContains(t, predicates, "func MetaEQ(")
+	assert.NotContains(t, predicates, "func HasAuthorWith(")
+
+	// The generated code is the same when it is generated again
+	assert.Equal(t, files, utils.Must(codegen.GenerateGo(sb, &codegen.GoConfig{
+		Package: "content",
+		Import:  "example.com/app/content",
+	})))
+}
+
+func TestGenerateGoDefaultPackage(t *testing.T) {
+	files := utils.Must(codegen.GenerateGo(createSchemaBuilder(t), nil))
+	assert.Contains(t, string(files["tag.go"]), "package models")
+	assert.Contains(t, string(files["tag/tag.go"]), "package tag\n")
+}
@@ -0,0 +1,746 @@
+package codegen
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/openapi"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+)
+
+// tsAnyEntity is the type of the entities that are not bound to a schema.
+const tsAnyEntity = "Record<string, any>"
+
+// tsFieldTypes are the TypeScript types of the field values.
+var tsFieldTypes = map[schema.FieldType]string{
+	schema.TypeBool:    "boolean",
+	schema.TypeTime:    "string",
+	schema.TypeJSON:    "any",
+	schema.TypeUUID:    "string",
+	schema.TypeBytes:   "string",
+	schema.TypeEnum:    "string",
+	schema.TypeString:  "string",
+	schema.TypeText:    "string",
+	schema.TypeInt8:    "number",
+	schema.TypeInt16:   "number",
+	schema.TypeInt32:   "number",
+	schema.TypeInt:     "number",
+	schema.TypeInt64:   "number",
+	schema.TypeUint8:   "number",
+	schema.TypeUint16:  "number",
+	schema.TypeUint32:  "number",
+	schema.TypeUint:    "number",
+	schema.TypeUint64:  "number",
+	schema.TypeFloat32: "number",
+	schema.TypeFloat64: "number",
+}
+
+// tsContentBodies are the request bodies of the content resources that read the payload
+// instead of binding the input, "%s" is replaced by the input type of the schema.
+var tsContentBodies = map[string]string{
+	"create":      "%s",
+	"upsert":      "%s",
+	"update":      "Partial<%s>",
+	"bulk-update": "Partial<%s>",
+}
+
+// tsContentOutputs are the responses of the content resources that are not typed by their handlers,
+// "%s" is replaced by the type of the schema.
+var tsContentOutputs = map[string]string{
+	"create": "%s",
+}
+
+// tsReservedNames are the names that are declared by the client runtime.
+var tsReservedNames = []string{
+	"ID", "Query", "ClientConfig", "ErrorResponse", "FastschemaError", "RequestOptions", "Client",
+}
+
+var (
+	tsIdentifier    = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	tsNameSeparator = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+)
+
+// TSConfig is the configuration of the TypeScript code generation.
+type TSConfig struct {
+	// SchemaBuilder contains the schemas that the interfaces are generated for.
+	SchemaBuilder *schema.Builder
+	// Resources are the resources that the client functions are generated for.
+	// The content resources are generated for each schema, typed with the schema interfaces.
+	Resources *fs.ResourcesManager
+}
+
+type tsGenerator struct {
+	sb          *schema.Builder
+	schemas     []*schema.Schema
+	schemaTypes map[reflect.Type]string
+	typeNames   map[reflect.Type]string
+	generics    map[reflect.Type]bool
+	names       map[string]bool
+	decls       []string
+}
+
+type tsNode struct {
+	key      string
+	function string
+	children []*tsNode
+}
+
+// GenerateTS generates a TypeScript module of the schemas and the resources.
+//
+//	The module contains an interface for each schema and its input, the enums are union types
+//	and the relations reference the interfaces of the target schemas:
+//		export interface BlogPost { id: number; title: string; tags?: Tag[] }
+//	The types of the resources are reflected from their signatures, as in the OpenAPI spec,
+//	and createClient returns a fetch client with a function for each resource, nested by the resource ID:
+//		const client = createClient({ baseURL: "http://localhost:8000", token });
+//		const posts = await client.api.content.blogPost.list({ limit: 10 });
+func GenerateTS(config *TSConfig) ([]byte, error) {
+	if config == nil || config.SchemaBuilder == nil {
+		return nil, fmt.Errorf("schema builder is required")
+	}
+
+	g := &tsGenerator{
+		sb:          config.SchemaBuilder,
+		schemaTypes: map[reflect.Type]string{},
+		typeNames:   map[reflect.Type]string{},
+		generics:    map[reflect.Type]bool{},
+		names:       map[string]bool{},
+	}
+
+	for _, name := range tsReservedNames {
+		g.names[name] = true
+	}
+
+	for _, s := range config.SchemaBuilder.Schemas() {
+		if s.IsJunctionSchema {
+			continue
+		}
+
+		g.schemas = append(g.schemas, s)
+		g.names[PascalName(s.Name)] = true
+		g.names[PascalName(s.Name)+"Input"] = true
+		if s.IsSystemSchema && s.SystemSchema != nil && s.SystemSchema.RType != nil {
+			g.schemaTypes[s.SystemSchema.RType] = PascalName(s.Name)
+		}
+	}
+
+	sort.Slice(g.schemas, func(i, j int) bool {
+		return g.schemas[i].Name < g.schemas[j].Name
+	})
+
+	var schemaDecls []string
+	for _, s := range g.schemas {
+		decls, err := g.schemaDecls(s)
+		if err != nil {
+			return nil, err
+		}
+
+		schemaDecls = append(schemaDecls, decls...)
+	}
+
+	root := &tsNode{}
+	if config.Resources != nil {
+		if err := g.resourceNodes(root, config.Resources); err != nil {
+			return nil, err
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(Header + "\n\n")
+	b.WriteString(strings.Join(schemaDecls, "\n\n") + "\n\n")
+	if len(g.decls) > 0 {
+		b.WriteString(strings.Join(g.decls, "\n\n") + "\n\n")
+	}
+
+	b.WriteString(tsRuntime)
+	b.WriteString("export function createClient(config: ClientConfig) {\n")
+	b.WriteString(tsRequestFunctions)
+	b.WriteString("\n  return " + root.render(1) + ";\n}\n\n")
+	b.WriteString("export type Client = ReturnType<typeof createClient>;\n")
+
+	return []byte(b.String()), nil
+}
+
+// schemaDecls returns the enum types, the interface and the input interface of a schema.
+func (g *tsGenerator) schemaDecls(s *schema.Schema) ([]string, error) {
+	name := PascalName(s.Name)
+	pk := s.PrimaryKeyName()
+	decls := []string{}
+	fields := []string{}
+	inputFields := []string{}
+
+	for _, f := range s.Fields {
+		if f.Type.IsRelationType() {
+			if f.Relation == nil {
+				return nil, fmt.Errorf("generate %s.%s: relation is not set", s.Name, f.Name)
+			}
+
+			target, err := g.sb.Schema(f.Relation.TargetSchemaName)
+			if err != nil {
+				return nil, fmt.Errorf("generate %s.%s: %w", s.Name, f.Name, err)
+			}
+
+			many := f.Relation.Type.IsM2M() || (f.Relation.Type.IsO2M() && f.Relation.Owner)
+			targetType := PascalName(target.Name)
+			inputType := fmt.Sprintf("Pick<%s, %q>", targetType, target.PrimaryKeyName())
+			fields = append(fields, tsProperty(f.Name, utils.If(many, targetType+"[]", targetType), true))
+			inputFields = append(inputFields, tsProperty(f.Name, utils.If(many, inputType+"[]", inputType), true))
+			continue
+		}
+
+		fieldType, ok := tsFieldTypes[f.Type]
+		if !ok {
+			return nil, fmt.Errorf("generate %s.%s: unsupported field type %s", s.Name, f.Name, f.Type)
+		}
+
+		if f.Type == schema.TypeEnum && len(f.Enums) > 0 {
+			fieldType = name + PascalName(f.Name)
+			values := utils.Map(f.Enums, func(e *schema.FieldEnum) string {
+				return strconv.Quote(e.Value)
+			})
+			decls = append(decls, fmt.Sprintf("export type %s = %s;", fieldType, strings.Join(values, " | ")))
+		}
+
+		// The optional fields without value are null, the fields that have a default value are optional in the input
+		if f.Optional && fieldType != "any" {
+			fieldType += " | null"
+		}
+
+		inputOptional := f.Optional || f.Default != nil || f.Name == pk
+		fields = append(fields, tsProperty(f.Name, fieldType, f.Optional))
+		inputFields = append(inputFields, tsProperty(f.Name, fieldType, inputOptional))
+	}
+
+	decls = append(
+		decls,
+		fmt.Sprintf("/** %s is a record of the %s schema. */\nexport interface %s %s", name, s.Name, name, tsObject(fields, 0)),
+		fmt.Sprintf("/** %sInput is the data to create or update the %s records. */\nexport interface %sInput %s", name, s.Name, name, tsObject(inputFields, 0)),
+	)
+
+	return decls, nil
+}
+
+// resourceNodes adds the client functions of the resources to the root node.
+//
+//	The content resources have the schema name in their path,
+//	a function is generated for each schema with the schema name in the ID and the path.
+//	The websocket resources are skipped, they can not be called with fetch.
+func (g *tsGenerator) resourceNodes(root *tsNode, resources *fs.ResourcesManager) error {
+	infos, err := openapi.FlattenResources(resources.Resources(), "/", fs.Args{})
+	if err != nil {
+		return err
+	}
+
+	for _, info := range infos {
+		if r := resources.Find(info.ID); r != nil && r.Meta() != nil && r.Meta().WS != "" {
+			continue
+		}
+
+		if !strings.Contains(info.Path, ":schema") {
+			if err := root.add(strings.Split(info.ID, "."), g.function(info, "", "")); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		for _, s := range g.schemas {
+			schemaInfo := info.Clone()
+			schemaInfo.Path = strings.ReplaceAll(info.Path, ":schema", s.Name)
+			schemaInfo.Args = info.Args.Clone()
+			delete(schemaInfo.Args, "schema")
+
+			keys := strings.Split(info.ID, ".")
+			keys = append(keys[:len(keys)-1:len(keys)-1], s.Name, keys[len(keys)-1])
+			if err := root.add(keys, g.function(schemaInfo, PascalName(s.Name), keys[len(keys)-1])); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// function returns the client function of a resource.
+//
+//	The path params are the first arguments, followed by the request body and the query.
+//	The content resources have the type of the schema and the name of the resource.
+func (g *tsGenerator) function(info *openapi.ResourceInfo, schemaType, contentResource string) string {
+	entityType, inputEntityType := tsAnyEntity, tsAnyEntity
+	if schemaType != "" {
+		entityType, inputEntityType = schemaType, schemaType+"Input"
+	}
+
+	infoPath := info.Path
+	if len(infoPath) > 1 {
+		infoPath = strings.TrimSuffix(infoPath, "/")
+	}
+
+	pathParams := openapi.ExtractPathParams(infoPath)
+	urlPath := strconv.Quote(infoPath)
+	params := []string{}
+	if len(pathParams) > 0 {
+		urlPath = strings.ReplaceAll(infoPath, "`", "\\`")
+		for _, param := range pathParams {
+			name := camelName(param)
+			params = append(params, name+": ID")
+			urlPath = strings.ReplaceAll(urlPath, ":"+param, "${encodeURIComponent("+name+")}")
+		}
+
+		urlPath = "`" + urlPath + "`"
+	}
+
+	queryFields := []string{}
+	queryRequired := false
+	argNames := make([]string, 0, len(info.Args))
+	for name := range info.Args {
+		argNames = append(argNames, name)
+	}
+
+	sort.Strings(argNames)
+	for _, name := range argNames {
+		if utils.Contains(pathParams, name) {
+			continue
+		}
+
+		arg := info.Args[name]
+		queryRequired = queryRequired || arg.Required
+		queryFields = append(queryFields, tsProperty(name, tsArgType(arg), !arg.Required))
+	}
+
+	queryType := "Query"
+	if len(queryFields) > 0 {
+		queryType += " & " + tsObject(queryFields, -1)
+	}
+
+	options := []string{"query"}
+	if utils.Contains([]string{"POST", "PUT", "PATCH"}, info.Method) {
+		inputType, _ := signatureType(info.Signatures, 0)
+		bodyType, bodyOptional := "unknown", true
+		if body, ok := tsContentBodies[contentResource]; ok && inputType == nil {
+			bodyType, bodyOptional = fmt.Sprintf(body, inputEntityType), false
+		} else if inputType != nil {
+			bodyType, bodyOptional = g.reflectType(inputType, inputEntityType, ""), false
+		}
+
+		params = append(params, "body"+utils.If(bodyOptional && !queryRequired, "?", "")+": "+bodyType)
+		options = append([]string{"body"}, options...)
+	}
+
+	params = append(params, "query"+utils.If(queryRequired, "", "?")+": "+queryType)
+	call := fmt.Sprintf("%q, %s, { %s }", info.Method, urlPath, strings.Join(options, ", "))
+
+	outputType, outputName := signatureType(info.Signatures, 1)
+	if output, ok := tsContentOutputs[contentResource]; ok {
+		return fmt.Sprintf("(%s) => request<%s>(%s)", strings.Join(params, ", "), fmt.Sprintf(output, entityType), call)
+	}
+
+	// The resources that write the HTTP response return the fetch response
+	if outputType != nil && (outputType == reflect.TypeFor[fs.HTTPResponse]() || outputType == reflect.TypeFor[*fs.HTTPResponse]()) {
+		return fmt.Sprintf("(%s) => send(%s)", strings.Join(params, ", "), call)
+	}
+
+	return fmt.Sprintf(
+		"(%s) => request<%s>(%s)",
+		strings.Join(params, ", "),
+		g.reflectType(outputType, entityType, outputName),
+		call,
+	)
+}
+
+// reflectType returns the TypeScript type of a Go type.
+//
+//	The structs are declared as interfaces, the structs that contain entities are generic
+//	so that the content resources return the schema types, for example: Pagination<BlogPost>.
+//	The types of the system schemas are replaced by the schema interfaces.
+func (g *tsGenerator) reflectType(t reflect.Type, entityType, name string) string {
+	if t == nil {
+		return "unknown"
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if schemaType, ok := g.schemaTypes[t]; ok {
+		return schemaType
+	}
+
+	switch {
+	case t == reflect.TypeFor[entity.Entity]():
+		return entityType
+	case t == reflect.TypeFor[time.Time](), t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return "string"
+	case reflect.PointerTo(t).Implements(reflect.TypeFor[json.Marshaler]()):
+		return "any"
+	case reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextMarshaler]()):
+		return "string"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Interface:
+		return "any"
+	case reflect.Slice, reflect.Array:
+		elemType := g.reflectType(t.Elem(), entityType, "")
+		if (strings.Contains(elemType, " | ") || strings.Contains(elemType, " & ")) && !strings.HasPrefix(elemType, "{") {
+			elemType = "(" + elemType + ")"
+		}
+
+		return elemType + "[]"
+	case reflect.Map:
+		return "Record<string, " + g.reflectType(t.Elem(), entityType, "") + ">"
+	case reflect.Struct:
+		return g.structType(t, entityType, name)
+	}
+
+	return "unknown"
+}
+
+// structType declares the interface of a named struct and returns its name,
+// the anonymous structs are inlined.
+func (g *tsGenerator) structType(t reflect.Type, entityType, name string) string {
+	if t.Name() == "" && name == "" {
+		return tsObject(g.structFields(t, entityType), -1)
+	}
+
+	typeName, ok := g.typeNames[t]
+	if !ok {
+		typeName = g.uniqueName(t, name)
+		g.typeNames[t] = typeName
+		g.generics[t] = g.containsEntity(t, map[reflect.Type]bool{})
+		typeParams, fieldsEntityType := "", tsAnyEntity
+		if g.generics[t] {
+			typeParams, fieldsEntityType = "<T = "+tsAnyEntity+">", "T"
+		}
+
+		// The declaration is reserved before the fields are reflected, the nested structs are declared after it
+		g.decls = append(g.decls, "")
+		index := len(g.decls) - 1
+		g.decls[index] = fmt.Sprintf(
+			"export interface %s%s %s",
+			typeName, typeParams, tsObject(g.structFields(t, fieldsEntityType), 0),
+		)
+	}
+
+	if g.generics[t] {
+		return typeName + "<" + entityType + ">"
+	}
+
+	return typeName
+}
+
+// structFields returns the properties of the JSON encoding of a struct,
+// the fields of the embedded structs are promoted.
+func (g *tsGenerator) structFields(t reflect.Type, entityType string) []string {
+	fields := []string{}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tagParts := strings.Split(field.Tag.Get("json"), ",")
+		fieldName := tagParts[0]
+		if fieldName == "-" && len(tagParts) == 1 {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && fieldName == "" && fieldType.Kind() == reflect.Struct {
+			fields = append(fields, g.structFields(fieldType, entityType)...)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldName = utils.If(fieldName == "", field.Name, fieldName)
+		fields = append(fields, tsProperty(
+			fieldName,
+			g.reflectType(field.Type, entityType, ""),
+			utils.Contains(tagParts[1:], "omitempty"),
+		))
+	}
+
+	return fields
+}
+
+// containsEntity reports whether the values of a type contain entities.
+func (g *tsGenerator) containsEntity(t reflect.Type, visited map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeFor[entity.Entity]() {
+		return true
+	}
+
+	if _, ok := g.schemaTypes[t]; ok || visited[t] {
+		return false
+	}
+
+	visited[t] = true
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return g.containsEntity(t.Elem(), visited)
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if (field.IsExported() || field.Anonymous) && g.containsEntity(field.Type, visited) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// uniqueName returns the name of the interface of a struct,
+// the structs of different packages that have the same name are prefixed with the package name.
+func (g *tsGenerator) uniqueName(t reflect.Type, name string) string {
+	if name == "" {
+		name = PascalName(tsNameSeparator.ReplaceAllString(t.Name(), "_"))
+	}
+
+	if g.names[name] && t.PkgPath() != "" {
+		name = PascalName(path.Base(t.PkgPath())) + name
+	}
+
+	uniqueName := name
+	for i := 2; g.names[uniqueName]; i++ {
+		uniqueName = name + strconv.Itoa(i)
+	}
+
+	g.names[uniqueName] = true
+	return uniqueName
+}
+
+// add adds a function to the node at the keys path,
+// the keys of a resource that has many methods are suffixed with the method.
+func (n *tsNode) add(keys []string, function string) error {
+	for _, key := range keys[:len(keys)-1] {
+		child := n.child(camelName(key))
+		if child.function != "" {
+			return fmt.Errorf("resource %s conflicts with a group", strings.Join(keys, "."))
+		}
+
+		n = child
+	}
+
+	key := camelName(keys[len(keys)-1])
+	for i := 2; n.find(key) != nil; i++ {
+		key = camelName(keys[len(keys)-1]) + strconv.Itoa(i)
+	}
+
+	n.children = append(n.children, &tsNode{key: key, function: function})
+	return nil
+}
+
+func (n *tsNode) find(key string) *tsNode {
+	for _, child := range n.children {
+		if child.key == key {
+			return child
+		}
+	}
+
+	return nil
+}
+
+func (n *tsNode) child(key string) *tsNode {
+	if child := n.find(key); child != nil {
+		return child
+	}
+
+	child := &tsNode{key: key}
+	n.children = append(n.children, child)
+	return child
+}
+
+// render returns the object literal of the node.
+func (n *tsNode) render(level int) string {
+	if n.function != "" {
+		return n.function
+	}
+
+	indent := strings.Repeat("  ", level+1)
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, child := range n.children {
+		b.WriteString(indent + tsKey(child.key) + ": " + child.render(level+1) + ",\n")
+	}
+
+	b.WriteString(strings.Repeat("  ", level) + "}")
+	return b.String()
+}
+
+// signatureType returns the type and the name of the signature at the index.
+func signatureType(signatures fs.Signatures, index int) (reflect.Type, string) {
+	if len(signatures) <= index || signatures[index] == nil {
+		return nil, ""
+	}
+
+	if signature, ok := signatures[index].(*fs.Signature); ok {
+		if signature.Type == nil {
+			return nil, signature.Name
+		}
+
+		return reflect.TypeOf(signature.Type), signature.Name
+	}
+
+	return reflect.TypeOf(signatures[index]), ""
+}
+
+// tsArgType returns the TypeScript type of a resource argument.
+func tsArgType(arg fs.Arg) string {
+	switch arg.Type.Common() {
+	case "boolean":
+		return "boolean"
+	case "integer", "number":
+		return "number"
+	}
+
+	return "string"
+}
+
+// tsProperty returns a property of an interface.
+func tsProperty(name, propertyType string, optional bool) string {
+	return tsKey(name) + utils.If(optional, "?", "") + ": " + propertyType
+}
+
+// tsObject returns an object type of the properties,
+// the object is written in a single line if the indent level is negative.
+func tsObject(properties []string, level int) string {
+	if len(properties) == 0 {
+		return "{}"
+	}
+
+	if level < 0 {
+		return "{ " + strings.Join(properties, "; ") + " }"
+	}
+
+	indent := strings.Repeat("  ", level+1)
+	return "{\n" + indent + strings.Join(properties, ";\n"+indent) + ";\n" + strings.Repeat("  ", level) + "}"
+}
+
+// tsKey returns a property key, the keys that are not identifiers are quoted.
+func tsKey(key string) string {
+	if tsIdentifier.MatchString(key) {
+		return key
+	}
+
+	return strconv.Quote(key)
+}
+
+// camelName converts a resource name to a camel case name, for example: "bulk-create" -> "bulkCreate".
+func camelName(name string) string {
+	var b strings.Builder
+	for i, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.' || r == ' ' || r == '/'
+	}) {
+		if i == 0 {
+			b.WriteString(part)
+			continue
+		}
+
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return b.String()
+}
+
+const tsRuntime = `export type ID = string | number;
+
+export type Query = Record<string, string | number | boolean | undefined>;
+
+export interface ClientConfig {
+  /** The base URL of the app, for example: "http://localhost:8000". */
+  baseURL: string;
+  /** The bearer token, or a function that returns the token of each request. */
+  token?: string | (() => string | undefined | Promise<string | undefined>);
+  /** The headers that are sent with each request. */
+  headers?: Record<string, string>;
+  /** The fetch implementation, default to the global fetch. */
+  fetch?: typeof fetch;
+}
+
+export interface ErrorResponse {
+  code?: string;
+  message: string;
+  detail?: string;
+  data?: any;
+}
+
+/** FastschemaError is thrown when a request does not succeed. */
+export class FastschemaError extends Error {
+  constructor(public status: number, public error: ErrorResponse) {
+    super(error.message);
+    this.name = "FastschemaError";
+  }
+}
+
+interface RequestOptions {
+  body?: unknown;
+  query?: Query;
+}
+
+`
+
+const tsRequestFunctions = `  const send = async (method: string, path: string, options: RequestOptions = {}): Promise<Response> => {
+    const search = new URLSearchParams();
+    for (const [key, value] of Object.entries(options.query ?? {})) {
+      if (value !== undefined) {
+        search.set(key, String(value));
+      }
+    }
+
+    const headers: Record<string, string> = { ...config.headers };
+    const token = typeof config.token === "function" ? await config.token() : config.token;
+    if (token) {
+      headers["Authorization"] = "Bearer " + token;
+    }
+
+    let body: BodyInit | undefined;
+    if (typeof options.body === "string" || options.body instanceof FormData || options.body instanceof Blob) {
+      body = options.body;
+    } else if (options.body !== undefined) {
+      headers["Content-Type"] = "application/json";
+      body = JSON.stringify(options.body);
+    }
+
+    const query = search.toString();
+    const url = config.baseURL.replace(/\/+$/, "") + path + (query ? "?" + query : "");
+    const response = await (config.fetch ?? fetch)(url, { method, headers, body });
+    if (!response.ok) {
+      const result = await response.json().catch(() => ({}));
+      throw new FastschemaError(response.status, result.error ?? { message: response.statusText });
+    }
+
+    return response;
+  };
+
+  const request = async <T>(method: string, path: string, options: RequestOptions = {}): Promise<T> => {
+    const response = await send(method, path, options);
+    const result = await response.json();
+    return result.data as T;
+  };
+`
@@ -0,0 +1,125 @@
+package codegen_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/codegen"
+	"github.com/fastschema/fastschema/pkg/utils"
+	cs "github.com/fastschema/fastschema/services/content"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type contentApp struct{}
+
+func (contentApp) DB() db.Client {
+	return nil
+}
+
+type tsLoginData struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+	Remember bool   `json:"remember,omitempty"`
+}
+
+type tsLoginResponse struct {
+	Token   string         `json:"token"`
+	User    *fs.User       `json:"user"`
+	Records []*tsRecord    `json:"records,omitempty"`
+	Extra   map[string]int `json:"extra"`
+	secret  string
+}
+
+type tsRecord struct {
+	Name   string         `json:"name"`
+	Entity *entity.Entity `json:"entity"`
+	Parent *tsRecord      `json:"parent,omitempty"`
+}
+
+func TestGenerateTSError(t *testing.T) {
+	_, err := codegen.GenerateTS(nil)
+	assert.Error(t, err)
+}
+
+func TestGenerateTS(t *testing.T) {
+	resources := fs.NewResourcesManager()
+	api := resources.Group("api")
+	cs.New(contentApp{}).CreateResource(api)
+	api.Group("auth").
+		Add(fs.Post("login", func(c fs.Context, data *tsLoginData) (*tsLoginResponse, error) {
+			return &tsLoginResponse{secret: data.Password}, nil
+		}, &fs.Meta{Public: true})).
+		Add(fs.Post("token/refresh", func(c fs.Context, _ any) (*fs.Map, error) {
+			return nil, nil
+		})).
+		Add(fs.Get("records", func(c fs.Context, _ any) ([]*tsRecord, error) {
+			return nil, nil
+		}, &fs.Meta{Args: fs.Args{"limit": {Type: fs.TypeUint, Description: "The limit"}}}))
+	api.Group("realtime").Add(fs.WS("content", func(c fs.Context, _ any) (any, error) {
+		return nil, nil
+	}))
+	require.NoError(t, resources.Init())
+
+	content := string(utils.Must(codegen.GenerateTS(&codegen.TSConfig{
+		SchemaBuilder: createSchemaBuilder(t),
+		Resources:     resources,
+	})))
+
+	assert.True(t, strings.HasPrefix(content, codegen.Header))
+
+	// Schemas, the junction schemas are skipped
+	assert.NotContains(t, content, "BlogPostsTags")
+	for _, expected := range []string{
+		`export type BlogPostStatus = "draft" | "published";`,
+		"export interface BlogPost {\n  id: number;\n  title: string;\n  meta?: any;\n  published: boolean;\n  views?: number | null;\n  status: BlogPostStatus;\n  tags?: Tag[];\n  author?: User;\n  author_id?: string | null;\n",
+		"export interface BlogPostInput {\n  id?: number;\n  title: string;\n  meta?: any;\n  published: boolean;\n  views?: number | null;\n  status?: BlogPostStatus;\n  tags?: Pick<Tag, \"id\">[];\n  author?: Pick<User, \"id\">;\n",
+		"export interface Tag {\n  id: string;\n  name: string;\n  posts?: BlogPost[];\n",
+		"export interface User {",
+		"  posts?: BlogPost[];\n",
+	} {
+		assert.Contains(t, content, expected)
+	}
+
+	// Resource types
+	for _, expected := range []string{
+		"export interface Pagination<T = Record<string, any>> {\n  total: number;",
+		"  items: T[];\n}",
+		"export interface TsLoginData {\n  login: string;\n  password: string;\n  remember?: boolean;\n}",
+		"export interface TsLoginResponse<T = Record<string, any>> {\n  token: string;\n  user: User;\n  records?: TsRecord<T>[];\n  extra: Record<string, number>;\n}",
+		"export interface TsRecord<T = Record<string, any>> {\n  name: string;\n  entity: T;\n  parent?: TsRecord<T>;\n}",
+	} {
+		assert.Contains(t, content, expected)
+	}
+
+	// Client functions
+	for _, expected := range []string{
+		`list: (query?: Query) => request<Pagination<BlogPost>>("GET", "/api/content/blog_post", { query }),`,
+		"detail: (id: ID, query?: Query) => request<BlogPost>(\"GET\", `/api/content/blog_post/${encodeURIComponent(id)}`, { query }),",
+		`create: (body: BlogPostInput, query?: Query) => request<BlogPost>("POST", "/api/content/blog_post", { body, query }),`,
+		`bulkCreate: (body: BlogPostInput[], query?: Query) => request<any[]>("POST", "/api/content/blog_post/bulk", { body, query }),`,
+		"update: (id: ID, body: Partial<BlogPostInput>, query?: Query) => request<BlogPost>(\"PUT\", `/api/content/blog_post/${encodeURIComponent(id)}`, { body, query }),",
+		`upsert: (body: BlogPostInput, query: Query & { conflict: string; update?: string }) => request<BlogPost>("PUT", "/api/content/blog_post/upsert", { body, query }),`,
+		`export: (query?: Query) => send("GET", "/api/content/blog_post/export", { query }),`,
+		`list: (query?: Query) => request<Pagination<Tag>>("GET", "/api/content/tag", { query }),`,
+		`list: (query?: Query) => request<Pagination<User>>("GET", "/api/content/user", { query }),`,
+		`login: (body: TsLoginData, query?: Query) => request<TsLoginResponse<Record<string, any>>>("POST", "/api/auth/login", { body, query }),`,
+		`tokenRefresh: (body?: unknown, query?: Query) => request<Record<string, any>>("POST", "/api/auth/token/refresh", { body, query }),`,
+		`records: (query?: Query & { limit?: number }) => request<TsRecord<Record<string, any>>[]>("GET", "/api/auth/records", { query }),`,
+	} {
+		assert.Contains(t, content, expected)
+	}
+
+	// The websocket resources and the content resources of the schema param are skipped
+	assert.NotContains(t, content, "realtime")
+	assert.NotContains(t, content, ":schema")
+
+	// The generated code is stable
+	assert.Equal(t, content, string(utils.Must(codegen.GenerateTS(&codegen.TSConfig{
+		SchemaBuilder: createSchemaBuilder(t),
+		Resources:     resources,
+	}))))
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"sort"
 
 	"entgo.io/ent/dialect"
@@ -34,6 +35,7 @@ type Adapter struct {
 	tables        []*entSchema.Table
 	edgeSpec      map[string]sqlgraph.EdgeSpec
 	typesModels   map[reflect.Type]*Model
+	replicas      *replicaSet
 }
 
 func (d *Adapter) SetSQLDB(db *sql.DB) {
@@ -49,11 +51,24 @@ func (d *Adapter) DB() *sql.DB {
 }
 
 func (d *Adapter) Hooks() *db.Hooks {
+	hooks := &db.Hooks{}
 	if d.config.Hooks != nil {
-		return d.config.Hooks()
+		hooks = d.config.Hooks()
 	}
 
-	return &db.Hooks{}
+	if d.config.Cache == nil {
+		return hooks
+	}
+
+	// The cache invalidation hooks run after the configured hooks.
+	// The configured hook slices are clipped so that they are never modified by the appends.
+	cacheHooks := db.CacheHooks(d.config.Cache)
+	withCacheHooks := *hooks
+	withCacheHooks.PostDBCreate = append(slices.Clip(hooks.PostDBCreate), cacheHooks.PostDBCreate...)
+	withCacheHooks.PostDBUpdate = append(slices.Clip(hooks.PostDBUpdate), cacheHooks.PostDBUpdate...)
+	withCacheHooks.PostDBDelete = append(slices.Clip(hooks.PostDBDelete), cacheHooks.PostDBDelete...)
+
+	return &withCacheHooks
 }
 
 func (d *Adapter) Config() *db.Config {
@@ -70,6 +85,21 @@ func (d *Adapter) Driver() dialect.Driver {
 	return d.driver
 }
 
+// ReadDriver returns the driver of a healthy read replica.
+// The primary driver is returned if there is no healthy replica
+// or the context forces the primary (see db.WithPrimary).
+func (d *Adapter) ReadDriver(ctx context.Context) dialect.Driver {
+	if d.replicas == nil || db.UsePrimary(ctx) {
+		return d.driver
+	}
+
+	if driver := d.replicas.driver(); driver != nil {
+		return driver
+	}
+
+	return d.driver
+}
+
 // Rollback rollbacks the transaction.
 func (d *Adapter) Rollback() error {
 	return nil
@@ -120,6 +150,10 @@ func (d *Adapter) Query(
 
 // Close closes the underlying driver.
 func (d *Adapter) Close() error {
+	if d.replicas != nil {
+		return errors.Join(d.driver.Close(), d.replicas.Close())
+	}
+
 	return d.driver.Close()
 }
 
@@ -441,6 +475,21 @@ func (d *Adapter) CreateModel(s *schema.Schema, relations ...*schema.Relation) *
 		}
 	}
 
+	tenantIndexes(s, m.entTable, entPrimaryColumn)
+
+	// add full-text search indexes, other dialects are handled by migrateSearch
+	if entDialect, _ := GetEntDialect(d.config); entDialect == dialect.MySQL {
+		for _, f := range s.SearchableFields() {
+			if entColumn, ok := m.entTable.Column(f.Name); ok {
+				m.entTable.Indexes = append(m.entTable.Indexes, &entSchema.Index{
+					Name:       searchIndexName(m.entTable.Name, f.Name),
+					Columns:    []*entSchema.Column{entColumn},
+					Annotation: &entsql.IndexAnnotation{Type: "FULLTEXT"},
+				})
+			}
+		}
+	}
+
 	// update junction model
 	if s.IsJunctionSchema {
 		if len(relations) == 0 {
@@ -0,0 +1,274 @@
+package entdbadapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+)
+
+// GroupBy sets the columns to group the aggregate query by.
+func (q *Query) GroupBy(columns ...string) db.Querier {
+	q.groupBy = append(q.groupBy, columns...)
+	return q
+}
+
+// Having adds predicates on the aggregation aliases of the aggregate query.
+func (q *Query) Having(predicates ...*db.Predicate) db.Querier {
+	q.having = append(q.having, predicates...)
+	return q
+}
+
+// Aggregate runs the given aggregations over the records that match the query.
+// It generates SQL like:
+//
+//	SELECT category, COUNT(*) AS count, SUM(price) AS sum_price
+//	FROM products WHERE ...
+//	GROUP BY category
+//	HAVING SUM(price) > ?
+//	ORDER BY SUM(price) DESC
+//
+// Group by columns and aggregated fields must be filterable,
+// ordering is allowed on sortable group by columns and on aggregation aliases.
+// The pre query hooks receive the group by columns and the aggregations before the query is built,
+// so the hooks can reject the fields that the request is not allowed to read.
+func (q *Query) Aggregate(ctx context.Context, aggregations ...*db.Aggregation) ([]*entity.Entity, error) {
+	if len(aggregations) == 0 {
+		return nil, errors.New("at least one aggregation is required")
+	}
+
+	entAdapter, ok := q.client.(EntAdapter)
+	if !ok {
+		return nil, errors.New("client is not an ent adapter")
+	}
+
+	option := q.Options()
+	option.Aggregations = aggregations
+
+	if err := runPreDBQueryHooks(ctx, q.client, option); err != nil {
+		return nil, err
+	}
+
+	if err := q.scope(ctx); err != nil {
+		return nil, err
+	}
+
+	builder := sql.Dialect(entAdapter.Driver().Dialect())
+	selector := builder.Select().From(builder.Table(q.model.schema.Namespace))
+
+	for _, columnName := range q.groupBy {
+		if _, err := q.aggregateColumn(columnName); err != nil {
+			return nil, err
+		}
+
+		selector.AppendSelect(selector.C(columnName))
+		selector.GroupBy(selector.C(columnName))
+	}
+
+	// aggregateExprs maps the aggregation aliases to their SQL expressions.
+	// The expressions are used in the HAVING and ORDER BY clauses instead of the aliases
+	// since Postgres does not allow referencing output column aliases in HAVING.
+	aggregateExprs := map[string]string{}
+	for _, aggregation := range aggregations {
+		if aggregation == nil {
+			continue
+		}
+
+		expr, err := q.aggregateExpr(selector, aggregation)
+		if err != nil {
+			return nil, err
+		}
+
+		if aggregation.Alias == "" {
+			return nil, fmt.Errorf("aggregation %s(%s) requires an alias", aggregation.Func, aggregation.Field)
+		}
+
+		if _, exists := aggregateExprs[aggregation.Alias]; exists || utils.Contains(q.groupBy, aggregation.Alias) {
+			return nil, fmt.Errorf("duplicate aggregation alias %q", aggregation.Alias)
+		}
+
+		aggregateExprs[aggregation.Alias] = expr
+		selector.AppendSelectAs(expr, aggregation.Alias)
+	}
+
+	if len(q.predicates) > 0 {
+		sqlPredicatesFn, err := createEntPredicates(entAdapter, q.model, q.predicates)
+		if err != nil {
+			return nil, err
+		}
+		selector.Where(sql.And(sqlPredicatesFn(selector)...))
+	}
+
+	if len(q.having) > 0 {
+		havingPredicates, err := createHavingPredicates(q.having, aggregateExprs)
+		if err != nil {
+			return nil, err
+		}
+		selector.Having(sql.And(havingPredicates...))
+	}
+
+	for _, order := range q.order {
+		orderFn := sql.Asc
+		columnName := order
+
+		if after, ok := strings.CutPrefix(order, "-"); ok {
+			columnName = after
+			orderFn = sql.Desc
+		}
+
+		if expr, ok := aggregateExprs[columnName]; ok {
+			selector.OrderBy(orderFn(expr))
+			continue
+		}
+
+		if !utils.Contains(q.groupBy, columnName) {
+			return nil, fmt.Errorf(`column %q must be a group by column or an aggregation alias`, columnName)
+		}
+
+		column, err := q.model.Column(columnName)
+		if err != nil {
+			return nil, err
+		}
+
+		if !column.field.Sortable {
+			return nil, fmt.Errorf(`column %q is not sortable`, columnName)
+		}
+
+		selector.OrderBy(orderFn(selector.C(columnName)))
+	}
+
+	if q.limit > 0 {
+		selector.Limit(int(q.limit))
+	}
+
+	if q.offset > 0 {
+		selector.Offset(int(q.offset))
+	}
+
+	query, args := selector.Query()
+	entities, err := driverQuery(entAdapter.ReadDriver(ctx), withSchema(ctx, q.model.name), query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return runPostDBQueryHooks(ctx, q.client, option, entities)
+}
+
+// aggregateColumn returns the column that can be used in an aggregate query.
+func (q *Query) aggregateColumn(columnName string) (*Column, error) {
+	column, err := q.model.Column(columnName)
+	if err != nil {
+		return nil, err
+	}
+
+	if column.field.Type.IsRelationType() {
+		return nil, fmt.Errorf(`column %q is a relation field and cannot be aggregated`, columnName)
+	}
+
+	if !column.field.Filterable {
+		return nil, fmt.Errorf(`column %q is not filterable`, columnName)
+	}
+
+	return column, nil
+}
+
+// aggregateExpr returns the SQL expression of the given aggregation.
+func (q *Query) aggregateExpr(selector *sql.Selector, aggregation *db.Aggregation) (string, error) {
+	if !aggregation.Func.Valid() {
+		return "", fmt.Errorf("invalid aggregate function %s", aggregation.Func)
+	}
+
+	if aggregation.Field == "" || aggregation.Field == "*" {
+		if aggregation.Func != db.AggregateCount {
+			return "", fmt.Errorf("aggregate function %s requires a field", aggregation.Func)
+		}
+
+		return sql.Count("*"), nil
+	}
+
+	column, err := q.aggregateColumn(aggregation.Field)
+	if err != nil {
+		return "", err
+	}
+
+	if (aggregation.Func == db.AggregateSum || aggregation.Func == db.AggregateAvg) && !column.field.Type.IsNumeric() {
+		return "", fmt.Errorf(
+			"aggregate function %s requires a numeric field, %q is %s",
+			aggregation.Func,
+			aggregation.Field,
+			column.field.Type,
+		)
+	}
+
+	c := selector.C(aggregation.Field)
+	switch aggregation.Func {
+	case db.AggregateCount:
+		return sql.Count(c), nil
+	case db.AggregateSum:
+		return sql.Sum(c), nil
+	case db.AggregateAvg:
+		return sql.Avg(c), nil
+	case db.AggregateMin:
+		return sql.Min(c), nil
+	default:
+		return sql.Max(c), nil
+	}
+}
+
+// createHavingPredicates creates the HAVING predicates from the given predicates.
+// The predicate fields are aggregation aliases that are replaced by their expressions.
+func createHavingPredicates(predicates []*db.Predicate, aggregateExprs map[string]string) ([]*sql.Predicate, error) {
+	sqlPredicates := []*sql.Predicate{}
+	for _, p := range predicates {
+		if p == nil {
+			continue
+		}
+
+		if p.Field == "" {
+			children, op := p.And, sql.And
+			if p.Or != nil {
+				children, op = p.Or, sql.Or
+			}
+
+			childPredicates, err := createHavingPredicates(children, aggregateExprs)
+			if err != nil {
+				return nil, err
+			}
+
+			sqlPredicates = append(sqlPredicates, op(childPredicates...))
+			continue
+		}
+
+		expr, ok := aggregateExprs[p.Field]
+		if !ok {
+			return nil, fmt.Errorf("%s is not an aggregation alias", p.Field)
+		}
+
+		if builder, ok := simplePredicateMap[p.Operator]; ok {
+			sqlPredicates = append(sqlPredicates, builder(expr, p.Value))
+			continue
+		}
+
+		switch p.Operator {
+		case db.OpIN, db.OpNIN:
+			arrayValue, err := validateArrayValue(p)
+			if err != nil {
+				return nil, err
+			}
+			op := utils.If(p.Operator == db.OpIN, sql.In, sql.NotIn)
+			sqlPredicates = append(sqlPredicates, op(expr, arrayValue...))
+		case db.OpNULL:
+			op := utils.If(p.Value == true, sql.IsNull, sql.NotNull)
+			sqlPredicates = append(sqlPredicates, op(expr))
+		default:
+			return nil, fmt.Errorf("operator %s is not supported in having", p.Operator)
+		}
+	}
+
+	return sqlPredicates, nil
+}
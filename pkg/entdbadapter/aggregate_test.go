@@ -0,0 +1,204 @@
+package entdbadapter
+
+import (
+	"context"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	dialectSql "entgo.io/ent/dialect/sql"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTestAggregateData struct {
+	Name         string
+	Schema       string
+	Dialect      string
+	Predicates   []*db.Predicate
+	GroupBy      []string
+	Having       []*db.Predicate
+	Order        []string
+	Limit        uint
+	Offset       uint
+	Aggregations []*db.Aggregation
+	Expect       func(sqlmock.Sqlmock)
+	ExpectError  string
+	ExpectResult []*entity.Entity
+}
+
+func TestAggregate(t *testing.T) {
+	tests := []MockTestAggregateData{
+		{
+			Name:         "Aggregate_without_aggregations",
+			Schema:       "card",
+			ExpectError:  "at least one aggregation is required",
+			Aggregations: []*db.Aggregation{},
+		},
+		{
+			Name:         "Aggregate_invalid_function",
+			Schema:       "card",
+			Aggregations: []*db.Aggregation{{Func: db.AggregateInvalid, Field: "balance", Alias: "x"}},
+			ExpectError:  "invalid aggregate function invalid",
+		},
+		{
+			Name:         "Aggregate_sum_without_field",
+			Schema:       "card",
+			Aggregations: []*db.Aggregation{{Func: db.AggregateSum, Alias: "x"}},
+			ExpectError:  "aggregate function sum requires a field",
+		},
+		{
+			Name:         "Aggregate_sum_non_numeric_field",
+			Schema:       "card",
+			Aggregations: []*db.Aggregation{db.AggSum("number")},
+			ExpectError:  `aggregate function sum requires a numeric field, "number" is string`,
+		},
+		{
+			Name:         "Aggregate_relation_field",
+			Schema:       "card",
+			Aggregations: []*db.Aggregation{db.AggCount("owner")},
+			ExpectError:  `column "owner" is a relation field and cannot be aggregated`,
+		},
+		{
+			Name:         "Aggregate_non_filterable_field",
+			Schema:       "user",
+			Aggregations: []*db.Aggregation{db.AggMax("bio")},
+			ExpectError:  `column "bio" is not filterable`,
+		},
+		{
+			Name:         "Aggregate_group_by_non_filterable_field",
+			Schema:       "user",
+			GroupBy:      []string{"bio"},
+			Aggregations: []*db.Aggregation{db.AggCount("")},
+			ExpectError:  `column "bio" is not filterable`,
+		},
+		{
+			Name:         "Aggregate_missing_alias",
+			Schema:       "card",
+			Aggregations: []*db.Aggregation{{Func: db.AggregateCount}},
+			ExpectError:  "aggregation count() requires an alias",
+		},
+		{
+			Name:         "Aggregate_duplicate_alias",
+			Schema:       "card",
+			GroupBy:      []string{"active"},
+			Aggregations: []*db.Aggregation{db.AggCount("", "active")},
+			ExpectError:  `duplicate aggregation alias "active"`,
+		},
+		{
+			Name:         "Aggregate_having_invalid_alias",
+			Schema:       "card",
+			Having:       []*db.Predicate{db.GT("total", 1)},
+			Aggregations: []*db.Aggregation{db.AggCount("")},
+			ExpectError:  "total is not an aggregation alias",
+		},
+		{
+			Name:         "Aggregate_having_invalid_operator",
+			Schema:       "card",
+			Having:       []*db.Predicate{db.Like("count", "1%")},
+			Aggregations: []*db.Aggregation{db.AggCount("")},
+			ExpectError:  "operator $like is not supported in having",
+		},
+		{
+			Name:         "Aggregate_order_by_non_group_column",
+			Schema:       "card",
+			Order:        []string{"number"},
+			Aggregations: []*db.Aggregation{db.AggCount("")},
+			ExpectError:  `column "number" must be a group by column or an aggregation alias`,
+		},
+		{
+			Name:         "Aggregate_without_group",
+			Schema:       "card",
+			Predicates:   []*db.Predicate{db.EQ("number", "123")},
+			Aggregations: []*db.Aggregation{db.AggCount(""), db.AggSum("balance"), db.AggAvg("balance", "average")},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery("SELECT COUNT(*) AS `count`, SUM(`cards`.`balance`) AS `sum_balance`, AVG(`cards`.`balance`) AS `average` FROM `cards` WHERE `cards`.`number` = ?")).
+					WithArgs("123").
+					WillReturnRows(mock.NewRows([]string{"count", "sum_balance", "average"}).AddRow(2, 30, 15))
+			},
+			ExpectResult: []*entity.Entity{
+				entity.New().Set("count", int64(2)).Set("sum_balance", int64(30)).Set("average", int64(15)),
+			},
+		},
+		{
+			Name:         "Aggregate_with_group_having_order",
+			Schema:       "card",
+			GroupBy:      []string{"active"},
+			Having:       []*db.Predicate{db.Or(db.GT("total", 10), db.In("count", []any{1, 2}), db.Null("max_expiry", false))},
+			Order:        []string{"-total", "active"},
+			Limit:        5,
+			Offset:       10,
+			Aggregations: []*db.Aggregation{db.AggCount(""), db.AggSum("balance", "total"), db.AggMax("expiry_date", "max_expiry")},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery("SELECT `cards`.`active`, COUNT(*) AS `count`, SUM(`cards`.`balance`) AS `total`, MAX(`cards`.`expiry_date`) AS `max_expiry` FROM `cards` GROUP BY `cards`.`active` HAVING SUM(`cards`.`balance`) > ? OR COUNT(*) IN (?, ?) OR MAX(`cards`.`expiry_date`) IS NOT NULL ORDER BY SUM(`cards`.`balance`) DESC, `cards`.`active` ASC LIMIT 5 OFFSET 10")).
+					WithArgs(10, 1, 2).
+					WillReturnRows(mock.NewRows([]string{"active", "count", "total"}).AddRow(true, 2, 30))
+			},
+			ExpectResult: []*entity.Entity{
+				entity.New().Set("active", true).Set("count", int64(2)).Set("total", int64(30)),
+			},
+		},
+		{
+			Name:         "Aggregate_postgres",
+			Schema:       "card",
+			Dialect:      dialect.Postgres,
+			Predicates:   []*db.Predicate{db.GT("balance", 1)},
+			GroupBy:      []string{"active"},
+			Having:       []*db.Predicate{db.GTE("min_balance", 2)},
+			Order:        []string{"-min_balance"},
+			Aggregations: []*db.Aggregation{db.AggMin("balance")},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery(`SELECT "cards"."active", MIN("cards"."balance") AS "min_balance" FROM "cards" WHERE "cards"."balance" > $1 GROUP BY "cards"."active" HAVING MIN("cards"."balance") >= $2 ORDER BY MIN("cards"."balance") DESC`)).
+					WithArgs(1, 2).
+					WillReturnRows(mock.NewRows([]string{"active", "min_balance"}).AddRow(false, 5))
+			},
+			ExpectResult: []*entity.Entity{
+				entity.New().Set("active", false).Set("min_balance", int64(5)),
+			},
+		},
+	}
+
+	sb := createSchemaBuilder()
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			dialectName := utils.If(tt.Dialect == "", dialect.MySQL, tt.Dialect)
+			client := utils.Must(NewEntClient(&db.Config{
+				Driver: "sqlmock",
+			}, sb, dialectSql.OpenDB(dialectName, mockDB)))
+
+			if tt.Expect != nil {
+				tt.Expect(mock)
+			}
+
+			model := utils.Must(client.Model(tt.Schema))
+			result, err := model.Query(tt.Predicates...).
+				GroupBy(tt.GroupBy...).
+				Having(tt.Having...).
+				Order(tt.Order...).
+				Limit(tt.Limit).
+				Offset(tt.Offset).
+				Aggregate(context.Background(), tt.Aggregations...)
+
+			if tt.ExpectError != "" {
+				assert.ErrorContains(t, err, tt.ExpectError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.ExpectResult, result)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAggregateClientIsNotEntAdapter(t *testing.T) {
+	q := &Query{}
+	_, err := q.Aggregate(context.Background(), db.AggCount(""))
+	assert.EqualError(t, err, "client is not an ent adapter")
+}
@@ -0,0 +1,133 @@
+package entdbadapter
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryCache(t *testing.T) {
+	ctx := context.Background()
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	sb := createQuantifierSchemaBuilder(t)
+	utils.Must(sb.Schema("post")).Settings = &schema.SchemaSettings{
+		Cache: &schema.SchemaCacheSettings{TTL: 60},
+	}
+
+	postQueryHookCalls := 0
+	cache := db.NewLRUCache(10)
+	client, err := NewClient(&db.Config{
+		Driver:       "sqlite",
+		Name:         ":memory:_" + utils.RandomString(10),
+		MigrationDir: migrationDir,
+		Cache:        cache,
+		Hooks: func() *db.Hooks {
+			return &db.Hooks{
+				PostDBQuery: []db.PostDBQuery{
+					func(
+						ctx context.Context,
+						option *db.QueryOption,
+						entities []*entity.Entity,
+					) ([]*entity.Entity, error) {
+						if option.Schema.Name == "post" {
+							postQueryHookCalls++
+						}
+						return entities, nil
+					},
+				},
+			}
+		},
+	}, sb)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	postModel := utils.Must(client.Model("post"))
+	commentModel := utils.Must(client.Model("comment"))
+	tagModel := utils.Must(client.Model("tag"))
+	_ = utils.Must(postModel.CreateFromJSON(ctx, `{"title": "post 1"}`))
+	_ = utils.Must(tagModel.CreateFromJSON(ctx, `{"name": "tag 1"}`))
+
+	titles := func(predicates ...*db.Predicate) []string {
+		posts, err := postModel.Query(predicates...).Select("title", "comments.content").Order("id").Get(ctx)
+		require.NoError(t, err)
+		return utils.Map(posts, func(e *entity.Entity) string {
+			comments := utils.Map(e.Get("comments", []*entity.Entity{}).([]*entity.Entity), func(c *entity.Entity) string {
+				return " (" + c.GetString("content") + ")"
+			})
+			return e.GetString("title") + strings.Join(comments, "")
+		})
+	}
+
+	// The results are cached and the post query hooks still run on the cached results
+	assert.Equal(t, []string{"post 1"}, titles())
+	assert.Equal(t, 1, cache.Len())
+	_ = utils.Must(client.Exec(ctx, "INSERT INTO posts (title) VALUES ('post 2')"))
+	assert.Equal(t, []string{"post 1"}, titles())
+	assert.Equal(t, 2, postQueryHookCalls)
+
+	// A different query is cached separately
+	assert.Equal(t, []string{"post 2"}, titles(db.EQ("title", "post 2")))
+	assert.Equal(t, 2, cache.Len())
+
+	// The results of a schema without cache TTL are not cached
+	_ = utils.Must(tagModel.Query().Get(ctx))
+	assert.Equal(t, 2, cache.Len())
+
+	// Creating a post invalidates the post results
+	_ = utils.Must(postModel.CreateFromJSON(ctx, `{"title": "post 3"}`))
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, []string{"post 1", "post 2", "post 3"}, titles())
+
+	// Mutating the m2m related schema invalidates the post results
+	_ = utils.Must(tagModel.CreateFromJSON(ctx, `{"name": "tag 2"}`))
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, []string{"post 1", "post 2", "post 3"}, titles())
+	assert.Equal(t, 1, cache.Len())
+
+	// Mutating the o2m related schema invalidates the post results
+	_ = utils.Must(commentModel.CreateFromJSON(ctx, `{"content": "comment", "approved": true, "post": {"id": 1}}`))
+	assert.Equal(t, []string{"post 1 (comment)", "post 2", "post 3"}, titles())
+	_ = utils.Must(commentModel.Mutation().Where(db.EQ("id", 1)).Update(ctx, entity.New().Set("content", "updated")))
+	assert.Equal(t, []string{"post 1 (updated)", "post 2", "post 3"}, titles())
+	_ = utils.Must(commentModel.Mutation().Where(db.EQ("id", 1)).Delete(ctx))
+	assert.Equal(t, []string{"post 1", "post 2", "post 3"}, titles())
+
+	// Updating and deleting posts invalidate the post results
+	_ = utils.Must(postModel.Mutation().Where(db.EQ("id", 2)).Update(ctx, entity.New().Set("title", "post 2 updated")))
+	assert.Equal(t, []string{"post 1", "post 2 updated", "post 3"}, titles())
+	_ = utils.Must(postModel.Mutation().Where(db.EQ("id", 3)).Delete(ctx))
+	assert.Equal(t, []string{"post 1", "post 2 updated"}, titles())
+
+	// The queries in transactions do not use the cache
+	tx := utils.Must(client.Tx(ctx))
+	_ = utils.Must(tx.Exec(ctx, "INSERT INTO posts (title) VALUES ('post 4')"))
+	posts := utils.Must(utils.Must(tx.Model("post")).Query().Order("id").Get(ctx))
+	assert.Len(t, posts, 3)
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, []string{"post 1", "post 2 updated"}, titles())
+
+	// The mutations in transactions invalidate the results after the commit
+	assert.Equal(t, 1, cache.Len())
+	tx = utils.Must(client.Tx(ctx))
+	_ = utils.Must(utils.Must(tx.Model("post")).Mutation().Where(db.EQ("id", 1)).Update(ctx, entity.New().Set("title", "post 1 updated")))
+	assert.Equal(t, 1, cache.Len())
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, []string{"post 1 updated", "post 2 updated", "post 4"}, titles())
+
+	// The invalidation is discarded if the transaction is rolled back
+	tx = utils.Must(client.Tx(ctx))
+	_ = utils.Must(utils.Must(tx.Model("post")).Mutation().Where(db.EQ("id", 1)).Update(ctx, entity.New().Set("title", "post 1")))
+	require.NoError(t, tx.Rollback())
+	assert.Equal(t, 1, cache.Len())
+}
@@ -25,9 +25,16 @@ import (
 	"github.com/fastschema/fastschema/pkg/utils"
 	"github.com/fastschema/fastschema/schema"
 	"github.com/google/uuid"
+	"github.com/ncruces/go-sqlite3"
+	sqliteRegexp "github.com/ncruces/go-sqlite3/ext/regexp"
 	_ "github.com/ncruces/go-sqlite3/vfs/memdb"
 )
 
+func init() {
+	// SQLite has no built-in REGEXP function, register it for every connection to support $regex.
+	sqlite3.AutoExtension(sqliteRegexp.Register)
+}
+
 // RelMaps map the relation type to the ent relation type
 var RelMaps = map[schema.RelationType]sqlgraph.Rel{
 	schema.O2O: sqlgraph.O2O,
@@ -160,19 +167,10 @@ func CreateDebugFN(config *db.Config) func(ctx context.Context, args ...any) {
 	return func(ctx context.Context, args ...any) {
 		msg := fmt.Sprintf("%v", args)
 		args = []any{msg}
-		traceable, ok := ctx.(fs.Traceable)
-		if ok {
-			if traceID := traceable.TraceID(); traceID != "" {
-				args = append(args, map[string]any{
-					fs.TraceID: traceID,
-				})
-			}
-		} else {
-			if traceID := ctx.Value(fs.ContextKeyTraceID); traceID != nil {
-				args = append(args, map[string]any{
-					fs.TraceID: traceID,
-				})
-			}
+		if traceID := contextTraceID(ctx); traceID != "" {
+			args = append(args, map[string]any{
+				fs.TraceID: traceID,
+			})
 		}
 
 		if config.Logger != nil {
@@ -6,18 +6,47 @@ import (
 	"fmt"
 
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/fastschema/fastschema/db"
 	"github.com/fastschema/fastschema/entity"
 	"github.com/fastschema/fastschema/expr"
 	"github.com/fastschema/fastschema/schema"
 	"github.com/google/uuid"
 )
 
+const (
+	// createManyBatchSize is the maximum number of rows inserted by a CreateMany statement.
+	createManyBatchSize = 1000
+	// createManyMaxParams is the maximum number of parameters of a CreateMany statement,
+	// it is the lowest limit of the supported databases (SQLite).
+	createManyMaxParams = 32766
+)
+
 // Create creates a new entity in the database
 func (m *Mutation) Create(ctx context.Context, e *entity.Entity) (_ any, err error) {
 	if m.model == nil || m.model.schema == nil {
 		return nil, fmt.Errorf("model or schema %s not found", m.model.name)
 	}
 
+	// The entity and its nested records are created in a single transaction,
+	// which is rolled back if the entity does not match the row filter of the context.
+	if m.client != nil && !m.client.IsTx() &&
+		(hasNestedWrites(m.model.schema, e) || len(db.RowFilterFromContext(ctx, m.model.schema.Name)) > 0) {
+		var id any
+		if err := m.withTx(ctx, func(ctx context.Context, mutation db.Mutator) (err error) {
+			id, err = mutation.Create(ctx, e)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+
+		return id, nil
+	}
+
+	// The values are validated as given, before the setters transform them
+	if err := m.model.schema.ValidateEntity(e); err != nil {
+		return nil, err
+	}
+
 	if err := m.model.schema.ApplySetters(ctx, e, expr.Config{
 		DB: func() expr.DBLike {
 			return m.client
@@ -26,15 +55,214 @@ func (m *Mutation) Create(ctx context.Context, e *entity.Entity) (_ any, err err
 		return nil, err
 	}
 
+	nestedWrites, err := m.parseNestedWrites(e)
+	if err != nil {
+		return nil, err
+	}
+
+	stripNestedWrites(e, nestedWrites)
+
 	if err := runPreDBCreateHooks(ctx, m.client, m.model.schema, e); err != nil {
 		return nil, err
 	}
 
+	if err := m.model.schema.ApplyValidators(ctx, e, nil, expr.Config{
+		DB: func() expr.DBLike {
+			return m.client
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := setTenant(ctx, m.model.schema, e); err != nil {
+		return nil, err
+	}
+
 	// Auto-generate UUID v7 for primary key if not provided
 	if err := m.autoGenerateUUID(e); err != nil {
 		return nil, err
 	}
 
+	entAdapter, ok := m.client.(EntAdapter)
+	if !ok {
+		return nil, errors.New("client is not an ent adapter")
+	}
+
+	if err := m.runNestedWritesBeforeCreate(ctx, e, nestedWrites); err != nil {
+		return nil, err
+	}
+
+	createSpec, err := m.createSpec(entAdapter, e)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = sqlgraph.CreateNode(withSchema(ctx, m.model.name), entAdapter.Driver(), createSpec); err != nil {
+		return nil, err
+	}
+
+	insertedID, err := m.setCreatedID(createSpec, e)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.runNestedWritesAfterCreate(ctx, e, nestedWrites); err != nil {
+		return nil, err
+	}
+
+	if err := m.checkRowFilter(ctx, m.client, []any{insertedID}); err != nil {
+		return nil, err
+	}
+
+	if m.autoCommit {
+		if err = m.client.Commit(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := runPostDBCreateHooks(ctx, m.client, m.model.schema, e, insertedID); err != nil {
+		return nil, err
+	}
+
+	return insertedID, nil
+}
+
+// CreateMany creates the entities in the database using batched multi-row inserts
+// and returns the created IDs in the same order as the entities.
+//
+//	All the batches are inserted in a single transaction, if the mutation client is not
+//	a transaction, a new transaction is started and committed after all the batches are inserted.
+//	The PreDBCreate hooks run for each entity before inserting,
+//	the PostDBCreate hooks run for each entity after all the entities are created, in the same transaction.
+func (m *Mutation) CreateMany(ctx context.Context, entities []*entity.Entity) (_ []any, err error) {
+	if m.model == nil || m.model.schema == nil {
+		return nil, fmt.Errorf("model or schema %s not found", m.model.name)
+	}
+
+	if _, ok := m.client.(EntAdapter); !ok {
+		return nil, errors.New("client is not an ent adapter")
+	}
+
+	if len(entities) == 0 {
+		return []any{}, nil
+	}
+
+	var ids []any
+	create := func(client db.Client) (err error) {
+		if ids, err = m.batchCreate(ctx, client, entities); err != nil {
+			return err
+		}
+
+		for i, e := range entities {
+			if err := runPostDBCreateHooks(ctx, client, m.model.schema, e, ids[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if m.client.IsTx() {
+		err = create(m.client)
+	} else {
+		err = db.WithTx(m.client, ctx, create)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if m.autoCommit {
+		if err = m.client.Commit(); err != nil {
+			return nil, err
+		}
+	}
+
+	return ids, nil
+}
+
+// batchCreate inserts the entities using the given transaction client.
+// The batch size is limited by the number of query parameters of a statement.
+func (m *Mutation) batchCreate(ctx context.Context, client db.Client, entities []*entity.Entity) ([]any, error) {
+	entAdapter, ok := client.(EntAdapter)
+	if !ok {
+		return nil, errors.New("client is not an ent adapter")
+	}
+
+	createSpecs := make([]*sqlgraph.CreateSpec, len(entities))
+	for i, e := range entities {
+		if e == nil {
+			return nil, fmt.Errorf("entity %d is nil", i)
+		}
+
+		if err := m.model.schema.ValidateEntity(e); err != nil {
+			return nil, fmt.Errorf("entity %d: %w", i, err)
+		}
+
+		if err := m.model.schema.ApplySetters(ctx, e, expr.Config{
+			DB: func() expr.DBLike {
+				return client
+			},
+		}); err != nil {
+			return nil, fmt.Errorf("entity %d: %w", i, err)
+		}
+
+		if err := runPreDBCreateHooks(ctx, client, m.model.schema, e); err != nil {
+			return nil, fmt.Errorf("entity %d: %w", i, err)
+		}
+
+		if err := m.model.schema.ApplyValidators(ctx, e, nil, expr.Config{
+			DB: func() expr.DBLike {
+				return client
+			},
+		}); err != nil {
+			return nil, fmt.Errorf("entity %d: %w", i, err)
+		}
+
+		if err := setTenant(ctx, m.model.schema, e); err != nil {
+			return nil, fmt.Errorf("entity %d: %w", i, err)
+		}
+
+		if err := m.autoGenerateUUID(e); err != nil {
+			return nil, fmt.Errorf("entity %d: %w", i, err)
+		}
+
+		createSpec, err := m.createSpec(entAdapter, e)
+		if err != nil {
+			return nil, fmt.Errorf("entity %d: %w", i, err)
+		}
+
+		createSpecs[i] = createSpec
+	}
+
+	batchSize := max(1, min(createManyBatchSize, createManyMaxParams/(len(m.model.columns)+1)))
+	for start := 0; start < len(createSpecs); start += batchSize {
+		end := min(start+batchSize, len(createSpecs))
+		if err := sqlgraph.BatchCreate(withSchema(ctx, m.model.name), entAdapter.Driver(), &sqlgraph.BatchCreateSpec{
+			Nodes: createSpecs[start:end],
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make([]any, len(entities))
+	for i, e := range entities {
+		id, err := m.setCreatedID(createSpecs[i], e)
+		if err != nil {
+			return nil, err
+		}
+
+		ids[i] = id
+	}
+
+	if err := m.checkRowFilter(ctx, client, ids); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// createSpec creates the ent create spec of the entity.
+func (m *Mutation) createSpec(entAdapter EntAdapter, e *entity.Entity) (*sqlgraph.CreateSpec, error) {
 	createSpec := &sqlgraph.CreateSpec{
 		Table: m.model.schema.Namespace,
 		ID: &sqlgraph.FieldSpec{
@@ -54,17 +282,11 @@ func (m *Mutation) Create(ctx context.Context, e *entity.Entity) (_ any, err err
 		}
 	}
 
-	entAdapter, ok := m.client.(EntAdapter)
-	if !ok {
-		return nil, errors.New("client is not an ent adapter")
-	}
-
-	var c *Column
 	for pair := e.First(); pair != nil; pair = pair.Next() {
 		fieldName := pair.Key
 		fieldValue := pair.Value
 
-		c, err = m.model.Column(fieldName)
+		c, err := m.model.Column(fieldName)
 		if err != nil {
 			return nil, fmt.Errorf("column error: %w", err)
 		}
@@ -100,10 +322,12 @@ func (m *Mutation) Create(ctx context.Context, e *entity.Entity) (_ any, err err
 		}
 	}
 
-	if err = sqlgraph.CreateNode(ctx, entAdapter.Driver(), createSpec); err != nil {
-		return nil, err
-	}
+	return createSpec, nil
+}
 
+// setCreatedID sets the ID returned by the create spec to the entity.
+func (m *Mutation) setCreatedID(createSpec *sqlgraph.CreateSpec, e *entity.Entity) (any, error) {
+	pkField := m.model.schema.PrimaryField()
 	insertedID := createSpec.ID.Value
 	if insertedID == nil && pkField != nil {
 		insertedID = e.Get(pkField.Name)
@@ -134,16 +358,6 @@ func (m *Mutation) Create(ctx context.Context, e *entity.Entity) (_ any, err err
 		return nil, err
 	}
 
-	if m.autoCommit {
-		if err = m.client.Commit(); err != nil {
-			return nil, err
-		}
-	}
-
-	if err := runPostDBCreateHooks(ctx, m.client, m.model.schema, e, insertedID); err != nil {
-		return nil, err
-	}
-
 	return insertedID, nil
 }
 
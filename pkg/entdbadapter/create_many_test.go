@@ -0,0 +1,228 @@
+package entdbadapter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	dialectSql "entgo.io/ent/dialect/sql"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateManyError(t *testing.T) {
+	mut := &Mutation{
+		model: &Model{name: "user"},
+	}
+	_, err := mut.CreateMany(context.Background(), nil)
+	assert.Equal(t, errors.New("model or schema user not found"), err)
+}
+
+func TestCreateManyClientIsNotEntClient(t *testing.T) {
+	mut := &Mutation{
+		model: &Model{
+			name:   "user",
+			schema: &schema.Schema{},
+		},
+		client: nil,
+	}
+	_, err := mut.CreateMany(context.Background(), []*entity.Entity{entity.New()})
+	assert.Equal(t, errors.New("client is not an ent adapter"), err)
+}
+
+func TestCreateManyNodes(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Dialect     string
+		InputJSON   []string
+		Expect      func(sqlmock.Sqlmock)
+		ExpectIDs   []any
+		ExpectError string
+	}{
+		{
+			Name:      "CreateMany_empty",
+			InputJSON: []string{},
+			Expect:    func(mock sqlmock.Sqlmock) {},
+			ExpectIDs: []any{},
+		},
+		{
+			Name:        "CreateMany_invalid_column",
+			InputJSON:   []string{`{"sku": "a", "name": "A"}`, `{"sku": "b", "invalid": "B"}`},
+			ExpectError: "entity 1: column error: column product.invalid not found",
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+		},
+		{
+			Name:      "CreateMany_mysql",
+			InputJSON: []string{`{"sku": "a", "name": "A", "stock": 1}`, `{"sku": "b", "name": "B", "stock": 2}`},
+			ExpectIDs: []any{uint64(5), uint64(6)},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(utils.EscapeQuery("INSERT INTO `products` (`name`, `sku`, `stock`) VALUES (?, ?, ?), (?, ?, ?)")).
+					WithArgs("A", "a", float64(1), "B", "b", float64(2)).
+					WillReturnResult(sqlmock.NewResult(5, 2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			Name:      "CreateMany_postgres",
+			Dialect:   dialect.Postgres,
+			InputJSON: []string{`{"sku": "a", "name": "A"}`, `{"sku": "b", "name": "B"}`},
+			ExpectIDs: []any{uint64(1), uint64(2)},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(utils.EscapeQuery(`INSERT INTO "products" ("name", "sku") VALUES ($1, $2), ($3, $4) RETURNING "id"`)).
+					WithArgs("A", "a", "B", "b").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			dialectName := utils.If(tt.Dialect == "", dialect.MySQL, tt.Dialect)
+			client := utils.Must(NewEntClient(&db.Config{
+				Driver: "sqlmock",
+			}, createUpsertSchemaBuilder(t), dialectSql.OpenDB(dialectName, mockDB)))
+
+			tt.Expect(mock)
+			entities := utils.Map(tt.InputJSON, func(json string) *entity.Entity {
+				return utils.Must(entity.NewEntityFromJSON(json))
+			})
+
+			model := utils.Must(client.Model("product"))
+			ids, err := model.Mutation().CreateMany(context.Background(), entities)
+			if tt.ExpectError != "" {
+				assert.ErrorContains(t, err, tt.ExpectError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.ExpectIDs, ids)
+				for i, e := range entities {
+					assert.Equal(t, tt.ExpectIDs[i], e.ID())
+				}
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCreateManySQLite(t *testing.T) {
+	ctx := context.Background()
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	calls := []string{}
+	client, err := NewTestClient(migrationDir, createUpsertSchemaBuilder(t), func() *db.Hooks {
+		return &db.Hooks{
+			PreDBCreate: []db.PreDBCreate{func(ctx context.Context, schema *schema.Schema, e *entity.Entity) error {
+				calls = append(calls, "pre_create:"+e.GetString("sku"))
+				return nil
+			}},
+			PostDBCreate: []db.PostDBCreate{func(
+				ctx context.Context,
+				schema *schema.Schema,
+				e *entity.Entity,
+				id any,
+			) error {
+				calls = append(calls, "post_create:"+e.GetString("sku"))
+				assert.Equal(t, e.ID(), id)
+
+				// The post create hooks run in the transaction of the batch
+				tx := db.TxFromContext(ctx, nil)
+				require.NotNil(t, tx)
+				assert.True(t, tx.IsTx())
+				if e.GetString("sku") == "hook_error" {
+					return errors.New("post create hook error")
+				}
+				return nil
+			}},
+		}
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	model := utils.Must(client.Model("product"))
+	createMany := func(client db.Client, jsons ...string) ([]any, error) {
+		return utils.Must(client.Model("product")).CreateMany(ctx, utils.Map(jsons, func(json string) *entity.Entity {
+			return utils.Must(entity.NewEntityFromJSON(json))
+		}))
+	}
+
+	ids, err := createMany(client, `{"sku": "a", "name": "A"}`, `{"sku": "b", "name": "B", "stock": 2}`)
+	require.NoError(t, err)
+	assert.Equal(t, []any{uint64(1), uint64(2)}, ids)
+	assert.Equal(t, []string{"pre_create:a", "pre_create:b", "post_create:a", "post_create:b"}, calls)
+
+	// A failed entity rolls back the whole batch
+	_, err = createMany(client, `{"sku": "c", "name": "C"}`, `{"sku": "a", "name": "A2"}`)
+	assert.ErrorContains(t, err, "UNIQUE constraint failed")
+	assert.Equal(t, 2, utils.Must(model.Query().Count(ctx, nil)))
+
+	// A failed post create hook rolls back the whole batch
+	_, err = createMany(client, `{"sku": "c", "name": "C"}`, `{"sku": "hook_error", "name": "E"}`)
+	assert.ErrorContains(t, err, "post create hook error")
+	assert.Equal(t, 2, utils.Must(model.Query().Count(ctx, nil)))
+
+	// The entities are created within the existing transaction
+	tx := utils.Must(client.Tx(ctx))
+	_, err = createMany(tx, `{"sku": "c", "name": "C"}`, `{"sku": "d", "name": "D"}`)
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+	assert.Equal(t, 2, utils.Must(model.Query().Count(ctx, nil)))
+
+	tx = utils.Must(client.Tx(ctx))
+	_, err = createMany(tx, `{"sku": "c", "name": "C"}`, `{"sku": "d", "name": "D"}`)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	products := utils.Must(model.Query().Order("id").Get(ctx))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, utils.Map(products, func(e *entity.Entity) string {
+		return e.GetString("sku")
+	}))
+}
+
+func TestCreateManyRelations(t *testing.T) {
+	ctx := context.Background()
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	client, err := NewTestClient(migrationDir, createSchemaBuilder())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	userModel := utils.Must(client.Model("user"))
+	userIDs := []any{}
+	for _, name := range []string{"User 1", "User 2"} {
+		userIDs = append(userIDs, utils.Must(userModel.CreateFromJSON(ctx, `{"name": "`+name+`"}`)))
+	}
+
+	groupModel := utils.Must(client.Model("group"))
+	ids, err := groupModel.CreateMany(ctx, []*entity.Entity{
+		entity.New().Set("name", "Group 1").Set("users", []*entity.Entity{entity.New(userIDs[0])}),
+		entity.New().Set("name", "Group 2").Set("users", []*entity.Entity{
+			entity.New(userIDs[0]),
+			entity.New(userIDs[1]),
+		}),
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+
+	groups := utils.Must(groupModel.Query().Select("id", "users.id").Order("id").Get(ctx))
+	require.Len(t, groups, 2)
+	assert.Len(t, groups[0].Get("users"), 1)
+	assert.Len(t, groups[1].Get("users"), 2)
+}
@@ -76,7 +76,7 @@ func NewEntClient(
 		sqlDriver = dialectSql.OpenDB(entDialect, db)
 	}
 
-	dialectDriver = sqlDriver
+	dialectDriver = newTimedDriver(sqlDriver, config)
 	adapter, err := NewDBAdapter(config, schemaBuilder)
 	if err != nil {
 		return nil, err
@@ -87,10 +87,6 @@ func NewEntClient(
 		return nil, errors.New("invalid adapter")
 	}
 
-	if config.LogQueries {
-		dialectDriver = dialect.DebugWithContext(sqlDriver, CreateDebugFN(config))
-	}
-
 	entAdapter.SetSQLDB(db)
 	entAdapter.SetDriver(dialectDriver)
 	if config.Driver == "sqlmock" {
@@ -108,6 +104,13 @@ func NewEntClient(
 		}
 	}
 
+	// The replicas are opened after the migrations, they receive the schema changes from the primary
+	if len(config.Replicas) > 0 {
+		if adapter.(*Adapter).replicas, err = newReplicaSet(config); err != nil {
+			return nil, err
+		}
+	}
+
 	return adapter, nil
 }
 
@@ -13,13 +13,22 @@ import (
 )
 
 // Delete deletes entities from the database
+//
+//	If soft deletes are enabled, the entities and their m2m junction rows are moved to the trash
+//	unless the context is created with db.WithForceDelete.
 func (m *Mutation) Delete(ctx context.Context) (affected int, err error) {
 	var hooks = &db.Hooks{}
 	var originalEntities []*entity.Entity
+	softDelete := m.client != nil && m.client.Config().UseSoftDeletes && !db.UseForceDelete(ctx)
 	if m.client != nil {
 		hooks = m.client.Hooks()
 		if len(hooks.PostDBDelete) > 0 {
-			originalEntities, err = m.model.Query(*m.predicates...).Get(ctx)
+			query := m.model.Query(*m.predicates...)
+			if !softDelete {
+				query = query.WithTrashed()
+			}
+
+			originalEntities, err = query.Get(db.WithRawRead(db.WithPrimary(ctx)))
 			if err != nil {
 				return 0, err
 			}
@@ -41,12 +50,13 @@ func (m *Mutation) Delete(ctx context.Context) (affected int, err error) {
 		)
 	}
 
-	if m.client != nil && m.client.Config().UseSoftDeletes {
-		if affected, err = m.model.
-			Mutation().
-			Where(*m.predicates...).
-			Update(ctx, entity.New().Set("deleted_at", time.Now())); err != nil {
-			return 0, fmt.Errorf("soft delete error: %w", err)
+	if err := m.scope(ctx); err != nil {
+		return 0, err
+	}
+
+	if softDelete {
+		if affected, err = m.softDelete(ctx); err != nil {
+			return 0, err
 		}
 
 		return affected, runPostDBDeleteHooks()
@@ -77,10 +87,34 @@ func (m *Mutation) Delete(ctx context.Context) (affected int, err error) {
 		}
 	}
 
-	affected, err = sqlgraph.DeleteNodes(ctx, entAdapter.Driver(), deleteSpec)
+	affected, err = sqlgraph.DeleteNodes(withSchema(ctx, m.model.name), entAdapter.Driver(), deleteSpec)
 	if err != nil {
 		return 0, fmt.Errorf("delete nodes error: %w", err)
 	}
 
 	return affected, runPostDBDeleteHooks()
 }
+
+// softDelete sets the deleted_at of the entities that are not in the trash and of their m2m junction rows.
+func (m *Mutation) softDelete(ctx context.Context) (affected int, err error) {
+	pkName := m.model.schema.PrimaryKeyName()
+	records, err := m.model.Query(*m.predicates...).Select(pkName).Get(db.WithPrimary(ctx))
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]any, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.Get(pkName))
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	if affected, err = m.setDeletedAt(ctx, ids, time.Now()); err != nil {
+		return 0, fmt.Errorf("soft delete error: %w", err)
+	}
+
+	return affected, nil
+}
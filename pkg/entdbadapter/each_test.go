@@ -0,0 +1,175 @@
+package entdbadapter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	dialectSql "entgo.io/ent/dialect/sql"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryEachError(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	client := utils.Must(NewEntClient(&db.Config{
+		Driver: "sqlmock",
+	}, createJSONSchemaBuilder(t), dialectSql.OpenDB("mysql", mockDB)))
+	model := utils.Must(client.Model("product"))
+	noop := func(*entity.Entity) error { return nil }
+
+	// Before cursor
+	err = model.Query().Before("cursor").Each(context.Background(), noop)
+	assert.EqualError(t, err, "before cursor is not supported when streaming entities")
+
+	// Invalid select
+	err = model.Query().Select("invalid").Each(context.Background(), noop)
+	assert.ErrorContains(t, err, "invalid")
+
+	// Invalid order
+	err = model.Query().Order("invalid").Each(context.Background(), noop)
+	assert.ErrorContains(t, err, "invalid")
+
+	// Query error
+	mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `products` WHERE `products`.`id` > ?")).
+		WithArgs(1).
+		WillReturnError(assert.AnError)
+	err = model.Query(db.GT("id", 1)).Each(context.Background(), noop)
+	assert.ErrorIs(t, err, assert.AnError)
+
+	// Rows error
+	mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `products`")).
+		WillReturnRows(mock.NewRows([]string{"id", "name"}).
+			AddRow(1, "A").
+			AddRow(2, "B").
+			RowError(1, assert.AnError))
+	err = model.Query().Each(context.Background(), noop)
+	assert.ErrorIs(t, err, assert.AnError)
+
+	// Function error stops the iteration
+	mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `products` ORDER BY `products`.`id` DESC LIMIT 10")).
+		WillReturnRows(mock.NewRows([]string{"id", "name"}).AddRow(1, "A").AddRow(2, "B"))
+	names := []string{}
+	err = model.Query().Limit(10).Order("-id").Each(context.Background(), func(e *entity.Entity) error {
+		names = append(names, e.GetString("name"))
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, []string{"A"}, names)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQueryEach(t *testing.T) {
+	defer func(batchSize int) { eachBatchSize = batchSize }(eachBatchSize)
+	eachBatchSize = 2
+
+	ctx := context.Background()
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	batches := [][]string{}
+	client, err := NewTestClient(migrationDir, createQuantifierSchemaBuilder(t), func() *db.Hooks {
+		return &db.Hooks{
+			PostDBQuery: []db.PostDBQuery{
+				func(
+					ctx context.Context,
+					option *db.QueryOption,
+					entities []*entity.Entity,
+				) ([]*entity.Entity, error) {
+					if option.Schema.Name != "post" {
+						return entities, nil
+					}
+
+					batches = append(batches, utils.Map(entities, func(e *entity.Entity) string {
+						return e.GetString("title")
+					}))
+
+					for _, e := range entities {
+						e.Set("batch", len(batches))
+					}
+
+					return entities, nil
+				},
+			},
+		}
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	postModel := utils.Must(client.Model("post"))
+	commentModel := utils.Must(client.Model("comment"))
+	for i := 1; i <= 5; i++ {
+		_ = utils.Must(postModel.CreateFromJSON(ctx, fmt.Sprintf(`{"title": "post %d"}`, i)))
+		_ = utils.Must(commentModel.CreateFromJSON(ctx, fmt.Sprintf(
+			`{"content": "comment %d", "approved": true, "post": {"id": %d}}`, i, i,
+		)))
+	}
+
+	type record struct {
+		Title    string
+		Batch    int
+		Comments []string
+	}
+
+	records := []record{}
+	err = postModel.Query(db.GT("id", 1)).
+		Select("title", "comments.content").
+		Order("id").
+		Each(ctx, func(e *entity.Entity) error {
+			records = append(records, record{
+				Title: e.GetString("title"),
+				Batch: e.Get("batch").(int),
+				Comments: utils.Map(e.Get("comments").([]*entity.Entity), func(c *entity.Entity) string {
+					return c.GetString("content")
+				}),
+			})
+			return nil
+		})
+	require.NoError(t, err)
+
+	// The hooks are run per batch and the relations are loaded for each batch
+	assert.Equal(t, [][]string{{"post 2", "post 3"}, {"post 4", "post 5"}}, batches)
+	assert.Equal(t, []record{
+		{Title: "post 2", Batch: 1, Comments: []string{"comment 2"}},
+		{Title: "post 3", Batch: 1, Comments: []string{"comment 3"}},
+		{Title: "post 4", Batch: 2, Comments: []string{"comment 4"}},
+		{Title: "post 5", Batch: 2, Comments: []string{"comment 5"}},
+	}, records)
+
+	// The remaining entities are processed in the last batch
+	batches = [][]string{}
+	count := 0
+	err = postModel.Query().Order("-id").Limit(3).Each(ctx, func(e *entity.Entity) error {
+		count++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, [][]string{{"post 5", "post 4"}, {"post 3"}}, batches)
+
+	// Empty result
+	batches = [][]string{}
+	err = postModel.Query(db.GT("id", 10)).Each(ctx, func(e *entity.Entity) error {
+		count++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, batches)
+	assert.Equal(t, 3, count)
+
+	// The function error stops the iteration
+	count = 0
+	err = postModel.Query().Each(ctx, func(e *entity.Entity) error {
+		count++
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, count)
+}
@@ -427,6 +427,16 @@ func (e *edgeLoader) executeM2MWindowQuery(
 	// Add WHERE clause for parent IDs
 	inner.Where(sql.InValues(junction.C(colCfg.conditionColumn), parentIDs...))
 
+	// Scope the related records to the tenant
+	tenantScope, err := tenantPredicate(e.ctx, e.edgeModel.schema)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if tenantScope != nil {
+		inner.Where(sql.EQ(edgeTable.C(tenantScope.Field), tenantScope.Value))
+	}
+
 	// Apply filter predicate if specified
 	if e.relOpt != nil && e.relOpt.Filter != nil {
 		schemaBuilder := e.q.client.SchemaBuilder()
@@ -480,7 +490,7 @@ func (e *edgeLoader) executeM2MWindowQueryRaw(
 	args []any,
 ) ([]*entity.Entity, []any, error) {
 	var rows = &sql.Rows{}
-	if err := entAdapter.Driver().Query(e.ctx, query, args, rows); err != nil {
+	if err := entAdapter.ReadDriver(e.ctx).Query(withSchema(e.ctx, e.edgeModel.name), query, args, rows); err != nil {
 		return nil, nil, err
 	}
 	defer rows.Close()
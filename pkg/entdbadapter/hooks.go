@@ -10,11 +10,54 @@ import (
 	"github.com/fastschema/fastschema/schema"
 )
 
+type postHooksContextKey struct{}
+
+// postHooksQueue holds the post mutation hooks that are run after the transaction of the mutation is committed.
+type postHooksQueue struct {
+	hooks []queuedPostHooks
+}
+
+type queuedPostHooks struct {
+	ctx context.Context
+	run func(ctx context.Context, client db.Client) error
+}
+
+// withPostHooksQueue returns a context that queues the post mutation hooks instead of running them.
+func withPostHooksQueue(ctx context.Context) (context.Context, *postHooksQueue) {
+	queue := &postHooksQueue{}
+	return context.WithValue(ctx, postHooksContextKey{}, queue), queue
+}
+
+// queuePostHooks adds the post mutation hooks to the queue of the context.
+// Returns false if the context has no queue, the hooks must be run immediately.
+func queuePostHooks(ctx context.Context, run func(ctx context.Context, client db.Client) error) bool {
+	queue, _ := ctx.Value(postHooksContextKey{}).(*postHooksQueue)
+	if queue == nil {
+		return false
+	}
+
+	queue.hooks = append(queue.hooks, queuedPostHooks{ctx: ctx, run: run})
+	return true
+}
+
+// run runs the queued post mutation hooks with the given client.
+func (q *postHooksQueue) run(client db.Client) error {
+	for _, queued := range q.hooks {
+		ctx := context.WithValue(queued.ctx, postHooksContextKey{}, (*postHooksQueue)(nil))
+		if err := queued.run(ctx, client); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func runPreDBQueryHooks(ctx context.Context, client db.Client, option *db.QueryOption) error {
 	if client == nil {
 		return nil
 	}
 
+	ctx = db.ContextWithTx(ctx, client)
 	hooks := client.Hooks()
 	if hooks != nil && len(hooks.PreDBQuery) > 0 {
 		for _, hook := range hooks.PreDBQuery {
@@ -37,6 +80,7 @@ func runPostDBQueryHooks(
 		return entities, nil
 	}
 
+	ctx = db.ContextWithTx(ctx, client)
 	hooks := client.Hooks()
 	if hooks != nil && len(hooks.PostDBQuery) > 0 {
 		for _, hook := range hooks.PostDBQuery {
@@ -55,6 +99,7 @@ func runPreDBExecHooks(ctx context.Context, client db.Client, option *db.QueryOp
 		return nil
 	}
 
+	ctx = db.ContextWithTx(ctx, client)
 	hooks := client.Hooks()
 	if hooks != nil && len(hooks.PreDBExec) > 0 {
 		for _, hook := range hooks.PreDBExec {
@@ -77,6 +122,7 @@ func runPostDBExecHooks(
 		return nil
 	}
 
+	ctx = db.ContextWithTx(ctx, client)
 	hooks := client.Hooks()
 	if hooks != nil && len(hooks.PostDBExec) > 0 {
 		for _, hook := range hooks.PostDBExec {
@@ -99,6 +145,7 @@ func runPreDBCreateHooks(
 		return nil
 	}
 
+	ctx = db.ContextWithTx(ctx, client)
 	hooks := client.Hooks()
 	if hooks != nil && len(hooks.PreDBCreate) > 0 {
 		for _, hook := range hooks.PreDBCreate {
@@ -122,6 +169,13 @@ func runPostDBCreateHooks(
 		return nil
 	}
 
+	if queuePostHooks(ctx, func(ctx context.Context, client db.Client) error {
+		return runPostDBCreateHooks(ctx, client, schema, createData, createdID)
+	}) {
+		return nil
+	}
+
+	ctx = db.ContextWithTx(ctx, client)
 	hooks := client.Hooks()
 	if hooks != nil && len(hooks.PostDBCreate) > 0 {
 		for _, hook := range hooks.PostDBCreate {
@@ -145,6 +199,7 @@ func runPreDBUpdateHooks(
 		return nil
 	}
 
+	ctx = db.ContextWithTx(ctx, client)
 	hooks := client.Hooks()
 	if hooks != nil && len(hooks.PreDBUpdate) > 0 {
 		for _, hook := range hooks.PreDBUpdate {
@@ -170,6 +225,13 @@ func runPostDBUpdateHooks(
 		return nil
 	}
 
+	if queuePostHooks(ctx, func(ctx context.Context, client db.Client) error {
+		return runPostDBUpdateHooks(ctx, client, schema, predicates, updateData, originalEntities, affected)
+	}) {
+		return nil
+	}
+
+	ctx = db.ContextWithTx(ctx, client)
 	hooks := client.Hooks()
 	if hooks != nil && len(hooks.PostDBUpdate) > 0 {
 		for _, hook := range hooks.PostDBUpdate {
@@ -192,6 +254,7 @@ func runPreDBDeleteHooks(
 		return nil
 	}
 
+	ctx = db.ContextWithTx(ctx, client)
 	hooks := client.Hooks()
 	if hooks != nil && len(hooks.PreDBDelete) > 0 {
 		for _, hook := range hooks.PreDBDelete {
@@ -216,6 +279,13 @@ func runPostDBDeleteHooks(
 		return nil
 	}
 
+	if queuePostHooks(ctx, func(ctx context.Context, client db.Client) error {
+		return runPostDBDeleteHooks(ctx, client, schema, predicates, originalEntities, affected)
+	}) {
+		return nil
+	}
+
+	ctx = db.ContextWithTx(ctx, client)
 	hooks := client.Hooks()
 	if hooks != nil && len(hooks.PostDBDelete) > 0 {
 		for _, hook := range hooks.PostDBDelete {
@@ -0,0 +1,200 @@
+package entdbadapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/pkg/utils"
+)
+
+// jsonPathIndex reports whether the JSON path segment is an array index.
+func jsonPathIndex(segment string) bool {
+	return strings.Trim(segment, "0123456789") == ""
+}
+
+// jsonNumeric reports whether the value is compared as a number with the JSON value.
+func jsonNumeric(value any) bool {
+	switch value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		return true
+	default:
+		return false
+	}
+}
+
+// jsonPathLiteral returns the MySQL/SQLite JSON path literal, e.g. '$."a"[0]'.
+func jsonPathLiteral(path []string) string {
+	b := &strings.Builder{}
+	b.WriteString("'$")
+	for _, segment := range path {
+		b.WriteString(utils.If(jsonPathIndex(segment), "["+segment+"]", `."`+segment+`"`))
+	}
+	b.WriteString("'")
+	return b.String()
+}
+
+// jsonExtractExpr returns the expression that extracts the value at the path of the JSON column.
+// If unquote is true, the value is extracted as text instead of JSON.
+//
+//	MySQL: JSON_UNQUOTE(JSON_EXTRACT(`t`.`c`, '$.a[0]'))
+//	Postgres: ("t"."c"->'a'->>0)
+//	SQLite: JSON_EXTRACT(`t`.`c`, '$.a[0]')
+func jsonExtractExpr(dialectName, column string, path []string, unquote bool) string {
+	if dialectName == dialect.Postgres {
+		b := &strings.Builder{}
+		b.WriteString("(" + column)
+		for i, segment := range path {
+			b.WriteString(utils.If(unquote && i == len(path)-1, "->>", "->"))
+			b.WriteString(utils.If(jsonPathIndex(segment), segment, "'"+segment+"'"))
+		}
+		b.WriteString(")")
+		return b.String()
+	}
+
+	extract := fmt.Sprintf("JSON_EXTRACT(%s, %s)", column, jsonPathLiteral(path))
+	if unquote && dialectName == dialect.MySQL {
+		return "JSON_UNQUOTE(" + extract + ")"
+	}
+
+	return extract
+}
+
+// jsonValueExpr returns the expression of the JSON value that is compared with the given value.
+// The JSON value is cast to the type of the compared value, so that numbers are not compared as text.
+func jsonValueExpr(dialectName, column string, path []string, value any) string {
+	switch dialectName {
+	case dialect.Postgres:
+		expr := jsonExtractExpr(dialectName, column, path, true)
+		if jsonNumeric(value) {
+			return expr + "::numeric"
+		}
+
+		if _, ok := value.(bool); ok {
+			return expr + "::boolean"
+		}
+
+		return expr
+	case dialect.MySQL:
+		// The MySQL JSON numbers are compared as numbers, the other values are compared as text.
+		return jsonExtractExpr(dialectName, column, path, !jsonNumeric(value))
+	default:
+		// SQLite JSON_EXTRACT returns the SQL value of the JSON value.
+		return jsonExtractExpr(dialectName, column, path, false)
+	}
+}
+
+// jsonNullExpr returns the expression of the JSON value that is NULL
+// if the path does not exist or the JSON value is null.
+func jsonNullExpr(dialectName, column string, path []string) string {
+	if dialectName == dialect.MySQL {
+		// MySQL JSON_EXTRACT returns the JSON null literal instead of NULL for the null values.
+		return fmt.Sprintf("NULLIF(%s, CAST('null' AS JSON))", jsonExtractExpr(dialectName, column, path, false))
+	}
+
+	return jsonExtractExpr(dialectName, column, path, true)
+}
+
+// jsonExistsExpr returns the expression of the JSON value that is NULL
+// only if the path does not exist, a JSON null value is not NULL.
+func jsonExistsExpr(dialectName, column string, path []string) string {
+	if dialectName == dialect.SQLite {
+		// SQLite JSON_EXTRACT returns NULL for the null values, JSON_TYPE returns 'null'.
+		return fmt.Sprintf("JSON_TYPE(%s, %s)", column, jsonPathLiteral(path))
+	}
+
+	return jsonExtractExpr(dialectName, column, path, false)
+}
+
+// jsonMySQLValue converts the boolean values to text since the MySQL JSON values
+// are compared as text and the MySQL drivers send the booleans as 0/1.
+func jsonMySQLValue(value any) any {
+	switch v := value.(type) {
+	case bool:
+		return utils.If(v, "true", "false")
+	case []any:
+		return utils.Map(v, jsonMySQLValue)
+	default:
+		return value
+	}
+}
+
+// createJSONFieldPredicate converts a predicate on a value inside a JSON field to ent predicate.
+// The comparison type is decided by the predicate value (or the first value of $in/$nin).
+func createJSONFieldPredicate(dialectName string, jsonPath *db.JSONPath, p *db.Predicate) (PredicateFN, error) {
+	typeValue := p.Value
+	if values, ok := p.Value.([]any); ok && len(values) > 0 {
+		typeValue = values[0]
+	}
+
+	predicate := &db.Predicate{Field: p.Field, Operator: p.Operator, Value: p.Value}
+	if dialectName == dialect.MySQL && p.Operator != db.OpNULL && p.Operator != db.OpExists {
+		predicate.Value = jsonMySQLValue(p.Value)
+	}
+
+	return createColumnPredicate(predicate, func(s *sql.Selector) string {
+		column := s.C(jsonPath.Field)
+		if p.Operator == db.OpNULL {
+			return jsonNullExpr(dialectName, column, jsonPath.Path)
+		}
+
+		if p.Operator == db.OpExists {
+			return jsonExistsExpr(dialectName, column, jsonPath.Path)
+		}
+
+		return jsonValueExpr(dialectName, column, jsonPath.Path, typeValue)
+	})
+}
+
+// jsonOrder creates the order expression that sorts the records by the value inside a JSON field.
+// The JSON values are sorted as JSON, so that numbers are not sorted as text.
+func jsonOrder(jsonPath *db.JSONPath, desc bool) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		expr := sql.Expr(jsonExtractExpr(s.Dialect(), s.C(jsonPath.Field), jsonPath.Path, false))
+		s.OrderExpr(utils.If(desc, sql.DescExpr(expr), expr))
+	}
+}
+
+// selectJSONPaths returns the JSON object that contains only the values at the given paths.
+// E.g. selecting the path "color" of {"color": "red", "size": 1} returns {"color": "red"}.
+// Returns nil if the value is not an object or none of the paths exist.
+func selectJSONPaths(value any, paths [][]string) any {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	result := map[string]any{}
+	nestedPaths := map[string][][]string{}
+	for _, path := range paths {
+		if _, ok := object[path[0]]; !ok {
+			continue
+		}
+
+		if len(path) == 1 {
+			result[path[0]] = object[path[0]]
+			continue
+		}
+
+		nestedPaths[path[0]] = append(nestedPaths[path[0]], path[1:])
+	}
+
+	for key, paths := range nestedPaths {
+		if _, ok := result[key]; ok {
+			continue
+		}
+
+		if nested := selectJSONPaths(object[key], paths); nested != nil {
+			result[key] = nested
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
@@ -0,0 +1,252 @@
+package entdbadapter
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	dialectSql "entgo.io/ent/dialect/sql"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createJSONSchemaBuilder(t *testing.T) *schema.Builder {
+	t.Helper()
+	productSchema := &schema.Schema{
+		Name:             "product",
+		Namespace:        "products",
+		LabelFieldName:   "name",
+		DisableTimestamp: true,
+		Fields: []*schema.Field{
+			{Name: "id", Label: "ID", Type: schema.TypeUint64},
+			{Name: "name", Label: "Name", Type: schema.TypeString},
+			{Name: "metadata", Label: "Metadata", Type: schema.TypeJSON, Optional: true, Sortable: true},
+			{
+				Name:     "vendor",
+				Label:    "Vendor",
+				Type:     schema.TypeRelation,
+				Optional: true,
+				Relation: &schema.Relation{
+					Type:             schema.O2M,
+					TargetSchemaName: "vendor",
+					TargetFieldName:  "products",
+					Optional:         true,
+				},
+			},
+		},
+	}
+
+	vendorSchema := &schema.Schema{
+		Name:             "vendor",
+		Namespace:        "vendors",
+		LabelFieldName:   "name",
+		DisableTimestamp: true,
+		Fields: []*schema.Field{
+			{Name: "id", Label: "ID", Type: schema.TypeUint64},
+			{Name: "name", Label: "Name", Type: schema.TypeString},
+			{Name: "settings", Label: "Settings", Type: schema.TypeJSON, Optional: true},
+			{
+				Name:  "products",
+				Label: "Products",
+				Type:  schema.TypeRelation,
+				Relation: &schema.Relation{
+					Type:             schema.O2M,
+					Owner:            true,
+					TargetSchemaName: "product",
+					TargetFieldName:  "vendor",
+				},
+			},
+		},
+	}
+
+	sb, err := schema.NewBuilderFromSchemas("", map[string]*schema.Schema{
+		productSchema.Name: productSchema,
+		vendorSchema.Name:  vendorSchema,
+	})
+	require.NoError(t, err)
+
+	return sb
+}
+
+func TestSelectJSONPaths(t *testing.T) {
+	value := map[string]any{
+		"color": "red",
+		"size":  map[string]any{"width": 1, "height": 2},
+		"tags":  []any{"a"},
+	}
+
+	assert.Equal(t, map[string]any{"color": "red"}, selectJSONPaths(value, [][]string{{"color"}}))
+	assert.Equal(t, map[string]any{
+		"color": "red",
+		"size":  map[string]any{"width": 1},
+	}, selectJSONPaths(value, [][]string{{"color"}, {"size", "width"}, {"size", "depth"}}))
+	assert.Equal(t, map[string]any{
+		"size": map[string]any{"width": 1, "height": 2},
+	}, selectJSONPaths(value, [][]string{{"size", "width"}, {"size"}}))
+	assert.Nil(t, selectJSONPaths(value, [][]string{{"invalid"}, {"color", "invalid"}}))
+	assert.Nil(t, selectJSONPaths([]any{1}, [][]string{{"0"}}))
+	assert.Nil(t, selectJSONPaths(nil, [][]string{{"color"}}))
+}
+
+func TestJSONQuery(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Dialect     string
+		Predicates  []*db.Predicate
+		Order       []string
+		Expect      func(sqlmock.Sqlmock)
+		ExpectError string
+	}{
+		{
+			Name:        "JSON_search",
+			Predicates:  []*db.Predicate{db.Search("metadata.color", "red")},
+			ExpectError: "operator $search is not supported on JSON field metadata.color",
+		},
+		{
+			Name:        "JSON_invalid_path",
+			Predicates:  []*db.Predicate{db.EQ("metadata.co'lor", "red")},
+			ExpectError: `invalid JSON path "metadata.co'lor"`,
+		},
+		{
+			Name:        "JSON_order_relation",
+			Order:       []string{"vendor.settings.currency"},
+			ExpectError: "column product.vendor.settings.currency not found",
+		},
+		{
+			Name: "JSON_mysql",
+			Predicates: []*db.Predicate{
+				db.EQ("metadata.color", "red"),
+				db.GT("metadata.size", 1),
+				db.EQ("metadata.active", true),
+				db.In("metadata.tags.0", []any{"a", "b"}),
+				db.Null("metadata.discount", true),
+				db.Like("metadata.color", "r%"),
+			},
+			Order: []string{"-metadata.size"},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `products` WHERE JSON_UNQUOTE(JSON_EXTRACT(`products`.`metadata`, '$.\"color\"')) = ? AND JSON_EXTRACT(`products`.`metadata`, '$.\"size\"') > ? AND JSON_UNQUOTE(JSON_EXTRACT(`products`.`metadata`, '$.\"active\"')) = ? AND JSON_UNQUOTE(JSON_EXTRACT(`products`.`metadata`, '$.\"tags\"[0]')) IN (?, ?) AND NULLIF(JSON_EXTRACT(`products`.`metadata`, '$.\"discount\"'), CAST('null' AS JSON)) IS NULL AND JSON_UNQUOTE(JSON_EXTRACT(`products`.`metadata`, '$.\"color\"')) LIKE ? ORDER BY JSON_EXTRACT(`products`.`metadata`, '$.\"size\"') DESC")).
+					WithArgs("red", 1, "true", "a", "b", "r%").
+					WillReturnRows(mock.NewRows([]string{"id", "name"}).AddRow(1, "A"))
+			},
+		},
+		{
+			Name:    "JSON_postgres",
+			Dialect: dialect.Postgres,
+			Predicates: []*db.Predicate{
+				db.EQ("metadata.color", "red"),
+				db.GTE("metadata.size.width", 1.5),
+				db.NEQ("metadata.active", false),
+				db.NotIn("metadata.tags.0", []any{1, 2}),
+				db.Null("metadata.discount", false),
+			},
+			Order: []string{"metadata.size.width"},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery(`SELECT * FROM "products" WHERE ("products"."metadata"->>'color') = $1 AND ("products"."metadata"->'size'->>'width')::numeric >= $2 AND ("products"."metadata"->>'active')::boolean AND ("products"."metadata"->'tags'->>0)::numeric NOT IN ($3, $4) AND ("products"."metadata"->>'discount') IS NOT NULL ORDER BY ("products"."metadata"->'size'->'width')`)).
+					WithArgs("red", 1.5, 1, 2).
+					WillReturnRows(mock.NewRows([]string{"id", "name"}).AddRow(1, "A"))
+			},
+		},
+		{
+			Name:       "JSON_relation_mysql",
+			Predicates: []*db.Predicate{db.EQ("vendor.settings.currency", "USD")},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `products` WHERE EXISTS (SELECT `vendors`.`id` FROM `vendors` WHERE `products`.`vendor_id` = `vendors`.`id` AND JSON_UNQUOTE(JSON_EXTRACT(`vendors`.`settings`, '$.\"currency\"')) = ?)")).
+					WithArgs("USD").
+					WillReturnRows(mock.NewRows([]string{"id", "name"}).AddRow(1, "A"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			dialectName := utils.If(tt.Dialect == "", dialect.MySQL, tt.Dialect)
+			client := utils.Must(NewEntClient(&db.Config{
+				Driver: "sqlmock",
+			}, createJSONSchemaBuilder(t), dialectSql.OpenDB(dialectName, mockDB)))
+
+			if tt.Expect != nil {
+				tt.Expect(mock)
+			}
+
+			model := utils.Must(client.Model("product"))
+			_, err = model.Query(tt.Predicates...).Order(tt.Order...).Get(context.Background())
+			if tt.ExpectError != "" {
+				assert.ErrorContains(t, err, tt.ExpectError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestJSONSQLite(t *testing.T) {
+	ctx := context.Background()
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	client, err := NewTestClient(migrationDir, createJSONSchemaBuilder(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	vendorModel := utils.Must(client.Model("vendor"))
+	_ = utils.Must(vendorModel.CreateFromJSON(ctx, `{"name": "Vendor 1", "settings": {"currency": "USD"}}`))
+	_ = utils.Must(vendorModel.CreateFromJSON(ctx, `{"name": "Vendor 2", "settings": {"currency": "EUR"}}`))
+
+	model := utils.Must(client.Model("product"))
+	for _, product := range []string{
+		`{"name": "A", "vendor": {"id": 1}, "metadata": {"color": "red", "size": 10, "active": true, "tags": ["x"]}}`,
+		`{"name": "B", "vendor": {"id": 2}, "metadata": {"color": "blue", "size": 9, "active": false, "discount": null}}`,
+		`{"name": "C", "metadata": {"color": "red", "size": 2.5, "tags": ["y", "z"]}}`,
+	} {
+		_ = utils.Must(model.CreateFromJSON(ctx, product))
+	}
+
+	names := func(predicates []*db.Predicate, order ...string) []string {
+		products, err := model.Query(predicates...).Order(order...).Get(ctx)
+		require.NoError(t, err)
+		return utils.Map(products, func(e *entity.Entity) string {
+			return e.GetString("name")
+		})
+	}
+
+	filter := func(filter string) []*db.Predicate {
+		return utils.Must(db.CreatePredicatesFromFilterObject(client.SchemaBuilder(), model.Schema(), filter))
+	}
+
+	assert.Equal(t, []string{"A", "C"}, names(filter(`{"metadata.color": "red"}`), "id"))
+	assert.Equal(t, []string{"A"}, names(filter(`{"metadata.size": {"$gt": 9}}`), "id"))
+	assert.Equal(t, []string{"B", "C"}, names(filter(`{"metadata.size": {"$lt": 10}}`), "id"))
+	assert.Equal(t, []string{"A"}, names(filter(`{"metadata.active": true}`), "id"))
+	assert.Equal(t, []string{"A", "C"}, names(filter(`{"metadata.tags.0": {"$in": ["x", "y"]}}`), "id"))
+	assert.Equal(t, []string{"B"}, names(filter(`{"metadata.tags": {"$null": true}}`), "id"))
+	assert.Equal(t, []string{"A", "B"}, names(filter(`{"metadata.discount": {"$null": true}, "metadata.active": {"$null": false}}`), "id"))
+	assert.Equal(t, []string{"B"}, names(filter(`{"metadata.color": {"$like": "bl%"}}`), "id"))
+	assert.Equal(t, []string{"A"}, names(filter(`{"vendor.settings.currency": "USD"}`), "id"))
+	assert.Equal(t, []string{"C", "B", "A"}, names(nil, "metadata.size"))
+	assert.Equal(t, []string{"A", "B", "C"}, names(nil, "-metadata.size"))
+
+	count, err := model.Query(filter(`{"metadata.color": "red"}`)...).Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	products, err := model.Query(db.EQ("name", "A")).Select("name", "metadata.color", "metadata.tags").Get(ctx)
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, map[string]any{"color": "red", "tags": []any{"x"}}, products[0].Get("metadata"))
+
+	products, err = model.Query(db.EQ("name", "A")).Select("vendor.settings.currency").Get(ctx)
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, map[string]any{"currency": "USD"}, products[0].Get("vendor").(*entity.Entity).Get("settings"))
+}
@@ -0,0 +1,82 @@
+package entdbadapter
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateOptimisticLock(t *testing.T) {
+	ctx := context.Background()
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	sb, err := schema.NewBuilderFromSchemas("", map[string]*schema.Schema{
+		"note": {
+			Name:           "note",
+			Namespace:      "notes",
+			LabelFieldName: "title",
+			OptimisticLock: true,
+			Fields: []*schema.Field{
+				{Name: "id", Label: "ID", Type: schema.TypeUint64},
+				{Name: "title", Label: "Title", Type: schema.TypeString},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	client, err := NewTestClient(migrationDir, sb)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	noteModel := utils.Must(client.Model("note"))
+	_ = utils.Must(noteModel.CreateFromJSON(ctx, `{"title": "note 1"}`))
+	_ = utils.Must(noteModel.CreateFromJSON(ctx, `{"title": "note 2"}`))
+	note := func(id int) *entity.Entity {
+		return utils.Must(noteModel.Query(db.EQ("id", id)).First(ctx))
+	}
+	version := func(id int) uint64 {
+		return utils.Must(note(id).GetUint64(entity.FieldVersion, false))
+	}
+	update := func(id int, e *entity.Entity) (int, error) {
+		return noteModel.Mutation().Where(db.EQ("id", id)).Update(ctx, e)
+	}
+
+	// Case 1: The version starts at 1
+	assert.Equal(t, uint64(1), version(1))
+
+	// Case 2: Update without the expected version increments the version
+	assert.Equal(t, 1, utils.Must(update(1, entity.New().Set("title", "note 1 updated"))))
+	assert.Equal(t, uint64(2), version(1))
+	assert.Equal(t, uint64(1), version(2))
+
+	// Case 3: Update with the expected version
+	assert.Equal(t, 1, utils.Must(update(1, entity.New().Set("title", "editor 1").Set(entity.FieldVersion, 2))))
+	assert.Equal(t, uint64(3), version(1))
+
+	// Case 4: Update with a stale version returns a conflict and does not update the record
+	_, err = update(1, entity.New().Set("title", "editor 2").Set(entity.FieldVersion, float64(2)))
+	assert.ErrorIs(t, err, db.ErrVersionConflict)
+	assert.Equal(t, "editor 1", note(1).GetString("title"))
+	assert.Equal(t, uint64(3), version(1))
+
+	// Case 5: The version field is used as the expected version, it is not set directly
+	assert.Equal(t, 1, utils.Must(update(2, entity.New().Set(entity.FieldVersion, 1))))
+	assert.Equal(t, uint64(2), version(2))
+
+	// Case 6: Invalid version
+	_, err = update(1, entity.New().Set("title", "editor 2").Set(entity.FieldVersion, "invalid"))
+	assert.ErrorContains(t, err, "invalid version")
+
+	// Case 7: Bulk update increments the version of all matched records
+	assert.Equal(t, 2, utils.Must(noteModel.Mutation().Update(ctx, entity.New().Set("title", "bulk"))))
+	assert.Equal(t, uint64(4), version(1))
+	assert.Equal(t, uint64(3), version(2))
+}
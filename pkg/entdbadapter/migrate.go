@@ -88,7 +88,7 @@ func (d *Adapter) Migrate(
 		return err
 	}
 
-	return nil
+	return d.migrateSearch(ctx)
 }
 
 // newEntMigrate creates a new ent migrate instance with common options
@@ -100,6 +100,7 @@ func (d *Adapter) newEntMigrate(dir atlasMigrate.Dir, opts ...entSchema.MigrateO
 		entSchema.WithFormatter(sqltool.GolangMigrateFormatter),
 		entSchema.WithDropIndex(true),
 		entSchema.WithForeignKeys(true),
+		entSchema.WithDiffHook(createSearchIndexesHook(d.searchIndexNames())),
 	}
 	migrateOptions = append(migrateOptions, opts...)
 
@@ -133,6 +133,11 @@ func (m *Model) Create(ctx context.Context, e *entity.Entity) (_ any, err error)
 	return m.Mutation().Create(ctx, e)
 }
 
+// CreateMany creates the entities using batched inserts
+func (m *Model) CreateMany(ctx context.Context, entities []*entity.Entity) (_ []any, err error) {
+	return m.Mutation().CreateMany(ctx, entities)
+}
+
 // CreateFromJSON creates a new entity from JSON
 func (m *Model) CreateFromJSON(ctx context.Context, json string) (_ any, err error) {
 	entity, err := entity.NewEntityFromJSON(json)
@@ -1,13 +1,17 @@
 package entdbadapter
 
 import (
+	"context"
 	"database/sql/driver"
+	"os"
 	"testing"
 
 	"github.com/fastschema/fastschema/db"
 	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
 	"github.com/fastschema/fastschema/schema"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMutation(t *testing.T) {
@@ -117,3 +121,234 @@ func TestMutationGetRelationEntityIDsTargetColumn(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, []driver.Value{uint64(77)}, values)
 }
+
+func TestMutationFieldValidation(t *testing.T) {
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	minLength, maxLength, minPrice := 3, 8, float64(0)
+	productSchema := &schema.Schema{
+		Name:             "product",
+		Namespace:        "products",
+		LabelFieldName:   "sku",
+		DisableTimestamp: true,
+		Fields: []*schema.Field{
+			{Name: "sku", Label: "SKU", Type: schema.TypeString, Unique: true, Validation: &schema.FieldValidation{
+				MinLength: &minLength,
+			}},
+			{Name: "price", Label: "Price", Type: schema.TypeFloat64, Validation: &schema.FieldValidation{
+				Min: &minPrice,
+			}},
+			// The values are validated before the setter transforms them
+			{
+				Name:     "code",
+				Label:    "Code",
+				Type:     schema.TypeString,
+				Optional: true,
+				Setter:   "$args.Value == nil ? nil : 'product-' + $args.Value",
+				Validation: &schema.FieldValidation{
+					MaxLength: &maxLength,
+				},
+			},
+		},
+	}
+	sb := utils.Must(schema.NewBuilderFromSchemas("", map[string]*schema.Schema{productSchema.Name: productSchema}))
+	client, err := NewClient(&db.Config{
+		Driver:       "sqlite",
+		Name:         ":memory:_" + utils.RandomString(10),
+		MigrationDir: migrationDir,
+	}, sb)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx := context.Background()
+	model := utils.Must(client.Model("product"))
+	assertValidationError := func(err error, code, field string) {
+		t.Helper()
+		var schemaErrors *schema.SchemaErrors
+		require.ErrorAs(t, err, &schemaErrors)
+		require.Len(t, schemaErrors.FieldErrors, 1)
+		assert.Equal(t, code, schemaErrors.FieldErrors[0].Code)
+		assert.Equal(t, field, schemaErrors.FieldErrors[0].Field)
+	}
+
+	// Create
+	_, err = model.Mutation().Create(ctx, entity.New().Set("sku", "a1").Set("price", 1.5))
+	assertValidationError(err, schema.CodeFieldValueMinLength, "sku")
+	id := utils.Must(model.Mutation().Create(ctx, entity.New().Set("sku", "abc").Set("price", 1.5).Set("code", "abc")))
+	_, err = model.Mutation().Create(ctx, entity.New().Set("sku", "xyz").Set("price", 1.5).Set("code", "abcdefghi"))
+	assertValidationError(err, schema.CodeFieldValueMaxLength, "code")
+	assert.Equal(t, "product-abc", utils.Must(model.Query(db.EQ("id", id)).First(ctx)).Get("code"))
+
+	// Create many
+	_, err = model.Mutation().CreateMany(ctx, []*entity.Entity{
+		entity.New().Set("sku", "def").Set("price", 2.0),
+		entity.New().Set("sku", "ghi").Set("price", -2.0),
+	})
+	assert.ErrorContains(t, err, "entity 1: ")
+	assertValidationError(err, schema.CodeFieldValueMin, "price")
+
+	// Update
+	_, err = model.Mutation().Where(db.EQ("id", id)).Update(ctx, entity.New().Set("price", -1.0))
+	assertValidationError(err, schema.CodeFieldValueMin, "price")
+	affected := utils.Must(model.Mutation().Where(db.EQ("id", id)).Update(ctx, entity.New().Set("price", 3.0)))
+	assert.Equal(t, 1, affected)
+
+	// Upsert
+	_, err = model.Mutation().Upsert(ctx, entity.New().Set("sku", "abc").Set("price", -3.0), []string{"sku"}, nil)
+	assertValidationError(err, schema.CodeFieldValueMin, "price")
+
+	// The invalid records are not written
+	assert.Equal(t, 1, utils.Must(model.Query().Count(ctx)))
+	product := utils.Must(model.Query(db.EQ("id", id)).First(ctx))
+	assert.Equal(t, 3.0, product.Get("price"))
+}
+
+func TestMutationSchemaValidators(t *testing.T) {
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	productSchema := &schema.Schema{
+		Name:             "product",
+		Namespace:        "products",
+		LabelFieldName:   "sku",
+		DisableTimestamp: true,
+		Fields: []*schema.Field{
+			{Name: "sku", Label: "SKU", Type: schema.TypeString, Unique: true},
+			{Name: "price", Label: "Price", Type: schema.TypeFloat64},
+			{Name: "discount", Label: "Discount", Type: schema.TypeFloat64, Optional: true},
+		},
+		Validators: []*schema.SchemaValidator{{
+			Expr:    "($args.Data.discount ?? 0) <= $args.Data.price",
+			Message: "The discount must not be greater than the price",
+			Fields:  []string{"discount"},
+		}},
+	}
+	sb := utils.Must(schema.NewBuilderFromSchemas("", map[string]*schema.Schema{productSchema.Name: productSchema}))
+	client, err := NewClient(&db.Config{
+		Driver:       "sqlite",
+		Name:         ":memory:_" + utils.RandomString(10),
+		MigrationDir: migrationDir,
+	}, sb)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx := context.Background()
+	model := utils.Must(client.Model("product"))
+	assertValidatorError := func(err error) {
+		t.Helper()
+		var schemaErrors *schema.SchemaErrors
+		require.ErrorAs(t, err, &schemaErrors)
+		assert.Equal(t, []*schema.FieldError{{
+			Code:    schema.CodeSchemaValidatorFailed,
+			Field:   "discount",
+			Message: "The discount must not be greater than the price",
+		}}, schemaErrors.FieldErrors)
+	}
+
+	// Create
+	_, err = model.Mutation().Create(ctx, entity.New().Set("sku", "abc").Set("price", 5.0).Set("discount", 6.0))
+	assertValidatorError(err)
+	id := utils.Must(model.Mutation().Create(ctx, entity.New().Set("sku", "abc").Set("price", 10.0).Set("discount", 5.0)))
+
+	// Create many
+	_, err = model.Mutation().CreateMany(ctx, []*entity.Entity{
+		entity.New().Set("sku", "def").Set("price", 2.0),
+		entity.New().Set("sku", "ghi").Set("price", 2.0).Set("discount", 3.0),
+	})
+	assert.ErrorContains(t, err, "entity 1: ")
+	assertValidatorError(err)
+
+	// Update, the new values are merged into the existing record
+	_, err = model.Mutation().Where(db.EQ("id", id)).Update(ctx, entity.New().Set("price", 4.0))
+	assertValidatorError(err)
+	affected := utils.Must(model.Mutation().Where(db.EQ("id", id)).Update(ctx, entity.New().Set("price", 6.0)))
+	assert.Equal(t, 1, affected)
+
+	// Upsert
+	_, err = model.Mutation().Upsert(ctx, entity.New().Set("sku", "abc").Set("discount", 7.0), []string{"sku"}, nil)
+	assertValidatorError(err)
+
+	// The invalid records are not written
+	assert.Equal(t, 1, utils.Must(model.Query().Count(ctx)))
+	product := utils.Must(model.Query(db.EQ("id", id)).First(ctx))
+	assert.Equal(t, 6.0, product.Get("price"))
+	assert.Equal(t, 5.0, product.Get("discount"))
+}
+
+func TestMutationOriginalsRawRead(t *testing.T) {
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	productSchema := &schema.Schema{
+		Name:             "product",
+		Namespace:        "products",
+		LabelFieldName:   "sku",
+		DisableTimestamp: true,
+		Fields: []*schema.Field{
+			{Name: "sku", Label: "SKU", Type: schema.TypeString, Unique: true, Getter: "'masked'"},
+			{Name: "price", Label: "Price", Type: schema.TypeFloat64},
+			{Name: "discount", Label: "Discount", Type: schema.TypeFloat64, Optional: true},
+		},
+		Validators: []*schema.SchemaValidator{{
+			Expr:   "($args.Data.discount ?? 0) <= $args.Data.price",
+			Fields: []string{"discount"},
+		}},
+	}
+	sb := utils.Must(schema.NewBuilderFromSchemas("", map[string]*schema.Schema{productSchema.Name: productSchema}))
+
+	var originals []*entity.Entity
+	client, err := NewClient(&db.Config{
+		Driver:       "sqlite",
+		Name:         ":memory:_" + utils.RandomString(10),
+		MigrationDir: migrationDir,
+		Hooks: func() *db.Hooks {
+			return &db.Hooks{
+				// The discount is hidden from the query results
+				PostDBQuery: []db.PostDBQuery{func(
+					ctx context.Context,
+					option *db.QueryOption,
+					entities []*entity.Entity,
+				) ([]*entity.Entity, error) {
+					for _, e := range entities {
+						e.Delete("discount")
+					}
+					return entities, nil
+				}},
+				PostDBUpdate: []db.PostDBUpdate{func(
+					ctx context.Context,
+					s *schema.Schema,
+					predicates *[]*db.Predicate,
+					updateData *entity.Entity,
+					originalEntities []*entity.Entity,
+					affected int,
+				) error {
+					originals = originalEntities
+					return nil
+				}},
+			}
+		},
+	}, sb)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx := context.Background()
+	model := utils.Must(client.Model("product"))
+	id := utils.Must(model.Mutation().Create(ctx, entity.New().Set("sku", "abc").Set("price", 10.0).Set("discount", 5.0)))
+	product := utils.Must(model.Query(db.EQ("id", id)).First(ctx))
+	assert.Equal(t, "masked", product.Get("sku"))
+	assert.Nil(t, product.Get("discount"))
+
+	// The validators check the hidden values of the existing record
+	_, err = model.Mutation().Where(db.EQ("id", id)).Update(ctx, entity.New().Set("price", 4.0))
+	var schemaErrors *schema.SchemaErrors
+	require.ErrorAs(t, err, &schemaErrors)
+	assert.Nil(t, originals)
+
+	// The post update hooks receive the stored values of the original records
+	_ = utils.Must(model.Mutation().Where(db.EQ("id", id)).Update(ctx, entity.New().Set("price", 6.0)))
+	require.Len(t, originals, 1)
+	assert.Equal(t, "abc", originals[0].Get("sku"))
+	assert.Equal(t, 5.0, originals[0].Get("discount"))
+	assert.Equal(t, 10.0, originals[0].Get("price"))
+}
@@ -0,0 +1,437 @@
+package entdbadapter
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/schema"
+)
+
+const (
+	nestedCreateKey = "$create"
+	nestedUpsertKey = "$upsert"
+)
+
+// nestedWrite holds the related records that are created or upserted with a relation field.
+//
+//	The nested records are written using the mutation of the related schema,
+//	so that their setters and hooks are run as if they were written directly:
+//		{
+//			"tags": {
+//				"$create": [{"name": "new tag"}],
+//				"$upsert": {"conflict": ["name"], "update": ["color"], "data": [{"name": "go", "color": "blue"}]},
+//				"$add": [{"id": 1}]
+//			}
+//		}
+//	The other keys of the relation value ($add, $clear) are processed as usual.
+type nestedWrite struct {
+	field           *schema.Field
+	creates         []*entity.Entity
+	upserts         []*entity.Entity
+	conflictColumns []string
+	updateColumns   []string
+	rest            *entity.Entity
+}
+
+// hasNestedWrites checks if the entity has a relation field with nested writes.
+func hasNestedWrites(s *schema.Schema, e *entity.Entity) bool {
+	for pair := e.First(); pair != nil; pair = pair.Next() {
+		if isNestedWriteValue(s.Field(pair.Key), pair.Value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isNestedWriteValue(field *schema.Field, value any) bool {
+	if field == nil || !field.Type.IsRelationType() || field.Relation == nil {
+		return false
+	}
+
+	nested, ok := value.(*entity.Entity)
+	if !ok {
+		return false
+	}
+
+	return nested.Get(nestedCreateKey) != nil || nested.Get(nestedUpsertKey) != nil
+}
+
+// parseNestedWrites returns the nested writes of the relation fields of the entity.
+func (m *Mutation) parseNestedWrites(e *entity.Entity) ([]*nestedWrite, error) {
+	writes := []*nestedWrite{}
+	for pair := e.First(); pair != nil; pair = pair.Next() {
+		field := m.model.schema.Field(pair.Key)
+		if !isNestedWriteValue(field, pair.Value) {
+			continue
+		}
+
+		w := &nestedWrite{field: field, rest: entity.New()}
+		for nestedPair := pair.Value.(*entity.Entity).First(); nestedPair != nil; nestedPair = nestedPair.Next() {
+			var err error
+			switch nestedPair.Key {
+			case nestedCreateKey:
+				w.creates, err = nestedEntities(nestedPair.Value)
+			case nestedUpsertKey:
+				err = w.parseUpsert(nestedPair.Value)
+			default:
+				w.rest.Set(nestedPair.Key, nestedPair.Value)
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("field %s.%s.%s: %w", m.model.name, field.Name, nestedPair.Key, err)
+			}
+		}
+
+		if !w.isMultiple() && len(w.creates)+len(w.upserts) > 1 {
+			return nil, fmt.Errorf("field %s.%s: only one related record can be written", m.model.name, field.Name)
+		}
+
+		writes = append(writes, w)
+	}
+
+	return writes, nil
+}
+
+func (w *nestedWrite) parseUpsert(value any) (err error) {
+	upsert, ok := value.(*entity.Entity)
+	if !ok {
+		return fmt.Errorf("must be an object with conflict, update and data")
+	}
+
+	if w.conflictColumns, err = nestedColumns(upsert.Get("conflict")); err != nil {
+		return fmt.Errorf("conflict %w", err)
+	}
+
+	if len(w.conflictColumns) == 0 {
+		return fmt.Errorf("conflict fields are required")
+	}
+
+	if w.updateColumns, err = nestedColumns(upsert.Get("update")); err != nil {
+		return fmt.Errorf("update %w", err)
+	}
+
+	w.upserts, err = nestedEntities(upsert.Get("data"))
+	return err
+}
+
+// afterParent checks if the foreign key is stored in the related records,
+// the related records are written after the parent record so that they can reference it.
+func (w *nestedWrite) afterParent() bool {
+	return !w.field.Relation.Type.IsM2M() && !w.field.Relation.HasFKs()
+}
+
+func (w *nestedWrite) isMultiple() bool {
+	relation := w.field.Relation
+	return relation.Type.IsM2M() || (relation.Type.IsO2M() && relation.Owner)
+}
+
+// execute creates and upserts the related records and returns them.
+// If the foreign key column is set, the related records reference the given parent value.
+func (w *nestedWrite) execute(
+	ctx context.Context,
+	client db.Client,
+	fkColumn string,
+	fkValue any,
+) ([]*entity.Entity, error) {
+	model, err := client.Model(w.field.Relation.TargetSchemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range w.creates {
+		if fkColumn != "" {
+			e.Set(fkColumn, fkValue)
+		}
+
+		if _, err := model.Mutation().Create(ctx, e); err != nil {
+			return nil, fmt.Errorf("field %s.%s: %w", w.field.Relation.SourceSchemaName, w.field.Name, err)
+		}
+	}
+
+	updateColumns := w.updateColumns
+	if fkColumn != "" && len(updateColumns) > 0 && !slices.Contains(updateColumns, fkColumn) {
+		updateColumns = append(slices.Clip(updateColumns), fkColumn)
+	}
+
+	for _, e := range w.upserts {
+		if fkColumn != "" {
+			e.Set(fkColumn, fkValue)
+		}
+
+		if _, err := model.Mutation().Upsert(ctx, e, w.conflictColumns, updateColumns); err != nil {
+			return nil, fmt.Errorf("field %s.%s: %w", w.field.Relation.SourceSchemaName, w.field.Name, err)
+		}
+	}
+
+	return append(slices.Clip(w.creates), w.upserts...), nil
+}
+
+// relationValue returns the relation field value of the written and the added related records.
+func (w *nestedWrite) relationValue(written []*entity.Entity) (any, error) {
+	added, err := nestedEntities(w.rest.Get("$add"))
+	if err != nil {
+		return nil, fmt.Errorf("field %s.%s.$add: %w", w.field.Relation.SourceSchemaName, w.field.Name, err)
+	}
+
+	entities := append(added, written...)
+	if w.isMultiple() {
+		return entities, nil
+	}
+
+	if len(entities) == 0 {
+		return nil, nil
+	}
+
+	if len(entities) > 1 {
+		return nil, fmt.Errorf("field %s.%s: only one related record can be set", w.field.Relation.SourceSchemaName, w.field.Name)
+	}
+
+	return entities[0], nil
+}
+
+// runNestedWritesBeforeCreate writes the related records that are referenced by the created record
+// and sets them to the relation fields, so that the edges are created with the record.
+// The relation fields of the records that reference the created record are reset to the added records.
+func (m *Mutation) runNestedWritesBeforeCreate(ctx context.Context, e *entity.Entity, writes []*nestedWrite) error {
+	for _, w := range writes {
+		var written []*entity.Entity
+		if !w.afterParent() {
+			var err error
+			if written, err = w.execute(ctx, m.client, "", nil); err != nil {
+				return err
+			}
+		}
+
+		if w.afterParent() && w.rest.Get("$add") == nil {
+			e.Delete(w.field.Name)
+			continue
+		}
+
+		value, err := w.relationValue(written)
+		if err != nil {
+			return err
+		}
+
+		e.Set(w.field.Name, value)
+	}
+
+	return nil
+}
+
+// runNestedWritesAfterCreate writes the related records that reference the created record.
+func (m *Mutation) runNestedWritesAfterCreate(ctx context.Context, e *entity.Entity, writes []*nestedWrite) error {
+	for _, w := range writes {
+		if !w.afterParent() {
+			continue
+		}
+
+		fkColumn, parentColumn, err := w.foreignKey()
+		if err != nil {
+			return err
+		}
+
+		written, err := w.execute(ctx, m.client, fkColumn, e.Get(parentColumn))
+		if err != nil {
+			return err
+		}
+
+		value, err := w.relationValue(written)
+		if err != nil {
+			return err
+		}
+
+		e.Set(w.field.Name, value)
+	}
+
+	return nil
+}
+
+// runNestedWritesBeforeUpdate writes the related records that are referenced by the updated records
+// and replaces the relation fields with the $add blocks of the written records.
+// The nested writes of the records that reference the updated record are returned to run after the update.
+func (m *Mutation) runNestedWritesBeforeUpdate(
+	ctx context.Context,
+	e *entity.Entity,
+	writes []*nestedWrite,
+) ([]*nestedWrite, error) {
+	afterWrites := []*nestedWrite{}
+	for _, w := range writes {
+		if w.afterParent() {
+			afterWrites = append(afterWrites, w)
+			if w.rest.Empty() {
+				e.Delete(w.field.Name)
+			} else {
+				e.Set(w.field.Name, w.rest)
+			}
+			continue
+		}
+
+		written, err := w.execute(ctx, m.client, "", nil)
+		if err != nil {
+			return nil, err
+		}
+
+		added, err := nestedEntities(w.rest.Get("$add"))
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s.$add: %w", m.model.name, w.field.Name, err)
+		}
+
+		e.Set(w.field.Name, w.rest.Set("$add", append(added, written...)))
+	}
+
+	return afterWrites, nil
+}
+
+// updatedParent returns the record that is updated with the nested writes that reference it.
+// The nested writes that reference the parent record require a single updated record.
+func (m *Mutation) updatedParent(ctx context.Context, writes []*nestedWrite) (*entity.Entity, error) {
+	if len(writes) == 0 {
+		return nil, nil
+	}
+
+	columns := []string{m.model.schema.PrimaryKeyName()}
+	for _, w := range writes {
+		_, parentColumn, err := w.foreignKey()
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(columns, parentColumn) {
+			columns = append(columns, parentColumn)
+		}
+	}
+
+	parents, err := m.model.Query(*m.predicates...).Select(columns...).Limit(2).Get(db.WithPrimary(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parents) != 1 {
+		return nil, fmt.Errorf(
+			"nested writes of %s.%s require exactly one %s record to update",
+			m.model.name,
+			writes[0].field.Name,
+			m.model.name,
+		)
+	}
+
+	return parents[0], nil
+}
+
+// runNestedWritesAfterUpdate writes the related records that reference the updated record.
+func (m *Mutation) runNestedWritesAfterUpdate(
+	ctx context.Context,
+	e *entity.Entity,
+	parent *entity.Entity,
+	writes []*nestedWrite,
+) error {
+	for _, w := range writes {
+		fkColumn, parentColumn, err := w.foreignKey()
+		if err != nil {
+			return err
+		}
+
+		written, err := w.execute(ctx, m.client, fkColumn, parent.Get(parentColumn))
+		if err != nil {
+			return err
+		}
+
+		if w.rest.Empty() {
+			e.Set(w.field.Name, written)
+		}
+	}
+
+	return nil
+}
+
+// foreignKey returns the foreign key column of the related schema
+// and the column of the parent record that it references.
+func (w *nestedWrite) foreignKey() (fkColumn, parentColumn string, err error) {
+	backRef := w.field.Relation.BackRef
+	if backRef == nil || backRef.SourceColumn == "" || backRef.TargetColumn == "" {
+		return "", "", fmt.Errorf(
+			"field %s.%s: foreign key of the related schema %s not found",
+			w.field.Relation.SourceSchemaName,
+			w.field.Name,
+			w.field.Relation.TargetSchemaName,
+		)
+	}
+
+	return backRef.SourceColumn, backRef.TargetColumn, nil
+}
+
+// withTx runs the mutation in a new transaction.
+// It is used to write the entity and its nested records atomically.
+// The post mutation hooks of the entity and of its nested records are run after the transaction is committed.
+func (m *Mutation) withTx(ctx context.Context, fn func(ctx context.Context, mutation db.Mutator) error) error {
+	txCtx, postHooks := withPostHooksQueue(ctx)
+	if err := db.WithTx(m.client, txCtx, func(tx db.Client) error {
+		model, err := tx.Model(m.model.name)
+		if err != nil {
+			return err
+		}
+
+		return fn(txCtx, model.Mutation().Where(*m.predicates...))
+	}); err != nil {
+		return err
+	}
+
+	return postHooks.run(m.client)
+}
+
+// stripNestedWrites replaces the relation values of the nested writes with their other keys ($add, $clear),
+// so that the pre mutation hooks and the validation do not see the nested records.
+// The relation values are set again when the nested records are written.
+func stripNestedWrites(e *entity.Entity, writes []*nestedWrite) {
+	for _, w := range writes {
+		if w.rest.Empty() {
+			e.Delete(w.field.Name)
+			continue
+		}
+
+		e.Set(w.field.Name, w.rest)
+	}
+}
+
+func nestedEntities(value any) ([]*entity.Entity, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case *entity.Entity:
+		return []*entity.Entity{v}, nil
+	case []*entity.Entity:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("must be an object or a list of objects")
+	}
+}
+
+func nestedColumns(value any) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return strings.Split(v, ","), nil
+	case []string:
+		return v, nil
+	case []any:
+		columns := make([]string, len(v))
+		for i, column := range v {
+			columnName, ok := column.(string)
+			if !ok {
+				return nil, fmt.Errorf("must be a list of field names")
+			}
+
+			columns[i] = columnName
+		}
+
+		return columns, nil
+	default:
+		return nil, fmt.Errorf("must be a list of field names")
+	}
+}
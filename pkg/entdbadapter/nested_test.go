@@ -0,0 +1,211 @@
+package entdbadapter
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNestedWrites(t *testing.T) {
+	ctx := context.Background()
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	createHookSchemas := []string{}
+	client, err := NewClient(&db.Config{
+		Driver:       "sqlite",
+		Name:         ":memory:_" + utils.RandomString(10),
+		MigrationDir: migrationDir,
+		Hooks: func() *db.Hooks {
+			return &db.Hooks{
+				PreDBCreate: []db.PreDBCreate{
+					func(ctx context.Context, s *schema.Schema, e *entity.Entity) error {
+						createHookSchemas = append(createHookSchemas, s.Name)
+						return nil
+					},
+				},
+			}
+		},
+	}, createQuantifierSchemaBuilder(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	postModel := utils.Must(client.Model("post"))
+	commentModel := utils.Must(client.Model("comment"))
+	tagModel := utils.Must(client.Model("tag"))
+	_ = utils.Must(tagModel.CreateFromJSON(ctx, `{"name": "existing tag"}`))
+	createHookSchemas = nil
+
+	post := func(id any) *entity.Entity {
+		return utils.Must(postModel.Query(db.EQ("id", id)).Select("title", "comments.content", "tags.name").First(ctx))
+	}
+	names := func(e *entity.Entity, field, column string) []string {
+		return utils.Map(e.Get(field, []*entity.Entity{}).([]*entity.Entity), func(e *entity.Entity) string {
+			return e.GetString(column)
+		})
+	}
+
+	// Case 1: Create a post with new comments and tags
+	postEntity := utils.Must(entity.NewEntityFromJSON(`{
+		"title": "post 1",
+		"comments": {"$create": [{"content": "comment 1", "approved": true}, {"content": "comment 2", "approved": true}]},
+		"tags": {
+			"$create": {"name": "new tag"},
+			"$upsert": {"conflict": "id", "data": [{"id": 1, "name": "upserted tag"}]},
+			"$add": [{"id": 1}]
+		}
+	}`))
+	postID := utils.Must(postModel.Mutation().Create(ctx, postEntity))
+	// The upserted tag exists, so that the update hooks are run instead of the create hooks
+	assert.Equal(t, []string{"post", "tag", "comment", "comment"}, createHookSchemas)
+	assert.Len(t, postEntity.Get("comments"), 2)
+	assert.NotNil(t, postEntity.Get("comments").([]*entity.Entity)[0].ID())
+
+	created := post(postID)
+	assert.Equal(t, []string{"comment 1", "comment 2"}, names(created, "comments", "content"))
+	assert.ElementsMatch(t, []string{"upserted tag", "new tag"}, names(created, "tags", "name"))
+
+	// Case 2: Create a comment with a new post
+	commentEntity := utils.Must(entity.NewEntityFromJSON(`{
+		"content": "comment 3",
+		"approved": true,
+		"post": {"$create": {"title": "post 2"}}
+	}`))
+	commentID := utils.Must(commentModel.Mutation().Create(ctx, commentEntity))
+	comment := utils.Must(commentModel.Query(db.EQ("id", commentID)).Select("content", "post.title").First(ctx))
+	assert.Equal(t, "post 2", comment.Get("post").(*entity.Entity).GetString("title"))
+
+	// Case 3: Update a post with new comments and tags
+	_ = utils.Must(postModel.Mutation().Where(db.EQ("id", postID)).Update(ctx, utils.Must(entity.NewEntityFromJSON(`{
+		"title": "post 1 updated",
+		"comments": {"$create": {"content": "comment 4", "approved": true}},
+		"tags": {"$clear": true, "$create": [{"name": "tag 3"}]}
+	}`))))
+	updated := post(postID)
+	assert.Equal(t, "post 1 updated", updated.GetString("title"))
+	assert.Equal(t, []string{"comment 1", "comment 2", "comment 4"}, names(updated, "comments", "content"))
+	assert.Equal(t, []string{"tag 3"}, names(updated, "tags", "name"))
+
+	// Case 4: Upsert the comments of a post
+	_ = utils.Must(postModel.Mutation().Where(db.EQ("id", postID)).Update(ctx, utils.Must(entity.NewEntityFromJSON(`{
+		"comments": {"$upsert": {"conflict": ["id"], "update": ["content"], "data": [
+			{"id": 1, "content": "comment 1 upserted", "approved": true},
+			{"id": 3, "content": "comment 3 moved", "approved": true}
+		]}}
+	}`))))
+	assert.Equal(
+		t,
+		[]string{"comment 1 upserted", "comment 2", "comment 3 moved", "comment 4"},
+		names(post(postID), "comments", "content"),
+	)
+
+	// Case 5: The writes are rolled back if a nested write fails
+	postCount := utils.Must(postModel.Query().Count(ctx))
+	_, err = postModel.Mutation().Create(ctx, utils.Must(entity.NewEntityFromJSON(`{
+		"title": "post 3",
+		"tags": {"$create": {"name": "tag 4"}},
+		"comments": {"$create": {"invalid": "comment"}}
+	}`)))
+	assert.ErrorContains(t, err, "field post.comments: column error")
+	assert.Equal(t, postCount, utils.Must(postModel.Query().Count(ctx)))
+	assert.Equal(t, 0, utils.Must(tagModel.Query(db.EQ("name", "tag 4")).Count(ctx)))
+
+	_, err = postModel.Mutation().Where(db.EQ("id", postID)).Update(ctx, utils.Must(entity.NewEntityFromJSON(`{
+		"title": "post 1 failed",
+		"tags": {"$create": {"name": "tag 5"}},
+		"comments": {"$create": {"invalid": "comment"}}
+	}`)))
+	assert.Error(t, err)
+	assert.Equal(t, "post 1 updated", post(postID).GetString("title"))
+	assert.Equal(t, 0, utils.Must(tagModel.Query(db.EQ("name", "tag 5")).Count(ctx)))
+
+	// Case 6: The nested writes that reference the updated record require a single record
+	_, err = postModel.Mutation().Update(ctx, utils.Must(entity.NewEntityFromJSON(`{
+		"comments": {"$create": {"content": "comment", "approved": true}}
+	}`)))
+	assert.ErrorContains(t, err, "nested writes of post.comments require exactly one post record to update")
+
+	// Case 7: Invalid nested writes
+	invalidWrites := map[string]string{
+		`{"title": "p", "comments": {"$create": "invalid"}}`:                              "field post.comments.$create: must be an object or a list of objects",
+		`{"title": "p", "comments": {"$upsert": [{"id": 1}]}}`:                            "field post.comments.$upsert: must be an object with conflict, update and data",
+		`{"title": "p", "comments": {"$upsert": {"data": [{"id": 1}]}}}`:                  "field post.comments.$upsert: conflict fields are required",
+		`{"title": "p", "comments": {"$upsert": {"conflict": [1], "data": [{"id": 1}]}}}`: "conflict must be a list of field names",
+		`{"content": "c", "post": {"$create": [{"title": "a"}, {"title": "b"}]}}`:         "field comment.post: only one related record can be written",
+	}
+	for data, expectError := range invalidWrites {
+		e := utils.Must(entity.NewEntityFromJSON(data))
+		model := utils.If(e.Get("title") != nil, postModel, commentModel)
+		_, err := model.Mutation().Create(ctx, e)
+		assert.ErrorContains(t, err, expectError, data)
+	}
+}
+
+func TestNestedWritesHooks(t *testing.T) {
+	ctx := context.Background()
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	preHookData := []string{}
+	postHookSchemas := []string{}
+	client, err := NewClient(&db.Config{
+		Driver:       "sqlite",
+		Name:         ":memory:_" + utils.RandomString(10),
+		MigrationDir: migrationDir,
+		Hooks: func() *db.Hooks {
+			return &db.Hooks{
+				PreDBCreate: []db.PreDBCreate{
+					func(ctx context.Context, s *schema.Schema, e *entity.Entity) error {
+						preHookData = append(preHookData, s.Name+":"+string(utils.Must(e.ToJSON())))
+						return nil
+					},
+				},
+				PostDBCreate: []db.PostDBCreate{
+					func(ctx context.Context, s *schema.Schema, e *entity.Entity, id any) error {
+						// The post hooks are run after the transaction is committed
+						if db.TxFromContext(ctx, nil) != nil {
+							return assert.AnError
+						}
+
+						postHookSchemas = append(postHookSchemas, s.Name)
+						return nil
+					},
+				},
+			}
+		},
+	}, createQuantifierSchemaBuilder(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	postModel := utils.Must(client.Model("post"))
+	_ = utils.Must(postModel.Mutation().Create(ctx, utils.Must(entity.NewEntityFromJSON(`{
+		"title": "post 1",
+		"comments": {"$create": [{"content": "comment 1", "approved": true}]},
+		"tags": {"$create": {"name": "tag 1"}}
+	}`))))
+
+	// The pre hooks of the parent record do not see the nested records
+	assert.Equal(t, []string{
+		`post:{"title":"post 1"}`,
+		`tag:{"name":"tag 1"}`,
+		`comment:{"content":"comment 1","approved":true,"post_id":1}`,
+	}, preHookData)
+	assert.Equal(t, []string{"tag", "comment", "post"}, postHookSchemas)
+
+	// The post hooks are not run if the transaction is rolled back
+	postHookSchemas = nil
+	_, err = postModel.Mutation().Create(ctx, utils.Must(entity.NewEntityFromJSON(`{
+		"title": "post 2",
+		"tags": {"$create": {"name": "tag 2"}},
+		"comments": {"$create": {"invalid": "comment"}}
+	}`)))
+	assert.Error(t, err)
+	assert.Empty(t, postHookSchemas)
+}
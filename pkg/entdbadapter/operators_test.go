@@ -0,0 +1,128 @@
+package entdbadapter
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	dialectSql "entgo.io/ent/dialect/sql"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterOperatorsQuery(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Dialect    string
+		Predicates []*db.Predicate
+		Expect     func(sqlmock.Sqlmock)
+	}{
+		{
+			Name: "mysql",
+			Predicates: []*db.Predicate{
+				db.Between("id", 1, 10),
+				db.StartsWith("name", "50%_"),
+				db.EndsWith("name", "son"),
+				db.Regex("name", "^jo(hn)?"),
+				db.Exists("metadata", true),
+				db.Exists("metadata.color", false),
+				db.Between("metadata.size", 1, 2),
+			},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `products` WHERE `products`.`id` BETWEEN ? AND ? AND `products`.`name` LIKE ? AND `products`.`name` LIKE ? AND `products`.`name` REGEXP ? AND `products`.`metadata` IS NOT NULL AND JSON_EXTRACT(`products`.`metadata`, '$.\"color\"') IS NULL AND JSON_EXTRACT(`products`.`metadata`, '$.\"size\"') BETWEEN ? AND ?")).
+					WithArgs(1, 10, "50\\%\\_%", "%son", "^jo(hn)?", 1, 2).
+					WillReturnRows(mock.NewRows([]string{"id", "name"}).AddRow(1, "A"))
+			},
+		},
+		{
+			Name:    "postgres",
+			Dialect: dialect.Postgres,
+			Predicates: []*db.Predicate{
+				db.Between("id", 1, 10),
+				db.StartsWith("name", "jo"),
+				db.Regex("name", "^jo(hn)?"),
+				db.Exists("metadata.color", true),
+				db.Between("metadata.size", 1.5, 2),
+			},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery(`SELECT * FROM "products" WHERE "products"."id" BETWEEN $1 AND $2 AND "products"."name" LIKE $3 AND "products"."name" ~ $4 AND ("products"."metadata"->'color') IS NOT NULL AND ("products"."metadata"->>'size')::numeric BETWEEN $5 AND $6`)).
+					WithArgs(1, 10, "jo%", "^jo(hn)?", 1.5, 2).
+					WillReturnRows(mock.NewRows([]string{"id", "name"}).AddRow(1, "A"))
+			},
+		},
+		{
+			Name:       "relation",
+			Predicates: []*db.Predicate{db.Regex("vendor.name", "^V")},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `products` WHERE EXISTS (SELECT `vendors`.`id` FROM `vendors` WHERE `products`.`vendor_id` = `vendors`.`id` AND `vendors`.`name` REGEXP ?)")).
+					WithArgs("^V").
+					WillReturnRows(mock.NewRows([]string{"id", "name"}).AddRow(1, "A"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			dialectName := utils.If(tt.Dialect == "", dialect.MySQL, tt.Dialect)
+			client := utils.Must(NewEntClient(&db.Config{
+				Driver: "sqlmock",
+			}, createJSONSchemaBuilder(t), dialectSql.OpenDB(dialectName, mockDB)))
+
+			tt.Expect(mock)
+			model := utils.Must(client.Model("product"))
+			_, err = model.Query(tt.Predicates...).Get(context.Background())
+			require.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFilterOperatorsSQLite(t *testing.T) {
+	ctx := context.Background()
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	client, err := NewTestClient(migrationDir, createJSONSchemaBuilder(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	model := utils.Must(client.Model("product"))
+	for _, product := range []string{
+		`{"name": "John", "metadata": {"color": "red", "size": 10}}`,
+		`{"name": "Johnson", "metadata": {"color": null, "size": 2}}`,
+		`{"name": "50%_off"}`,
+		`{"name": "500 off", "metadata": {"size": 5}}`,
+	} {
+		_ = utils.Must(model.CreateFromJSON(ctx, product))
+	}
+
+	names := func(filter string) []string {
+		predicates := utils.Must(db.CreatePredicatesFromFilterObject(client.SchemaBuilder(), model.Schema(), filter))
+		products, err := model.Query(predicates...).Order("id").Get(ctx)
+		require.NoError(t, err)
+		return utils.Map(products, func(e *entity.Entity) string {
+			return e.GetString("name")
+		})
+	}
+
+	assert.Equal(t, []string{"Johnson", "50%_off"}, names(`{"id": {"$between": [2, 3]}}`))
+	assert.Equal(t, []string{"Johnson", "500 off"}, names(`{"metadata.size": {"$between": [2, 5]}}`))
+	assert.Equal(t, []string{"John", "Johnson"}, names(`{"name": {"$startswith": "Jo"}}`))
+	assert.Equal(t, []string{"50%_off"}, names(`{"name": {"$startswith": "50%_"}}`))
+	assert.Equal(t, []string{"Johnson"}, names(`{"name": {"$endswith": "son"}}`))
+	assert.Equal(t, []string{"50%_off", "500 off"}, names(`{"name": {"$endswith": "off"}}`))
+	assert.Equal(t, []string{"John"}, names(`{"name": {"$regex": "^Jo(hn)?$"}}`))
+	assert.Equal(t, []string{"50%_off", "500 off"}, names(`{"name": {"$regex": "^[0-9]+"}}`))
+	assert.Equal(t, []string{"John", "Johnson", "500 off"}, names(`{"metadata": {"$exists": true}}`))
+	assert.Equal(t, []string{"50%_off"}, names(`{"metadata": {"$exists": false}}`))
+	assert.Equal(t, []string{"John", "Johnson"}, names(`{"metadata.color": {"$exists": true}}`))
+	assert.Equal(t, []string{"John"}, names(`{"metadata.color": {"$null": false}}`))
+}
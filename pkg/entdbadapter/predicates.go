@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"entgo.io/ent/dialect"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/fastschema/fastschema/db"
@@ -62,6 +63,32 @@ func createEntPredicates(
 		// This allows using db.EQ("teams.slug", value) for relation filtering
 		relationFields, fieldName := parseFieldPath(p.Field)
 
+		// The JSON path is the remaining path after the relation fields (e.g. "owner.metadata.color")
+		jsonPath, err := db.ParseJSONPath(entAdapter.SchemaBuilder(), model.schema, p.Field)
+		if err != nil {
+			return nil, err
+		}
+
+		if jsonPath != nil {
+			relationFields, fieldName = jsonPath.Relations, jsonPath.FieldPath()
+		}
+
+		// The relation quantifiers filter the records by their related records
+		// E.g. db.None("comments") or db.Count("posts", db.GT("posts", 5))
+		if p.Operator.IsRelationQuantifier() && len(relationFields) == 0 {
+			if jsonPath != nil {
+				return nil, fmt.Errorf("operator %s is not supported on JSON field %s", p.Operator, p.Field)
+			}
+
+			predicateFn, err := createRelationQuantifierPredicate(entAdapter, model, p)
+			if err != nil {
+				return nil, err
+			}
+
+			predicateFns = append(predicateFns, predicateFn)
+			continue
+		}
+
 		// Check if this is a relation field without dot notation (implicit PK filter)
 		// E.g. db.EQ("tags", 1) should be transformed to db.EQ("tags.id", 1)
 		if len(relationFields) == 0 && p.Field != "" {
@@ -83,6 +110,25 @@ func createEntPredicates(
 			}
 		}
 
+		if len(relationFields) > 0 && p.Operator == db.OpSearch {
+			return nil, fmt.Errorf("operator %s is not supported on relation field %s", p.Operator, p.Field)
+		}
+
+		if jsonPath != nil && p.Operator == db.OpSearch {
+			return nil, fmt.Errorf("operator %s is not supported on JSON field %s", p.Operator, p.Field)
+		}
+
+		if p.Operator == db.OpSearch {
+			field := model.schema.Field(p.Field)
+			if field == nil {
+				return nil, schema.ErrFieldNotFound(model.schema.Name, p.Field)
+			}
+
+			if !field.Searchable {
+				return nil, fmt.Errorf("field %s is not searchable", p.Field)
+			}
+		}
+
 		if len(relationFields) > 0 {
 			relationPredicateFn, err := createRelationsPredicate(
 				entAdapter,
@@ -99,6 +145,16 @@ func createEntPredicates(
 			continue
 		}
 
+		if jsonPath != nil {
+			predicateFn, err := createJSONFieldPredicate(entAdapter.Driver().Dialect(), jsonPath, p)
+			if err != nil {
+				return nil, err
+			}
+
+			predicateFns = append(predicateFns, predicateFn)
+			continue
+		}
+
 		if p.Field != "" {
 			predicateFn, err := CreateFieldPredicate(p)
 			if err != nil {
@@ -154,46 +210,11 @@ func createRelationsPredicate(
 	relationFieldName := relationFieldNames[0]
 	relationFieldNames = relationFieldNames[1:]
 	hasNestedRelations := len(relationFieldNames) > 0
-	relationField := model.schema.Field(relationFieldName)
-
-	if relationField == nil {
-		return nil, schema.ErrFieldNotFound(model.schema.Name, relationFieldName)
-	}
-
-	relation := relationField.Relation
-
-	targetModel, err := model.client.Model(relation.TargetSchemaName)
+	relationStep, entTargetModel, err := createRelationStep(entAdapter, model, relationFieldName)
 	if err != nil {
 		return nil, err
 	}
 
-	entTargetModel, ok := targetModel.(*Model)
-	if !ok {
-		return nil, fmt.Errorf("model %s is not an ent model", targetModel.Schema().Name)
-	}
-
-	stepOption, err := entAdapter.NewEdgeStepOption(relation)
-	if err != nil {
-		return nil, fmt.Errorf("invalid edge step option '%s': %w", relationFieldName, err)
-	}
-
-	fromColumn := model.entPrimaryColumn.Name
-	toColumn := entTargetModel.entPrimaryColumn.Name
-
-	if column := relationStepFromColumn(model, relation); column != "" {
-		fromColumn = column
-	}
-
-	if column := relationStepToColumn(entTargetModel, relation); column != "" {
-		toColumn = column
-	}
-
-	relationStep := sqlgraph.NewStep(
-		sqlgraph.From(model.schema.Namespace, fromColumn),
-		sqlgraph.To(entTargetModel.schema.Namespace, toColumn),
-		stepOption,
-	)
-
 	var pred func(*sql.Selector)
 	useNegatedExists := false
 	if hasNestedRelations {
@@ -222,7 +243,7 @@ func createRelationsPredicate(
 			}
 		}
 
-		predFn, err := createEntPredicates(entAdapter, model, []*db.Predicate{targetPredicate})
+		predFn, err := createEntPredicates(entAdapter, entTargetModel, []*db.Predicate{targetPredicate})
 		if err != nil {
 			return nil, err
 		}
@@ -243,6 +264,159 @@ func createRelationsPredicate(
 	}, nil
 }
 
+// createRelationStep creates the graph step from the model to the target model of the relation field.
+func createRelationStep(
+	entAdapter EntAdapter,
+	model *Model,
+	relationFieldName string,
+) (*sqlgraph.Step, *Model, error) {
+	relationField := model.schema.Field(relationFieldName)
+	if relationField == nil {
+		return nil, nil, schema.ErrFieldNotFound(model.schema.Name, relationFieldName)
+	}
+
+	if !relationField.Type.IsRelationType() {
+		return nil, nil, fmt.Errorf("%s is not a relation field", relationFieldName)
+	}
+
+	relation := relationField.Relation
+
+	targetModel, err := model.client.Model(relation.TargetSchemaName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entTargetModel, ok := targetModel.(*Model)
+	if !ok {
+		return nil, nil, fmt.Errorf("model %s is not an ent model", targetModel.Schema().Name)
+	}
+
+	stepOption, err := entAdapter.NewEdgeStepOption(relation)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid edge step option '%s': %w", relationFieldName, err)
+	}
+
+	fromColumn := model.entPrimaryColumn.Name
+	toColumn := entTargetModel.entPrimaryColumn.Name
+
+	if column := relationStepFromColumn(model, relation); column != "" {
+		fromColumn = column
+	}
+
+	if column := relationStepToColumn(entTargetModel, relation); column != "" {
+		toColumn = column
+	}
+
+	relationStep := sqlgraph.NewStep(
+		sqlgraph.From(model.schema.Namespace, fromColumn),
+		sqlgraph.To(entTargetModel.schema.Namespace, toColumn),
+		stepOption,
+	)
+
+	return relationStep, entTargetModel, nil
+}
+
+// createRelationQuantifierPredicate creates the predicate of a relation quantifier ($some, $none, $every, $count).
+// $some, $none and $every compile to a correlated EXISTS subquery on the related records,
+// $count compares the result of a correlated COUNT subquery.
+func createRelationQuantifierPredicate(
+	entAdapter EntAdapter,
+	model *Model,
+	p *db.Predicate,
+) (PredicateFN, error) {
+	predicates, ok := p.Value.([]*db.Predicate)
+	if !ok {
+		return nil, fmt.Errorf("value of field %s.%s = %v (%T) must be predicates", p.Field, p.Operator, p.Value, p.Value)
+	}
+
+	relationStep, entTargetModel, err := createRelationStep(entAdapter, model, p.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Operator == db.OpCount {
+		countPredicateFns := make([]PredicateFN, 0, len(predicates))
+		for _, countPredicate := range predicates {
+			countPredicateFn, err := createColumnPredicate(countPredicate, func(s *sql.Selector) string {
+				return neighborsCountExpr(s, relationStep)
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			countPredicateFns = append(countPredicateFns, countPredicateFn)
+		}
+
+		return func(s *sql.Selector) *sql.Predicate {
+			return sql.And(utils.Map(countPredicateFns, func(fn PredicateFN) *sql.Predicate {
+				return fn(s)
+			})...)
+		}, nil
+	}
+
+	if p.Operator == db.OpEvery && len(predicates) == 0 {
+		return nil, fmt.Errorf("operator %s of field %s requires at least one predicate", p.Operator, p.Field)
+	}
+
+	targetPredicatesFn, err := createEntPredicates(entAdapter, entTargetModel, predicates)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(selector *sql.Selector) *sql.Predicate {
+		s1 := selector.Clone().SetP(nil)
+		sqlgraph.HasNeighborsWith(s1, relationStep, func(s2 *sql.Selector) {
+			targetPredicates := targetPredicatesFn(s2)
+			if len(targetPredicates) == 0 {
+				return
+			}
+
+			// Every related record matches if there is no related record that does not match.
+			if p.Operator == db.OpEvery {
+				s2.Where(sql.Not(sql.And(targetPredicates...)))
+				return
+			}
+
+			s2.Where(sql.And(targetPredicates...))
+		})
+
+		if p.Operator == db.OpSome {
+			return s1.P()
+		}
+
+		return sql.Not(s1.P())
+	}, nil
+}
+
+// neighborsCountExpr returns the correlated subquery that counts the related records of the step.
+func neighborsCountExpr(q *sql.Selector, s *sqlgraph.Step) string {
+	builder := sql.Dialect(q.Dialect())
+	count := builder.Select(sql.Count("*"))
+
+	switch {
+	case s.ThroughEdgeTable():
+		// The junction table column that references the source records.
+		fromColumn := utils.If(s.Edge.Inverse, s.Edge.Columns[1], s.Edge.Columns[0])
+		edge := builder.Table(s.Edge.Table).Schema(s.Edge.Schema)
+		count.From(edge).Where(sql.ColumnsEQ(edge.C(fromColumn), q.C(s.From.Column)))
+	case s.FromEdgeOwner():
+		to := builder.Table(s.To.Table).Schema(s.To.Schema)
+		if s.To.Table == q.TableName() {
+			to.As(s.To.Table + "_edge")
+		}
+		count.From(to).Where(sql.ColumnsEQ(to.C(s.To.Column), q.C(s.Edge.Columns[0])))
+	default:
+		edge := builder.Table(s.Edge.Table).Schema(s.Edge.Schema)
+		if s.Edge.Table == q.TableName() {
+			edge.As(s.Edge.Table + "_edge")
+		}
+		count.From(edge).Where(sql.ColumnsEQ(edge.C(s.Edge.Columns[0]), q.C(s.From.Column)))
+	}
+
+	query, _ := count.Query()
+	return "(" + query + ")"
+}
+
 // =============================================================================
 // Predicate Helper Functions
 // =============================================================================
@@ -306,10 +480,18 @@ var simplePredicateMap = map[db.OperatorType]simplePredicateBuilder{
 
 // CreateFieldPredicate convert a predicate to ent predicate
 func CreateFieldPredicate(predicate *db.Predicate) (PredicateFN, error) {
+	return createColumnPredicate(predicate, func(s *sql.Selector) string {
+		return columnWrap(predicate.Field, s)
+	})
+}
+
+// createColumnPredicate converts a predicate to ent predicate
+// that compares the column expression returned by the column function.
+func createColumnPredicate(predicate *db.Predicate, column func(*sql.Selector) string) (PredicateFN, error) {
 	// Check for simple comparison operators first
 	if builder, ok := simplePredicateMap[predicate.Operator]; ok {
 		return func(s *sql.Selector) *sql.Predicate {
-			return builder(columnWrap(predicate.Field, s), predicate.Value)
+			return builder(column(s), predicate.Value)
 		}, nil
 	}
 
@@ -320,7 +502,7 @@ func CreateFieldPredicate(predicate *db.Predicate) (PredicateFN, error) {
 			return nil, err
 		}
 		return func(s *sql.Selector) *sql.Predicate {
-			return sql.Like(columnWrap(predicate.Field, s), stringValue)
+			return sql.Like(column(s), stringValue)
 		}, nil
 
 	case db.OpNotLike:
@@ -329,7 +511,7 @@ func CreateFieldPredicate(predicate *db.Predicate) (PredicateFN, error) {
 			return nil, err
 		}
 		return func(s *sql.Selector) *sql.Predicate {
-			return sql.Not(sql.Like(columnWrap(predicate.Field, s), stringValue))
+			return sql.Not(sql.Like(column(s), stringValue))
 		}, nil
 
 	case db.OpContains:
@@ -338,7 +520,7 @@ func CreateFieldPredicate(predicate *db.Predicate) (PredicateFN, error) {
 			return nil, err
 		}
 		return func(s *sql.Selector) *sql.Predicate {
-			return sql.Contains(columnWrap(predicate.Field, s), stringValue)
+			return sql.Contains(column(s), stringValue)
 		}, nil
 
 	case db.OpNotContains:
@@ -347,7 +529,7 @@ func CreateFieldPredicate(predicate *db.Predicate) (PredicateFN, error) {
 			return nil, err
 		}
 		return func(s *sql.Selector) *sql.Predicate {
-			return sql.Not(sql.Contains(columnWrap(predicate.Field, s), stringValue))
+			return sql.Not(sql.Contains(column(s), stringValue))
 		}, nil
 
 	case db.OpContainsFold:
@@ -356,7 +538,7 @@ func CreateFieldPredicate(predicate *db.Predicate) (PredicateFN, error) {
 			return nil, err
 		}
 		return func(s *sql.Selector) *sql.Predicate {
-			return sql.ContainsFold(columnWrap(predicate.Field, s), stringValue)
+			return sql.ContainsFold(column(s), stringValue)
 		}, nil
 
 	case db.OpNotContainsFold:
@@ -365,7 +547,7 @@ func CreateFieldPredicate(predicate *db.Predicate) (PredicateFN, error) {
 			return nil, err
 		}
 		return func(s *sql.Selector) *sql.Predicate {
-			return sql.Not(sql.ContainsFold(columnWrap(predicate.Field, s), stringValue))
+			return sql.Not(sql.ContainsFold(column(s), stringValue))
 		}, nil
 
 	case db.OpIN, db.OpNIN:
@@ -375,13 +557,73 @@ func CreateFieldPredicate(predicate *db.Predicate) (PredicateFN, error) {
 		}
 		return func(s *sql.Selector) *sql.Predicate {
 			op := utils.If(predicate.Operator == db.OpIN, sql.In, sql.NotIn)
-			return op(columnWrap(predicate.Field, s), arrayValue...)
+			return op(column(s), arrayValue...)
 		}, nil
 
 	case db.OpNULL:
 		return func(s *sql.Selector) *sql.Predicate {
 			op := utils.If(predicate.Value == true, sql.IsNull, sql.NotNull)
-			return op(columnWrap(predicate.Field, s))
+			return op(column(s))
+		}, nil
+
+	case db.OpBetween:
+		arrayValue, err := validateArrayValue(predicate)
+		if err != nil {
+			return nil, err
+		}
+		if len(arrayValue) != 2 {
+			return nil, fmt.Errorf(
+				"value of field %s.%s = %v must be an array of two values",
+				predicate.Field,
+				predicate.Operator,
+				predicate.Value,
+			)
+		}
+		return func(s *sql.Selector) *sql.Predicate {
+			return sql.P(func(b *sql.Builder) {
+				b.Ident(column(s)).WriteString(" BETWEEN ").Arg(arrayValue[0]).WriteString(" AND ").Arg(arrayValue[1])
+			})
+		}, nil
+
+	case db.OpStartsWith, db.OpEndsWith:
+		stringValue, err := validateStringValue(predicate)
+		if err != nil {
+			return nil, err
+		}
+		return func(s *sql.Selector) *sql.Predicate {
+			// The LIKE wildcards in the value are escaped by the ent predicates.
+			op := utils.If(predicate.Operator == db.OpStartsWith, sql.HasPrefix, sql.HasSuffix)
+			return op(column(s), stringValue)
+		}, nil
+
+	case db.OpRegex:
+		stringValue, err := validateStringValue(predicate)
+		if err != nil {
+			return nil, err
+		}
+		return func(s *sql.Selector) *sql.Predicate {
+			return sql.P(func(b *sql.Builder) {
+				// SQLite REGEXP is provided by the function registered in init.
+				op := utils.If(s.Dialect() == dialect.Postgres, " ~ ", " REGEXP ")
+				b.Ident(column(s)).WriteString(op).Arg(stringValue)
+			})
+		}, nil
+
+	case db.OpExists:
+		return func(s *sql.Selector) *sql.Predicate {
+			op := utils.If(predicate.Value == true, sql.NotNull, sql.IsNull)
+			return op(column(s))
+		}, nil
+
+	case db.OpSearch:
+		stringValue, err := validateStringValue(predicate)
+		if err != nil {
+			return nil, err
+		}
+		return func(s *sql.Selector) *sql.Predicate {
+			return sql.P(func(b *sql.Builder) {
+				writeSearchMatch(b, s, predicate.Field, stringValue)
+			})
 		}, nil
 
 	default:
@@ -404,6 +404,50 @@ func TestCreateFieldPredicate(t *testing.T) {
 			},
 			expectError: errors.New("value of field name.$notcontainsfold = 123 (int) must be string"),
 		},
+		{
+			name:      "Between",
+			predicate: db.Between("age", 1, 10),
+			expectSQLPredicate: dialectSql.P(func(b *dialectSql.Builder) {
+				b.Ident("age").WriteString(" BETWEEN ").Arg(1).WriteString(" AND ").Arg(10)
+			}),
+		},
+		{
+			name:        "BetweenInvalid",
+			predicate:   &db.Predicate{Field: "age", Operator: db.OpBetween, Value: []any{1}},
+			expectError: errors.New("value of field age.$between = [1] must be an array of two values"),
+		},
+		{
+			name:               "StartsWith",
+			predicate:          db.StartsWith("name", "50%_"),
+			expectSQLPredicate: dialectSql.HasPrefix("name", "50%_"),
+		},
+		{
+			name:               "EndsWith",
+			predicate:          db.EndsWith("name", "son"),
+			expectSQLPredicate: dialectSql.HasSuffix("name", "son"),
+		},
+		{
+			name:        "EndsWithInvalid",
+			predicate:   &db.Predicate{Field: "name", Operator: db.OpEndsWith, Value: 1},
+			expectError: errors.New("value of field name.$endswith = 1 (int) must be string"),
+		},
+		{
+			name:      "Regex",
+			predicate: db.Regex("name", "^jo"),
+			expectSQLPredicate: dialectSql.P(func(b *dialectSql.Builder) {
+				b.Ident("name").WriteString(" REGEXP ").Arg("^jo")
+			}),
+		},
+		{
+			name:               "ExistsTrue",
+			predicate:          db.Exists("bio", true),
+			expectSQLPredicate: dialectSql.NotNull("bio"),
+		},
+		{
+			name:               "ExistsFalse",
+			predicate:          db.Exists("bio", false),
+			expectSQLPredicate: dialectSql.IsNull("bio"),
+		},
 		// NIN (NotIn)
 		{
 			name: "NINInvalid",
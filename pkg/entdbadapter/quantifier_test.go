@@ -0,0 +1,295 @@
+package entdbadapter
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	dialectSql "entgo.io/ent/dialect/sql"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createQuantifierSchemaBuilder(t *testing.T) *schema.Builder {
+	t.Helper()
+	postSchema := &schema.Schema{
+		Name:             "post",
+		Namespace:        "posts",
+		LabelFieldName:   "title",
+		DisableTimestamp: true,
+		Fields: []*schema.Field{
+			{Name: "id", Label: "ID", Type: schema.TypeUint64},
+			{Name: "title", Label: "Title", Type: schema.TypeString},
+			{
+				Name:  "comments",
+				Label: "Comments",
+				Type:  schema.TypeRelation,
+				Relation: &schema.Relation{
+					Type:             schema.O2M,
+					Owner:            true,
+					TargetSchemaName: "comment",
+					TargetFieldName:  "post",
+				},
+			},
+			{
+				Name:  "tags",
+				Label: "Tags",
+				Type:  schema.TypeRelation,
+				Relation: &schema.Relation{
+					Type:             schema.M2M,
+					Owner:            true,
+					TargetSchemaName: "tag",
+					TargetFieldName:  "posts",
+				},
+			},
+		},
+	}
+
+	commentSchema := &schema.Schema{
+		Name:             "comment",
+		Namespace:        "comments",
+		LabelFieldName:   "content",
+		DisableTimestamp: true,
+		Fields: []*schema.Field{
+			{Name: "id", Label: "ID", Type: schema.TypeUint64},
+			{Name: "content", Label: "Content", Type: schema.TypeString},
+			{Name: "approved", Label: "Approved", Type: schema.TypeBool},
+			{
+				Name:     "post",
+				Label:    "Post",
+				Type:     schema.TypeRelation,
+				Optional: true,
+				Relation: &schema.Relation{
+					Type:             schema.O2M,
+					TargetSchemaName: "post",
+					TargetFieldName:  "comments",
+					Optional:         true,
+				},
+			},
+		},
+	}
+
+	tagSchema := &schema.Schema{
+		Name:             "tag",
+		Namespace:        "tags",
+		LabelFieldName:   "name",
+		DisableTimestamp: true,
+		Fields: []*schema.Field{
+			{Name: "id", Label: "ID", Type: schema.TypeUint64},
+			{Name: "name", Label: "Name", Type: schema.TypeString},
+			{
+				Name:  "posts",
+				Label: "Posts",
+				Type:  schema.TypeRelation,
+				Relation: &schema.Relation{
+					Type:             schema.M2M,
+					TargetSchemaName: "post",
+					TargetFieldName:  "tags",
+				},
+			},
+		},
+	}
+
+	sb, err := schema.NewBuilderFromSchemas("", map[string]*schema.Schema{
+		postSchema.Name:    postSchema,
+		commentSchema.Name: commentSchema,
+		tagSchema.Name:     tagSchema,
+	})
+	require.NoError(t, err)
+
+	return sb
+}
+
+func TestRelationQuantifierQuery(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Dialect     string
+		Schema      string
+		Predicates  []*db.Predicate
+		Expect      func(sqlmock.Sqlmock)
+		ExpectError string
+	}{
+		{
+			Name:        "invalid_value",
+			Schema:      "post",
+			Predicates:  []*db.Predicate{{Field: "comments", Operator: db.OpSome, Value: 1}},
+			ExpectError: "value of field comments.$some = 1 (int) must be predicates",
+		},
+		{
+			Name:        "not_relation",
+			Schema:      "post",
+			Predicates:  []*db.Predicate{db.Some("title")},
+			ExpectError: "title is not a relation field",
+		},
+		{
+			Name:        "field_not_found",
+			Schema:      "post",
+			Predicates:  []*db.Predicate{db.None("invalid")},
+			ExpectError: "field 'invalid' is not defined in schema 'post'",
+		},
+		{
+			Name:        "every_without_predicates",
+			Schema:      "post",
+			Predicates:  []*db.Predicate{db.Every("comments")},
+			ExpectError: "operator $every of field comments requires at least one predicate",
+		},
+		{
+			Name:       "o2m_mysql",
+			Schema:     "post",
+			Predicates: []*db.Predicate{db.Some("comments", db.EQ("content", "hi")), db.None("comments")},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `posts` WHERE EXISTS (SELECT `comments`.`post_id` FROM `comments` WHERE `posts`.`id` = `comments`.`post_id` AND `comments`.`content` = ?) AND (NOT (EXISTS (SELECT `comments`.`post_id` FROM `comments` WHERE `posts`.`id` = `comments`.`post_id`)))")).
+					WithArgs("hi").
+					WillReturnRows(mock.NewRows([]string{"id", "title"}).AddRow(1, "A"))
+			},
+		},
+		{
+			Name:       "o2m_every_mysql",
+			Schema:     "post",
+			Predicates: []*db.Predicate{db.Every("comments", db.EQ("content", "hi"))},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `posts` WHERE NOT (EXISTS (SELECT `comments`.`post_id` FROM `comments` WHERE `posts`.`id` = `comments`.`post_id` AND (NOT (`comments`.`content` = ?))))")).
+					WithArgs("hi").
+					WillReturnRows(mock.NewRows([]string{"id", "title"}).AddRow(1, "A"))
+			},
+		},
+		{
+			Name:       "o2m_count_mysql",
+			Schema:     "post",
+			Predicates: []*db.Predicate{db.Count("comments", db.GT("comments", 5), db.LTE("comments", 10))},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `posts` WHERE (SELECT COUNT(*) FROM `comments` WHERE `comments`.`post_id` = `posts`.`id`) > ? AND (SELECT COUNT(*) FROM `comments` WHERE `comments`.`post_id` = `posts`.`id`) <= ?")).
+					WithArgs(5, 10).
+					WillReturnRows(mock.NewRows([]string{"id", "title"}).AddRow(1, "A"))
+			},
+		},
+		{
+			Name:       "m2o_count_mysql",
+			Schema:     "comment",
+			Predicates: []*db.Predicate{db.Count("post", db.EQ("post", 0))},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `comments` WHERE (SELECT COUNT(*) FROM `posts` WHERE `posts`.`id` = `comments`.`post_id`) = ?")).
+					WithArgs(0).
+					WillReturnRows(mock.NewRows([]string{"id", "content"}).AddRow(1, "A"))
+			},
+		},
+		{
+			Name:       "m2m_postgres",
+			Dialect:    dialect.Postgres,
+			Schema:     "post",
+			Predicates: []*db.Predicate{db.Some("tags", db.EQ("name", "go")), db.Count("tags", db.GTE("tags", 2))},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery(`SELECT * FROM "posts" WHERE "posts"."id" IN (SELECT "posts_tags"."posts" FROM "posts_tags" JOIN "tags" AS "t1" ON "posts_tags"."tags" = "t1"."id" WHERE "t1"."name" = $1) AND (SELECT COUNT(*) FROM "posts_tags" WHERE "posts_tags"."posts" = "posts"."id") >= $2`)).
+					WithArgs("go", 2).
+					WillReturnRows(mock.NewRows([]string{"id", "title"}).AddRow(1, "A"))
+			},
+		},
+		{
+			Name:       "nested_relation_mysql",
+			Schema:     "comment",
+			Predicates: []*db.Predicate{db.None("post.tags")},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `comments` WHERE EXISTS (SELECT `posts`.`id` FROM `posts` WHERE `comments`.`post_id` = `posts`.`id` AND NOT (`posts`.`id` IN (SELECT `posts_tags`.`posts` FROM `posts_tags` JOIN `tags` AS `t1` ON `posts_tags`.`tags` = `t1`.`id`)))")).
+					WillReturnRows(mock.NewRows([]string{"id", "content"}).AddRow(1, "A"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			dialectName := utils.If(tt.Dialect == "", dialect.MySQL, tt.Dialect)
+			client := utils.Must(NewEntClient(&db.Config{
+				Driver: "sqlmock",
+			}, createQuantifierSchemaBuilder(t), dialectSql.OpenDB(dialectName, mockDB)))
+
+			if tt.Expect != nil {
+				tt.Expect(mock)
+			}
+
+			model := utils.Must(client.Model(tt.Schema))
+			_, err = model.Query(tt.Predicates...).Get(context.Background())
+			if tt.ExpectError != "" {
+				assert.ErrorContains(t, err, tt.ExpectError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRelationQuantifierSQLite(t *testing.T) {
+	ctx := context.Background()
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	client, err := NewTestClient(migrationDir, createQuantifierSchemaBuilder(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	tagModel := utils.Must(client.Model("tag"))
+	_ = utils.Must(tagModel.CreateFromJSON(ctx, `{"name": "go"}`))
+	_ = utils.Must(tagModel.CreateFromJSON(ctx, `{"name": "sql"}`))
+
+	postModel := utils.Must(client.Model("post"))
+	_ = utils.Must(postModel.CreateFromJSON(ctx, `{"title": "A", "tags": [{"id": 1}, {"id": 2}]}`))
+	_ = utils.Must(postModel.CreateFromJSON(ctx, `{"title": "B", "tags": [{"id": 2}]}`))
+	_ = utils.Must(postModel.CreateFromJSON(ctx, `{"title": "C"}`))
+
+	commentModel := utils.Must(client.Model("comment"))
+	for _, comment := range []string{
+		`{"content": "A1", "approved": true, "post": {"id": 1}}`,
+		`{"content": "A2", "approved": true, "post": {"id": 1}}`,
+		`{"content": "B1", "approved": true, "post": {"id": 2}}`,
+		`{"content": "B2", "approved": false, "post": {"id": 2}}`,
+		`{"content": "orphan", "approved": false}`,
+	} {
+		_ = utils.Must(commentModel.CreateFromJSON(ctx, comment))
+	}
+
+	titles := func(filter string) []string {
+		predicates := utils.Must(db.CreatePredicatesFromFilterObject(client.SchemaBuilder(), postModel.Schema(), filter))
+		posts, err := postModel.Query(predicates...).Order("id").Get(ctx)
+		require.NoError(t, err)
+		return utils.Map(posts, func(e *entity.Entity) string {
+			return e.GetString("title")
+		})
+	}
+
+	assert.Equal(t, []string{"C"}, titles(`{"comments": {"$none": {}}}`))
+	assert.Equal(t, []string{"A", "B"}, titles(`{"comments": {"$some": {}}}`))
+	assert.Equal(t, []string{"B"}, titles(`{"comments": {"$some": {"approved": false}}}`))
+	assert.Equal(t, []string{"A", "C"}, titles(`{"comments": {"$every": {"approved": true}}}`))
+	assert.Equal(t, []string{"A", "C"}, titles(`{"comments": {"$every": {"approved": true}}, "tags": {"$count": {"$lt": 5}}}`))
+	assert.Equal(t, []string{"A", "B"}, titles(`{"comments": {"$count": 2}}`))
+	assert.Equal(t, []string{"C"}, titles(`{"comments": {"$count": {"$lt": 1}}}`))
+	assert.Equal(t, []string{"A"}, titles(`{"tags": {"$count": {"$gt": 1}}}`))
+	assert.Equal(t, []string{"B", "C"}, titles(`{"tags": {"$none": {"name": "go"}}}`))
+	assert.Equal(t, []string{"A", "B"}, titles(`{"tags": {"$every": {"name": {"$in": ["go", "sql"]}}, "$some": {}}}`))
+	assert.Equal(t, []string{"A"}, titles(`{"$or": [{"tags": {"$count": {"$between": [2, 3]}}}, {"comments": {"$some": {"content": "none"}}}]}`))
+
+	commentPredicates := utils.Must(db.CreatePredicatesFromFilterObject(
+		client.SchemaBuilder(),
+		commentModel.Schema(),
+		`{"post.tags": {"$some": {"name": "go"}}}`,
+	))
+	comments, err := commentModel.Query(commentPredicates...).Order("id").Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A1", "A2"}, utils.Map(comments, func(e *entity.Entity) string {
+		return e.GetString("content")
+	}))
+
+	count, err := commentModel.Query(db.Count("post", db.EQ("post", 0))).Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
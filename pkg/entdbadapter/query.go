@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
 	"strings"
 
+	"entgo.io/ent/dialect"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"github.com/fastschema/fastschema/db"
@@ -34,6 +37,11 @@ type Query struct {
 	model           *Model
 	querySpec       *sqlgraph.QuerySpec
 	perParentLimit  *perParentLimitConfig // For per-parent limit/offset in edge queries
+	groupBy         []string
+	having          []*db.Predicate
+	after           string
+	before          string
+	scoped          bool
 }
 
 func (q *Query) WithTrashed() db.Querier {
@@ -74,6 +82,10 @@ func (q *Query) Options() *db.QueryOption {
 		Order:      q.order,
 		Predicates: &q.predicates,
 		Schema:     q.model.schema,
+		GroupBy:    q.groupBy,
+		Having:     q.having,
+		After:      q.after,
+		Before:     q.before,
 	}
 }
 
@@ -95,6 +107,18 @@ func (q *Query) Order(order ...string) db.Querier {
 	return q
 }
 
+// After sets the cursor to return the records after, using keyset pagination.
+func (q *Query) After(cursor string) db.Querier {
+	q.after = cursor
+	return q
+}
+
+// Before sets the cursor to return the records before, using keyset pagination.
+func (q *Query) Before(cursor string) db.Querier {
+	q.before = cursor
+	return q
+}
+
 // Select sets the columns of the query.
 func (q *Query) Select(fields ...string) db.Querier {
 	q.fields = append(q.fields, fields...)
@@ -133,6 +157,10 @@ func (q *Query) Count(ctx context.Context, options ...*db.QueryOption) (int, err
 		return 0, err
 	}
 
+	if err := q.scope(ctx); err != nil {
+		return 0, err
+	}
+
 	if opts != nil {
 		q.querySpec.Unique = opts.Unique
 		if opts.Column != "" {
@@ -150,7 +178,7 @@ func (q *Query) Count(ctx context.Context, options ...*db.QueryOption) (int, err
 		}
 	}
 
-	count, err := sqlgraph.CountNodes(ctx, entAdapter.Driver(), q.querySpec)
+	count, err := sqlgraph.CountNodes(withSchema(ctx, q.model.name), entAdapter.ReadDriver(ctx), q.querySpec)
 	if err != nil {
 		return 0, err
 	}
@@ -199,6 +227,46 @@ func (q *Query) Only(ctx context.Context) (*entity.Entity, error) {
 	return entities[0], nil
 }
 
+// SQL returns the statement and the arguments of the query without running it.
+// The pre query hooks are run so that the statement is the one that Get runs,
+// the relations are loaded by separate queries that are not included.
+func (q *Query) SQL(ctx context.Context) (string, []any, error) {
+	if err := q.applyCursor(); err != nil {
+		return "", nil, err
+	}
+
+	if err := runPreDBQueryHooks(ctx, q.client, q.Options()); err != nil {
+		return "", nil, err
+	}
+
+	if err := q.scope(ctx); err != nil {
+		return "", nil, err
+	}
+
+	entAdapter, ok := q.client.(EntAdapter)
+	if !ok {
+		return "", nil, errors.New("client is not an ent adapter")
+	}
+
+	buildResult, err := q.buildQueryColumns()
+	if err != nil {
+		return "", nil, err
+	}
+
+	buildResult.directColumnNames = append(buildResult.directColumnNames, buildResult.fkColumns...)
+	if err := q.buildQuerySpec(entAdapter, utils.Unique(buildResult.directColumnNames), buildResult); err != nil {
+		return "", nil, err
+	}
+
+	selector, err := querySpecSelector(ctx, q.querySpec)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query, args := selector.Query()
+	return query, args, nil
+}
+
 func (q *Query) parseNestedFields(fields []string) ([]string, map[string][]string, map[string]bool, error) {
 	edgeColumns := map[string][]string{}
 	processedFields := []string{}
@@ -242,6 +310,7 @@ type queryBuildResult struct {
 	directColumnNames  []string
 	fkColumns          []string
 	edges              map[string]*edgeSelection
+	jsonPaths          map[string][][]string // selected paths inside the JSON columns
 	allSelectsAreEdges bool
 }
 
@@ -252,6 +321,7 @@ func (q *Query) buildQueryColumns() (*queryBuildResult, error) {
 		directColumnNames:  []string{q.model.entPrimaryColumn.Name},
 		fkColumns:          []string{},
 		edges:              map[string]*edgeSelection{},
+		jsonPaths:          map[string][][]string{},
 		allSelectsAreEdges: true,
 	}
 
@@ -296,11 +366,33 @@ func (q *Query) buildQueryColumns() (*queryBuildResult, error) {
 			result.directColumnNames = append(result.directColumnNames, fieldName)
 			result.allSelectsAreEdges = false
 		}
+
+		// The JSON column is selected and the values are filtered to the selected paths after the query
+		// E.g. "metadata.color" selects {"metadata": {"color": "red"}}
+		if column.field.Type == schema.TypeJSON && !directSelections[fieldName] {
+			for _, path := range edgeColumns[fieldName] {
+				jsonPath, err := db.ParseJSONPath(nil, q.model.schema, fieldName+"."+path)
+				if err != nil {
+					return nil, err
+				}
+
+				result.jsonPaths[fieldName] = append(result.jsonPaths[fieldName], jsonPath.Path)
+			}
+		}
 	}
 
 	return result, nil
 }
 
+// selectJSONPaths filters the values of the JSON columns to the selected paths.
+func (q *Query) selectJSONPaths(jsonPaths map[string][][]string) {
+	for column, paths := range jsonPaths {
+		for _, e := range q.entities {
+			e.Set(column, selectJSONPaths(e.Get(column), paths))
+		}
+	}
+}
+
 // buildQueryPredicates builds the predicate function for the query spec.
 func (q *Query) buildQueryPredicates(entAdapter EntAdapter) error {
 	if len(q.predicates) == 0 {
@@ -340,6 +432,30 @@ func (q *Query) buildQueryOrder() error {
 			orderFn = sql.Desc
 		}
 
+		if columnName == db.SearchRelevance {
+			predicates := searchPredicates(q.predicates)
+			if len(predicates) == 0 {
+				return fmt.Errorf("ordering by %s requires a $search predicate", db.SearchRelevance)
+			}
+
+			orderSelectors = append(orderSelectors, searchRelevanceOrder(predicates, columnName != order))
+			continue
+		}
+
+		jsonPath, err := db.ParseJSONPath(nil, q.model.schema, columnName)
+		if err != nil {
+			return err
+		}
+
+		if jsonPath != nil {
+			if !q.model.schema.Field(jsonPath.Field).Sortable {
+				return fmt.Errorf(`column %q is not sortable`, jsonPath.Field)
+			}
+
+			orderSelectors = append(orderSelectors, jsonOrder(jsonPath, columnName != order))
+			continue
+		}
+
 		column, err := q.model.Column(columnName)
 		if err != nil {
 			return err
@@ -365,14 +481,91 @@ func (q *Query) buildQueryOrder() error {
 	return nil
 }
 
+// applyCursor applies the keyset pagination cursor to the query.
+// The query order is replaced by the keyset order and the cursor predicates are added.
+// When paginating backward, the order is reversed for the query
+// and the results are reversed back after the query.
+func (q *Query) applyCursor() error {
+	if q.after == "" && q.before == "" {
+		return nil
+	}
+
+	if q.after != "" && q.before != "" {
+		return errors.New("after and before cursors cannot be used together")
+	}
+
+	cursor, err := db.DecodeCursor(utils.If(q.after != "", q.after, q.before))
+	if err != nil {
+		return err
+	}
+
+	// Postgres orders the NULL values last in ascending order, the other dialects order them first
+	nullsFirst := q.client.Dialect() != dialect.Postgres
+	predicates, err := cursor.Predicates(q.model.schema, q.order, q.before != "", nullsFirst)
+	if err != nil {
+		return err
+	}
+
+	q.predicates = append(q.predicates, predicates...)
+	q.order = db.KeysetOrder(q.model.schema, q.order)
+
+	if q.before != "" {
+		for i, order := range q.order {
+			if after, ok := strings.CutPrefix(order, "-"); ok {
+				q.order[i] = after
+			} else {
+				q.order[i] = "-" + order
+			}
+		}
+	}
+
+	return nil
+}
+
 // Get returns the list of entities that match the query.
+//
+//	If the database cache is enabled and the schema has a cache TTL,
+//	the entities are cached before the getters and the post query hooks are applied,
+//	so that the cached entities do not depend on the request context.
+//	The raw reads of db.WithRawRead skip the hooks, the getters and the cache.
 func (q *Query) Get(ctx context.Context) (_ []*entity.Entity, err error) {
+	if err := q.applyCursor(); err != nil {
+		return nil, err
+	}
+
 	option := q.Options()
+	rawRead := db.UseRawRead(ctx)
 
-	if err := runPreDBQueryHooks(ctx, q.client, option); err != nil {
+	if !rawRead {
+		if err := runPreDBQueryHooks(ctx, q.client, option); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := q.scope(ctx); err != nil {
 		return nil, err
 	}
 
+	entAdapter, ok := q.client.(EntAdapter)
+	if !ok {
+		return nil, errors.New("client is not an ent adapter")
+	}
+
+	// The cache key is created after the pre query hooks, since the hooks may modify the query
+	cacheKey := ""
+	if !rawRead {
+		if cacheKey, err = q.cacheKey(option); err != nil {
+			return nil, err
+		}
+	}
+
+	if cacheKey != "" {
+		if entities, ok := q.client.Config().Cache.Get(ctx, cacheKey); ok {
+			q.entities = entities
+			return q.resolveEntities(ctx, entAdapter, option)
+		}
+	}
+
 	// Build query columns
 	buildResult, err := q.buildQueryColumns()
 	if err != nil {
@@ -383,11 +576,6 @@ func (q *Query) Get(ctx context.Context) (_ []*entity.Entity, err error) {
 	buildResult.directColumnNames = append(buildResult.directColumnNames, buildResult.fkColumns...)
 	allColumns := utils.Unique(buildResult.directColumnNames)
 
-	entAdapter, ok := q.client.(EntAdapter)
-	if !ok {
-		return nil, errors.New("client is not an ent adapter")
-	}
-
 	// Use window function query for per-parent limit/offset
 	if q.perParentLimit != nil {
 		// For window function queries, we need explicit columns.
@@ -398,7 +586,175 @@ func (q *Query) Get(ctx context.Context) (_ []*entity.Entity, err error) {
 		return q.getWithPerParentLimit(ctx, entAdapter, allColumns, buildResult)
 	}
 
-	// Build query spec
+	if err := q.buildQuerySpec(entAdapter, allColumns, buildResult); err != nil {
+		return nil, err
+	}
+
+	// Execute query
+	if err := sqlgraph.QueryNodes(withSchema(ctx, q.model.name), entAdapter.ReadDriver(ctx), q.querySpec); err != nil {
+		return nil, err
+	}
+
+	// Restore the query order for backward keyset pagination
+	if q.before != "" {
+		slices.Reverse(q.entities)
+	}
+
+	if err := q.loadEntities(ctx, buildResult); err != nil {
+		return nil, err
+	}
+
+	if cacheKey != "" {
+		q.client.Config().Cache.Set(ctx, cacheKey, q.model.schema.Name, q.entities, db.CacheTTL(q.model.schema))
+	}
+
+	if rawRead {
+		return q.entities, nil
+	}
+
+	return q.resolveEntities(ctx, entAdapter, option)
+}
+
+// cacheKey returns the cache key of the query.
+// Returns an empty key if the query results are not cached:
+// the cache is disabled, the schema has no cache TTL or the query runs in a transaction.
+func (q *Query) cacheKey(option *db.QueryOption) (string, error) {
+	config := q.client.Config()
+	if config == nil ||
+		config.Cache == nil ||
+		q.client.IsTx() ||
+		q.perParentLimit != nil ||
+		db.CacheTTL(q.model.schema) <= 0 {
+		return "", nil
+	}
+
+	return db.CacheKey(option, q.relationOptions)
+}
+
+// Each streams the entities that match the query to the given function.
+// The rows are read from the database one by one instead of being loaded in memory at once.
+// The entities are processed in batches: the relations are loaded and
+// the post query hooks are run for each batch before calling the function.
+// If the function returns an error, the iteration stops and the error is returned.
+//
+// Loading relations runs extra queries while the rows are being read,
+// which the MySQL and Postgres drivers do not support inside a transaction.
+func (q *Query) Each(ctx context.Context, fn func(*entity.Entity) error) (err error) {
+	if q.before != "" {
+		return errors.New("before cursor is not supported when streaming entities")
+	}
+
+	if err := q.applyCursor(); err != nil {
+		return err
+	}
+
+	option := q.Options()
+
+	if err := runPreDBQueryHooks(ctx, q.client, option); err != nil {
+		return err
+	}
+
+	if err := q.scope(ctx); err != nil {
+		return err
+	}
+
+	buildResult, err := q.buildQueryColumns()
+	if err != nil {
+		return err
+	}
+
+	entAdapter, ok := q.client.(EntAdapter)
+	if !ok {
+		return errors.New("client is not an ent adapter")
+	}
+
+	buildResult.directColumnNames = append(buildResult.directColumnNames, buildResult.fkColumns...)
+	if err := q.buildQuerySpec(entAdapter, utils.Unique(buildResult.directColumnNames), buildResult); err != nil {
+		return err
+	}
+
+	selector, err := querySpecSelector(ctx, q.querySpec)
+	if err != nil {
+		return err
+	}
+
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := entAdapter.ReadDriver(ctx).Query(withSchema(ctx, q.model.name), query, args, rows); err != nil {
+		return err
+	}
+
+	defer func() {
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	flush := func() error {
+		entities, err := q.processEntities(ctx, entAdapter, option, buildResult)
+		if err != nil {
+			return err
+		}
+
+		q.entities = []*entity.Entity{}
+		for _, e := range entities {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	q.entities = []*entity.Entity{}
+	for rows.Next() {
+		values, err := q.querySpec.ScanValues(columns)
+		if err != nil {
+			return err
+		}
+
+		for i, v := range values {
+			if _, ok := v.(*sql.UnknownType); ok {
+				values[i] = sql.ScanTypeOf(rows, i)
+			}
+		}
+
+		if err := rows.Scan(values...); err != nil {
+			return err
+		}
+
+		if err := q.querySpec.Assign(columns, values); err != nil {
+			return err
+		}
+
+		if len(q.entities) >= eachBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(q.entities) > 0 {
+		return flush()
+	}
+
+	return nil
+}
+
+// eachBatchSize is the number of entities that are processed together when streaming entities.
+var eachBatchSize = 1000
+
+// buildQuerySpec sets the columns, predicates, order, limit and offset of the query spec.
+func (q *Query) buildQuerySpec(entAdapter EntAdapter, allColumns []string, buildResult *queryBuildResult) error {
 	builder := sql.Dialect(entAdapter.Driver().Dialect())
 	if !buildResult.allSelectsAreEdges {
 		q.querySpec.Node.Columns = allColumns
@@ -409,12 +765,12 @@ func (q *Query) Get(ctx context.Context) (_ []*entity.Entity, err error) {
 
 	// Build predicates
 	if err := q.buildQueryPredicates(entAdapter); err != nil {
-		return nil, err
+		return err
 	}
 
 	// Build order
 	if err := q.buildQueryOrder(); err != nil {
-		return nil, err
+		return err
 	}
 
 	// Apply limit and offset
@@ -425,16 +781,56 @@ func (q *Query) Get(ctx context.Context) (_ []*entity.Entity, err error) {
 		q.querySpec.Offset = int(q.offset)
 	}
 
-	// Execute query
-	if err := sqlgraph.QueryNodes(ctx, entAdapter.Driver(), q.querySpec); err != nil {
-		return nil, err
+	return nil
+}
+
+// querySpecSelector builds the selector of the query spec the same way as sqlgraph.QueryNodes.
+func querySpecSelector(ctx context.Context, spec *sqlgraph.QuerySpec) (*sql.Selector, error) {
+	selector := spec.From.WithContext(ctx)
+	selector.Select(selector.Columns(spec.Node.Columns...)...)
+	if spec.Order != nil {
+		spec.Order(selector)
+	}
+	if spec.Predicate != nil {
+		spec.Predicate(selector)
+	}
+	if spec.Offset != 0 {
+		// Limit is mandatory for the offset clause.
+		selector.Offset(spec.Offset).Limit(math.MaxInt32)
 	}
+	if spec.Limit != 0 {
+		selector.Limit(spec.Limit)
+	}
+
+	return selector, selector.Err()
+}
 
-	// Load edges
-	if err := q.loadEdges(ctx, buildResult.edges); err != nil {
+// processEntities loads and resolves the queried entities.
+func (q *Query) processEntities(
+	ctx context.Context,
+	entAdapter EntAdapter,
+	option *db.QueryOption,
+	buildResult *queryBuildResult,
+) ([]*entity.Entity, error) {
+	if err := q.loadEntities(ctx, buildResult); err != nil {
 		return nil, err
 	}
 
+	return q.resolveEntities(ctx, entAdapter, option)
+}
+
+// loadEntities selects the JSON paths and loads the edges of the queried entities.
+func (q *Query) loadEntities(ctx context.Context, buildResult *queryBuildResult) error {
+	q.selectJSONPaths(buildResult.jsonPaths)
+	return q.loadEdges(ctx, buildResult.edges)
+}
+
+// resolveEntities applies the getters and runs the post query hooks on the loaded entities.
+func (q *Query) resolveEntities(
+	ctx context.Context,
+	entAdapter EntAdapter,
+	option *db.QueryOption,
+) ([]*entity.Entity, error) {
 	// Apply getters
 	for _, entity := range q.entities {
 		if err := q.model.schema.ApplyGetters(ctx, entity, expr.Config{
@@ -521,29 +917,12 @@ func (q *Query) getWithPerParentLimit(
 
 	// Execute query
 	query, args := outer.Query()
-	entities, err := driverQuery(entAdapter.Driver(), ctx, query, args)
+	entities, err := driverQuery(entAdapter.ReadDriver(ctx), withSchema(ctx, q.model.name), query, args)
 	if err != nil {
 		return nil, err
 	}
 
 	q.entities = entities
 
-	// Load edges
-	if err := q.loadEdges(ctx, buildResult.edges); err != nil {
-		return nil, err
-	}
-
-	// Apply getters
-	for _, entity := range q.entities {
-		if err := q.model.schema.ApplyGetters(ctx, entity, expr.Config{
-			DB: func() expr.DBLike {
-				return entAdapter
-			},
-		}); err != nil {
-			return nil, err
-		}
-	}
-
-	option := q.Options()
-	return runPostDBQueryHooks(ctx, q.client, option, q.entities)
+	return q.processEntities(ctx, entAdapter, q.Options(), buildResult)
 }
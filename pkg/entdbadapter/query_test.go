@@ -2353,3 +2353,131 @@ func TestQueryOptionsMethod(t *testing.T) {
 	assert.Equal(t, &query.predicates, opts.Predicates)
 	assert.Equal(t, carModel.(*Model).schema, opts.Schema)
 }
+
+func TestQueryCursor(t *testing.T) {
+	name, id := "car2", "2"
+	cursor := (&db.Cursor{Order: []string{"name", "id"}, Values: []*string{&name, &id}}).Encode()
+	runWithCursor := func(after, before string) func(
+		model db.Model,
+		predicates []*db.Predicate,
+		limit, offset uint,
+		order []string,
+		relationOptions db.RelationOptions,
+		columns ...string,
+	) ([]*entity.Entity, error) {
+		return func(
+			model db.Model,
+			predicates []*db.Predicate,
+			limit, offset uint,
+			order []string,
+			relationOptions db.RelationOptions,
+			columns ...string,
+		) ([]*entity.Entity, error) {
+			return model.Query(predicates...).
+				Limit(limit).
+				Order(order...).
+				After(after).
+				Before(before).
+				Get(context.Background())
+		}
+	}
+
+	tests := []MockTestQueryData{
+		{
+			Name:        "Query_cursor_after_and_before",
+			Schema:      "car",
+			Order:       []string{"name"},
+			Run:         runWithCursor(cursor, cursor),
+			ExpectError: "after and before cursors cannot be used together",
+		},
+		{
+			Name:        "Query_cursor_invalid",
+			Schema:      "car",
+			Run:         runWithCursor("invalid!", ""),
+			ExpectError: "invalid cursor: illegal base64 data at input byte 7",
+		},
+		{
+			Name:        "Query_cursor_order_mismatch",
+			Schema:      "car",
+			Order:       []string{"-name"},
+			Run:         runWithCursor(cursor, ""),
+			ExpectError: `cursor order "name,id" does not match the query order "-name,id"`,
+		},
+		{
+			Name:   "Query_cursor_after",
+			Schema: "car",
+			Limit:  2,
+			Order:  []string{"name"},
+			Run:    runWithCursor(cursor, ""),
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `cars` WHERE `cars`.`name` > ? OR (`cars`.`name` = ? AND `cars`.`id` > ?) ORDER BY `cars`.`name` ASC, `cars`.`id` ASC LIMIT 2")).
+					WithArgs("car2", "car2", uint64(2)).
+					WillReturnRows(mock.NewRows([]string{"id", "name"}).
+						AddRow(3, "car3").
+						AddRow(4, "car4"))
+			},
+			ExpectEntities: []*entity.Entity{
+				entity.New(3).Set("name", "car3"),
+				entity.New(4).Set("name", "car4"),
+			},
+		},
+		{
+			Name:   "Query_cursor_before",
+			Schema: "car",
+			Limit:  2,
+			Order:  []string{"name"},
+			Run:    runWithCursor("", cursor),
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `cars` WHERE `cars`.`name` < ? OR (`cars`.`name` = ? AND `cars`.`id` < ?) ORDER BY `cars`.`name` DESC, `cars`.`id` DESC LIMIT 2")).
+					WithArgs("car2", "car2", uint64(2)).
+					WillReturnRows(mock.NewRows([]string{"id", "name"}).
+						AddRow(1, "car1").
+						AddRow(0, "car0"))
+			},
+			ExpectEntities: []*entity.Entity{
+				entity.New(0).Set("name", "car0"),
+				entity.New(1).Set("name", "car1"),
+			},
+		},
+	}
+
+	sb := createSchemaBuilder()
+	MockRunQueryTests(func(d *sql.DB) db.Client {
+		client := utils.Must(NewEntClient(&db.Config{
+			Driver: "sqlmock",
+		}, sb, dialectSql.OpenDB(dialect.MySQL, d)))
+		return client
+	}, sb, t, tests)
+}
+
+func TestQuerySQL(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	client := utils.Must(NewEntClient(&db.Config{
+		Driver: "sqlmock",
+	}, createJSONSchemaBuilder(t), dialectSql.OpenDB("mysql", mockDB)))
+	model := utils.Must(client.Model("product"))
+
+	query, args, err := model.Query(db.GT("id", 1), db.Like("name", "%a%")).
+		Select("name", "vendor.name").
+		Order("-id").
+		Limit(10).
+		Offset(20).
+		SQL(context.Background())
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		"SELECT `products`.`id`, `products`.`name`, `products`.`vendor_id` FROM `products` "+
+			"WHERE `products`.`id` > ? AND `products`.`name` LIKE ? "+
+			"ORDER BY `products`.`id` DESC LIMIT 10 OFFSET 20",
+		query,
+	)
+	assert.Equal(t, []any{1, "%a%"}, args)
+
+	_, _, err = model.Query().Select("invalid").SQL(context.Background())
+	assert.ErrorContains(t, err, "invalid")
+
+	// The statement is not run
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
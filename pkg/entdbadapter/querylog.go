@@ -0,0 +1,208 @@
+package entdbadapter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/logger"
+	"github.com/google/uuid"
+)
+
+type schemaContextKey struct{}
+
+// schemaContext is the context that is passed to the driver to run the queries of a schema.
+// It keeps the trace ID of the parent context, so that the queries can be logged with both.
+type schemaContext struct {
+	context.Context
+	schema string
+}
+
+// withSchema returns the context to run the queries of the given schema.
+func withSchema(ctx context.Context, schemaName string) context.Context {
+	return &schemaContext{Context: ctx, schema: schemaName}
+}
+
+// Value returns the schema name for the schema context key.
+func (c *schemaContext) Value(key any) any {
+	if _, ok := key.(schemaContextKey); ok {
+		return c.schema
+	}
+
+	return c.Context.Value(key)
+}
+
+// TraceID returns the trace ID of the parent context.
+func (c *schemaContext) TraceID() string {
+	return contextTraceID(c.Context)
+}
+
+// contextSchema returns the name of the schema whose queries are run with the context.
+func contextSchema(ctx context.Context) string {
+	schemaName, _ := ctx.Value(schemaContextKey{}).(string)
+	return schemaName
+}
+
+// contextTraceID returns the trace ID of the context if any.
+func contextTraceID(ctx context.Context) string {
+	if traceable, ok := ctx.(fs.Traceable); ok {
+		return traceable.TraceID()
+	}
+
+	if traceID := ctx.Value(fs.ContextKeyTraceID); traceID != nil {
+		return fmt.Sprintf("%v", traceID)
+	}
+
+	return ""
+}
+
+// timedDriver measures the duration of the queries.
+//
+//	If the queries are logged, each query is logged once at the debug level with its arguments and duration,
+//	the driver is used instead of the ent debug driver.
+//	The queries that take longer than the slow query threshold are logged as warnings
+//	with the trace ID of the request and the schema that the query is run for.
+//	The arguments of the slow queries are not logged since they may contain sensitive data.
+type timedDriver struct {
+	dialect.Driver
+	config *db.Config
+}
+
+// newTimedDriver wraps the driver to measure the query durations.
+// The driver is returned as is if neither the query log nor the slow query log is enabled.
+func newTimedDriver(driver dialect.Driver, config *db.Config) dialect.Driver {
+	if !config.LogQueries && config.SlowQueryThreshold <= 0 {
+		return driver
+	}
+
+	return &timedDriver{Driver: driver, config: config}
+}
+
+// Exec executes the query and logs its duration.
+func (d *timedDriver) Exec(ctx context.Context, query string, args, v any) error {
+	start := time.Now()
+	err := d.Driver.Exec(ctx, query, args, v)
+	logQuery(ctx, d.config, "", "Exec", query, args, time.Since(start))
+	return err
+}
+
+// Query executes the query and logs its duration.
+func (d *timedDriver) Query(ctx context.Context, query string, args, v any) error {
+	start := time.Now()
+	err := d.Driver.Query(ctx, query, args, v)
+	logQuery(ctx, d.config, "", "Query", query, args, time.Since(start))
+	return err
+}
+
+// Tx starts a transaction whose queries are timed.
+func (d *timedDriver) Tx(ctx context.Context) (dialect.Tx, error) {
+	tx, err := d.Driver.Tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	timedTx := &timedTx{Tx: tx, config: d.config, id: uuid.NewString(), ctx: ctx}
+	timedTx.log("started")
+	return timedTx, nil
+}
+
+// timedTx measures the duration of the queries of a transaction.
+type timedTx struct {
+	dialect.Tx
+	config *db.Config
+	id     string
+	ctx    context.Context
+}
+
+// Exec executes the query and logs its duration.
+func (tx *timedTx) Exec(ctx context.Context, query string, args, v any) error {
+	start := time.Now()
+	err := tx.Tx.Exec(ctx, query, args, v)
+	logQuery(ctx, tx.config, tx.id, "Tx.Exec", query, args, time.Since(start))
+	return err
+}
+
+// Query executes the query and logs its duration.
+func (tx *timedTx) Query(ctx context.Context, query string, args, v any) error {
+	start := time.Now()
+	err := tx.Tx.Query(ctx, query, args, v)
+	logQuery(ctx, tx.config, tx.id, "Tx.Query", query, args, time.Since(start))
+	return err
+}
+
+// Commit commits the transaction.
+func (tx *timedTx) Commit() error {
+	tx.log("committed")
+	return tx.Tx.Commit()
+}
+
+// Rollback rolls back the transaction.
+func (tx *timedTx) Rollback() error {
+	tx.log("rolled back")
+	return tx.Tx.Rollback()
+}
+
+// log logs the state of the transaction if the queries are logged.
+func (tx *timedTx) log(state string) {
+	if tx.config.LogQueries {
+		debugQueryLog(tx.config, queryLogContext(tx.ctx, tx.id), "driver.Tx: "+state)
+	}
+}
+
+// logQuery logs the query with its duration if the queries are logged,
+// or as a warning if it is slower than the slow query threshold.
+func logQuery(
+	ctx context.Context,
+	config *db.Config,
+	txID, operation, query string,
+	args any,
+	duration time.Duration,
+) {
+	threshold := time.Duration(config.SlowQueryThreshold) * time.Millisecond
+	slow := threshold > 0 && duration >= threshold
+	if !slow && !config.LogQueries {
+		return
+	}
+
+	logContext := queryLogContext(ctx, txID)
+	logContext["duration"] = duration.String()
+	if slow {
+		if config.Logger != nil {
+			config.Logger.WithContext(logContext).Warn(fmt.Sprintf("slow query: driver.%s: query=%s", operation, query))
+		}
+		return
+	}
+
+	debugQueryLog(config, logContext, fmt.Sprintf("driver.%s: query=%s args=%v", operation, query, args))
+}
+
+// queryLogContext returns the log context of the queries with the schema, the trace ID and the transaction ID.
+func queryLogContext(ctx context.Context, txID string) logger.LogContext {
+	logContext := logger.LogContext{}
+	if schemaName := contextSchema(ctx); schemaName != "" {
+		logContext["schema"] = schemaName
+	}
+
+	if traceID := contextTraceID(ctx); traceID != "" {
+		logContext[fs.TraceID] = traceID
+	}
+
+	if txID != "" {
+		logContext["tx"] = txID
+	}
+
+	return logContext
+}
+
+// debugQueryLog logs the message at the debug level, or prints it if the config has no logger.
+func debugQueryLog(config *db.Config, logContext logger.LogContext, message string) {
+	if config.Logger == nil {
+		fmt.Println(message, logContext)
+		return
+	}
+
+	config.Logger.WithContext(logContext).Debug(message)
+}
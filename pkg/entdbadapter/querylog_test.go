@@ -0,0 +1,128 @@
+package entdbadapter
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	dialectSql "entgo.io/ent/dialect/sql"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/logger"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type contextLogger struct {
+	*logger.MockLogger
+	mu       sync.Mutex
+	contexts []logger.LogContext
+}
+
+func (l *contextLogger) WithContext(context logger.LogContext, _ ...int) logger.Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.contexts = append(l.contexts, context)
+	return l
+}
+
+func TestTimedDriverSlowQuery(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	log := &contextLogger{MockLogger: logger.CreateMockLogger(true)}
+	config := &db.Config{Driver: "sqlmock", Logger: log, SlowQueryThreshold: 10}
+	sqlDriver := dialectSql.OpenDB("mysql", mockDB)
+	client := utils.Must(NewEntClient(config, createJSONSchemaBuilder(t), sqlDriver))
+	client.(EntAdapter).SetDriver(newTimedDriver(sqlDriver, config))
+	model := utils.Must(client.Model("product"))
+	ctx := context.WithValue(context.Background(), fs.ContextKeyTraceID, "trace-1")
+
+	// Fast query
+	mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `products`")).
+		WillReturnRows(mock.NewRows([]string{"id", "name"}).AddRow(1, "A"))
+	_ = utils.Must(model.Query().Get(ctx))
+	assert.Empty(t, log.Messages)
+
+	// Slow query
+	mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `products` WHERE `products`.`id` = ?")).
+		WithArgs(1).
+		WillDelayFor(20 * time.Millisecond).
+		WillReturnRows(mock.NewRows([]string{"id", "name"}).AddRow(1, "A"))
+	_ = utils.Must(model.Query(db.EQ("id", 1)).Get(ctx))
+	require.Len(t, log.Messages, 1)
+	assert.Equal(t, "Warn", log.Last().Type)
+	assert.Contains(t, log.Last().String(), "slow query: driver.Query: query=SELECT * FROM `products` WHERE `products`.`id` = ?")
+	require.Len(t, log.contexts, 1)
+	assert.Equal(t, "product", log.contexts[0]["schema"])
+	assert.Equal(t, "trace-1", log.contexts[0][fs.TraceID])
+	assert.NotEmpty(t, log.contexts[0]["duration"])
+
+	// Slow transaction query
+	mock.ExpectBegin()
+	mock.ExpectExec(utils.EscapeQuery("INSERT INTO `products` (`name`) VALUES (?)")).
+		WithArgs("B").
+		WillDelayFor(20 * time.Millisecond).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+	require.NoError(t, db.WithTx(client, ctx, func(tx db.Client) error {
+		_, err := utils.Must(tx.Model("product")).Mutation().Create(ctx, entity.New().Set("name", "B"))
+		return err
+	}))
+	require.Len(t, log.Messages, 2)
+	assert.Contains(t, log.Last().String(), "slow query: driver.Tx.Exec: query=INSERT INTO `products`")
+	assert.Equal(t, "product", log.contexts[1]["schema"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTimedDriverLogQueries(t *testing.T) {
+	// The driver is not wrapped if the queries are not logged
+	driver := dialectSql.OpenDB("sqlite3", nil)
+	assert.Equal(t, driver, newTimedDriver(driver, &db.Config{}))
+
+	log := &contextLogger{MockLogger: logger.CreateMockLogger(true)}
+	client, err := NewClient(&db.Config{
+		Driver:       "sqlite",
+		Name:         ":memory:_" + utils.RandomString(10),
+		MigrationDir: utils.Must(os.MkdirTemp("", "migrations")),
+		LogQueries:   true,
+		Logger:       log,
+	}, createJSONSchemaBuilder(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	// Each query is logged once with its duration
+	log.contexts = nil
+	log.Messages = nil
+	_ = utils.Must(utils.Must(client.Model("vendor")).Query().Get(context.Background()))
+	require.Len(t, log.Messages, 1)
+	assert.Equal(t, "Debug", log.Last().Type)
+	assert.Contains(t, log.Last().String(), "driver.Query: query=SELECT")
+	assert.Equal(t, "vendor", log.contexts[0]["schema"])
+	assert.NotEmpty(t, log.contexts[0]["duration"])
+
+	// The queries of a transaction are logged with the transaction ID
+	log.contexts = nil
+	log.Messages = nil
+	require.NoError(t, db.WithTx(client, context.Background(), func(tx db.Client) error {
+		_, err := utils.Must(tx.Model("vendor")).Query().Get(context.Background())
+		return err
+	}))
+	require.Len(t, log.Messages, 3)
+	assert.Contains(t, log.Messages[0].String(), "driver.Tx: started")
+	assert.Contains(t, log.Messages[1].String(), "driver.Tx.Query: query=SELECT")
+	assert.Contains(t, log.Messages[2].String(), "driver.Tx: committed")
+	assert.NotEmpty(t, log.contexts[1]["tx"])
+	assert.Equal(t, log.contexts[0]["tx"], log.contexts[1]["tx"])
+	assert.NotEmpty(t, log.contexts[1]["duration"])
+
+	tx := utils.Must(NewTx(context.Background(), client))
+	assert.Equal(t, log.contexts[len(log.contexts)-1]["tx"], tx.driver.(*TxDriver).ID())
+	require.NoError(t, tx.Rollback())
+	assert.Contains(t, log.Last().String(), "driver.Tx: rolled back")
+}
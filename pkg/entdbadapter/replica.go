@@ -0,0 +1,153 @@
+package entdbadapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"entgo.io/ent/dialect"
+	dialectSql "entgo.io/ent/dialect/sql"
+	"github.com/fastschema/fastschema/db"
+)
+
+// replicaPingTimeout is the timeout of a replica health check.
+var replicaPingTimeout = 5 * time.Second
+
+type replica struct {
+	name    string
+	sqldb   *sql.DB
+	driver  dialect.Driver
+	healthy atomic.Bool
+}
+
+// replicaSet routes the queries to the healthy read replicas in round-robin.
+// The replicas are checked periodically, an unhealthy replica is skipped until it is healthy again.
+type replicaSet struct {
+	config    *db.Config
+	replicas  []*replica
+	next      atomic.Uint64
+	stop      chan struct{}
+	stopped   sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// newReplicaSet opens the replicas of the config and starts the health checks.
+// The replicas that are not reachable are marked as unhealthy instead of failing.
+func newReplicaSet(config *db.Config) (_ *replicaSet, err error) {
+	entDialect, err := GetEntDialect(config)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported driver: %v", config.Driver)
+	}
+
+	driverName := goSqlDriverNameMap[config.Driver]
+	if driverName == "" {
+		driverName = config.Driver
+	}
+
+	rs := &replicaSet{config: config, stop: make(chan struct{})}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, rs.Close())
+		}
+	}()
+
+	for i, replicaConfig := range config.Replicas {
+		replicaDBConfig := replicaConfig.Config(config)
+		sqldb, err := sql.Open(driverName, CreateDBDSN(replicaDBConfig))
+		if err != nil {
+			return nil, fmt.Errorf("open replica %d: %w", i, err)
+		}
+
+		driver := newTimedDriver(dialectSql.OpenDB(entDialect, sqldb), replicaDBConfig)
+
+		r := &replica{
+			name:   fmt.Sprintf("%d (%s)", i, replicaDBConfig.Name),
+			sqldb:  sqldb,
+			driver: driver,
+		}
+		if replicaDBConfig.Host != "" {
+			r.name = fmt.Sprintf("%d (%s:%s/%s)", i, replicaDBConfig.Host, replicaDBConfig.Port, replicaDBConfig.Name)
+		}
+
+		// The replicas are healthy until checked, so that only the unhealthy ones are logged on startup
+		r.healthy.Store(true)
+		rs.replicas = append(rs.replicas, r)
+	}
+
+	rs.check(context.Background())
+	interval := time.Duration(config.ReplicaHealthCheck) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	rs.stopped.Add(1)
+	go func() {
+		defer rs.stopped.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-rs.stop:
+				return
+			case <-ticker.C:
+				rs.check(context.Background())
+			}
+		}
+	}()
+
+	return rs, nil
+}
+
+// check pings the replicas and updates their health.
+func (rs *replicaSet) check(ctx context.Context) {
+	for _, r := range rs.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+		err := r.sqldb.PingContext(pingCtx)
+		cancel()
+
+		healthy := err == nil
+		if r.healthy.Swap(healthy) == healthy || rs.config.Logger == nil {
+			continue
+		}
+
+		if healthy {
+			rs.config.Logger.Infof("replica %s is healthy", r.name)
+		} else {
+			rs.config.Logger.Errorf("replica %s is unhealthy: %v", r.name, err)
+		}
+	}
+}
+
+// driver returns the driver of the next healthy replica, or nil if there is no healthy replica.
+func (rs *replicaSet) driver() dialect.Driver {
+	healthy := make([]*replica, 0, len(rs.replicas))
+	for _, r := range rs.replicas {
+		if r.healthy.Load() {
+			healthy = append(healthy, r)
+		}
+	}
+
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	return healthy[(rs.next.Add(1)-1)%uint64(len(healthy))].driver
+}
+
+// Close stops the health checks and closes the replicas.
+func (rs *replicaSet) Close() error {
+	var err error
+	rs.closeOnce.Do(func() {
+		close(rs.stop)
+		rs.stopped.Wait()
+		for _, r := range rs.replicas {
+			err = errors.Join(err, r.driver.Close())
+		}
+	})
+
+	return err
+}
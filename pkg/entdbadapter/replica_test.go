@@ -0,0 +1,99 @@
+package entdbadapter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/logger"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicas(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	// The replicas are not replicated in the test, each of them has its own records
+	replicaNames := []string{}
+	for _, name := range []string{"replica1", "replica2"} {
+		replicaNames = append(replicaNames, filepath.Join(dir, name+".db"))
+		replicaClient, err := NewClient(&db.Config{
+			Driver:       "sqlite",
+			Name:         replicaNames[len(replicaNames)-1],
+			MigrationDir: utils.Must(os.MkdirTemp(migrationDir, name)),
+		}, createQuantifierSchemaBuilder(t))
+		require.NoError(t, err)
+		_ = utils.Must(utils.Must(replicaClient.Model("post")).CreateFromJSON(ctx, `{"title": "`+name+`"}`))
+		require.NoError(t, replicaClient.Close())
+	}
+
+	mockLogger := logger.CreateMockLogger(true)
+	client, err := NewClient(&db.Config{
+		Driver:       "sqlite",
+		Name:         filepath.Join(dir, "primary.db"),
+		MigrationDir: migrationDir,
+		Logger:       mockLogger,
+		Replicas: []*db.ReplicaConfig{
+			{Name: replicaNames[0]},
+			{Name: filepath.Join(dir, "invalid", "replica.db")},
+			{Name: replicaNames[1]},
+		},
+	}, createQuantifierSchemaBuilder(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	assert.Len(t, mockLogger.Messages, 1)
+	assert.Contains(t, mockLogger.Last().String(), "replica 1 ("+filepath.Join(dir, "invalid", "replica.db")+") is unhealthy")
+
+	postModel := utils.Must(client.Model("post"))
+	_ = utils.Must(postModel.CreateFromJSON(ctx, `{"title": "primary"}`))
+	title := func(ctx context.Context) string {
+		posts, err := postModel.Query().Get(ctx)
+		require.NoError(t, err)
+		require.Len(t, posts, 1)
+		return posts[0].GetString("title")
+	}
+
+	// The reads are routed to the healthy replicas in round-robin
+	first, second := title(ctx), title(ctx)
+	assert.ElementsMatch(t, []string{"replica1", "replica2"}, []string{first, second})
+	assert.Equal(t, first, title(ctx))
+
+	count, err := postModel.Query().Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// The context can force the reads to the primary
+	assert.Equal(t, "primary", title(db.WithPrimary(ctx)))
+
+	// The writes and their internal reads use the primary
+	_ = utils.Must(postModel.Mutation().Where(db.EQ("id", 1)).Update(ctx, entity.New().Set("title", "primary updated")))
+	assert.Equal(t, "primary updated", title(db.WithPrimary(ctx)))
+	_ = utils.Must(client.Exec(ctx, "UPDATE posts SET title = 'primary exec'"))
+	assert.Equal(t, "primary exec", title(db.WithPrimary(ctx)))
+	assert.Contains(t, []string{"replica1", "replica2"}, title(ctx))
+
+	// The reads inside a transaction use the primary
+	require.NoError(t, db.WithTx(client, ctx, func(tx db.Client) error {
+		posts, err := utils.Must(tx.Model("post")).Query().Get(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "primary exec", posts[0].GetString("title"))
+		return nil
+	}))
+
+	// The reads fall back to the primary if there is no healthy replica
+	replicas := client.(*Adapter).replicas
+	for _, r := range replicas.replicas {
+		require.NoError(t, r.sqldb.Close())
+	}
+	replicas.check(ctx)
+	assert.Contains(t, mockLogger.Last().String(), "is unhealthy")
+	assert.Equal(t, "primary exec", title(ctx))
+	assert.Nil(t, replicas.driver())
+}
@@ -0,0 +1,312 @@
+package entdbadapter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	atlasSchema "ariga.io/atlas/sql/schema"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	entSchema "entgo.io/ent/dialect/sql/schema"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+)
+
+// postgresSearchConfig is the text search configuration used to build the Postgres tsvector.
+// The "simple" configuration does not apply any language specific stemming or stop words.
+const postgresSearchConfig = "simple"
+
+// searchIndexName returns the name of the full-text search index of a column.
+func searchIndexName(table, column string) string {
+	return fmt.Sprintf("%s_%s_search", table, column)
+}
+
+// searchFTSTable returns the name of the SQLite FTS5 virtual table of a table.
+func searchFTSTable(table string) string {
+	return table + "_fts"
+}
+
+// searchFTSQuery converts the search query to a FTS5 query that matches all the terms.
+// Each term is quoted so that the FTS5 syntax characters in the query are not interpreted.
+func searchFTSQuery(query string) string {
+	terms := utils.Map(strings.Fields(query), func(term string) string {
+		return `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	})
+
+	return strings.Join(terms, " ")
+}
+
+// writeSearchMatch writes the full-text search condition of the column.
+func writeSearchMatch(b *sql.Builder, s *sql.Selector, column, query string) {
+	switch s.Dialect() {
+	case dialect.MySQL:
+		writeMySQLMatch(b, s, column, query)
+	case dialect.Postgres:
+		writePostgresTSVector(b, s.C(column))
+		b.WriteString(" @@ ")
+		writePostgresTSQuery(b, query)
+	default:
+		ftsTable := searchFTSTable(s.TableName())
+		b.Ident(s.C("rowid")).WriteString(" IN (SELECT ").Ident("rowid").
+			WriteString(" FROM ").Ident(ftsTable).
+			WriteString(" WHERE ").Ident(ftsTable).WriteByte('.').Ident(column).
+			WriteString(" MATCH ").Arg(searchFTSQuery(query)).
+			WriteByte(')')
+	}
+}
+
+// writeSearchRank writes the full-text search relevance of the column.
+// The higher the value, the more relevant the record is.
+func writeSearchRank(b *sql.Builder, s *sql.Selector, column, query string) {
+	switch s.Dialect() {
+	case dialect.MySQL:
+		writeMySQLMatch(b, s, column, query)
+	case dialect.Postgres:
+		b.WriteString("ts_rank(")
+		writePostgresTSVector(b, s.C(column))
+		b.Comma()
+		writePostgresTSQuery(b, query)
+		b.WriteByte(')')
+	default:
+		// bm25 returns lower values for more relevant records
+		ftsTable := searchFTSTable(s.TableName())
+		b.WriteString("COALESCE((SELECT -bm25(").Ident(ftsTable).WriteString(")").
+			WriteString(" FROM ").Ident(ftsTable).
+			WriteString(" WHERE ").Ident(ftsTable).WriteByte('.').Ident("rowid").
+			WriteString(" = ").Ident(s.C("rowid")).
+			WriteString(" AND ").Ident(ftsTable).WriteByte('.').Ident(column).
+			WriteString(" MATCH ").Arg(searchFTSQuery(query)).
+			WriteString("), 0)")
+	}
+}
+
+func writeMySQLMatch(b *sql.Builder, s *sql.Selector, column, query string) {
+	b.WriteString("MATCH(").Ident(s.C(column)).WriteString(") AGAINST(").
+		Arg(query).
+		WriteString(" IN NATURAL LANGUAGE MODE)")
+}
+
+func writePostgresTSVector(b *sql.Builder, column string) {
+	b.WriteString(fmt.Sprintf("to_tsvector('%s', COALESCE(", postgresSearchConfig)).
+		Ident(column).
+		WriteString(", ''))")
+}
+
+func writePostgresTSQuery(b *sql.Builder, query string) {
+	b.WriteString(fmt.Sprintf("websearch_to_tsquery('%s', ", postgresSearchConfig)).
+		Arg(query).
+		WriteByte(')')
+}
+
+// searchPredicates returns the $search predicates of the given predicates, including the nested ones.
+func searchPredicates(predicates []*db.Predicate) []*db.Predicate {
+	result := []*db.Predicate{}
+	for _, p := range predicates {
+		if p == nil {
+			continue
+		}
+
+		if p.Operator == db.OpSearch && !strings.Contains(p.Field, ".") {
+			result = append(result, p)
+		}
+
+		result = append(result, searchPredicates(p.And)...)
+		result = append(result, searchPredicates(p.Or)...)
+	}
+
+	return result
+}
+
+// searchRelevanceOrder creates the order expression that sorts the records
+// by the sum of the relevance of the $search predicates.
+// sql.ExprFunc is used instead of Selector.OrderExprFunc since the latter drops the query arguments.
+func searchRelevanceOrder(predicates []*db.Predicate, desc bool) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		s.OrderExpr(sql.ExprFunc(func(b *sql.Builder) {
+			b.WriteByte('(')
+			for i, p := range predicates {
+				if i > 0 {
+					b.WriteString(" + ")
+				}
+
+				writeSearchRank(b, s, p.Field, p.Value.(string))
+			}
+			b.WriteString(utils.If(desc, ") DESC", ") ASC"))
+		}))
+	}
+}
+
+// searchIndexNames returns the names of the Postgres full-text search indexes of the models.
+func (d *Adapter) searchIndexNames() []string {
+	names := []string{}
+	for _, m := range d.models {
+		for _, f := range m.schema.SearchableFields() {
+			names = append(names, searchIndexName(m.entTable.Name, f.Name))
+		}
+	}
+
+	return names
+}
+
+// createSearchIndexesHook prevents the migration from dropping the full-text search indexes
+// that are not part of the ent schema (the Postgres tsvector expression indexes).
+// The search indexes of the fields that are no longer searchable are dropped as usual.
+func createSearchIndexesHook(indexNames []string) entSchema.DiffHook {
+	return func(next entSchema.Differ) entSchema.Differ {
+		return entSchema.DiffFunc(func(current, desired *atlasSchema.Schema) ([]atlasSchema.Change, error) {
+			changes, err := next.Diff(current, desired)
+			if err != nil {
+				return nil, err
+			}
+
+			result := []atlasSchema.Change{}
+			for _, c := range changes {
+				modifyTable, ok := c.(*atlasSchema.ModifyTable)
+				if !ok {
+					result = append(result, c)
+					continue
+				}
+
+				modifyTable.Changes = utils.Filter(modifyTable.Changes, func(change atlasSchema.Change) bool {
+					dropIndex, ok := change.(*atlasSchema.DropIndex)
+					return !ok || !utils.Contains(indexNames, dropIndex.I.Name)
+				})
+
+				if len(modifyTable.Changes) > 0 {
+					result = append(result, modifyTable)
+				}
+			}
+
+			return result, nil
+		})
+	}
+}
+
+// migrateSearch provisions the full-text search structures of the searchable fields.
+// MySQL FULLTEXT indexes are part of the ent schema and are created by the migration.
+func (d *Adapter) migrateSearch(ctx context.Context) error {
+	switch d.driver.Dialect() {
+	case dialect.Postgres:
+		return d.migratePostgresSearch(ctx)
+	case dialect.SQLite:
+		return d.migrateSQLiteSearch(ctx)
+	default:
+		return nil
+	}
+}
+
+// migratePostgresSearch creates a GIN index on the tsvector of each searchable field.
+func (d *Adapter) migratePostgresSearch(ctx context.Context) error {
+	for _, m := range d.models {
+		for _, f := range m.schema.SearchableFields() {
+			b := &sql.Builder{}
+			b.SetDialect(dialect.Postgres)
+			b.WriteString("CREATE INDEX IF NOT EXISTS ").
+				Ident(searchIndexName(m.entTable.Name, f.Name)).
+				WriteString(" ON ").Ident(m.entTable.Name).
+				WriteString(" USING GIN (")
+			writePostgresTSVector(b, f.Name)
+			b.WriteByte(')')
+
+			if _, err := driverExec(d.driver, ctx, b.String(), []any{}); err != nil {
+				return fmt.Errorf("create search index %s.%s: %w", m.schema.Name, f.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// migrateSQLiteSearch creates a FTS5 virtual table for each table that has searchable fields.
+// The virtual table uses the table as external content and is kept in sync using triggers.
+// When the searchable fields change or a trigger is missing, the virtual table is recreated and rebuilt.
+func (d *Adapter) migrateSQLiteSearch(ctx context.Context) error {
+	quote := func(ident string) string {
+		return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+	}
+
+	for _, m := range d.models {
+		table := m.entTable.Name
+		ftsTable := searchFTSTable(table)
+		columns := utils.Map(m.schema.SearchableFields(), func(f *schema.Field) string {
+			return quote(f.Name)
+		})
+
+		existing, err := driverQuery(d.driver, ctx, "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", []any{ftsTable})
+		if err != nil {
+			return err
+		}
+
+		createSQL := fmt.Sprintf(
+			"CREATE VIRTUAL TABLE %s USING fts5(%s, content=%s)",
+			quote(ftsTable), strings.Join(columns, ", "), quote(table),
+		)
+
+		if len(existing) > 0 && existing[0].GetString("sql") == createSQL {
+			// The index is out of sync if a trigger is missing, it is recreated and rebuilt
+			triggers, err := driverQuery(
+				d.driver,
+				ctx,
+				"SELECT name FROM sqlite_master WHERE type = 'trigger' AND tbl_name = ? AND name IN (?, ?, ?)",
+				[]any{table, ftsTable + "_ai", ftsTable + "_ad", ftsTable + "_au"},
+			)
+			if err != nil {
+				return err
+			}
+
+			if len(triggers) == 3 {
+				continue
+			}
+		}
+
+		if len(existing) == 0 && len(columns) == 0 {
+			continue
+		}
+
+		statements := []string{
+			fmt.Sprintf("DROP TRIGGER IF EXISTS %s", quote(ftsTable+"_ai")),
+			fmt.Sprintf("DROP TRIGGER IF EXISTS %s", quote(ftsTable+"_ad")),
+			fmt.Sprintf("DROP TRIGGER IF EXISTS %s", quote(ftsTable+"_au")),
+			fmt.Sprintf("DROP TABLE IF EXISTS %s", quote(ftsTable)),
+		}
+
+		if len(columns) > 0 {
+			newValues := "new.rowid, " + strings.Join(utils.Map(columns, func(c string) string {
+				return "new." + c
+			}), ", ")
+			oldValues := "'delete', old.rowid, " + strings.Join(utils.Map(columns, func(c string) string {
+				return "old." + c
+			}), ", ")
+			insertColumns := fmt.Sprintf("%s(rowid, %s)", quote(ftsTable), strings.Join(columns, ", "))
+			deleteColumns := fmt.Sprintf("%s(%s, rowid, %s)", quote(ftsTable), quote(ftsTable), strings.Join(columns, ", "))
+
+			statements = append(
+				statements,
+				createSQL,
+				fmt.Sprintf(
+					"CREATE TRIGGER %s AFTER INSERT ON %s BEGIN INSERT INTO %s VALUES (%s); END",
+					quote(ftsTable+"_ai"), quote(table), insertColumns, newValues,
+				),
+				fmt.Sprintf(
+					"CREATE TRIGGER %s AFTER DELETE ON %s BEGIN INSERT INTO %s VALUES (%s); END",
+					quote(ftsTable+"_ad"), quote(table), deleteColumns, oldValues,
+				),
+				fmt.Sprintf(
+					"CREATE TRIGGER %s AFTER UPDATE ON %s BEGIN INSERT INTO %s VALUES (%s); INSERT INTO %s VALUES (%s); END",
+					quote(ftsTable+"_au"), quote(table), deleteColumns, oldValues, insertColumns, newValues,
+				),
+				fmt.Sprintf("INSERT INTO %s(%s) VALUES ('rebuild')", quote(ftsTable), quote(ftsTable)),
+			)
+		}
+
+		for _, statement := range statements {
+			if _, err := driverExec(d.driver, ctx, statement, []any{}); err != nil {
+				return fmt.Errorf("migrate search table %s: %w", ftsTable, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+package entdbadapter
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	atlasSchema "ariga.io/atlas/sql/schema"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	dialectSql "entgo.io/ent/dialect/sql"
+	entSchema "entgo.io/ent/dialect/sql/schema"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createSearchSchemaBuilder(t *testing.T, searchableFields ...string) *schema.Builder {
+	t.Helper()
+	articleSchema := &schema.Schema{
+		Name:           "article",
+		Namespace:      "articles",
+		LabelFieldName: "title",
+		Fields: []*schema.Field{
+			{Name: "id", Label: "ID", Type: schema.TypeUint64},
+			{Name: "title", Label: "Title", Type: schema.TypeString},
+			{Name: "body", Label: "Body", Type: schema.TypeText, Optional: true},
+			{Name: "views", Label: "Views", Type: schema.TypeInt, Optional: true, Sortable: true},
+		},
+	}
+
+	for _, f := range articleSchema.Fields {
+		f.Searchable = utils.Contains(searchableFields, f.Name)
+	}
+
+	sb, err := schema.NewBuilderFromSchemas("", map[string]*schema.Schema{
+		articleSchema.Name: articleSchema,
+	})
+	require.NoError(t, err)
+
+	return sb
+}
+
+func TestSearchFTSQuery(t *testing.T) {
+	assert.Equal(t, `"hello" "world"`, searchFTSQuery(" hello  world "))
+	assert.Equal(t, `"say" """hi""" "OR" "x*"`, searchFTSQuery(`say "hi" OR x*`))
+	assert.Equal(t, "", searchFTSQuery(""))
+}
+
+func TestSearchPredicates(t *testing.T) {
+	search1 := db.Search("title", "a")
+	search2 := db.Search("body", "b")
+	predicates := searchPredicates([]*db.Predicate{
+		nil,
+		db.EQ("views", 1),
+		search1,
+		db.Or(db.And(search2), db.Search("owner.name", "c")),
+	})
+
+	assert.Equal(t, []*db.Predicate{search1, search2}, predicates)
+}
+
+func TestSearchIndexesHook(t *testing.T) {
+	table := atlasSchema.NewTable("articles")
+	changes := []atlasSchema.Change{
+		&atlasSchema.AddTable{T: atlasSchema.NewTable("posts")},
+		&atlasSchema.ModifyTable{T: table, Changes: []atlasSchema.Change{
+			&atlasSchema.DropIndex{I: atlasSchema.NewIndex("articles_title_search")},
+		}},
+		&atlasSchema.ModifyTable{T: table, Changes: []atlasSchema.Change{
+			&atlasSchema.DropIndex{I: atlasSchema.NewIndex("articles_title_search")},
+			&atlasSchema.DropIndex{I: atlasSchema.NewIndex("articles_body_search")},
+		}},
+	}
+
+	differ := createSearchIndexesHook([]string{"articles_title_search"})(
+		entSchema.DiffFunc(func(current, desired *atlasSchema.Schema) ([]atlasSchema.Change, error) {
+			return changes, nil
+		}),
+	)
+
+	result, err := differ.Diff(nil, nil)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.IsType(t, &atlasSchema.AddTable{}, result[0])
+	assert.Equal(t, []atlasSchema.Change{
+		&atlasSchema.DropIndex{I: atlasSchema.NewIndex("articles_body_search")},
+	}, result[1].(*atlasSchema.ModifyTable).Changes)
+}
+
+func TestSearchMySQLFullTextIndex(t *testing.T) {
+	sb := createSearchSchemaBuilder(t, "title", "body")
+	client := utils.Must(NewEntClient(&db.Config{Driver: "sqlmock"}, sb, dialectSql.OpenDB(dialect.MySQL, nil)))
+	model := utils.Must(client.(*Adapter).model("article"))
+
+	indexes := utils.Filter(model.entTable.Indexes, func(index *entSchema.Index) bool {
+		return index.Annotation != nil && index.Annotation.Type == "FULLTEXT"
+	})
+
+	require.Len(t, indexes, 2)
+	assert.Equal(t, "articles_title_search", indexes[0].Name)
+	assert.Equal(t, "title", indexes[0].Columns[0].Name)
+	assert.Equal(t, &entsql.IndexAnnotation{Type: "FULLTEXT"}, indexes[1].Annotation)
+	assert.Equal(t, "articles_body_search", indexes[1].Name)
+	assert.Equal(t, []string{"articles_title_search", "articles_body_search"}, client.(*Adapter).searchIndexNames())
+}
+
+func TestSearchQuery(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Dialect     string
+		Predicates  []*db.Predicate
+		Order       []string
+		Expect      func(sqlmock.Sqlmock)
+		ExpectError string
+	}{
+		{
+			Name:        "Search_non_searchable_field",
+			Predicates:  []*db.Predicate{db.Search("views", "1")},
+			ExpectError: "field views is not searchable",
+		},
+		{
+			Name:        "Search_invalid_field",
+			Predicates:  []*db.Predicate{db.Search("invalid", "1")},
+			ExpectError: "field 'invalid' is not defined in schema 'article'",
+		},
+		{
+			Name:        "Search_relation_field",
+			Predicates:  []*db.Predicate{db.Search("owner.name", "1")},
+			ExpectError: "operator $search is not supported on relation field owner.name",
+		},
+		{
+			Name:        "Search_invalid_value",
+			Predicates:  []*db.Predicate{{Field: "title", Operator: db.OpSearch, Value: 1}},
+			ExpectError: "value of field title.$search = 1 (int) must be string",
+		},
+		{
+			Name:        "Search_relevance_without_search",
+			Order:       []string{"-_relevance"},
+			ExpectError: "ordering by _relevance requires a $search predicate",
+		},
+		{
+			Name:       "Search_mysql",
+			Predicates: []*db.Predicate{db.Or(db.Search("title", "hello"), db.Search("body", "hello"))},
+			Order:      []string{"-_relevance"},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery("SELECT * FROM `articles` WHERE MATCH(`articles`.`title`) AGAINST(? IN NATURAL LANGUAGE MODE) OR MATCH(`articles`.`body`) AGAINST(? IN NATURAL LANGUAGE MODE) ORDER BY (MATCH(`articles`.`title`) AGAINST(? IN NATURAL LANGUAGE MODE) + MATCH(`articles`.`body`) AGAINST(? IN NATURAL LANGUAGE MODE)) DESC")).
+					WithArgs("hello", "hello", "hello", "hello").
+					WillReturnRows(mock.NewRows([]string{"id", "title"}).AddRow(1, "hello"))
+			},
+		},
+		{
+			Name:       "Search_postgres",
+			Dialect:    dialect.Postgres,
+			Predicates: []*db.Predicate{db.Search("title", "hello world"), db.GT("views", 1)},
+			Order:      []string{"_relevance", "views"},
+			Expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(utils.EscapeQuery(`SELECT * FROM "articles" WHERE to_tsvector('simple', COALESCE("articles"."title", '')) @@ websearch_to_tsquery('simple', $1) AND "articles"."views" > $2 ORDER BY (ts_rank(to_tsvector('simple', COALESCE("articles"."title", '')), websearch_to_tsquery('simple', $3))) ASC, "articles"."views" ASC`)).
+					WithArgs("hello world", 1, "hello world").
+					WillReturnRows(mock.NewRows([]string{"id", "title"}).AddRow(1, "hello"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			sb := createSearchSchemaBuilder(t, "title", "body")
+			dialectName := utils.If(tt.Dialect == "", dialect.MySQL, tt.Dialect)
+			client := utils.Must(NewEntClient(&db.Config{
+				Driver: "sqlmock",
+			}, sb, dialectSql.OpenDB(dialectName, mockDB)))
+
+			if tt.Expect != nil {
+				tt.Expect(mock)
+			}
+
+			model := utils.Must(client.Model("article"))
+			_, err = model.Query(tt.Predicates...).Order(tt.Order...).Get(context.Background())
+			if tt.ExpectError != "" {
+				assert.ErrorContains(t, err, tt.ExpectError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSearchSQLite(t *testing.T) {
+	ctx := context.Background()
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	client, err := NewTestClient(migrationDir, createSearchSchemaBuilder(t, "title", "body"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	model := utils.Must(client.Model("article"))
+	for _, article := range []string{
+		`{"title": "Hello world", "body": "The first article"}`,
+		`{"title": "Second article", "body": "hello hello, a warm welcome"}`,
+		`{"title": "Third", "body": "Nothing to see"}`,
+	} {
+		_, err := model.CreateFromJSON(ctx, article)
+		require.NoError(t, err)
+	}
+
+	ftsSQL := func(client db.Client) string {
+		rows, err := client.Query(ctx, "SELECT sql FROM sqlite_master WHERE name = ?", "articles_fts")
+		require.NoError(t, err)
+		if len(rows) == 0 {
+			return ""
+		}
+		return rows[0].GetString("sql")
+	}
+
+	search := func(client db.Client, predicates ...*db.Predicate) []uint64 {
+		entities, err := utils.Must(client.Model("article")).
+			Query(predicates...).
+			Order("-_relevance", "id").
+			Get(ctx)
+		require.NoError(t, err)
+		return utils.Map(entities, func(e *entity.Entity) uint64 {
+			return e.ID().(uint64)
+		})
+	}
+
+	hello := utils.Must(db.SearchFields(model.Schema(), "hello"))
+	assert.Equal(t, `CREATE VIRTUAL TABLE "articles_fts" USING fts5("title", "body", content="articles")`, ftsSQL(client))
+	assert.Equal(t, []uint64{2, 1}, search(client, hello))
+	assert.Equal(t, []uint64{1}, search(client, db.Search("title", "hello")))
+	assert.Equal(t, []uint64{2}, search(client, db.Search("body", `hello "warm`)))
+
+	// Update and delete are synced to the search table
+	_, err = model.Mutation().Where(db.EQ("id", 3)).Update(ctx, entity.New().Set("title", "Hello again"))
+	require.NoError(t, err)
+	_, err = model.Mutation().Where(db.EQ("id", 1)).Delete(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3}, search(client, hello))
+
+	// Changing the searchable fields recreates the search table
+	adapter := client.(*Adapter)
+	client, err = adapter.Reload(ctx, createSearchSchemaBuilder(t, "title"), nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, `CREATE VIRTUAL TABLE "articles_fts" USING fts5("title", content="articles")`, ftsSQL(client))
+	assert.Equal(t, []uint64{3}, search(client, db.Search("title", "hello")))
+
+	// Migrating again keeps the search table
+	require.NoError(t, client.(*Adapter).migrateSearch(ctx))
+	assert.Equal(t, []uint64{3}, search(client, db.Search("title", "hello")))
+
+	// A missing trigger recreates the triggers and rebuilds the search table
+	_, err = client.Exec(ctx, `DROP TRIGGER "articles_fts_ai"`)
+	require.NoError(t, err)
+	_, err = utils.Must(client.Model("article")).CreateFromJSON(ctx, `{"title": "Hello there", "body": "Not indexed"}`)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{3}, search(client, db.Search("title", "hello")))
+
+	require.NoError(t, client.(*Adapter).migrateSearch(ctx))
+	triggers, err := client.Query(ctx, "SELECT name FROM sqlite_master WHERE type = 'trigger' AND tbl_name = ?", "articles")
+	require.NoError(t, err)
+	assert.Len(t, triggers, 3)
+	assert.Equal(t, []uint64{3, 4}, search(client, db.Search("title", "hello")))
+
+	// Removing all the searchable fields drops the search table
+	client, err = client.(*Adapter).Reload(ctx, createSearchSchemaBuilder(t), nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, "", ftsSQL(client))
+}
@@ -0,0 +1,163 @@
+package entdbadapter
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	entSchema "entgo.io/ent/dialect/sql/schema"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/schema"
+)
+
+// tenantPredicate returns the predicate that scopes the records of a tenant schema to the tenant of the context.
+// Returns nil if the schema is not a tenant schema or the context accesses all the tenants.
+func tenantPredicate(ctx context.Context, s *schema.Schema) (*db.Predicate, error) {
+	if !s.Tenant || db.UseAllTenants(ctx) {
+		return nil, nil
+	}
+
+	tenantID := db.TenantFromContext(ctx)
+	if tenantID == "" {
+		return nil, db.ErrTenantRequired
+	}
+
+	return db.EQ(entity.FieldTenantID, tenantID), nil
+}
+
+// scope adds the tenant predicate and the row filter predicates to the query.
+// They are added after the pre query hooks so that the hooks cannot remove them.
+func (q *Query) scope(ctx context.Context) error {
+	if q.scoped {
+		return nil
+	}
+
+	predicate, err := tenantPredicate(ctx, q.model.schema)
+	if err != nil {
+		return err
+	}
+
+	if predicate != nil {
+		q.predicates = append(q.predicates, predicate)
+	}
+
+	q.predicates = append(q.predicates, db.RowFilterFromContext(ctx, q.model.schema.Name)...)
+	q.scoped = true
+	return nil
+}
+
+// scope adds the tenant predicate and the row filter predicates to the mutation.
+// They are added after the pre mutation hooks so that the hooks cannot remove them.
+func (m *Mutation) scope(ctx context.Context) error {
+	predicate, err := tenantPredicate(ctx, m.model.schema)
+	if err != nil {
+		return err
+	}
+
+	if predicate != nil && !slices.ContainsFunc(*m.predicates, func(p *db.Predicate) bool {
+		return p.Field == predicate.Field && p.Operator == predicate.Operator && p.Value == predicate.Value
+	}) {
+		*m.predicates = append(*m.predicates, predicate)
+	}
+
+	for _, predicate := range db.RowFilterFromContext(ctx, m.model.schema.Name) {
+		if !slices.Contains(*m.predicates, predicate) {
+			*m.predicates = append(*m.predicates, predicate)
+		}
+	}
+
+	return nil
+}
+
+// setTenant sets the tenant of the created entity to the tenant of the context.
+// The contexts that access all the tenants must set the tenant of the entity.
+func setTenant(ctx context.Context, s *schema.Schema, e *entity.Entity) error {
+	if !s.Tenant {
+		return nil
+	}
+
+	if db.UseAllTenants(ctx) {
+		if e.GetString(entity.FieldTenantID) == "" {
+			return db.ErrTenantRequired
+		}
+
+		return nil
+	}
+
+	tenantID := db.TenantFromContext(ctx)
+	if tenantID == "" {
+		return db.ErrTenantRequired
+	}
+
+	e.Set(entity.FieldTenantID, tenantID)
+	return nil
+}
+
+// tenantConflictColumns adds the tenant column to the upsert conflict columns of a tenant schema,
+// since the unique keys of the tenant schemas are unique per tenant.
+// The primary key is unique across the tenants and is kept as is.
+func tenantConflictColumns(s *schema.Schema, conflictColumns []string) []string {
+	if !s.Tenant ||
+		slices.Contains(conflictColumns, entity.FieldTenantID) ||
+		slices.Equal(conflictColumns, []string{s.PrimaryKeyName()}) {
+		return conflictColumns
+	}
+
+	return append(slices.Clip(conflictColumns), entity.FieldTenantID)
+}
+
+// tenantIndexes replaces the unique columns of a tenant schema with unique indexes
+// that include the tenant column, so that the unique values are unique per tenant.
+// The tenant column is indexed since all the queries are filtered by it.
+func tenantIndexes(s *schema.Schema, table *entSchema.Table, primaryColumn *entSchema.Column) {
+	tenantColumn, ok := table.Column(entity.FieldTenantID)
+	if !s.Tenant || !ok {
+		return
+	}
+
+	table.Indexes = append(table.Indexes, &entSchema.Index{
+		Name:    fmt.Sprintf("idx_%s_%s", s.Name, entity.FieldTenantID),
+		Columns: []*entSchema.Column{tenantColumn},
+	})
+
+	for _, column := range table.Columns {
+		if !column.Unique || column == primaryColumn || column == tenantColumn {
+			continue
+		}
+
+		column.Unique = false
+		table.Indexes = append(table.Indexes, &entSchema.Index{
+			Name:    fmt.Sprintf("idx_%s_%s_%s", s.Name, entity.FieldTenantID, column.Name),
+			Unique:  true,
+			Columns: []*entSchema.Column{tenantColumn, column},
+		})
+	}
+}
+
+// checkRowFilter checks that the created records match the row filter of the context,
+// so that the records are not created outside of the rows that the context can access.
+// The records are read in the transaction of the creation, which is rolled back on mismatch.
+func (m *Mutation) checkRowFilter(ctx context.Context, client db.Client, ids []any) error {
+	rowFilter := db.RowFilterFromContext(ctx, m.model.schema.Name)
+	if len(rowFilter) == 0 || len(ids) == 0 {
+		return nil
+	}
+
+	model, err := client.Model(m.model.name)
+	if err != nil {
+		return err
+	}
+
+	predicates := append([]*db.Predicate{db.In(m.model.schema.PrimaryKeyName(), ids)}, rowFilter...)
+	count, err := model.Query(predicates...).Count(db.WithRawRead(db.WithPrimary(ctx)))
+	if err != nil {
+		return err
+	}
+
+	if count != len(ids) {
+		return db.ErrRowFilterMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,293 @@
+package entdbadapter
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTenantSchemaBuilder(t *testing.T) *schema.Builder {
+	t.Helper()
+	projectSchema := &schema.Schema{
+		Name:             "project",
+		Namespace:        "projects",
+		LabelFieldName:   "name",
+		DisableTimestamp: true,
+		Tenant:           true,
+		Fields: []*schema.Field{
+			{Name: "name", Label: "Name", Type: schema.TypeString, Unique: true},
+			{
+				Name:  "tasks",
+				Label: "Tasks",
+				Type:  schema.TypeRelation,
+				Relation: &schema.Relation{
+					Type:             schema.O2M,
+					Owner:            true,
+					TargetSchemaName: "task",
+					TargetFieldName:  "project",
+				},
+			},
+			{
+				Name:  "labels",
+				Label: "Labels",
+				Type:  schema.TypeRelation,
+				Relation: &schema.Relation{
+					Type:             schema.M2M,
+					Owner:            true,
+					TargetSchemaName: "label",
+					TargetFieldName:  "projects",
+				},
+			},
+		},
+	}
+
+	taskSchema := &schema.Schema{
+		Name:             "task",
+		Namespace:        "tasks",
+		LabelFieldName:   "name",
+		DisableTimestamp: true,
+		Tenant:           true,
+		Fields: []*schema.Field{
+			{Name: "name", Label: "Name", Type: schema.TypeString},
+			{
+				Name:     "project",
+				Label:    "Project",
+				Type:     schema.TypeRelation,
+				Optional: true,
+				Relation: &schema.Relation{
+					Type:             schema.O2M,
+					TargetSchemaName: "project",
+					TargetFieldName:  "tasks",
+					Optional:         true,
+				},
+			},
+		},
+	}
+
+	labelSchema := &schema.Schema{
+		Name:             "label",
+		Namespace:        "labels",
+		LabelFieldName:   "name",
+		DisableTimestamp: true,
+		Tenant:           true,
+		Fields: []*schema.Field{
+			{Name: "name", Label: "Name", Type: schema.TypeString},
+			{
+				Name:  "projects",
+				Label: "Projects",
+				Type:  schema.TypeRelation,
+				Relation: &schema.Relation{
+					Type:             schema.M2M,
+					TargetSchemaName: "project",
+					TargetFieldName:  "labels",
+				},
+			},
+		},
+	}
+
+	sb, err := schema.NewBuilderFromSchemas("", map[string]*schema.Schema{
+		projectSchema.Name: projectSchema,
+		taskSchema.Name:    taskSchema,
+		labelSchema.Name:   labelSchema,
+	})
+	require.NoError(t, err)
+
+	return sb
+}
+
+func TestTenantScoping(t *testing.T) {
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	client, err := NewClient(&db.Config{
+		Driver:       "sqlite",
+		Name:         ":memory:_" + utils.RandomString(10),
+		MigrationDir: migrationDir,
+	}, createTenantSchemaBuilder(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	acme := db.WithTenant(context.Background(), "acme")
+	globex := db.WithTenant(context.Background(), "globex")
+	allTenants := db.WithAllTenants(context.Background())
+	projectModel := utils.Must(client.Model("project"))
+	taskModel := utils.Must(client.Model("task"))
+	labelModel := utils.Must(client.Model("label"))
+	names := func(entities []*entity.Entity) []string {
+		return utils.Map(entities, func(e *entity.Entity) string { return e.GetString("name") })
+	}
+
+	// A tenant is required to access the tenant schemas
+	_, err = projectModel.Query().Get(context.Background())
+	assert.ErrorIs(t, err, db.ErrTenantRequired)
+	_, err = projectModel.Mutation().Create(context.Background(), entity.New().Set("name", "p"))
+	assert.ErrorIs(t, err, db.ErrTenantRequired)
+	_, err = projectModel.Mutation().Create(allTenants, entity.New().Set("name", "p"))
+	assert.ErrorIs(t, err, db.ErrTenantRequired)
+
+	// The tenant is set from the context and the unique values are unique per tenant
+	acmeProject := utils.Must(projectModel.Mutation().Create(acme, entity.New().
+		Set("name", "website").
+		Set(entity.FieldTenantID, "globex")))
+	globexProject := utils.Must(projectModel.Mutation().Create(globex, entity.New().Set("name", "website")))
+	_ = utils.Must(projectModel.Mutation().Create(allTenants, entity.New().
+		Set("name", "mobile").
+		Set(entity.FieldTenantID, "globex")))
+	_, err = projectModel.Mutation().Create(acme, entity.New().Set("name", "website"))
+	assert.ErrorContains(t, err, "UNIQUE constraint failed")
+
+	_ = utils.Must(taskModel.Mutation().CreateMany(acme, []*entity.Entity{
+		entity.New().Set("name", "design").Set("project", entity.New(acmeProject)),
+		entity.New().Set("name", "build").Set("project", entity.New(acmeProject)),
+	}))
+	_ = utils.Must(taskModel.Mutation().Create(globex, entity.New().
+		Set("name", "launch").
+		Set("project", entity.New(globexProject))))
+
+	// A task of globex that is linked to the project of acme
+	_ = utils.Must(taskModel.Mutation().Create(allTenants, entity.New().
+		Set("name", "leaked").
+		Set(entity.FieldTenantID, "globex").
+		Set("project", entity.New(acmeProject))))
+
+	// Queries and counts
+	projects := utils.Must(projectModel.Query().Select("name", "tenant_id", "tasks.name").Get(acme))
+	require.Len(t, projects, 1)
+	assert.Equal(t, "acme", projects[0].GetString("tenant_id"))
+	assert.Equal(t, []string{"design", "build"}, names(projects[0].Get("tasks").([]*entity.Entity)))
+	assert.Equal(t, 2, utils.Must(projectModel.Query().Count(globex, &db.QueryOption{})))
+	assert.Equal(t, 3, utils.Must(projectModel.Query().Count(allTenants, &db.QueryOption{})))
+	assert.Equal(t, 0, utils.Must(projectModel.Query(db.EQ("tenant_id", "globex")).Count(acme, &db.QueryOption{})))
+
+	task := utils.Must(taskModel.Query(db.EQ("name", "design")).Select("name", "project.name").First(acme))
+	assert.Equal(t, "website", task.Get("project").(*entity.Entity).GetString("name"))
+	_, err = taskModel.Query(db.EQ("name", "launch")).First(acme)
+	assert.True(t, db.IsNotFound(err))
+
+	// Updates and deletes
+	affected := utils.Must(projectModel.Mutation().Where(db.EQ("name", "mobile")).Update(globex, entity.New().
+		Set("name", "app").
+		Set(entity.FieldTenantID, "acme")))
+	assert.Equal(t, 1, affected)
+	assert.Equal(t, 0, utils.Must(projectModel.Mutation().Where(db.EQ("name", "app")).Update(acme, entity.New().
+		Set("name", "stolen"))))
+	assert.Equal(t, []string{"website"}, names(utils.Must(projectModel.Query().Get(acme))))
+	assert.ElementsMatch(t, []string{"website", "app"}, names(utils.Must(projectModel.Query().Get(globex))))
+	assert.Equal(t, 0, utils.Must(taskModel.Mutation().Where(db.EQ("name", "launch")).Delete(acme)))
+	assert.Equal(t, 1, utils.Must(taskModel.Mutation().Where(db.EQ("name", "launch")).Delete(globex)))
+
+	// Upserts
+	label := utils.Must(labelModel.Mutation().Create(acme, entity.New().Set("name", "urgent")))
+	_, err = labelModel.Mutation().Upsert(globex, entity.New().Set("id", label).Set("name", "stolen"), []string{"id"}, nil)
+	assert.ErrorContains(t, err, "the conflict column values are used by another tenant")
+	_ = utils.Must(projectModel.Mutation().Upsert(globex, entity.New().Set("name", "website"), []string{"name"}, nil))
+	assert.Equal(t, 2, utils.Must(projectModel.Query().Count(globex, &db.QueryOption{})))
+	assert.Equal(t, 1, utils.Must(projectModel.Query().Count(acme, &db.QueryOption{})))
+
+	// M2M edges are scoped to the tenant, including the per parent limits
+	globexLabel := utils.Must(labelModel.Mutation().Create(globex, entity.New().Set("name", "backlog")))
+	_ = utils.Must(projectModel.Mutation().Where(db.EQ("id", acmeProject)).Update(allTenants, entity.New().
+		Set("labels", entity.New().Set("$add", []*entity.Entity{entity.New(label), entity.New(globexLabel)}))))
+	project := utils.Must(projectModel.Query(db.EQ("id", acmeProject)).Select("name", "labels.name").First(acme))
+	assert.Equal(t, []string{"urgent"}, names(project.Get("labels").([]*entity.Entity)))
+	project = utils.Must(projectModel.Query(db.EQ("id", acmeProject)).
+		Select("name", "labels.name").
+		WithRelationOptions(db.RelationOptions{"labels": {Limit: 5}}).
+		First(acme))
+	assert.Equal(t, []string{"urgent"}, names(project.Get("labels").([]*entity.Entity)))
+
+	// The SQL of the queries include the tenant predicate
+	query, args, err := projectModel.Query().SQL(acme)
+	require.NoError(t, err)
+	assert.Contains(t, query, "`projects`.`tenant_id` = ?")
+	assert.Equal(t, []any{"acme"}, args)
+}
+
+func TestRowFilterScoping(t *testing.T) {
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	client, err := NewClient(&db.Config{
+		Driver:       "sqlite",
+		Name:         ":memory:_" + utils.RandomString(10),
+		MigrationDir: migrationDir,
+	}, createTenantSchemaBuilder(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	acme := db.WithTenant(context.Background(), "acme")
+	taskModel := utils.Must(client.Model("task"))
+	projectModel := utils.Must(client.Model("project"))
+	_ = utils.Must(projectModel.Mutation().Create(acme, entity.New().Set("name", "website")))
+	_ = utils.Must(taskModel.Mutation().CreateMany(acme, []*entity.Entity{
+		entity.New().Set("name", "design"),
+		entity.New().Set("name", "build"),
+		entity.New().Set("name", "launch"),
+	}))
+
+	filtered := db.WithRowFilter(acme, &db.RowFilter{
+		Schema:     "task",
+		Predicates: []*db.Predicate{db.NEQ("name", "launch")},
+	})
+
+	// Queries and counts of the filter schema are restricted
+	tasks := utils.Must(taskModel.Query().Order("id").Get(filtered))
+	assert.Equal(t, []string{"design", "build"}, utils.Map(tasks, func(e *entity.Entity) string {
+		return e.GetString("name")
+	}))
+	assert.Equal(t, 2, utils.Must(taskModel.Query().Count(filtered, &db.QueryOption{})))
+	_, err = taskModel.Query(db.EQ("name", "launch")).First(filtered)
+	assert.True(t, db.IsNotFound(err))
+
+	// The other schemas are not restricted
+	assert.Equal(t, 1, utils.Must(projectModel.Query().Count(filtered, &db.QueryOption{})))
+
+	// Updates and deletes are restricted
+	mutation := taskModel.Mutation()
+	affected := utils.Must(mutation.Update(filtered, entity.New().Set("name", "updated")))
+	assert.Equal(t, 2, affected)
+	assert.Equal(t, 1, utils.Must(taskModel.Query(db.EQ("name", "launch")).Count(acme, &db.QueryOption{})))
+
+	affected = utils.Must(taskModel.Mutation().Where(db.EQ("name", "launch")).Delete(filtered))
+	assert.Equal(t, 0, affected)
+	affected = utils.Must(taskModel.Mutation().Delete(filtered))
+	assert.Equal(t, 2, affected)
+	assert.Equal(t, 1, utils.Must(taskModel.Query().Count(acme, &db.QueryOption{})))
+
+	// The created records must match the row filter, the creation is rolled back otherwise
+	_, err = taskModel.Mutation().Create(filtered, entity.New().Set("name", "launch"))
+	assert.ErrorIs(t, err, db.ErrRowFilterMismatch)
+	_, err = taskModel.Mutation().CreateMany(filtered, []*entity.Entity{
+		entity.New().Set("name", "review"),
+		entity.New().Set("name", "launch"),
+	})
+	assert.ErrorIs(t, err, db.ErrRowFilterMismatch)
+	assert.Equal(t, 1, utils.Must(taskModel.Query().Count(acme, &db.QueryOption{})))
+
+	reviewID := utils.Must(taskModel.Mutation().Create(filtered, entity.New().Set("name", "review")))
+	assert.Equal(t, 2, utils.Must(taskModel.Query().Count(acme, &db.QueryOption{})))
+
+	// The upserts must not write the records outside the row filter, the upsert is rolled back otherwise
+	launch := utils.Must(taskModel.Query(db.EQ("name", "launch")).Only(acme))
+	_, err = taskModel.Mutation().Upsert(filtered, entity.New(launch.ID()).Set("name", "launched"), []string{"id"}, nil)
+	assert.ErrorIs(t, err, db.ErrRowFilterMismatch)
+	_, err = taskModel.Mutation().Upsert(filtered, entity.New(reviewID).Set("name", "launch"), []string{"id"}, nil)
+	assert.ErrorIs(t, err, db.ErrRowFilterMismatch)
+	_, err = taskModel.Mutation().Upsert(filtered, entity.New(uuid.New()).Set("name", "launch"), []string{"id"}, nil)
+	assert.ErrorIs(t, err, db.ErrRowFilterMismatch)
+	tasks = utils.Must(taskModel.Query().Order("id").Get(acme))
+	assert.Equal(t, []string{"launch", "review"}, utils.Map(tasks, func(e *entity.Entity) string {
+		return e.GetString("name")
+	}))
+
+	_ = utils.Must(taskModel.Mutation().Upsert(filtered, entity.New(reviewID).Set("name", "reviewed"), []string{"id"}, nil))
+	assert.Equal(t, "reviewed", utils.Must(taskModel.Query(db.EQ("id", reviewID)).Only(acme)).GetString("name"))
+}
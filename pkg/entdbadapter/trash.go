@@ -0,0 +1,155 @@
+package entdbadapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+)
+
+// Restore restores the soft deleted entities and their m2m junction rows from the trash.
+//
+//	The deleted_at of the records is cleared by a single update statement,
+//	the setters, the validators and the optimistic lock of the update are not applied.
+//	The update hooks are run with the trashed records as the original entities.
+func (m *Mutation) Restore(ctx context.Context) (affected int, err error) {
+	if m.model == nil || m.model.schema == nil {
+		return 0, fmt.Errorf("model or schema %s not found", m.model.name)
+	}
+
+	if _, ok := m.client.(EntAdapter); !ok {
+		return 0, errors.New("client is not an ent adapter")
+	}
+
+	// The records and their junction rows are restored in a single transaction
+	if !m.client.IsTx() {
+		if err := m.withTx(ctx, func(ctx context.Context, mutation db.Mutator) (err error) {
+			affected, err = mutation.Restore(ctx)
+			return err
+		}); err != nil {
+			return 0, err
+		}
+
+		return affected, nil
+	}
+
+	restoreData := entity.New().Set(entity.FieldDeletedAt, nil)
+	if err := runPreDBUpdateHooks(ctx, m.client, m.model.schema, m.predicates, restoreData); err != nil {
+		return 0, err
+	}
+
+	if err := m.scope(ctx); err != nil {
+		return 0, err
+	}
+
+	// The original entities are the trashed records, they are read as stored for the post update hooks.
+	originalEntities, err := m.model.
+		Query(*m.predicates...).
+		OnlyTrashed().
+		Get(db.WithRawRead(db.WithPrimary(ctx)))
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]any, 0, len(originalEntities))
+	for _, originalEntity := range originalEntities {
+		ids = append(ids, originalEntity.ID())
+	}
+
+	if affected, err = m.setDeletedAt(ctx, ids, nil); err != nil {
+		return 0, fmt.Errorf("restore error: %w", err)
+	}
+
+	return affected, runPostDBUpdateHooks(
+		ctx,
+		m.client,
+		m.model.schema,
+		m.predicates,
+		restoreData,
+		originalEntities,
+		affected,
+	)
+}
+
+// setDeletedAt sets the deleted_at of the records and of their m2m junction rows,
+// the records are trashed if deletedAt is set, otherwise they are restored.
+// The columns are updated directly, so the update hooks, setters and validators are not run.
+func (m *Mutation) setDeletedAt(ctx context.Context, ids []any, deletedAt any) (affected int, err error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	entAdapter, ok := m.client.(EntAdapter)
+	if !ok {
+		return 0, errors.New("client is not an ent adapter")
+	}
+
+	restore := deletedAt == nil
+	updateSpec := &sqlgraph.UpdateSpec{
+		Node: &sqlgraph.NodeSpec{
+			Table: m.model.schema.Namespace,
+			ID: &sqlgraph.FieldSpec{
+				Column: m.model.entPrimaryColumn.Name,
+				Type:   m.model.entPrimaryColumn.Type,
+			},
+		},
+		Predicate: func(s *sql.Selector) {
+			s.Where(sql.And(
+				sql.In(s.C(m.model.entPrimaryColumn.Name), ids...),
+				deletedAtPredicate(s.C(entity.FieldDeletedAt), restore),
+			))
+		},
+	}
+
+	if restore {
+		updateSpec.Fields.Clear = []*sqlgraph.FieldSpec{{Column: entity.FieldDeletedAt}}
+	} else {
+		updateSpec.Fields.Set = []*sqlgraph.FieldSpec{{Column: entity.FieldDeletedAt, Value: deletedAt}}
+	}
+
+	if affected, err = sqlgraph.UpdateNodes(withSchema(ctx, m.model.name), entAdapter.Driver(), updateSpec); err != nil {
+		return 0, err
+	}
+
+	builder := sql.Dialect(entAdapter.Driver().Dialect())
+	for _, field := range m.model.schema.Fields {
+		if !field.Type.IsRelationType() || field.Relation == nil || !field.Relation.Type.IsM2M() {
+			continue
+		}
+
+		relation := field.Relation
+		if relation.JunctionSchema == nil || relation.JunctionSchema.DisableTimestamp {
+			continue
+		}
+
+		update := builder.Update(relation.JunctionTable)
+		if restore {
+			update.SetNull(entity.FieldDeletedAt)
+		} else {
+			update.Set(entity.FieldDeletedAt, deletedAt)
+		}
+
+		query, args := update.Where(sql.And(
+			sql.In(relation.TargetColumn, ids...),
+			deletedAtPredicate(entity.FieldDeletedAt, restore),
+		)).Query()
+		if _, err := driverExec(entAdapter.Driver(), withSchema(ctx, relation.JunctionSchema.Name), query, args); err != nil {
+			return 0, fmt.Errorf("update junction %s: %w", relation.JunctionTable, err)
+		}
+	}
+
+	return affected, nil
+}
+
+// deletedAtPredicate matches the trashed rows if trashed is true, otherwise the rows that are not trashed.
+func deletedAtPredicate(column string, trashed bool) *sql.Predicate {
+	if trashed {
+		return sql.NotNull(column)
+	}
+
+	return sql.IsNull(column)
+}
@@ -5,7 +5,10 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"reflect"
+	"sync"
+	"sync/atomic"
 
 	"entgo.io/ent/dialect"
 	entSchema "entgo.io/ent/dialect/sql/schema"
@@ -19,11 +22,23 @@ var _ db.Client = (*Tx)(nil)
 var _ EntAdapter = (*Tx)(nil)
 
 // Tx hold the transaction and the schema manager.
+//
+//	A nested transaction created by Tx.Tx shares the driver of its parent transaction
+//	and is backed by a savepoint: committing it releases the savepoint,
+//	rolling it back discards the changes made since the savepoint without aborting the parent.
+//	The commit callbacks of a nested transaction are passed to its parent when it is committed.
 type Tx struct {
-	ctx    context.Context
-	driver dialect.Driver
-	client db.Client
-	config *db.Config
+	ctx        context.Context
+	driver     dialect.Driver
+	client     db.Client
+	config     *db.Config
+	savepoint  string
+	savepoints *atomic.Uint64
+	parent     *Tx
+	mu         sync.Mutex
+	callbacks  []func()
+	finished   bool
+	committed  bool
 }
 
 // NewTx creates a new transaction.
@@ -36,10 +51,11 @@ func NewTx(ctx context.Context, client db.Client) (*Tx, error) {
 	}
 
 	txd := &Tx{
-		ctx:    ctx,
-		driver: &TxDriver{driver: driver, dialectTx: tx},
-		client: client,
-		config: client.Config(),
+		ctx:        ctx,
+		driver:     &TxDriver{driver: driver, dialectTx: tx},
+		client:     client,
+		config:     client.Config(),
+		savepoints: &atomic.Uint64{},
 	}
 
 	return txd, nil
@@ -127,6 +143,11 @@ func (tx *Tx) Driver() dialect.Driver {
 	return tx.driver
 }
 
+// ReadDriver returns the transaction driver, the reads inside a transaction always use the primary.
+func (tx *Tx) ReadDriver(context.Context) dialect.Driver {
+	return tx.driver
+}
+
 // Exec executes a query.
 func (tx *Tx) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
 	option := &db.QueryOption{Query: query, Args: args}
@@ -163,15 +184,76 @@ func (tx *Tx) Close() error {
 }
 
 // Rollback rollbacks the transaction.
+// A nested transaction is rolled back to its savepoint, the parent transaction is not affected.
+// The commit callbacks of the transaction are discarded.
 func (tx *Tx) Rollback() error {
+	tx.finish(false)
+	if tx.savepoint != "" {
+		if err := tx.execSavepoint("ROLLBACK TO SAVEPOINT"); err != nil {
+			return err
+		}
+
+		return tx.execSavepoint("RELEASE SAVEPOINT")
+	}
+
 	txDriver := tx.driver.(*TxDriver)
 	return txDriver.dialectTx.Rollback()
 }
 
 // Commit commits the transaction.
+// A nested transaction releases its savepoint, the changes are committed with the parent transaction.
 func (tx *Tx) Commit() error {
+	if tx.savepoint != "" {
+		if err := tx.execSavepoint("RELEASE SAVEPOINT"); err != nil {
+			return err
+		}
+
+		for _, fn := range tx.finish(true) {
+			tx.parent.OnCommit(fn)
+		}
+
+		return nil
+	}
+
 	txDriver := tx.driver.(*TxDriver)
-	return txDriver.dialectTx.Commit()
+	if err := txDriver.dialectTx.Commit(); err != nil {
+		return err
+	}
+
+	for _, fn := range tx.finish(true) {
+		fn()
+	}
+
+	return nil
+}
+
+// OnCommit registers fn to run after the transaction is committed.
+// fn runs immediately if the transaction is already committed and is discarded if it is rolled back.
+func (tx *Tx) OnCommit(fn func()) {
+	tx.mu.Lock()
+	if !tx.finished {
+		tx.callbacks = append(tx.callbacks, fn)
+		tx.mu.Unlock()
+		return
+	}
+
+	committed := tx.committed
+	tx.mu.Unlock()
+	if committed {
+		fn()
+	}
+}
+
+// finish marks the transaction as committed or rolled back and returns its pending commit callbacks.
+func (tx *Tx) finish(committed bool) []func() {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	callbacks := tx.callbacks
+	tx.callbacks = nil
+	tx.finished = true
+	tx.committed = committed
+	return callbacks
 }
 
 // IsTx returns true if the client is a transaction.
@@ -179,9 +261,31 @@ func (tx *Tx) IsTx() bool {
 	return true
 }
 
-// Tx returns the transaction.
+// Tx creates a nested transaction using a savepoint.
 func (tx *Tx) Tx(ctx context.Context) (t db.Client, err error) {
-	return tx, nil
+	nestedTx := &Tx{
+		ctx:        ctx,
+		driver:     tx.driver,
+		client:     tx.client,
+		config:     tx.config,
+		savepoint:  fmt.Sprintf("fs_savepoint_%d", tx.savepoints.Add(1)),
+		savepoints: tx.savepoints,
+		parent:     tx,
+	}
+
+	if err := nestedTx.execSavepoint("SAVEPOINT"); err != nil {
+		return nil, err
+	}
+
+	return nestedTx, nil
+}
+
+// execSavepoint executes the savepoint statement of the nested transaction.
+// The statement is executed even if the context of the transaction is canceled,
+// so that the savepoint can be released or rolled back.
+func (tx *Tx) execSavepoint(statement string) error {
+	_, err := driverExec(tx.driver, context.WithoutCancel(tx.ctx), statement+" "+tx.savepoint, []any{})
+	return err
 }
 
 // GenerateMigrationFiles generates a diff for the transaction.
@@ -203,6 +307,10 @@ func (tx *TxDriver) Tx(context.Context) (dialect.Tx, error) { return tx, nil }
 
 // ID returns the transaction id.
 func (tx *TxDriver) ID() string {
+	if timedTx, ok := tx.dialectTx.(*timedTx); ok {
+		return timedTx.id
+	}
+
 	debugTx, ok := tx.dialectTx.(*dialect.DebugTx)
 	if !ok {
 		return ""
@@ -59,7 +59,10 @@ func TestNewTx(t *testing.T) {
 	assert.NotNil(t, tx.Dialect())
 	assert.Nil(t, tx.Migrate(context.Background(), nil, false))
 	assert.Equal(t, true, tx.IsTx())
-	assert.Equal(t, tx, utils.Must(tx.Tx(context.Background())))
+	nestedTx := utils.Must(tx.Tx(context.Background()))
+	assert.NotEqual(t, tx, nestedTx)
+	assert.True(t, nestedTx.IsTx())
+	assert.NoError(t, nestedTx.Commit())
 	tx.SetSQLDB(nil)
 	tx.SetDriver(nil)
 	_, err = tx.Reload(
@@ -197,3 +200,97 @@ func TestTx_GenerateMigrationFiles(t *testing.T) {
 	err := tx.GenerateMigrationFiles(context.Background(), "test_migration")
 	assert.NoError(t, err)
 }
+
+func TestTxNested(t *testing.T) {
+	for _, dialectName := range []string{dialect.MySQL, dialect.Postgres, dialect.SQLite} {
+		t.Run(dialectName, func(t *testing.T) {
+			sb := createTestSchemaBuilder(t)
+			mdb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			assert.NoError(t, err)
+			client := utils.Must(NewEntClient(&db.Config{
+				Driver: "sqlmock",
+			}, sb, dialectSql.OpenDB(dialectName, mdb)))
+
+			mock.ExpectBegin()
+			mock.ExpectExec("SAVEPOINT fs_savepoint_1").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("SAVEPOINT fs_savepoint_2").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("ROLLBACK TO SAVEPOINT fs_savepoint_2").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("RELEASE SAVEPOINT fs_savepoint_2").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("RELEASE SAVEPOINT fs_savepoint_1").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("SAVEPOINT fs_savepoint_3").WillReturnError(assert.AnError)
+			mock.ExpectCommit()
+
+			tx := createTx(t, client, sb)
+			nestedTx := utils.Must(tx.Tx(context.Background()))
+			nestedNestedTx := utils.Must(nestedTx.Tx(context.Background()))
+			assert.NoError(t, nestedNestedTx.Rollback())
+			assert.NoError(t, nestedTx.Commit())
+
+			_, err = tx.Tx(context.Background())
+			assert.ErrorIs(t, err, assert.AnError)
+			assert.NoError(t, tx.Commit())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTxNestedRollbackError(t *testing.T) {
+	sb := createTestSchemaBuilder(t)
+	mdb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	client := utils.Must(NewEntClient(&db.Config{
+		Driver: "sqlmock",
+	}, sb, dialectSql.OpenDB(dialect.MySQL, mdb)))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT fs_savepoint_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT fs_savepoint_1").WillReturnError(assert.AnError)
+
+	tx := createTx(t, client, sb)
+	nestedTx := utils.Must(tx.Tx(context.Background()))
+	assert.ErrorIs(t, nestedTx.Rollback(), assert.AnError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxOnCommit(t *testing.T) {
+	sb := createTestSchemaBuilder(t)
+	mdb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	client := utils.Must(NewEntClient(&db.Config{
+		Driver: "sqlmock",
+	}, sb, dialectSql.OpenDB(dialect.MySQL, mdb)))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT fs_savepoint_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT fs_savepoint_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT fs_savepoint_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT fs_savepoint_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT fs_savepoint_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	calls := []string{}
+	tx := createTx(t, client, sb)
+	ctx := db.ContextWithTx(context.Background(), tx)
+	assert.Same(t, tx, db.TxFromContext(ctx, client))
+	db.AfterCommit(ctx, func() { calls = append(calls, "tx") })
+
+	// The callbacks of a committed nested transaction run after the parent is committed
+	nestedTx := utils.Must(tx.Tx(context.Background())).(*Tx)
+	nestedTx.OnCommit(func() { calls = append(calls, "nested") })
+	assert.NoError(t, nestedTx.Commit())
+
+	// The callbacks of a rolled back nested transaction are discarded
+	rolledBackTx := utils.Must(tx.Tx(context.Background())).(*Tx)
+	rolledBackTx.OnCommit(func() { calls = append(calls, "rolled back") })
+	assert.NoError(t, rolledBackTx.Rollback())
+	assert.Empty(t, calls)
+
+	assert.NoError(t, tx.Commit())
+	assert.Equal(t, []string{"tx", "nested"}, calls)
+
+	// The callbacks of a committed transaction run immediately
+	tx.OnCommit(func() { calls = append(calls, "committed") })
+	rolledBackTx.OnCommit(func() { calls = append(calls, "rolled back") })
+	assert.Equal(t, []string{"tx", "nested", "committed"}, calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -16,6 +16,8 @@ type EntAdapter interface {
 	db.Client
 
 	Driver() dialect.Driver
+	// ReadDriver returns the driver that runs the read queries of the context.
+	ReadDriver(ctx context.Context) dialect.Driver
 	SetSQLDB(db *sql.DB)
 	SetDriver(driver dialect.Driver)
 	NewEdgeStepOption(r *schema.Relation) (sqlgraph.StepOption, error)
@@ -8,6 +8,7 @@ import (
 
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/fastschema/fastschema/db"
 	"github.com/fastschema/fastschema/entity"
 	"github.com/fastschema/fastschema/expr"
 	"github.com/fastschema/fastschema/pkg/utils"
@@ -24,6 +25,23 @@ func (m *Mutation) Update(ctx context.Context, e *entity.Entity) (affected int,
 		return 0, errors.New("client is not an ent adapter")
 	}
 
+	// The entities and their nested records are updated in a single transaction
+	if !m.client.IsTx() && hasNestedWrites(m.model.schema, e) {
+		if err := m.withTx(ctx, func(ctx context.Context, mutation db.Mutator) (err error) {
+			affected, err = mutation.Update(ctx, e)
+			return err
+		}); err != nil {
+			return 0, err
+		}
+
+		return affected, nil
+	}
+
+	// The values are validated as given, before the setters transform them
+	if err := m.model.schema.ValidateEntity(e); err != nil {
+		return 0, err
+	}
+
 	if err := m.model.schema.ApplySetters(ctx, e, expr.Config{
 		DB: func() expr.DBLike {
 			return m.client
@@ -35,21 +53,56 @@ func (m *Mutation) Update(ctx context.Context, e *entity.Entity) (affected int,
 	var originalEntities []*entity.Entity
 	if m.client != nil {
 		hooks := m.client.Hooks()
-		if len(hooks.PostDBUpdate) > 0 {
+		// The original entities are used by the post update hooks, the schema validators
+		// and the validation of the added values, they are read as stored
+		// so that the hidden fields and the getters do not change them.
+		if len(hooks.PostDBUpdate) > 0 ||
+			len(m.model.schema.Validators) > 0 ||
+			m.model.schema.HasValidatedAddFields(e) {
 			originalEntities, err = m.model.
 				Query(*m.predicates...).
-				Select(m.model.Schema().PrimaryKeyName()).
-				Get(ctx)
+				Get(db.WithRawRead(db.WithPrimary(ctx)))
 			if err != nil {
 				return 0, err
 			}
 		}
 	}
 
+	nestedWrites, err := m.parseNestedWrites(e)
+	if err != nil {
+		return 0, err
+	}
+
+	stripNestedWrites(e, nestedWrites)
+
 	if err := runPreDBUpdateHooks(ctx, m.client, m.model.schema, m.predicates, e); err != nil {
 		return 0, err
 	}
 
+	for _, originalEntity := range originalEntities {
+		if err := m.model.schema.ValidateAddedValues(e, originalEntity); err != nil {
+			return 0, err
+		}
+
+		if err := m.model.schema.ApplyValidators(ctx, e, originalEntity, expr.Config{
+			DB: func() expr.DBLike {
+				return m.client
+			},
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	afterWrites, err := m.runNestedWritesBeforeUpdate(ctx, e, nestedWrites)
+	if err != nil {
+		return 0, err
+	}
+
+	parent, err := m.updatedParent(ctx, afterWrites)
+	if err != nil {
+		return 0, err
+	}
+
 	m.updateSpec = &sqlgraph.UpdateSpec{
 		Node: &sqlgraph.NodeSpec{
 			Table: m.model.schema.Namespace,
@@ -70,6 +123,10 @@ func (m *Mutation) Update(ctx context.Context, e *entity.Entity) (affected int,
 		},
 	}
 
+	if err := m.scope(ctx); err != nil {
+		return 0, err
+	}
+
 	if len(*m.predicates) > 0 {
 		sqlPredicatesFn, err := createEntPredicates(entAdapter, m.model, *m.predicates)
 		if err != nil {
@@ -80,7 +137,21 @@ func (m *Mutation) Update(ctx context.Context, e *entity.Entity) (affected int,
 		}
 	}
 
+	expectedVersion, err := m.processOptimisticLock(e)
+	if err != nil {
+		return 0, err
+	}
+
 	for pair := e.First(); pair != nil; pair = pair.Next() {
+		if m.model.schema.OptimisticLock && pair.Key == entity.FieldVersion {
+			continue
+		}
+
+		// The tenant of the records is never changed
+		if m.model.schema.Tenant && pair.Key == entity.FieldTenantID {
+			continue
+		}
+
 		switch pair.Key {
 		case "$add":
 			if err := m.ProcessUpdateBlockAdd(entAdapter, pair.Value); err != nil {
@@ -118,16 +189,25 @@ func (m *Mutation) Update(ctx context.Context, e *entity.Entity) (affected int,
 		})
 	}
 
-	if affected, err = sqlgraph.UpdateNodes(ctx, entAdapter.Driver(), m.updateSpec); err != nil {
+	if affected, err = sqlgraph.UpdateNodes(withSchema(ctx, m.model.name), entAdapter.Driver(), m.updateSpec); err != nil {
 		return 0, err
 	}
 
+	// The version is checked before the commit, so that a conflict rolls back the transaction
+	if expectedVersion != nil && affected == 0 {
+		return 0, m.versionConflictError(ctx)
+	}
+
 	if m.autoCommit {
 		if err = m.client.Commit(); err != nil {
 			return 0, err
 		}
 	}
 
+	if err := m.runNestedWritesAfterUpdate(ctx, e, parent, afterWrites); err != nil {
+		return 0, err
+	}
+
 	return affected, runPostDBUpdateHooks(
 		ctx,
 		m.client,
@@ -139,6 +219,57 @@ func (m *Mutation) Update(ctx context.Context, e *entity.Entity) (affected int,
 	)
 }
 
+// processOptimisticLock increments the version of the updated records if the schema has optimistic lock.
+// If the entity contains the expected version, only the records of that version are updated.
+// Returns the expected version, or nil if there is no version to check.
+func (m *Mutation) processOptimisticLock(e *entity.Entity) (*uint64, error) {
+	if !m.model.schema.OptimisticLock {
+		return nil, nil
+	}
+
+	m.updateSpec.Modifiers = append(m.updateSpec.Modifiers, func(u *sql.UpdateBuilder) {
+		u.Add(entity.FieldVersion, 1)
+	})
+
+	if e.Get(entity.FieldVersion) == nil {
+		return nil, nil
+	}
+
+	version, err := e.GetUint64(entity.FieldVersion, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", entity.FieldVersion, err)
+	}
+
+	predicate := m.updateSpec.Predicate
+	m.updateSpec.Predicate = func(s *sql.Selector) {
+		if predicate != nil {
+			predicate(s)
+		}
+		s.Where(sql.EQ(s.C(entity.FieldVersion), version))
+	}
+
+	return &version, nil
+}
+
+// versionConflictError returns the error of an update with an expected version that affected no record:
+// a NotFoundError if no record matches the predicates, otherwise ErrVersionConflict.
+func (m *Mutation) versionConflictError(ctx context.Context) error {
+	records, err := m.model.
+		Query(*m.predicates...).
+		Select(m.model.schema.PrimaryKeyName()).
+		Limit(1).
+		Get(db.WithRawRead(db.WithPrimary(ctx)))
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return &db.NotFoundError{Message: fmt.Sprintf("no %s found to update", m.model.name)}
+	}
+
+	return db.ErrVersionConflict
+}
+
 // ProcessUpdateBlockExpr processes the $expr block
 func (m *Mutation) ProcessUpdateBlockExpr(entAdapter EntAdapter, fieldValue any) {
 	if expr, ok := fieldValue.(*entity.Entity); ok {
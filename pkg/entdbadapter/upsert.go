@@ -0,0 +1,427 @@
+package entdbadapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"entgo.io/ent/dialect/sql"
+	entSchema "entgo.io/ent/dialect/sql/schema"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/expr"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+)
+
+// Upsert creates the entity or updates the existing entity that has the same conflict column values.
+//
+//	The insert and the update are executed in a single statement using
+//	ON CONFLICT (Postgres, SQLite) or ON DUPLICATE KEY UPDATE (MySQL),
+//	so that concurrent upserts of the same record do not fail on the unique constraint.
+//
+//	The conflict columns must be the primary key or a unique key of the schema.
+//	The update columns are the columns that are updated when the entity exists,
+//	if empty, all the given columns except the conflict columns and the primary key are updated.
+//
+//	The create hooks are run if the entity does not exist, otherwise the update hooks are run.
+//	ErrUpsertTrashed is returned if the existing entity is in the trash.
+//	ErrRowFilterMismatch is returned if the existing entity or the upserted entity
+//	does not match the row filter of the context.
+//
+//	If the schema has optimistic lock, the version of the entity is the expected version of the existing record,
+//	ErrVersionConflict is returned if the record does not exist or has another version.
+//	The version is set to 1 on insert and incremented on update, the entity holds the new version.
+func (m *Mutation) Upsert(
+	ctx context.Context,
+	e *entity.Entity,
+	conflictColumns []string,
+	updateColumns []string,
+) (_ any, err error) {
+	if m.model == nil || m.model.schema == nil {
+		return nil, fmt.Errorf("model or schema %s not found", m.model.name)
+	}
+
+	entAdapter, ok := m.client.(EntAdapter)
+	if !ok {
+		return nil, errors.New("client is not an ent adapter")
+	}
+
+	conflictColumns = tenantConflictColumns(m.model.schema, conflictColumns)
+	if err := m.validateUpsertConflictColumns(conflictColumns); err != nil {
+		return nil, err
+	}
+
+	// The existing record is read, upserted and read again in a single transaction,
+	// so that the insert or the update detected by the read is the one executed.
+	if m.client != nil && !m.client.IsTx() {
+		var id any
+		if err := m.withTx(ctx, func(ctx context.Context, mutation db.Mutator) (err error) {
+			id, err = mutation.Upsert(ctx, e, conflictColumns, updateColumns)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+
+		return id, nil
+	}
+
+	// The values are validated as given, before the setters transform them
+	if err := m.model.schema.ValidateEntity(e); err != nil {
+		return nil, err
+	}
+
+	if err := m.model.schema.ApplySetters(ctx, e, expr.Config{
+		DB: func() expr.DBLike {
+			return m.client
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := setTenant(ctx, m.model.schema, e); err != nil {
+		return nil, err
+	}
+
+	expectedVersion, err := upsertExpectedVersion(m.model.schema, e)
+	if err != nil {
+		return nil, err
+	}
+
+	predicates := []*db.Predicate{}
+	for _, column := range conflictColumns {
+		value := e.Get(column)
+		if value == nil {
+			return nil, fmt.Errorf("upsert conflict column %s.%s value is required", m.model.name, column)
+		}
+
+		predicates = append(predicates, db.EQ(column, value))
+	}
+
+	// The existing entity is read as stored from the primary since the replicas may lag behind,
+	// it is passed to the post update hooks as the original entity.
+	// The conflict is resolved on all the records, so the trashed records and
+	// the records that do not match the row filter are read too and checked explicitly.
+	pkName := m.model.schema.PrimaryKeyName()
+	unfilteredCtx := db.WithRowFilter(db.WithPrimary(ctx), nil)
+	existingEntities, err := m.model.
+		Query(predicates...).
+		WithTrashed().
+		Get(db.WithRawRead(unfilteredCtx))
+	if err != nil {
+		return nil, err
+	}
+
+	exists := len(existingEntities) > 0
+	if err := m.checkUpsertTenant(ctx, exists, predicates); err != nil {
+		return nil, err
+	}
+
+	if exists {
+		if existingEntities[0].Get(entity.FieldDeletedAt) != nil {
+			return nil, db.ErrUpsertTrashed
+		}
+
+		if err := m.checkRowFilter(ctx, m.client, []any{existingEntities[0].ID()}); err != nil {
+			return nil, err
+		}
+	}
+
+	var existingVersion uint64
+	if exists && m.model.schema.OptimisticLock {
+		if existingVersion, err = existingEntities[0].GetUint64(entity.FieldVersion, true); err != nil {
+			return nil, err
+		}
+	}
+
+	if expectedVersion != nil && (!exists || existingVersion != *expectedVersion) {
+		return nil, db.ErrVersionConflict
+	}
+
+	if exists {
+		if err := runPreDBUpdateHooks(ctx, m.client, m.model.schema, &predicates, e); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := runPreDBCreateHooks(ctx, m.client, m.model.schema, e); err != nil {
+			return nil, err
+		}
+	}
+
+	var existingEntity *entity.Entity
+	if exists {
+		existingEntity = existingEntities[0]
+	}
+
+	if err := m.model.schema.ApplyValidators(ctx, e, existingEntity, expr.Config{
+		DB: func() expr.DBLike {
+			return m.client
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	// The tenant is set again since the hooks must not change it
+	if err := setTenant(ctx, m.model.schema, e); err != nil {
+		return nil, err
+	}
+
+	// The primary key is generated for the existing entity too since the insert
+	// statement must be valid before the conflict is resolved, it is never updated.
+	if err := m.autoGenerateUUID(e); err != nil {
+		return nil, err
+	}
+
+	insert, err := m.createUpsertBuilder(entAdapter, e, conflictColumns, updateColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args := insert.Query()
+	if _, err := driverExec(entAdapter.Driver(), withSchema(ctx, m.model.name), query, args); err != nil {
+		return nil, err
+	}
+
+	// The created ID is queried since MySQL does not return the ID of the updated row
+	upsertedEntities := existingEntities
+	if !exists {
+		if upsertedEntities, err = m.model.Query(predicates...).Select(pkName).Get(unfilteredCtx); err != nil {
+			return nil, err
+		}
+
+		if len(upsertedEntities) == 0 {
+			return nil, fmt.Errorf("upsert mutation for %s returned no ID", m.model.name)
+		}
+	}
+
+	upsertedID, err := normalizeIDValue(m.model.schema.PrimaryField(), upsertedEntities[0].ID())
+	if err != nil {
+		return nil, err
+	}
+
+	// The transaction is rolled back if the written entity does not match the row filter
+	if err := m.checkRowFilter(ctx, m.client, []any{upsertedID}); err != nil {
+		return nil, err
+	}
+
+	e.SetIDField(pkName)
+	if err := e.SetID(upsertedID); err != nil {
+		return nil, err
+	}
+
+	if m.model.schema.OptimisticLock {
+		e.Set(entity.FieldVersion, utils.If(exists, existingVersion+1, uint64(1)))
+	}
+
+	if m.autoCommit {
+		if err = m.client.Commit(); err != nil {
+			return nil, err
+		}
+	}
+
+	if exists {
+		return upsertedID, runPostDBUpdateHooks(
+			ctx,
+			m.client,
+			m.model.schema,
+			&predicates,
+			e,
+			existingEntities,
+			1,
+		)
+	}
+
+	if err := runPostDBCreateHooks(ctx, m.client, m.model.schema, e, upsertedID); err != nil {
+		return nil, err
+	}
+
+	return upsertedID, nil
+}
+
+// upsertExpectedVersion returns the expected version of the entity of a schema with optimistic lock,
+// or nil if the entity has no version. The version of the entity is set to the version of a new record,
+// so the version given by the client is never written.
+func upsertExpectedVersion(s *schema.Schema, e *entity.Entity) (*uint64, error) {
+	if !s.OptimisticLock {
+		return nil, nil
+	}
+
+	var expectedVersion *uint64
+	if e.Get(entity.FieldVersion) != nil {
+		version, err := e.GetUint64(entity.FieldVersion, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", entity.FieldVersion, err)
+		}
+		expectedVersion = &version
+	}
+
+	e.Set(entity.FieldVersion, uint64(1))
+	return expectedVersion, nil
+}
+
+// checkUpsertTenant checks that the conflicting record of a tenant schema belongs to the tenant.
+// The primary key is unique across the tenants, so that the upsert would update the record of another tenant
+// if the primary key is used by another tenant.
+func (m *Mutation) checkUpsertTenant(ctx context.Context, exists bool, predicates []*db.Predicate) error {
+	if exists || !m.model.schema.Tenant || db.UseAllTenants(ctx) {
+		return nil
+	}
+
+	pkName := m.model.schema.PrimaryKeyName()
+	otherEntities, err := m.model.
+		Query(predicates...).
+		Select(pkName).
+		Get(db.WithAllTenants(db.WithPrimary(ctx)))
+	if err != nil {
+		return err
+	}
+
+	if len(otherEntities) > 0 {
+		return fmt.Errorf("upsert %s: the conflict column values are used by another tenant", m.model.name)
+	}
+
+	return nil
+}
+
+// validateUpsertConflictColumns checks that the conflict columns are
+// the primary key or the columns of a unique key of the model.
+func (m *Mutation) validateUpsertConflictColumns(conflictColumns []string) error {
+	if len(conflictColumns) == 0 {
+		return fmt.Errorf("upsert %s requires at least one conflict column", m.model.name)
+	}
+
+	entColumns := []string{}
+	for _, column := range conflictColumns {
+		c, err := m.model.Column(column)
+		if err != nil {
+			return fmt.Errorf("upsert conflict column error: %w", err)
+		}
+
+		if c.field.Type.IsRelationType() {
+			return fmt.Errorf("upsert conflict column %s.%s must not be a relation", m.model.name, column)
+		}
+
+		entColumns = append(entColumns, c.entColumn.Name)
+	}
+
+	slices.Sort(entColumns)
+	sameColumns := func(columns []string) bool {
+		return slices.Equal(entColumns, slices.Sorted(slices.Values(columns)))
+	}
+
+	table := m.model.entTable
+	if sameColumns(utils.Map(table.PrimaryKey, func(c *entSchema.Column) string { return c.Name })) {
+		return nil
+	}
+
+	for _, c := range table.Columns {
+		if c.Unique && sameColumns([]string{c.Name}) {
+			return nil
+		}
+	}
+
+	for _, index := range table.Indexes {
+		if index.Unique && sameColumns(utils.Map(index.Columns, func(c *entSchema.Column) string { return c.Name })) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"upsert conflict columns %s must be the primary key or a unique key of %s",
+		strings.Join(conflictColumns, ","),
+		m.model.name,
+	)
+}
+
+// createUpsertBuilder creates the insert statement with the conflict resolution.
+func (m *Mutation) createUpsertBuilder(
+	entAdapter EntAdapter,
+	e *entity.Entity,
+	conflictColumns []string,
+	updateColumns []string,
+) (*sql.InsertBuilder, error) {
+	pkName := m.model.schema.PrimaryKeyName()
+	insertColumns := []string{}
+	insertValues := []any{}
+	fieldColumns := map[string]string{}
+
+	for pair := e.First(); pair != nil; pair = pair.Next() {
+		c, err := m.model.Column(pair.Key)
+		if err != nil {
+			return nil, fmt.Errorf("column error: %w", err)
+		}
+
+		if c.field.Type.IsRelationType() {
+			return nil, fmt.Errorf("upsert does not support relation field %s.%s", m.model.name, pair.Key)
+		}
+
+		if pair.Key == pkName && isZeroValue(pair.Value) && !slices.Contains(conflictColumns, pkName) {
+			continue
+		}
+
+		insertColumns = append(insertColumns, c.entColumn.Name)
+		insertValues = append(insertValues, pair.Value)
+		fieldColumns[pair.Key] = c.entColumn.Name
+	}
+
+	if len(updateColumns) == 0 {
+		for pair := e.First(); pair != nil; pair = pair.Next() {
+			if pair.Key != pkName && !slices.Contains(conflictColumns, pair.Key) {
+				updateColumns = append(updateColumns, pair.Key)
+			}
+		}
+	}
+
+	setColumns := []string{}
+	for _, column := range updateColumns {
+		// The tenant of the existing record is never changed
+		if m.model.schema.Tenant && column == entity.FieldTenantID {
+			continue
+		}
+
+		// The version of the existing record is incremented instead
+		if m.model.schema.OptimisticLock && column == entity.FieldVersion {
+			continue
+		}
+
+		entColumn, ok := fieldColumns[column]
+		if !ok {
+			return nil, fmt.Errorf("upsert update column %s.%s is not set", m.model.name, column)
+		}
+
+		setColumns = append(setColumns, entColumn)
+	}
+
+	dialect := entAdapter.Driver().Dialect()
+	insert := sql.Dialect(dialect).
+		Insert(m.model.entTable.Name).
+		Columns(insertColumns...).
+		Values(insertValues...)
+
+	return insert.OnConflict(
+		sql.ConflictColumns(utils.Map(conflictColumns, func(column string) string {
+			return fieldColumns[column]
+		})...),
+		sql.ResolveWith(func(u *sql.UpdateSet) {
+			if m.model.schema.OptimisticLock {
+				u.Add(entity.FieldVersion, 1)
+			}
+
+			// Setting a conflict column to itself keeps the existing record unchanged
+			if len(setColumns) == 0 {
+				u.SetIgnore(fieldColumns[conflictColumns[0]])
+				return
+			}
+
+			for _, column := range setColumns {
+				u.SetExcluded(column)
+			}
+
+			if !m.model.schema.DisableTimestamp && !slices.Contains(setColumns, entity.FieldUpdatedAt) {
+				u.Set(entity.FieldUpdatedAt, NOW(m.client.Dialect()))
+			}
+		}),
+	), nil
+}
@@ -0,0 +1,369 @@
+package entdbadapter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	dialectSql "entgo.io/ent/dialect/sql"
+	entSchema "entgo.io/ent/dialect/sql/schema"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createUpsertSchemaBuilder(t *testing.T) *schema.Builder {
+	t.Helper()
+	productSchema := &schema.Schema{
+		Name:             "product",
+		Namespace:        "products",
+		LabelFieldName:   "name",
+		DisableTimestamp: true,
+		Fields: []*schema.Field{
+			{Name: "id", Label: "ID", Type: schema.TypeUint64},
+			{Name: "sku", Label: "SKU", Type: schema.TypeString, Unique: true},
+			{Name: "name", Label: "Name", Type: schema.TypeString},
+			{Name: "stock", Label: "Stock", Type: schema.TypeInt, Optional: true},
+			{Name: "vendor", Label: "Vendor", Type: schema.TypeString, Optional: true},
+			{Name: "code", Label: "Code", Type: schema.TypeString, Optional: true},
+		},
+		DB: &schema.SchemaDB{
+			Indexes: []*schema.SchemaDBIndex{
+				{Name: "vendor_code", Unique: true, Columns: []string{"vendor", "code"}},
+			},
+		},
+	}
+
+	sb, err := schema.NewBuilderFromSchemas("", map[string]*schema.Schema{
+		productSchema.Name: productSchema,
+	})
+	require.NoError(t, err)
+
+	return sb
+}
+
+func TestUpsertError(t *testing.T) {
+	mut := &Mutation{
+		model: &Model{name: "user"},
+	}
+	_, err := mut.Upsert(context.Background(), nil, nil, nil)
+	assert.Equal(t, errors.New("model or schema user not found"), err)
+}
+
+func TestUpsertClientIsNotEntClient(t *testing.T) {
+	mut := &Mutation{
+		model: &Model{
+			name:             "user",
+			schema:           &schema.Schema{},
+			entPrimaryColumn: &entSchema.Column{},
+		},
+		client: nil,
+	}
+	_, err := mut.Upsert(context.Background(), entity.New(), nil, nil)
+	assert.Equal(t, errors.New("client is not an ent adapter"), err)
+}
+
+func TestUpsertNode(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Dialect         string
+		InputJSON       string
+		ConflictColumns []string
+		UpdateColumns   []string
+		Expect          func(sqlmock.Sqlmock)
+		ExpectError     string
+		ExpectID        any
+	}{
+		{
+			Name:        "conflict_columns_required",
+			InputJSON:   `{"sku": "a"}`,
+			ExpectError: "upsert product requires at least one conflict column",
+		},
+		{
+			Name:            "conflict_column_invalid",
+			InputJSON:       `{"sku": "a"}`,
+			ConflictColumns: []string{"invalid"},
+			ExpectError:     "upsert conflict column error: column product.invalid not found",
+		},
+		{
+			Name:            "conflict_columns_not_unique",
+			InputJSON:       `{"sku": "a"}`,
+			ConflictColumns: []string{"name"},
+			ExpectError:     "upsert conflict columns name must be the primary key or a unique key of product",
+		},
+		{
+			Name:            "conflict_columns_partial_index",
+			InputJSON:       `{"vendor": "a"}`,
+			ConflictColumns: []string{"vendor"},
+			ExpectError:     "upsert conflict columns vendor must be the primary key or a unique key of product",
+		},
+		{
+			Name:            "conflict_value_required",
+			InputJSON:       `{"name": "a"}`,
+			ConflictColumns: []string{"sku"},
+			ExpectError:     "upsert conflict column product.sku value is required",
+			Expect: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectRollback()
+			},
+		},
+		{
+			Name:            "update_column_not_set",
+			InputJSON:       `{"sku": "a", "name": "A"}`,
+			ConflictColumns: []string{"sku"},
+			UpdateColumns:   []string{"stock"},
+			ExpectError:     "upsert update column product.stock is not set",
+			Expect: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(utils.EscapeQuery("SELECT * FROM `products` WHERE `products`.`sku` = ?")).
+					WithArgs("a").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				m.ExpectRollback()
+			},
+		},
+		{
+			Name:            "mysql_create",
+			InputJSON:       `{"sku": "a", "name": "A", "stock": 1}`,
+			ConflictColumns: []string{"sku"},
+			ExpectID:        uint64(5),
+			Expect: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(utils.EscapeQuery("SELECT * FROM `products` WHERE `products`.`sku` = ?")).
+					WithArgs("a").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				m.ExpectExec(utils.EscapeQuery("INSERT INTO `products` (`sku`, `name`, `stock`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `stock` = VALUES(`stock`)")).
+					WithArgs("a", "A", float64(1)).
+					WillReturnResult(sqlmock.NewResult(5, 1))
+				m.ExpectQuery(utils.EscapeQuery("SELECT * FROM `products` WHERE `products`.`sku` = ?")).
+					WithArgs("a").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				m.ExpectCommit()
+			},
+		},
+		{
+			Name:            "mysql_update_columns",
+			InputJSON:       `{"vendor": "v", "code": "c", "name": "A", "stock": 1}`,
+			ConflictColumns: []string{"code", "vendor"},
+			UpdateColumns:   []string{"stock"},
+			ExpectID:        uint64(2),
+			Expect: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(utils.EscapeQuery("SELECT * FROM `products` WHERE `products`.`code` = ? AND `products`.`vendor` = ?")).
+					WithArgs("c", "v").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				m.ExpectExec(utils.EscapeQuery("INSERT INTO `products` (`vendor`, `code`, `name`, `stock`) VALUES (?, ?, ?, ?) ON DUPLICATE KEY UPDATE `stock` = VALUES(`stock`)")).
+					WithArgs("v", "c", "A", float64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				m.ExpectCommit()
+			},
+		},
+		{
+			Name:            "mysql_nothing_to_update",
+			InputJSON:       `{"sku": "a"}`,
+			ConflictColumns: []string{"sku"},
+			ExpectID:        uint64(2),
+			Expect: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(utils.EscapeQuery("SELECT * FROM `products` WHERE `products`.`sku` = ?")).
+					WithArgs("a").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				m.ExpectExec(utils.EscapeQuery("INSERT INTO `products` (`sku`) VALUES (?) ON DUPLICATE KEY UPDATE `sku` = `products`.`sku`")).
+					WithArgs("a").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				m.ExpectCommit()
+			},
+		},
+		{
+			Name:            "postgres_create",
+			Dialect:         dialect.Postgres,
+			InputJSON:       `{"sku": "a", "name": "A"}`,
+			ConflictColumns: []string{"sku"},
+			ExpectID:        uint64(1),
+			Expect: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(utils.EscapeQuery(`SELECT * FROM "products" WHERE "products"."sku" = $1`)).
+					WithArgs("a").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				m.ExpectExec(utils.EscapeQuery(`INSERT INTO "products" ("sku", "name") VALUES ($1, $2) ON CONFLICT ("sku") DO UPDATE SET "name" = "excluded"."name"`)).
+					WithArgs("a", "A").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				m.ExpectQuery(utils.EscapeQuery(`SELECT * FROM "products" WHERE "products"."sku" = $1`)).
+					WithArgs("a").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				m.ExpectCommit()
+			},
+		},
+		{
+			Name:            "exec_error",
+			InputJSON:       `{"sku": "a", "name": "A"}`,
+			ConflictColumns: []string{"sku"},
+			ExpectError:     "Error 1062: Duplicate entry",
+			Expect: func(m sqlmock.Sqlmock) {
+				m.ExpectBegin()
+				m.ExpectQuery(utils.EscapeQuery("SELECT * FROM `products` WHERE `products`.`sku` = ?")).
+					WithArgs("a").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				m.ExpectExec(utils.EscapeQuery("INSERT INTO `products` (`sku`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)")).
+					WithArgs("a", "A").
+					WillReturnError(errors.New("Error 1062: Duplicate entry"))
+				m.ExpectRollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			dialectName := utils.If(tt.Dialect == "", dialect.MySQL, tt.Dialect)
+			client := utils.Must(NewEntClient(&db.Config{
+				Driver: "sqlmock",
+			}, createUpsertSchemaBuilder(t), dialectSql.OpenDB(dialectName, mockDB)))
+
+			if tt.Expect != nil {
+				tt.Expect(mock)
+			}
+
+			model := utils.Must(client.Model("product"))
+			e := utils.Must(entity.NewEntityFromJSON(tt.InputJSON))
+			id, err := model.Mutation().Upsert(context.Background(), e, tt.ConflictColumns, tt.UpdateColumns)
+			if tt.ExpectError != "" {
+				assert.ErrorContains(t, err, tt.ExpectError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.ExpectID, id)
+			assert.Equal(t, tt.ExpectID, e.ID())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpsertSQLite(t *testing.T) {
+	ctx := context.Background()
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	calls := []string{}
+	client, err := NewTestClient(migrationDir, createUpsertSchemaBuilder(t), func() *db.Hooks {
+		return &db.Hooks{
+			PreDBCreate: []db.PreDBCreate{func(ctx context.Context, schema *schema.Schema, e *entity.Entity) error {
+				calls = append(calls, "pre_create")
+				return nil
+			}},
+			PostDBCreate: []db.PostDBCreate{func(
+				ctx context.Context,
+				schema *schema.Schema,
+				e *entity.Entity,
+				id any,
+			) error {
+				calls = append(calls, "post_create")
+				return nil
+			}},
+			PreDBUpdate: []db.PreDBUpdate{func(
+				ctx context.Context,
+				schema *schema.Schema,
+				predicates *[]*db.Predicate,
+				e *entity.Entity,
+			) error {
+				calls = append(calls, "pre_update")
+				return nil
+			}},
+			PostDBUpdate: []db.PostDBUpdate{func(
+				ctx context.Context,
+				schema *schema.Schema,
+				predicates *[]*db.Predicate,
+				e *entity.Entity,
+				originalEntities []*entity.Entity,
+				affected int,
+			) error {
+				calls = append(calls, "post_update")
+				assert.Equal(t, uint64(1), originalEntities[0].ID())
+				return nil
+			}},
+		}
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	model := utils.Must(client.Model("product"))
+	upsert := func(json string, updateColumns ...string) any {
+		e := utils.Must(entity.NewEntityFromJSON(json))
+		return utils.Must(model.Mutation().Upsert(ctx, e, []string{"sku"}, updateColumns))
+	}
+
+	// Create
+	assert.Equal(t, uint64(1), upsert(`{"sku": "a", "name": "A", "stock": 1}`))
+	assert.Equal(t, []string{"pre_create", "post_create"}, calls)
+
+	// Update all the given columns
+	calls = []string{}
+	assert.Equal(t, uint64(1), upsert(`{"sku": "a", "name": "A2", "stock": 2}`))
+	assert.Equal(t, []string{"pre_update", "post_update"}, calls)
+
+	// Update the given update columns only
+	assert.Equal(t, uint64(1), upsert(`{"sku": "a", "name": "A3", "stock": 3}`, "stock"))
+	// The auto increment value may be consumed by the conflicting inserts
+	createdID := upsert(`{"sku": "b", "name": "B"}`)
+
+	products := utils.Must(model.Query().Order("id").Get(ctx))
+	require.Len(t, products, 2)
+	assert.Equal(t, "A2", products[0].GetString("name"))
+	assert.Equal(t, 3, products[0].Get("stock"))
+	assert.Equal(t, createdID, products[1].ID())
+	assert.Equal(t, "B", products[1].GetString("name"))
+}
+
+func TestUpsertTrashedSQLite(t *testing.T) {
+	ctx := context.Background()
+	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
+	t.Cleanup(func() { _ = os.RemoveAll(migrationDir) })
+
+	sb, err := schema.NewBuilderFromSchemas("", map[string]*schema.Schema{
+		"product": {
+			Name:           "product",
+			Namespace:      "products",
+			LabelFieldName: "name",
+			Fields: []*schema.Field{
+				{Name: "sku", Label: "SKU", Type: schema.TypeString, Unique: true},
+				{Name: "name", Label: "Name", Type: schema.TypeString},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	client, err := NewClient(&db.Config{
+		Driver:         "sqlite",
+		Name:           ":memory:_" + utils.RandomString(10),
+		MigrationDir:   migrationDir,
+		UseSoftDeletes: true,
+	}, sb)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	model := utils.Must(client.Model("product"))
+	upsert := func(json string) (any, error) {
+		e := utils.Must(entity.NewEntityFromJSON(json))
+		return model.Mutation().Upsert(ctx, e, []string{"sku"}, nil)
+	}
+
+	id := utils.Must(upsert(`{"sku": "a", "name": "A"}`))
+	_, err = model.Mutation().Where(db.EQ("id", id)).Delete(ctx)
+	require.NoError(t, err)
+
+	// The trashed record is neither updated nor restored
+	_, err = upsert(`{"sku": "a", "name": "A2"}`)
+	assert.ErrorIs(t, err, db.ErrUpsertTrashed)
+
+	trashed := utils.Must(model.Query(db.EQ("id", id)).OnlyTrashed().Only(ctx))
+	assert.Equal(t, "A", trashed.GetString("name"))
+	assert.Equal(t, 0, utils.Must(model.Query().Count(ctx)))
+}
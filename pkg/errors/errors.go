@@ -33,6 +33,7 @@ var (
 	BadRequest          = CreateErrorFn(http.StatusBadRequest)
 	Forbidden           = CreateErrorFn(http.StatusForbidden)
 	NotFound            = CreateErrorFn(http.StatusNotFound)
+	Conflict            = CreateErrorFn(http.StatusConflict)
 	BadGateway          = CreateErrorFn(http.StatusBadGateway)
 	UnprocessableEntity = CreateErrorFn(http.StatusUnprocessableEntity)
 	TooManyRequests     = CreateErrorFn(http.StatusTooManyRequests)
@@ -44,6 +45,7 @@ var errStatusMap = map[int]func(msgs ...any) *Error{
 	http.StatusBadRequest:          BadRequest,
 	http.StatusForbidden:           Forbidden,
 	http.StatusNotFound:            NotFound,
+	http.StatusConflict:            Conflict,
 	http.StatusBadGateway:          BadGateway,
 	http.StatusUnprocessableEntity: UnprocessableEntity,
 	http.StatusTooManyRequests:     TooManyRequests,
@@ -16,6 +16,35 @@ import (
 	"golang.org/x/text/language"
 )
 
+// ContentFilterDescription documents the filter grammar of the content filter argument.
+const ContentFilterDescription = `Filter the results by a JSON filter object.
+
+Each key is a field name, a dot notation path to a relation field (e.g. "owner.name")
+or a dot notation path inside a JSON field (e.g. "metadata.color").
+A primitive value matches the field by equality, an object value applies the operators,
+multiple operators of a field are combined with AND:
+
+- $eq, $neq, $gt, $gte, $lt, $lte: compare the field with the value
+- $between: match the values in the inclusive range [min, max], e.g. {"price": {"$between": [10, 20]}}
+- $in, $nin: match any or none of the array values
+- $like, $notlike: match the SQL LIKE pattern, % and _ are wildcards
+- $contains, $notcontains, $containsfold, $notcontainsfold: match a substring, the fold variants are case-insensitive
+- $startswith, $endswith: match a prefix or suffix, % and _ are matched literally
+- $regex: match the regular expression (REGEXP for MySQL and SQLite, ~ for Postgres)
+- $null: true matches the fields without value, false matches the fields with value
+- $exists: true matches the optional fields that have a value, for JSON paths it matches the existing keys even if the value is null
+- $search: full-text search on a searchable field
+
+A relation field can be filtered by its related records, the value of $some, $none and $every
+is a filter object of the related schema:
+
+- $some: match the records having at least one related record that matches the filter, e.g. {"comments": {"$some": {"approved": true}}}
+- $none: match the records having no related record that matches the filter, e.g. {"comments": {"$none": {}}}
+- $every: match the records whose related records all match the filter, e.g. {"orders": {"$every": {"status": "paid"}}}
+- $count: compare the number of related records with a number or the $eq, $neq, $gt, $gte, $lt, $lte, $in, $nin and $between operators, e.g. {"posts": {"$count": {"$gt": 5}}}
+
+The root keys $and and $or take an array of filter objects, the root key $search takes a full-text search query.`
+
 type ResourceInfo struct {
 	ID         string
 	Path       string
@@ -43,6 +72,8 @@ type OpenAPISpecConfig struct {
 	Resources     *fs.ResourcesManager
 	SchemaBuilder *schema.Builder
 	BaseURL       string
+	// Roles are used to document the field permissions of the content schemas
+	Roles []*fs.Role
 }
 
 // Clone creates a copy of the ResourceInfo object.
@@ -68,6 +99,7 @@ func NewSpec(config *OpenAPISpecConfig) (*OpenAPISpec, error) {
 			Resources:     config.Resources.Clone(),
 			SchemaBuilder: config.SchemaBuilder,
 			BaseURL:       config.BaseURL,
+			Roles:         config.Roles,
 		},
 		referenceSchemas: map[string]ReferenceSchemaType{},
 		ogenSpec: &ogen.Spec{
@@ -153,8 +185,8 @@ func (oas *OpenAPISpec) CreateResourcesForSchemas() {
 	}
 	contentFilterArg := fs.Arg{
 		Type:        fs.TypeJSON,
-		Description: "Filter the results by a field",
-		Example:     `{"name":{"$like":"%test%"}}`,
+		Description: ContentFilterDescription,
+		Example:     `{"name":{"$startswith":"test"},"price":{"$between":[10,20]}}`,
 	}
 
 	listArgs := fs.Args{
@@ -172,6 +204,28 @@ func (oas *OpenAPISpec) CreateResourcesForSchemas() {
 			Type:        fs.TypeUint,
 			Description: "The number of items per page",
 		},
+		"pagination": {
+			Type:        fs.TypeString,
+			Description: "Set to cursor to use keyset pagination instead of page numbers",
+			Example:     "cursor",
+		},
+		"after": {
+			Type:        fs.TypeString,
+			Description: "Return the items after this cursor (next_cursor of the previous response)",
+		},
+		"before": {
+			Type:        fs.TypeString,
+			Description: "Return the items before this cursor (prev_cursor of the previous response)",
+		},
+		"total": {
+			Type:        fs.TypeBool,
+			Description: "Count the total number of items in cursor pagination mode",
+		},
+		"search": {
+			Type:        fs.TypeString,
+			Description: "Full-text search the searchable fields, the results are sorted by relevance unless a sort is given",
+			Example:     "hello world",
+		},
 	}
 
 	for _, s := range schemas {
@@ -188,6 +242,62 @@ func (oas *OpenAPISpec) CreateResourcesForSchemas() {
 			Signatures: []any{nil, ContentListResponseSchema(s)},
 			Args:       listArgs,
 		})
+		schemaGroup.AddResource("aggregate", nil, &fs.Meta{
+			Get:        "/aggregate",
+			Signatures: []any{nil, []map[string]any{}},
+			Args: fs.Args{
+				"filter": contentFilterArg,
+				"group": {
+					Type:        fs.TypeString,
+					Description: "Group the results by filterable fields",
+					Example:     "category",
+				},
+				"aggregate": {
+					Type:        fs.TypeString,
+					Description: "The aggregations in the format func[:field[:alias]], func is one of count, sum, avg, min, max",
+					Example:     "count,sum:price:total_price",
+				},
+				"having": {
+					Type:        fs.TypeJSON,
+					Description: "Filter the groups by the aggregation aliases",
+					Example:     `{"total_price":{"$gt":100}}`,
+				},
+				"sort": {
+					Type:        fs.TypeString,
+					Description: "Sort the results by group fields or aggregation aliases",
+					Example:     "-total_price",
+				},
+				"limit": {
+					Type:        fs.TypeUint,
+					Description: "The maximum number of groups to return",
+				},
+				"offset": {
+					Type:        fs.TypeUint,
+					Description: "The number of groups to skip",
+				},
+			},
+		})
+		schemaGroup.AddResource("export", nil, &fs.Meta{
+			Get:        "/export",
+			Signatures: []any{nil, contentDetailSchema},
+			Args: fs.Args{
+				"filter": contentFilterArg,
+				"select": {
+					Type:        fs.TypeString,
+					Description: "Select the fields to export",
+					Example:     "id,name",
+				},
+				"sort": {
+					Type:        fs.TypeString,
+					Description: "Sort the exported items, default to the primary key",
+					Example:     "-id",
+				},
+				"limit": {
+					Type:        fs.TypeUint,
+					Description: "The maximum number of items to export",
+				},
+			},
+		})
 		schemaGroup.AddResource("detail", nil, &fs.Meta{
 			Get:        "/:id",
 			Signatures: []any{nil, contentDetailSchema},
@@ -204,6 +314,27 @@ func (oas *OpenAPISpec) CreateResourcesForSchemas() {
 			Post:       "/",
 			Signatures: []any{contentCreateSchema, contentDetailSchema},
 		})
+		schemaGroup.AddResource("bulk-create", nil, &fs.Meta{
+			Post:       "/bulk",
+			Signatures: []any{contentCreateSchema.AsArray(), ogen.NewSchema().AsArray()},
+		})
+		schemaGroup.AddResource("upsert", nil, &fs.Meta{
+			Put: "/upsert",
+			Args: fs.Args{
+				"conflict": {
+					Type:        fs.TypeString,
+					Required:    true,
+					Description: "The comma separated fields to detect the existing content, must be a unique key",
+					Example:     "slug",
+				},
+				"update": {
+					Type:        fs.TypeString,
+					Description: "The comma separated fields to update if the content exists, default to all the given fields",
+					Example:     "name,content",
+				},
+			},
+			Signatures: []any{contentCreateSchema, contentDetailSchema},
+		})
 		schemaGroup.AddResource("update", nil, &fs.Meta{
 			Put:        "/:id",
 			Args:       fs.Args{"id": contentIDArg},
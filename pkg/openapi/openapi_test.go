@@ -1,6 +1,7 @@
 package openapi_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/fastschema/fastschema/entity"
@@ -339,4 +340,10 @@ func TestCreateResourcesForSchemas(t *testing.T) {
 	oas.CreateResourcesForSchemas()
 	ogenUserSchema := oas.Schema("Schema.Category")
 	assert.NotNil(t, ogenUserSchema)
+
+	filterDescription := utils.Must(json.Marshal(openapi.ContentFilterDescription))
+	assert.Contains(t, string(oas.Spec()), string(filterDescription))
+	for _, operator := range []string{"$between", "$startswith", "$endswith", "$regex", "$exists", "$some", "$none", "$every", "$count"} {
+		assert.Contains(t, openapi.ContentFilterDescription, operator)
+	}
 }
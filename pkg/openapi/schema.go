@@ -2,6 +2,8 @@ package openapi
 
 import (
 	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/fastschema/fastschema/pkg/utils"
 	"github.com/fastschema/fastschema/schema"
@@ -27,6 +29,8 @@ func ContentListResponseSchema(s *schema.Schema) *ogen.Schema {
 	responseSchema.AddRequiredProperties(PrimitiveToOgenTypeMaps[reflect.Uint]().ToProperty("per_page"))
 	responseSchema.AddRequiredProperties(PrimitiveToOgenTypeMaps[reflect.Uint]().ToProperty("current_page"))
 	responseSchema.AddRequiredProperties(PrimitiveToOgenTypeMaps[reflect.Uint]().ToProperty("last_page"))
+	responseSchema.AddOptionalProperties(PrimitiveToOgenTypeMaps[reflect.String]().ToProperty("next_cursor"))
+	responseSchema.AddOptionalProperties(PrimitiveToOgenTypeMaps[reflect.String]().ToProperty("prev_cursor"))
 
 	itemSchema := ContentDetailSchema(s)
 	responseSchema.AddRequiredProperties(itemSchema.AsArray().ToProperty("items"))
@@ -71,12 +75,14 @@ func (oas *OpenAPISpec) SchemaToOGenSchema(s *schema.Schema) {
 	IDOnlySchema := RefSchema(SchemaIDOnlyName)
 
 	for _, field := range s.Fields {
+		description := oas.FieldPermissionsDescription(s, field.Name)
+
 		// Non-relation field
 		if !field.Type.IsRelationType() {
 			zeroedField := utils.CreateZeroValue(field.Type.StructType())
 			fieldSchema := oas.TypeToOgenSchema(zeroedField)
-			ogenSchema.AddOptionalProperties(fieldSchema.ToProperty(field.Name))
-			ogenCreateSchema.AddOptionalProperties(fieldSchema.ToProperty(field.Name))
+			ogenSchema.AddOptionalProperties(describeSchema(fieldSchema, description).ToProperty(field.Name))
+			ogenCreateSchema.AddOptionalProperties(describeSchema(fieldSchema, description).ToProperty(field.Name))
 			continue
 		}
 
@@ -87,14 +93,67 @@ func (oas *OpenAPISpec) SchemaToOGenSchema(s *schema.Schema) {
 
 		// if not array, we need to create a reference schema
 		if !isArrayField {
-			ogenSchema.AddOptionalProperties(relSchema.ToProperty(field.Name))
-			ogenCreateSchema.AddOptionalProperties(IDOnlySchema.ToProperty(field.Name))
+			ogenSchema.AddOptionalProperties(describeSchema(relSchema, description).ToProperty(field.Name))
+			ogenCreateSchema.AddOptionalProperties(describeSchema(IDOnlySchema, description).ToProperty(field.Name))
 		} else {
-			ogenSchema.AddOptionalProperties(relSchema.AsArray().ToProperty(field.Name))
-			ogenCreateSchema.AddOptionalProperties(IDOnlySchema.AsArray().ToProperty(field.Name))
+			ogenSchema.AddOptionalProperties(describeSchema(relSchema.AsArray(), description).ToProperty(field.Name))
+			ogenCreateSchema.AddOptionalProperties(describeSchema(IDOnlySchema.AsArray(), description).ToProperty(field.Name))
 		}
 	}
 
 	oas.ogenSpec.Components.Schemas[schemaName] = ogenSchema
 	oas.ogenSpec.Components.Schemas[schemaCreateName] = ogenCreateSchema
 }
+
+// FieldPermissionsDescription describes the roles that are not allowed to read or write a field of a content schema.
+// It returns an empty string if all the roles can read and write the field.
+func (oas *OpenAPISpec) FieldPermissionsDescription(s *schema.Schema, fieldName string) string {
+	notReadable := []string{}
+	notWritable := []string{}
+	contentPrefix := "api.content." + s.Name + "."
+
+	for _, role := range oas.config.Roles {
+		if role.Root {
+			continue
+		}
+
+		for _, permission := range role.Permissions {
+			matchPrefix := strings.HasPrefix(permission.Resource, contentPrefix)
+			matchWildcard := strings.HasSuffix(permission.Resource, ".*") &&
+				strings.HasPrefix(contentPrefix, permission.Resource[:len(permission.Resource)-1])
+			if permission.IsDenied() || (!matchPrefix && !matchWildcard) {
+				continue
+			}
+
+			if !permission.CanReadField(fieldName) && !slices.Contains(notReadable, role.Name) {
+				notReadable = append(notReadable, role.Name)
+			}
+
+			if !permission.CanWriteField(fieldName) && !slices.Contains(notWritable, role.Name) {
+				notWritable = append(notWritable, role.Name)
+			}
+		}
+	}
+
+	descriptions := []string{}
+	if len(notReadable) > 0 {
+		descriptions = append(descriptions, "Not readable by the roles: "+strings.Join(notReadable, ", ")+".")
+	}
+
+	if len(notWritable) > 0 {
+		descriptions = append(descriptions, "Not writable by the roles: "+strings.Join(notWritable, ", ")+".")
+	}
+
+	return strings.Join(descriptions, " ")
+}
+
+// describeSchema returns a copy of the schema with the given description.
+func describeSchema(s *ogen.Schema, description string) *ogen.Schema {
+	if description == "" {
+		return s
+	}
+
+	described := *s
+	described.Description = description
+	return &described
+}
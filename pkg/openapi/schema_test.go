@@ -153,3 +153,55 @@ func TestSchemasToOGenSchemas(t *testing.T) {
 	assert.Len(t, permissionRoleProperty, 1)
 	assert.Equal(t, "#/components/schemas/Schema.Role", permissionRoleProperty[0].Schema.Ref)
 }
+
+func TestSchemaFieldPermissions(t *testing.T) {
+	resources := fs.NewResourcesManager()
+	sb := utils.Must(schema.NewBuilderFromDir(t.TempDir(), fs.SystemSchemaTypes...))
+	editorPermission := &fs.Permission{
+		Resource: "api.content.user.*",
+		Value:    "allow",
+		Fields:   `{"read": ["*", "-email"], "write": ["username"]}`,
+	}
+	viewerPermission := &fs.Permission{
+		Resource: "api.content.*",
+		Value:    "allow",
+		Fields:   `{"write": []}`,
+	}
+	deniedPermission := &fs.Permission{
+		Resource: "api.content.user.list",
+		Value:    "deny",
+		Fields:   `{"read": []}`,
+	}
+	for _, p := range []*fs.Permission{editorPermission, viewerPermission, deniedPermission} {
+		assert.NoError(t, p.Compile())
+	}
+
+	oas := utils.Must(openapi.NewSpec(&openapi.OpenAPISpecConfig{
+		SchemaBuilder: sb,
+		Resources:     resources,
+		Roles: []*fs.Role{
+			{Name: "admin", Root: true, Permissions: []*fs.Permission{{Resource: "api.content.user.list", Value: "allow", Fields: `{"read": []}`}}},
+			{Name: "editor", Permissions: []*fs.Permission{editorPermission}},
+			{Name: "viewer", Permissions: []*fs.Permission{viewerPermission, deniedPermission}},
+		},
+	}))
+
+	userSchema := utils.Must(sb.Schema("user"))
+	assert.Equal(t, "Not writable by the roles: viewer.", oas.FieldPermissionsDescription(userSchema, "username"))
+	assert.Equal(
+		t,
+		"Not readable by the roles: editor. Not writable by the roles: editor, viewer.",
+		oas.FieldPermissionsDescription(userSchema, "email"),
+	)
+
+	property := func(schemaName, name string) ogen.Property {
+		return utils.Filter(oas.Schema(schemaName).Properties, func(p ogen.Property) bool {
+			return p.Name == name
+		})[0]
+	}
+	assert.Equal(t, "Not writable by the roles: viewer.", property("Schema.User", "username").Schema.Description)
+	assert.Equal(t, "Not writable by the roles: viewer.", property("Schema.User.Create", "username").Schema.Description)
+	assert.Equal(t, "Not writable by the roles: editor, viewer.", property("Schema.User", "roles").Schema.Description)
+	assert.Equal(t, "#/components/schemas/Schema.Role", property("Schema.User", "roles").Schema.Items.Item.Ref)
+	assert.Equal(t, "Not writable by the roles: viewer.", property("Schema.Role", "name").Schema.Description)
+}
@@ -1,6 +1,7 @@
 package restfulresolver
 
 import (
+	"bufio"
 	"net/http"
 
 	"github.com/fastschema/fastschema/fs"
@@ -229,6 +230,17 @@ func httpResourceHandler(r *fs.Resource, hooks *fs.Hooks, methodHandler MethodDa
 				return c.ctx.SendStream(httpResponse.Stream)
 			}
 
+			if httpResponse.BodyWriter != nil {
+				// The context is released after the handler returns, keep the logger only
+				contextLogger := c.Logger()
+				c.Status(status).ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+					if err := httpResponse.BodyWriter(w); err != nil {
+						contextLogger.Error(err)
+					}
+				})
+				return nil
+			}
+
 			return c.Status(status).Send(httpResponse.Body)
 		}
 
@@ -238,16 +238,16 @@ func TestConfig(t *testing.T) {
 	tests := []testHooksLength{
 		{"PreResolve", 2, len(hooks.PreResolve)}, // including the default one: authorize
 		{"PostResolve", 1, len(hooks.PostResolve)},
-		{"PreDBQuery", 1, len(hooks.DBHooks.PreDBQuery)},
+		{"PreDBQuery", 2, len(hooks.DBHooks.PreDBQuery)},   // including the default one: auth.FieldsQueryHook
 		{"PostDBQuery", 2, len(hooks.DBHooks.PostDBQuery)}, // including the default one: FileListHook
 		{"PreDBExec", 1, len(hooks.DBHooks.PreDBExec)},
 		{"PostDBExec", 1, len(hooks.DBHooks.PostDBExec)},
 		{"PreDBCreate", 1, len(hooks.DBHooks.PreDBCreate)},
-		{"PostDBCreate", 2, len(hooks.DBHooks.PostDBCreate)}, // including the default one: realtime.ContentCreateHook
+		{"PostDBCreate", 3, len(hooks.DBHooks.PostDBCreate)}, // including the default ones: realtime.ContentCreateHook, audit.CreateHook
 		{"PreDBUpdate", 1, len(hooks.DBHooks.PreDBUpdate)},
-		{"PostDBUpdate", 2, len(hooks.DBHooks.PostDBUpdate)}, // including the default one: realtime.ContentUpdateHook
+		{"PostDBUpdate", 4, len(hooks.DBHooks.PostDBUpdate)}, // including the default ones: realtime.ContentUpdateHook, audit.UpdateHook, content.RevisionUpdateHook
 		{"PreDBDelete", 1, len(hooks.DBHooks.PreDBDelete)},
-		{"PostDBDelete", 2, len(hooks.DBHooks.PostDBDelete)}, // including the default one: realtime.ContentDeleteHook
+		{"PostDBDelete", 4, len(hooks.DBHooks.PostDBDelete)}, // including the default ones: realtime.ContentDeleteHook, audit.DeleteHook, content.RevisionDeleteHook
 	}
 
 	for _, test := range tests {
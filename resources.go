@@ -18,7 +18,13 @@ type AppConfig struct {
 func (a *App) createServices() {
 	a.services = services.New(a)
 	realTimeService := a.services.Realtime()
+	auditService := a.services.Audit()
+	contentService := a.services.Content()
 
+	a.config.Hooks.DBHooks.PreDBQuery = append(
+		a.config.Hooks.DBHooks.PreDBQuery,
+		a.services.Auth().FieldsQueryHook,
+	)
 	a.config.Hooks.DBHooks.PostDBQuery = append(
 		a.config.Hooks.DBHooks.PostDBQuery,
 		a.services.File().FileListHook,
@@ -26,14 +32,19 @@ func (a *App) createServices() {
 	a.config.Hooks.DBHooks.PostDBCreate = append(
 		a.config.Hooks.DBHooks.PostDBCreate,
 		realTimeService.ContentCreateHook,
+		auditService.CreateHook,
 	)
 	a.config.Hooks.DBHooks.PostDBUpdate = append(
 		a.config.Hooks.DBHooks.PostDBUpdate,
 		realTimeService.ContentUpdateHook,
+		auditService.UpdateHook,
+		contentService.RevisionUpdateHook,
 	)
 	a.config.Hooks.DBHooks.PostDBDelete = append(
 		a.config.Hooks.DBHooks.PostDBDelete,
 		realTimeService.ContentDeleteHook,
+		auditService.DeleteHook,
+		contentService.RevisionDeleteHook,
 	)
 	a.config.Hooks.PreResolve = append(
 		a.config.Hooks.PreResolve,
@@ -59,6 +70,7 @@ func (a *App) createResources() {
 	a.services.Role().CreateResource(a.api)
 	a.services.File().CreateResource(a.api)
 	a.services.Tool().CreateResource(a.api)
+	a.services.Audit().CreateResource(a.api)
 
 	a.api.Add(fs.Get("config", func(c fs.Context, _ any) (*AppConfig, error) {
 		schemas, err := a.services.Schema().List(c, nil)
@@ -293,6 +293,11 @@ func (t FieldType) IsInteger() bool {
 	return false
 }
 
+// IsNumeric reports if the given type is an integer or a float type.
+func (t FieldType) IsNumeric() bool {
+	return t.IsInteger() || t == TypeFloat32 || t == TypeFloat64
+}
+
 func (t FieldType) IsUnsignedInteger() bool {
 	switch t {
 	case TypeUint, TypeUint8, TypeUint16, TypeUint32, TypeUint64:
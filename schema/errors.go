@@ -18,6 +18,8 @@ const (
 	CodeSchemaPrimaryFieldRequired   = "schema.primary_field.required"
 	CodeSchemaIOReadError            = "schema.io.read_error"
 	CodeSchemaInitUnknown            = "schema.init.unknown"
+	CodeSchemaValidatorCompileError  = "schema.validator.compile_error"
+	CodeSchemaValidatorFailed        = "schema.validator.failed"
 
 	// Field-level
 	CodeFieldNameRequired       = "field.name.required"
@@ -33,6 +35,17 @@ const (
 	CodeFieldFileSchemaRequired = "field.file.schema.required"
 	CodeFieldSetterCompileError = "field.setter.compile_error"
 	CodeFieldGetterCompileError = "field.getter.compile_error"
+	CodeFieldSearchableInvalid  = "field.searchable.invalid"
+	CodeFieldValidationInvalid  = "field.validation.invalid"
+
+	// Field value
+	CodeFieldValueMinLength = "field.value.min_length"
+	CodeFieldValueMaxLength = "field.value.max_length"
+	CodeFieldValueMin       = "field.value.min"
+	CodeFieldValueMax       = "field.value.max"
+	CodeFieldValuePattern   = "field.value.pattern"
+	CodeFieldValueEmail     = "field.value.email"
+	CodeFieldValueURL       = "field.value.url"
 
 	// Relation
 	CodeRelationTargetNotFound   = "relation.target.not_found"
@@ -355,6 +368,24 @@ func SchemaPrimaryFieldRequired(schemaName string) *SchemaError {
 	}
 }
 
+func SchemaValidatorCompileError(index int, cause error) *FieldError {
+	return &FieldError{
+		Code:    CodeSchemaValidatorCompileError,
+		Message: fmt.Sprintf("validator at index %d failed to compile: %v", index, cause),
+		Cause:   cause,
+	}
+}
+
+// SchemaValidatorFailed is returned when an entity does not pass a schema validator.
+// The error is attributed to the given field, or to the whole entity if the field is empty.
+func SchemaValidatorFailed(fieldName, message string) *FieldError {
+	return &FieldError{
+		Code:    CodeSchemaValidatorFailed,
+		Field:   fieldName,
+		Message: message,
+	}
+}
+
 func FieldNameRequired(index int) *FieldError {
 	idx := index
 	return &FieldError{
@@ -395,6 +426,14 @@ func FieldEnumRequired(fieldName string) *FieldError {
 	}
 }
 
+func FieldSearchableInvalid(fieldName, fieldType string) *FieldError {
+	return &FieldError{
+		Code:    CodeFieldSearchableInvalid,
+		Field:   fieldName,
+		Message: fmt.Sprintf("searchable is only supported for string and text fields, got '%s'", fieldType),
+	}
+}
+
 func FieldRelationRequired(fieldName string) *FieldError {
 	return &FieldError{
 		Code:    CodeFieldRelationRequired,
@@ -461,6 +500,29 @@ func FieldGetterCompileError(fieldName string, cause error) *FieldError {
 	}
 }
 
+func FieldValidationInvalid(fieldName, reason string) *FieldError {
+	return &FieldError{
+		Code:    CodeFieldValidationInvalid,
+		Field:   fieldName,
+		Message: fmt.Sprintf("invalid validation rules: %s", reason),
+	}
+}
+
+// FieldValueInvalid is returned when a value of an entity does not pass the field validation rules.
+// The custom message of the rules takes precedence over the given message.
+func FieldValueInvalid(code, fieldName string, rules *FieldValidation, format string, args ...any) *FieldError {
+	message := fmt.Sprintf(format, args...)
+	if rules != nil && rules.Message != "" {
+		message = rules.Message
+	}
+
+	return &FieldError{
+		Code:    code,
+		Field:   fieldName,
+		Message: message,
+	}
+}
+
 // ----- Cross-schema helpers (return *SchemaError) -----
 
 func RelationTargetNotFound(sourceSchema, sourceField, targetSchema string) *SchemaError {
@@ -13,22 +13,24 @@ import (
 
 // Field define the data struct for a field
 type Field struct {
-	Type          FieldType      `json:"type"`
-	Name          string         `json:"name"`
-	Label         string         `json:"label"`
-	IsMultiple    bool           `json:"multiple,omitempty"`  // Is a multiple field.
-	Size          int64          `json:"size,omitempty"`      // max size parameter for string, blob, etc.
-	Unique        bool           `json:"unique,omitempty"`    // column with unique constraint.
-	Optional      bool           `json:"optional,omitempty"`  // null or not null attribute.
-	Default       any            `json:"default,omitempty"`   // default value.
-	Immutable     bool           `json:"immutable,omitempty"` // cannot be changed after creation.
-	Setter        string         `json:"setter,omitempty"`    // setter expression.
-	Getter        string         `json:"getter,omitempty"`    // getter expression.
-	setterProgram *SetterProgram `json:"-"`                   // Compiled setter program
-	getterProgram *GetterProgram `json:"-"`                   // Compiled getter program
+	Type          FieldType        `json:"type"`
+	Name          string           `json:"name"`
+	Label         string           `json:"label"`
+	IsMultiple    bool             `json:"multiple,omitempty"`   // Is a multiple field.
+	Size          int64            `json:"size,omitempty"`       // max size parameter for string, blob, etc.
+	Unique        bool             `json:"unique,omitempty"`     // column with unique constraint.
+	Optional      bool             `json:"optional,omitempty"`   // null or not null attribute.
+	Default       any              `json:"default,omitempty"`    // default value.
+	Immutable     bool             `json:"immutable,omitempty"`  // cannot be changed after creation.
+	Setter        string           `json:"setter,omitempty"`     // setter expression.
+	Getter        string           `json:"getter,omitempty"`     // getter expression.
+	setterProgram *SetterProgram   `json:"-"`                    // Compiled setter program
+	getterProgram *GetterProgram   `json:"-"`                    // Compiled getter program
+	Validation    *FieldValidation `json:"validation,omitempty"` // value validation rules.
 	// Querier
 	Sortable   bool         `json:"sortable,omitempty"`   // Has a "sort" option in the tag.
 	Filterable bool         `json:"filterable,omitempty"` // Has a "filter" option in the tag.
+	Searchable bool         `json:"searchable,omitempty"` // Full-text searchable, only for string and text fields.
 	Enums      []*FieldEnum `json:"enums,omitempty"`      // enum values.
 	Relation   *Relation    `json:"relation,omitempty"`   // relation of the field.
 	DB         *FieldDB     `json:"db,omitempty"`         // db config for the field.
@@ -68,6 +70,12 @@ func (f *Field) Init(schemaNames ...string) (err error) {
 		return FieldGetterCompileError(f.Name, err)
 	}
 
+	if f.Validation != nil {
+		if err := f.Validation.check(f); err != nil {
+			return FieldValidationInvalid(f.Name, err.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -86,10 +94,12 @@ func (f *Field) Clone() *Field {
 		Getter:        f.Getter,
 		Sortable:      f.Sortable,
 		Filterable:    f.Filterable,
+		Searchable:    f.Searchable,
 		IsSystemField: f.IsSystemField,
 		Immutable:     f.Immutable,
 		Relation:      f.Relation.Clone(),
 		DB:            f.DB.Clone(),
+		Validation:    f.Validation.Clone(),
 	}
 
 	if f.Enums != nil {
@@ -251,11 +261,16 @@ func MergeFields(f1, f2 *Field) {
 		f1.DB = f2.DB.Clone()
 	}
 
+	if f2.Validation != nil {
+		f1.Validation = f2.Validation.Clone()
+	}
+
 	f1.IsMultiple = f2.IsMultiple
 	f1.Unique = f2.Unique
 	f1.Optional = f2.Optional
 	f1.Sortable = f2.Sortable
 	f1.Filterable = f2.Filterable
+	f1.Searchable = f2.Searchable
 	f1.IsSystemField = f2.IsSystemField
 	f1.Immutable = f2.Immutable
 }
@@ -210,6 +210,7 @@ func TestFieldClone(t *testing.T) {
 		Setter:        "5",
 		Sortable:      true,
 		Filterable:    true,
+		Searchable:    true,
 		IsSystemField: false,
 		Relation: &Relation{
 			SourceSchemaName: "user",
@@ -248,6 +249,7 @@ func TestFieldClone(t *testing.T) {
 	assert.Equal(t, field.Setter, clonedField.Setter)
 	assert.Equal(t, field.Sortable, clonedField.Sortable)
 	assert.Equal(t, field.Filterable, clonedField.Filterable)
+	assert.Equal(t, field.Searchable, clonedField.Searchable)
 	assert.Equal(t, field.IsSystemField, clonedField.IsSystemField)
 
 	// Check if the cloned field's relation is a separate instance
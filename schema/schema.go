@@ -16,17 +16,22 @@ type Schema struct {
 	initialized bool
 	dbColumns   []string `json:"-"`
 
-	Name             string          `json:"name"`
-	Namespace        string          `json:"namespace"`
-	LabelFieldName   string          `json:"label_field"`
-	PrimaryFieldName string          `json:"primary_field,omitempty"`
-	DisableTimestamp bool            `json:"disable_timestamp,omitempty"`
-	Fields           []*Field        `json:"fields"`
-	IsSystemSchema   bool            `json:"is_system_schema,omitempty"`
-	IsJunctionSchema bool            `json:"is_junction_schema,omitempty"`
-	DB               *SchemaDB       `json:"db,omitempty"`
-	Settings         *SchemaSettings `json:"settings,omitempty"`
-	primaryField     string          `json:"-"`
+	Name             string             `json:"name"`
+	Namespace        string             `json:"namespace"`
+	LabelFieldName   string             `json:"label_field"`
+	PrimaryFieldName string             `json:"primary_field,omitempty"`
+	DisableTimestamp bool               `json:"disable_timestamp,omitempty"`
+	OptimisticLock   bool               `json:"optimistic_lock,omitempty"`
+	Tenant           bool               `json:"tenant,omitempty"`
+	Audit            bool               `json:"audit,omitempty"`
+	Revisions        *SchemaRevisions   `json:"revisions,omitempty"`
+	Fields           []*Field           `json:"fields"`
+	Validators       []*SchemaValidator `json:"validators,omitempty"`
+	IsSystemSchema   bool               `json:"is_system_schema,omitempty"`
+	IsJunctionSchema bool               `json:"is_junction_schema,omitempty"`
+	DB               *SchemaDB          `json:"db,omitempty"`
+	Settings         *SchemaSettings    `json:"settings,omitempty"`
+	primaryField     string             `json:"-"`
 }
 
 // NewSchemaFromJSON creates a new node from a json string.
@@ -144,6 +149,60 @@ func (s *Schema) Init(disableIDColumn bool) error {
 		}
 	}
 
+	// The version field is managed by the database adapter:
+	// it is checked and incremented on every update of the record.
+	if s.OptimisticLock {
+		versionField := &Field{
+			IsSystemField: true,
+			Immutable:     true,
+			Type:          TypeUint64,
+			Name:          entity.FieldVersion,
+			Label:         "Version",
+			Default:       1,
+			Filterable:    true,
+			Sortable:      false,
+		}
+
+		existedVersionField := s.Field(entity.FieldVersion)
+		if existedVersionField != nil {
+			MergeFields(existedVersionField, versionField)
+		} else {
+			s.dbColumns = append(s.dbColumns, entity.FieldVersion)
+			s.Fields = append(s.Fields, versionField)
+			if err := versionField.Init(); err != nil {
+				appendStageError(errs, err, versionField.Name)
+				return errs
+			}
+		}
+	}
+
+	// The tenant field is managed by the database adapter:
+	// it is set to the tenant of the context on create and the records are scoped to it.
+	if s.Tenant {
+		tenantField := &Field{
+			IsSystemField: true,
+			Immutable:     true,
+			Type:          TypeString,
+			Name:          entity.FieldTenantID,
+			Label:         "Tenant ID",
+			Optional:      true,
+			Filterable:    true,
+			Sortable:      false,
+		}
+
+		existedTenantField := s.Field(entity.FieldTenantID)
+		if existedTenantField != nil {
+			MergeFields(existedTenantField, tenantField)
+		} else {
+			s.dbColumns = append(s.dbColumns, entity.FieldTenantID)
+			s.Fields = append(s.Fields, tenantField)
+			if err := tenantField.Init(); err != nil {
+				appendStageError(errs, err, tenantField.Name)
+				return errs
+			}
+		}
+	}
+
 	s.initialized = true
 	return nil
 }
@@ -206,6 +265,10 @@ func (s *Schema) Clone() *Schema {
 		LabelFieldName:   s.LabelFieldName,
 		PrimaryFieldName: s.PrimaryFieldName,
 		DisableTimestamp: s.DisableTimestamp,
+		OptimisticLock:   s.OptimisticLock,
+		Tenant:           s.Tenant,
+		Audit:            s.Audit,
+		Revisions:        s.Revisions.Clone(),
 		dbColumns:        dbColumnsCopy,
 		IsSystemSchema:   s.IsSystemSchema,
 		IsJunctionSchema: s.IsJunctionSchema,
@@ -218,6 +281,10 @@ func (s *Schema) Clone() *Schema {
 		clone.Fields = append(clone.Fields, f.Clone())
 	}
 
+	for _, v := range s.Validators {
+		clone.Validators = append(clone.Validators, v.Clone())
+	}
+
 	return clone
 }
 
@@ -250,9 +317,27 @@ func MergeSchemas(target, source *Schema) {
 	if source.DisableTimestamp {
 		target.DisableTimestamp = source.DisableTimestamp
 	}
+	if source.OptimisticLock {
+		target.OptimisticLock = source.OptimisticLock
+	}
+	if source.Tenant {
+		target.Tenant = source.Tenant
+	}
+	if source.Audit {
+		target.Audit = source.Audit
+	}
+	if source.Revisions != nil {
+		target.Revisions = source.Revisions.Clone()
+	}
 	if source.Settings != nil {
 		target.Settings = source.Settings
 	}
+	if source.Validators != nil {
+		target.Validators = make([]*SchemaValidator, len(source.Validators))
+		for i, v := range source.Validators {
+			target.Validators[i] = v.Clone()
+		}
+	}
 
 	// Merge fields
 	for _, sourceField := range source.Fields {
@@ -326,6 +411,13 @@ func (s *Schema) Field(name string) *Field {
 	})
 }
 
+// SearchableFields returns the full-text searchable fields of the schema.
+func (s *Schema) SearchableFields() []*Field {
+	return utils.Filter(s.Fields, func(f *Field) bool {
+		return f.Searchable
+	})
+}
+
 // PrimaryField returns the primary key field definition.
 func (s *Schema) PrimaryField() *Field {
 	primaryName := s.PrimaryKeyName()
@@ -399,6 +491,16 @@ func (s *Schema) Validate() error {
 			fieldErrors = append(fieldErrors, FieldEnumRequired(field.Name))
 		}
 
+		if field.Searchable && field.Type != TypeString && field.Type != TypeText {
+			fieldErrors = append(fieldErrors, FieldSearchableInvalid(field.Name, field.Type.String()))
+		}
+
+		if field.Validation != nil {
+			if err := field.Validation.check(field); err != nil {
+				fieldErrors = append(fieldErrors, FieldValidationInvalid(field.Name, err.Error()))
+			}
+		}
+
 		if field.Type.IsRelationType() && !field.Type.IsFileType() {
 			relation := field.Relation
 			if relation == nil {
@@ -424,6 +526,8 @@ func (s *Schema) Validate() error {
 		}
 	}
 
+	fieldErrors = append(fieldErrors, s.compileValidators()...)
+
 	// If schema is system schema, skip checking label field
 	// "id" is always present (auto-created by ensurePrimaryField) but isn't in Fields yet when Validate() runs
 	if s.LabelFieldName == "id" {
@@ -408,6 +408,19 @@ func TestSchemaValidate(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "enum field requires 'enums' array")
 
+	// Test searchable non text field
+	s.Fields = []*Field{
+		{
+			Name:       "field1",
+			Type:       TypeInt,
+			Label:      "label1",
+			Searchable: true,
+		},
+	}
+	err = s.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "searchable is only supported for string and text fields, got 'int'")
+
 	// Test missing relation
 	s.Fields = []*Field{
 		{
@@ -1029,6 +1042,79 @@ func TestSchema_Init_ValidSchema(t *testing.T) {
 	assert.NotNil(t, s.Field(entity.FieldID))
 }
 
+func TestSchema_Init_OptimisticLock(t *testing.T) {
+	s := &Schema{
+		Name:           "post",
+		Namespace:      "posts",
+		LabelFieldName: "title",
+		OptimisticLock: true,
+		Fields: []*Field{
+			{Name: "title", Type: TypeString, Label: "Title"},
+			{Name: "version", Type: TypeString, Label: "Custom Version"},
+		},
+	}
+	assert.NoError(t, s.Init(false))
+	versionField := s.Field(entity.FieldVersion)
+	assert.NotNil(t, versionField)
+	assert.Equal(t, TypeUint64, versionField.Type)
+	assert.Equal(t, "Version", versionField.Label)
+	assert.Equal(t, 1, versionField.Default)
+	assert.Len(t, s.Fields, 6)
+	assert.True(t, s.Clone().OptimisticLock)
+
+	target := &Schema{Name: "post"}
+	MergeSchemas(target, &Schema{OptimisticLock: true})
+	assert.True(t, target.OptimisticLock)
+}
+
+func TestSchema_Init_Tenant(t *testing.T) {
+	s := &Schema{
+		Name:           "post",
+		Namespace:      "posts",
+		LabelFieldName: "title",
+		Tenant:         true,
+		Fields: []*Field{
+			{Name: "title", Type: TypeString, Label: "Title", Unique: true},
+			{Name: "tenant_id", Type: TypeString, Label: "Organization", Optional: false},
+		},
+	}
+	assert.NoError(t, s.Init(false))
+	tenantField := s.Field(entity.FieldTenantID)
+	assert.NotNil(t, tenantField)
+	assert.Equal(t, TypeString, tenantField.Type)
+	assert.Equal(t, "Tenant ID", tenantField.Label)
+	assert.True(t, tenantField.Immutable)
+	assert.True(t, tenantField.Filterable)
+	assert.True(t, tenantField.IsSystemField)
+	assert.Len(t, s.Fields, 6)
+	assert.True(t, s.Clone().Tenant)
+
+	target := &Schema{Name: "post"}
+	MergeSchemas(target, &Schema{Tenant: true})
+	assert.True(t, target.Tenant)
+}
+
+func TestSchema_Audit(t *testing.T) {
+	s := &Schema{Name: "post", Audit: true}
+	assert.True(t, s.Clone().Audit)
+
+	target := &Schema{Name: "post"}
+	MergeSchemas(target, &Schema{Audit: true})
+	assert.True(t, target.Audit)
+}
+
+func TestSchema_Revisions(t *testing.T) {
+	s := &Schema{Name: "post", Revisions: &SchemaRevisions{Max: 5}}
+	clone := s.Clone()
+	assert.Equal(t, 5, clone.Revisions.Max)
+	clone.Revisions.Max = 10
+	assert.Equal(t, 5, s.Revisions.Max)
+
+	target := &Schema{Name: "post"}
+	MergeSchemas(target, &Schema{Revisions: &SchemaRevisions{Max: 3}})
+	assert.Equal(t, 3, target.Revisions.Max)
+}
+
 func TestSchema_Init_LabelFieldNotFound(t *testing.T) {
 	s := &Schema{
 		Name:           "post",
@@ -60,9 +60,22 @@ type SchemaListSettings struct {
 	Views      map[string]SchemaListView `json:"views,omitempty"`
 }
 
+// SchemaCacheSettings configures the query result cache of the schema.
+// The results are cached only if the database cache is enabled and TTL is greater than 0.
+type SchemaCacheSettings struct {
+	TTL int `json:"ttl,omitempty"` // time to live of the cached results in seconds
+}
+
+// SchemaRevisions configures the revisions of the schema records.
+// The previous versions of the records are kept on update and delete.
+type SchemaRevisions struct {
+	Max int `json:"max,omitempty"` // maximum number of revisions kept per record, 0 keeps all the revisions
+}
+
 type SchemaSettings struct {
-	Form *SchemaFormSettings `json:"form,omitempty"`
-	List *SchemaListSettings `json:"list,omitempty"`
+	Form  *SchemaFormSettings  `json:"form,omitempty"`
+	List  *SchemaListSettings  `json:"list,omitempty"`
+	Cache *SchemaCacheSettings `json:"cache,omitempty"`
 }
 
 // Clone returns a deep copy of SchemaDBIndex
@@ -150,13 +163,30 @@ func (s *SchemaListSettings) Clone() *SchemaListSettings {
 	return clone
 }
 
+// Clone returns a deep copy of SchemaCacheSettings
+func (s *SchemaCacheSettings) Clone() *SchemaCacheSettings {
+	if s == nil {
+		return nil
+	}
+	return &SchemaCacheSettings{TTL: s.TTL}
+}
+
+// Clone returns a deep copy of SchemaRevisions
+func (s *SchemaRevisions) Clone() *SchemaRevisions {
+	if s == nil {
+		return nil
+	}
+	return &SchemaRevisions{Max: s.Max}
+}
+
 // Clone returns a deep copy of SchemaSettings
 func (s *SchemaSettings) Clone() *SchemaSettings {
 	if s == nil {
 		return nil
 	}
 	return &SchemaSettings{
-		Form: s.Form.Clone(),
-		List: s.List.Clone(),
+		Form:  s.Form.Clone(),
+		List:  s.List.Clone(),
+		Cache: s.Cache.Clone(),
 	}
 }
@@ -36,6 +36,10 @@ type SystemSchema struct {
 //			- Namespace
 //			- LabelFieldName
 //			- DisableTimestamp
+//			- OptimisticLock
+//			- Tenant
+//			- Audit
+//			- Revisions
 //			- IsJunctionSchema
 //			- DB
 //			- Settings
@@ -124,6 +128,15 @@ func (s *Schema) Customize() (_ *Schema, err error) {
 				s.PrimaryFieldName = value
 			case "disable_timestamp":
 				s.DisableTimestamp = true
+			case "optimistic_lock":
+				s.OptimisticLock = true
+			case "tenant":
+				s.Tenant = true
+			case "audit":
+				s.Audit = true
+			case "revisions":
+				maxRevisions, _ := strconv.Atoi(value)
+				s.Revisions = &SchemaRevisions{Max: maxRevisions}
 			case "is_junction_schema":
 				s.IsJunctionSchema = true
 			}
@@ -171,6 +184,22 @@ func (s *Schema) Customize() (_ *Schema, err error) {
 			s.DisableTimestamp = customizedSchema.DisableTimestamp
 		}
 
+		if customizedSchema.OptimisticLock {
+			s.OptimisticLock = customizedSchema.OptimisticLock
+		}
+
+		if customizedSchema.Tenant {
+			s.Tenant = customizedSchema.Tenant
+		}
+
+		if customizedSchema.Audit {
+			s.Audit = customizedSchema.Audit
+		}
+
+		if customizedSchema.Revisions != nil {
+			s.Revisions = customizedSchema.Revisions
+		}
+
 		if customizedSchema.IsJunctionSchema {
 			s.IsJunctionSchema = customizedSchema.IsJunctionSchema
 		}
@@ -278,7 +307,7 @@ func (s *Schema) CreateField(sf reflect.StructField) (*Field, error) {
 // ExtendFieldByTag extends the given field by parsing the struct field tag and updating the field properties accordingly.
 //
 //	Common properties format:
-//	- E.g: `fs:"type=string;name=custom_name;label=Custom Label;size=10;multiple;unique;optional;sortable;filterable;default=10"`
+//	- E.g: `fs:"type=string;name=custom_name;label=Custom Label;size=10;multiple;unique;optional;sortable;filterable;searchable;default=10"`
 //	- Supported field properties:
 //		- type: Tag fs="type=string".
 //		- name: Use json tag to customize the field name, e.g. `json:"custom_name"`.
@@ -289,13 +318,18 @@ func (s *Schema) CreateField(sf reflect.StructField) (*Field, error) {
 //		- optional: Tag fs="optional".
 //		- sortable: Tag fs="sortable".
 //		- filterable: Tag fs="filterable".
+//		- searchable: Tag fs="searchable".
 //		- default: Tag fs="default=10", if field is time, use RFC3339 format.
+//		- validate: Tag fs="validate=min_length:3,max_length:100,email", the rules are separated by comma.
+//		  Supported rules: min_length:n, max_length:n, min:n, max:n, pattern:regex, email, url.
+//		  The pattern can be quoted to contain commas: fs="validate=pattern:'^[a-z]{3,5}$'".
 //	Complex properties format:
 //	- E.g: `fs.enums="[{'value': 'v1', 'label': 'L1'}, {'value': 'v2', 'label': 'L2'}]"`
 //	- E.g: `fs.relation="{'type': 'o2m', 'schema': 'post', 'field': 'categories', 'owner': true}"`
 //		- enums: Only for string fields. Tag fs.enums=hjson -> []*FieldEnum
 //		- relation: Tag fs.relation=hjson -> *Relation
 //		- db: Tag fs.db=hjson -> *FieldDB
+//		- validation: Tag fs.validation=hjson -> *FieldValidation, e.g. for patterns containing "," or "=".
 //
 // To extend other field properties, you can implement the CustomizableSchema interface.
 func (s *Schema) ExtendFieldByTag(sf reflect.StructField, field *Field) error {
@@ -333,6 +367,8 @@ func (s *Schema) ExtendFieldByTag(sf reflect.StructField, field *Field) error {
 			field.Sortable = true
 		case "filterable":
 			field.Filterable = true
+		case "searchable":
+			field.Searchable = true
 		case "default":
 			// only set the default value if the field type is primitive type
 			if field.Type.IsAtomic() {
@@ -343,6 +379,12 @@ func (s *Schema) ExtendFieldByTag(sf reflect.StructField, field *Field) error {
 
 				field.Default = value
 			}
+		case "validate":
+			validation, err := parseValidationTag(value)
+			if err != nil {
+				return fmt.Errorf("%s.%s.%s=%s: %w", s.Name, field.Name, key, value, err)
+			}
+			field.Validation = validation
 
 		// label_field is a special field that is used to display the label of the schema content.
 		// if the label field name is available, then set the schema label field.
@@ -403,5 +445,18 @@ func (s *Schema) ExtendFieldByTag(sf reflect.StructField, field *Field) error {
 		field.Getter = getterTag
 	}
 
+	// Customize validation property
+	validationTag := sf.Tag.Get("fs.validation")
+	if validationTag != "" {
+		validation, err := utils.ParseHJSON[*FieldValidation]([]byte(validationTag))
+		if err != nil {
+			return fmt.Errorf("%s.%s=%s: invalid validation format: %w", s.Name, field.Name, validationTag, err)
+		}
+
+		if validation != nil {
+			field.Validation = validation
+		}
+	}
+
 	return nil
 }
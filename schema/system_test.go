@@ -54,7 +54,7 @@ func TestCreateSchemaErrorNonStruct(t *testing.T) {
 
 func TestCreateSchemaFieldTagCommon(t *testing.T) {
 	type Category struct {
-		Name string `json:"title" fs:"label=Category Name;multiple;unique;optional;sortable;filterable;size=255"`
+		Name string `json:"title" fs:"label=Category Name;multiple;unique;optional;sortable;filterable;searchable;size=255"`
 		Slug string `json:"slug" fs:"label_field"`
 	}
 
@@ -74,6 +74,7 @@ func TestCreateSchemaFieldTagCommon(t *testing.T) {
 			Optional:      true,
 			Sortable:      true,
 			Filterable:    true,
+			Searchable:    true,
 			Size:          255,
 		},
 		{
@@ -237,6 +238,56 @@ func TestCreateSchemaFieldTagDB(t *testing.T) {
 	assert.Equal(t, expectedFields, ss.Fields)
 }
 
+func TestCreateSchemaFieldTagValidation(t *testing.T) {
+	// Case 1: Invalid rule
+	type Category1 struct {
+		Name string `json:"name" fs:"validate=required"`
+	}
+
+	ss, err := schema.CreateSchema(Category1{})
+	assert.Nil(t, ss)
+	assert.Contains(t, err.Error(), `category_1.name.validate=required: unknown validation rule "required"`)
+
+	// Case 2: Invalid validation format
+	type Category2 struct {
+		Name string `json:"name" fs.validation:"invalid"`
+	}
+
+	ss, err = schema.CreateSchema(Category2{})
+	assert.Nil(t, ss)
+	assert.Contains(t, err.Error(), "invalid validation format")
+
+	// Case 3: Rules not supported by the field type
+	type Category3 struct {
+		Name  string `json:"name"`
+		Views int    `json:"views" fs:"validate=email"`
+	}
+
+	ss, err = schema.CreateSchema(Category3{})
+	assert.Nil(t, ss)
+	assert.Contains(t, err.Error(), "field.validation.invalid")
+
+	// Case 4: Success
+	type Category4 struct {
+		Name  string `json:"name" fs:"validate=min_length:3,max_length:50"`
+		Slug  string `json:"slug" fs.validation:"{'pattern': '^[a-z0-9]+(?:-[a-z0-9]+)*$', 'message': 'Invalid slug'}"`
+		Views int    `json:"views" fs:"validate=min:0"`
+	}
+
+	ss, err = schema.CreateSchema(Category4{})
+	assert.NoError(t, err)
+	minLength, maxLength, minViews := 3, 50, float64(0)
+	assert.Equal(t, &schema.FieldValidation{MinLength: &minLength, MaxLength: &maxLength}, ss.Field("name").Validation)
+	assert.Equal(t, &schema.FieldValidation{Min: &minViews}, ss.Field("views").Validation)
+	assert.Equal(t, "^[a-z0-9]+(?:-[a-z0-9]+)*$", ss.Field("slug").Validation.Pattern)
+	assert.Equal(t, "Invalid slug", ss.Field("slug").Validation.Message)
+
+	fieldError := ss.Field("slug").ValidateValue("Hello World")
+	assert.Equal(t, schema.CodeFieldValuePattern, fieldError.Code)
+	assert.Equal(t, "Invalid slug", fieldError.Message)
+	assert.Nil(t, ss.Field("slug").ValidateValue("hello-world"))
+}
+
 func TestCreateSchemaFieldTagDefault(t *testing.T) {
 	// Case 1: Default valid value
 	type Category struct {
@@ -478,7 +529,7 @@ func TestCreateSchemaCustomizeSchemaUseTag(t *testing.T) {
 
 	// Case 2: Success
 	type Category struct {
-		_    any    `json:"-" fs:"name=cat;namespace=cats;label_field=slug;disable_timestamp;is_junction_schema" fs.db:"{'indexes':[{'name': 'idx_name_slug','unique':true,'columns':['name','slug']}]}"`
+		_    any    `json:"-" fs:"name=cat;namespace=cats;label_field=slug;disable_timestamp;optimistic_lock;is_junction_schema" fs.db:"{'indexes':[{'name': 'idx_name_slug','unique':true,'columns':['name','slug']}]}"`
 		Name string `json:"name"`
 		Slug string `json:"slug"`
 	}
@@ -489,6 +540,7 @@ func TestCreateSchemaCustomizeSchemaUseTag(t *testing.T) {
 	assert.Equal(t, "cats", ss.Namespace)
 	assert.Equal(t, "slug", ss.LabelFieldName)
 	assert.True(t, ss.DisableTimestamp)
+	assert.True(t, ss.OptimisticLock)
 	assert.True(t, ss.IsJunctionSchema)
 	assert.Equal(t, &schema.SchemaDB{
 		Indexes: []*schema.SchemaDBIndex{
@@ -0,0 +1,351 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+)
+
+// FieldValidation contains the rules that the values of a field must pass on create and update.
+//
+//   - min_length, max_length, pattern, email, url: Only for string and text fields.
+//   - min, max: Only for numeric fields.
+//   - message: A custom message that replaces the default messages of all the rules.
+type FieldValidation struct {
+	MinLength *int     `json:"min_length,omitempty"`
+	MaxLength *int     `json:"max_length,omitempty"`
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	Email     bool     `json:"email,omitempty"`
+	URL       bool     `json:"url,omitempty"`
+	Message   string   `json:"message,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// Clone returns a copy of the validation rules.
+func (v *FieldValidation) Clone() *FieldValidation {
+	if v == nil {
+		return nil
+	}
+
+	clone := *v
+	clone.MinLength = clonePointer(v.MinLength)
+	clone.MaxLength = clonePointer(v.MaxLength)
+	clone.Min = clonePointer(v.Min)
+	clone.Max = clonePointer(v.Max)
+
+	return &clone
+}
+
+// compile compiles the pattern of the rules.
+func (v *FieldValidation) compile() (err error) {
+	v.pattern = nil
+	if v.Pattern == "" {
+		return nil
+	}
+
+	if v.pattern, err = regexp.Compile(v.Pattern); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", v.Pattern, err)
+	}
+
+	return nil
+}
+
+// check returns an error if the rules are not valid for the field.
+func (v *FieldValidation) check(f *Field) error {
+	isString := f.Type == TypeString || f.Type == TypeText
+	if !isString && (v.MinLength != nil || v.MaxLength != nil || v.Pattern != "" || v.Email || v.URL) {
+		return fmt.Errorf("min_length, max_length, pattern, email and url are only supported for string and text fields, got '%s'", f.Type)
+	}
+
+	if !f.Type.IsNumeric() && (v.Min != nil || v.Max != nil) {
+		return fmt.Errorf("min and max are only supported for numeric fields, got '%s'", f.Type)
+	}
+
+	if (v.MinLength != nil && *v.MinLength < 0) || (v.MaxLength != nil && *v.MaxLength < 0) {
+		return errors.New("min_length and max_length must not be negative")
+	}
+
+	if v.MinLength != nil && v.MaxLength != nil && *v.MinLength > *v.MaxLength {
+		return errors.New("min_length must not be greater than max_length")
+	}
+
+	if v.Min != nil && v.Max != nil && *v.Min > *v.Max {
+		return errors.New("min must not be greater than max")
+	}
+
+	return v.compile()
+}
+
+// ValidateValue checks the value against the validation rules of the field.
+// Nil values are skipped, the optional attribute of the field is checked by the database.
+// Each item of a multiple field value is checked separately.
+func (f *Field) ValidateValue(value any) *FieldError {
+	if f.Validation == nil || value == nil {
+		return nil
+	}
+
+	if values, ok := value.([]any); ok {
+		for _, v := range values {
+			if err := f.ValidateValue(v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	rules := f.Validation
+	if str, ok := value.(string); ok {
+		length := utf8.RuneCountInString(str)
+		if rules.MinLength != nil && length < *rules.MinLength {
+			return FieldValueInvalid(CodeFieldValueMinLength, f.Name, rules, "must be at least %d characters", *rules.MinLength)
+		}
+
+		if rules.MaxLength != nil && length > *rules.MaxLength {
+			return FieldValueInvalid(CodeFieldValueMaxLength, f.Name, rules, "must be at most %d characters", *rules.MaxLength)
+		}
+
+		if rules.Pattern != "" {
+			// The pattern is compiled in Field.Init, the rules that are not initialized are compiled on the fly.
+			pattern := rules.pattern
+			if pattern == nil {
+				var err error
+				if pattern, err = regexp.Compile(rules.Pattern); err != nil {
+					return FieldValidationInvalid(f.Name, err.Error())
+				}
+			}
+
+			if !pattern.MatchString(str) {
+				return FieldValueInvalid(CodeFieldValuePattern, f.Name, rules, "must match the pattern %s", rules.Pattern)
+			}
+		}
+
+		if rules.Email && !utils.IsValidEmail(str) {
+			return FieldValueInvalid(CodeFieldValueEmail, f.Name, rules, "must be a valid email address")
+		}
+
+		if rules.URL && !isValidURL(str) {
+			return FieldValueInvalid(CodeFieldValueURL, f.Name, rules, "must be a valid URL")
+		}
+	}
+
+	if number, ok := toFloat(value); ok {
+		if rules.Min != nil && number < *rules.Min {
+			return FieldValueInvalid(CodeFieldValueMin, f.Name, rules, "must be greater than or equal to %v", *rules.Min)
+		}
+
+		if rules.Max != nil && number > *rules.Max {
+			return FieldValueInvalid(CodeFieldValueMax, f.Name, rules, "must be less than or equal to %v", *rules.Max)
+		}
+	}
+
+	return nil
+}
+
+// ValidateEntity checks the entity values against the validation rules of the schema fields.
+// Only the fields present in the entity are checked, so it is used for both create and update.
+// The values of the update block $set are checked too, the values of the update block $add
+// depend on the existing records and are checked by ValidateAddedValues, the other blocks are skipped.
+// Returns a *SchemaErrors containing an error for each invalid field, or nil.
+func (s *Schema) ValidateEntity(e *entity.Entity) error {
+	if e == nil {
+		return nil
+	}
+
+	errs := &SchemaErrors{Schema: s.Name}
+	s.validateEntityValues(e, errs)
+
+	if setBlock, ok := e.Get("$set").(*entity.Entity); ok {
+		s.validateEntityValues(setBlock, errs)
+	}
+
+	if errs.HasErrors() {
+		return errs
+	}
+
+	return nil
+}
+
+// HasValidatedAddFields reports whether the update block $add of the entity
+// adds to fields that have validation rules, their values are checked by ValidateAddedValues.
+func (s *Schema) HasValidatedAddFields(e *entity.Entity) bool {
+	return len(s.validatedAddFields(e)) > 0
+}
+
+// ValidateAddedValues checks the values that result from the update block $add
+// against the validation rules of the fields. The values of $add are added to the values of old,
+// the existing record, a missing value is added as zero.
+// Returns a *SchemaErrors containing an error for each invalid field, or nil.
+func (s *Schema) ValidateAddedValues(e, old *entity.Entity) error {
+	errs := &SchemaErrors{Schema: s.Name}
+	addBlock, _ := e.Get("$add").(*entity.Entity)
+	for _, field := range s.validatedAddFields(e) {
+		var oldValue any
+		if old != nil {
+			oldValue = old.Get(field.Name)
+		}
+
+		// The values that are not numbers are rejected when the update is built
+		sum, ok := addNumbers(oldValue, addBlock.Get(field.Name))
+		if !ok {
+			continue
+		}
+
+		errs.Add(field.ValidateValue(sum))
+	}
+
+	if errs.HasErrors() {
+		return errs
+	}
+
+	return nil
+}
+
+// validatedAddFields returns the fields of the update block $add that have validation rules.
+func (s *Schema) validatedAddFields(e *entity.Entity) []*Field {
+	addBlock, ok := e.Get("$add").(*entity.Entity)
+	if !ok {
+		return nil
+	}
+
+	fields := []*Field{}
+	for pair := addBlock.First(); pair != nil; pair = pair.Next() {
+		field := s.Field(pair.Key)
+		if field == nil || field.Validation == nil || field.Relation != nil {
+			continue
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+func (s *Schema) validateEntityValues(e *entity.Entity, errs *SchemaErrors) {
+	for pair := e.First(); pair != nil; pair = pair.Next() {
+		if strings.HasPrefix(pair.Key, "$") {
+			continue
+		}
+
+		field := s.Field(pair.Key)
+		if field == nil || field.Validation == nil {
+			continue
+		}
+
+		errs.Add(field.ValidateValue(pair.Value))
+	}
+}
+
+// parseValidationTag parses the validate property of the fs struct tag.
+// The pattern can be quoted with single quotes to contain commas.
+//
+//	E.g: `fs:"validate=min_length:3,max_length:100,pattern:'^[a-z]{3,5}$',email,url,min:1,max:10"`
+func parseValidationTag(tag string) (*FieldValidation, error) {
+	validationRules, err := splitValidationRules(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := &FieldValidation{}
+	for _, rule := range validationRules {
+		name, value, _ := strings.Cut(strings.TrimSpace(rule), ":")
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+
+		switch name {
+		case "":
+			continue
+		case "email":
+			rules.Email = true
+		case "url":
+			rules.URL = true
+		case "pattern":
+			if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+				value = value[1 : len(value)-1]
+			}
+
+			rules.Pattern = value
+		case "min_length", "max_length":
+			length, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value %q", name, value)
+			}
+
+			rules.MinLength = utils.If(name == "min_length", &length, rules.MinLength)
+			rules.MaxLength = utils.If(name == "max_length", &length, rules.MaxLength)
+		case "min", "max":
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value %q", name, value)
+			}
+
+			rules.Min = utils.If(name == "min", &number, rules.Min)
+			rules.Max = utils.If(name == "max", &number, rules.Max)
+		default:
+			return nil, fmt.Errorf("unknown validation rule %q", name)
+		}
+	}
+
+	return rules, nil
+}
+
+// splitValidationRules splits the rules of the validate tag by the commas that are not quoted.
+func splitValidationRules(tag string) ([]string, error) {
+	rules := []string{}
+	quoted := false
+	start := 0
+	for i, char := range tag {
+		switch {
+		case char == '\'':
+			quoted = !quoted
+		case char == ',' && !quoted:
+			rules = append(rules, tag[start:i])
+			start = i + 1
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", tag)
+	}
+
+	return append(rules, tag[start:]), nil
+}
+
+func clonePointer[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+
+	clone := *v
+	return &clone
+}
+
+func isValidURL(value string) bool {
+	u, err := url.ParseRequestURI(value)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+func toFloat(value any) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
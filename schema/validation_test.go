@@ -0,0 +1,325 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(v int) *int { return &v }
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestFieldValidationCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		field      *Field
+		errMessage string
+	}{
+		{
+			name: "valid_string_rules",
+			field: &Field{Type: TypeString, Name: "slug", Validation: &FieldValidation{
+				MinLength: intPtr(1), MaxLength: intPtr(10), Pattern: "^[a-z-]+$",
+			}},
+		},
+		{
+			name:  "valid_number_rules",
+			field: &Field{Type: TypeFloat64, Name: "price", Validation: &FieldValidation{Min: floatPtr(0), Max: floatPtr(10)}},
+		},
+		{
+			name:       "length_on_number",
+			field:      &Field{Type: TypeInt, Name: "views", Validation: &FieldValidation{MaxLength: intPtr(10)}},
+			errMessage: "only supported for string and text fields, got 'int'",
+		},
+		{
+			name:       "email_on_bool",
+			field:      &Field{Type: TypeBool, Name: "active", Validation: &FieldValidation{Email: true}},
+			errMessage: "only supported for string and text fields, got 'bool'",
+		},
+		{
+			name:       "min_on_string",
+			field:      &Field{Type: TypeString, Name: "name", Validation: &FieldValidation{Min: floatPtr(1)}},
+			errMessage: "min and max are only supported for numeric fields, got 'string'",
+		},
+		{
+			name:       "negative_length",
+			field:      &Field{Type: TypeString, Name: "name", Validation: &FieldValidation{MinLength: intPtr(-1)}},
+			errMessage: "min_length and max_length must not be negative",
+		},
+		{
+			name:       "min_length_greater_than_max_length",
+			field:      &Field{Type: TypeText, Name: "name", Validation: &FieldValidation{MinLength: intPtr(5), MaxLength: intPtr(1)}},
+			errMessage: "min_length must not be greater than max_length",
+		},
+		{
+			name:       "min_greater_than_max",
+			field:      &Field{Type: TypeInt, Name: "views", Validation: &FieldValidation{Min: floatPtr(5), Max: floatPtr(1)}},
+			errMessage: "min must not be greater than max",
+		},
+		{
+			name:       "invalid_pattern",
+			field:      &Field{Type: TypeString, Name: "slug", Validation: &FieldValidation{Pattern: "[a-z"}},
+			errMessage: `invalid pattern "[a-z"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.field.Init()
+			if tt.errMessage == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var fieldError *FieldError
+			require.ErrorAs(t, err, &fieldError)
+			assert.Equal(t, CodeFieldValidationInvalid, fieldError.Code)
+			assert.Equal(t, tt.field.Name, fieldError.Field)
+			assert.Contains(t, fieldError.Message, tt.errMessage)
+		})
+	}
+}
+
+func TestFieldValidateValue(t *testing.T) {
+	stringField := &Field{Type: TypeString, Name: "name", Validation: &FieldValidation{
+		MinLength: intPtr(2),
+		MaxLength: intPtr(5),
+		Pattern:   "^[a-zà-ỹ@.]+$",
+	}}
+	require.NoError(t, stringField.Init())
+
+	tests := []struct {
+		name    string
+		field   *Field
+		value   any
+		code    string
+		message string
+	}{
+		{name: "no_rules", field: &Field{Type: TypeString, Name: "name"}, value: ""},
+		{name: "nil_value", field: stringField, value: nil},
+		{name: "valid_string", field: stringField, value: "abc"},
+		{name: "length_counts_runes", field: stringField, value: "àáâãè"},
+		{name: "min_length", field: stringField, value: "a", code: CodeFieldValueMinLength, message: "must be at least 2 characters"},
+		{name: "max_length", field: stringField, value: "abcdef", code: CodeFieldValueMaxLength, message: "must be at most 5 characters"},
+		{name: "pattern", field: stringField, value: "ABC", code: CodeFieldValuePattern, message: "must match the pattern ^[a-zà-ỹ@.]+$"},
+		{name: "multiple_values", field: stringField, value: []any{"ab", "a"}, code: CodeFieldValueMinLength},
+		{
+			name:  "valid_email",
+			field: &Field{Type: TypeString, Name: "email", Validation: &FieldValidation{Email: true}},
+			value: "john@example.com",
+		},
+		{
+			name:    "invalid_email",
+			field:   &Field{Type: TypeString, Name: "email", Validation: &FieldValidation{Email: true}},
+			value:   "john@",
+			code:    CodeFieldValueEmail,
+			message: "must be a valid email address",
+		},
+		{
+			name:  "valid_url",
+			field: &Field{Type: TypeString, Name: "website", Validation: &FieldValidation{URL: true}},
+			value: "https://fastschema.com/docs?q=1",
+		},
+		{
+			name:    "invalid_url",
+			field:   &Field{Type: TypeString, Name: "website", Validation: &FieldValidation{URL: true}},
+			value:   "fastschema.com",
+			code:    CodeFieldValueURL,
+			message: "must be a valid URL",
+		},
+		{
+			name:  "valid_number",
+			field: &Field{Type: TypeInt, Name: "views", Validation: &FieldValidation{Min: floatPtr(0), Max: floatPtr(10)}},
+			value: 10,
+		},
+		{
+			name:    "min",
+			field:   &Field{Type: TypeInt, Name: "views", Validation: &FieldValidation{Min: floatPtr(0)}},
+			value:   int64(-1),
+			code:    CodeFieldValueMin,
+			message: "must be greater than or equal to 0",
+		},
+		{
+			name:    "max",
+			field:   &Field{Type: TypeFloat64, Name: "price", Validation: &FieldValidation{Max: floatPtr(9.5)}},
+			value:   9.75,
+			code:    CodeFieldValueMax,
+			message: "must be less than or equal to 9.5",
+		},
+		{
+			name:    "custom_message",
+			field:   &Field{Type: TypeUint, Name: "age", Validation: &FieldValidation{Min: floatPtr(18), Message: "You must be an adult"}},
+			value:   uint(17),
+			code:    CodeFieldValueMin,
+			message: "You must be an adult",
+		},
+		{
+			name:    "uninitialized_pattern",
+			field:   &Field{Type: TypeString, Name: "slug", Validation: &FieldValidation{Pattern: "^[a-z]+$"}},
+			value:   "a1",
+			code:    CodeFieldValuePattern,
+			message: "must match the pattern ^[a-z]+$",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.field.ValidateValue(tt.value)
+			if tt.code == "" {
+				assert.Nil(t, err)
+				return
+			}
+
+			require.NotNil(t, err)
+			assert.Equal(t, tt.code, err.Code)
+			assert.Equal(t, tt.field.Name, err.Field)
+			if tt.message != "" {
+				assert.Equal(t, tt.message, err.Message)
+			}
+		})
+	}
+}
+
+func TestSchemaValidateEntity(t *testing.T) {
+	s := &Schema{
+		Name:           "post",
+		Namespace:      "posts",
+		LabelFieldName: "title",
+		Fields: []*Field{
+			{Type: TypeString, Name: "title", Validation: &FieldValidation{MinLength: intPtr(3)}},
+			{Type: TypeInt, Name: "rating", Validation: &FieldValidation{Min: floatPtr(1), Max: floatPtr(5)}},
+			{Type: TypeString, Name: "body"},
+		},
+	}
+	require.NoError(t, s.Init(false))
+
+	assert.NoError(t, s.ValidateEntity(nil))
+	assert.NoError(t, s.ValidateEntity(entity.New().Set("title", "Hello").Set("rating", 5).Set("body", "")))
+
+	// Only the fields present in the entity are checked
+	assert.NoError(t, s.ValidateEntity(entity.New().Set("rating", 1)))
+
+	err := s.ValidateEntity(entity.New().Set("title", "Hi").Set("rating", 6).Set("unknown", ""))
+	var schemaErrors *SchemaErrors
+	require.ErrorAs(t, err, &schemaErrors)
+	assert.Equal(t, "post", schemaErrors.Schema)
+	assert.Equal(t, []*FieldError{
+		{Code: CodeFieldValueMinLength, Field: "title", Message: "must be at least 3 characters"},
+		{Code: CodeFieldValueMax, Field: "rating", Message: "must be less than or equal to 5"},
+	}, schemaErrors.FieldErrors)
+
+	// The $set block is checked, the other update blocks are skipped
+	err = s.ValidateEntity(entity.New().
+		Set("$set", entity.New().Set("rating", 0)).
+		Set("$expr", entity.New().Set("rating", "rating + 10")))
+	require.ErrorAs(t, err, &schemaErrors)
+	assert.True(t, schemaErrors.HasCode(CodeFieldValueMin))
+	assert.Len(t, schemaErrors.FieldErrors, 1)
+}
+
+func TestSchemaValidateAddedValues(t *testing.T) {
+	s := &Schema{
+		Name:           "post",
+		Namespace:      "posts",
+		LabelFieldName: "title",
+		Fields: []*Field{
+			{Type: TypeString, Name: "title"},
+			{Type: TypeInt, Name: "rating", Validation: &FieldValidation{Min: floatPtr(1), Max: floatPtr(5)}},
+			{Type: TypeInt, Name: "views"},
+		},
+	}
+	require.NoError(t, s.Init(false))
+
+	// Only the added fields with validation rules are checked
+	assert.False(t, s.HasValidatedAddFields(entity.New().Set("rating", 10)))
+	assert.False(t, s.HasValidatedAddFields(entity.New().Set("$add", entity.New().Set("views", 10))))
+	assert.True(t, s.HasValidatedAddFields(entity.New().Set("$add", entity.New().Set("rating", 1))))
+
+	old := entity.New().Set("rating", 3).Set("views", 10)
+	assert.NoError(t, s.ValidateAddedValues(entity.New().Set("$add", entity.New().Set("rating", 2).Set("views", 100)), old))
+	assert.NoError(t, s.ValidateAddedValues(entity.New().Set("rating", 10), old))
+
+	// The added values are added to the existing values
+	var schemaErrors *SchemaErrors
+	err := s.ValidateAddedValues(entity.New().Set("$add", entity.New().Set("rating", 1000000)), old)
+	require.ErrorAs(t, err, &schemaErrors)
+	assert.Equal(t, []*FieldError{
+		{Code: CodeFieldValueMax, Field: "rating", Message: "must be less than or equal to 5"},
+	}, schemaErrors.FieldErrors)
+
+	err = s.ValidateAddedValues(entity.New().Set("$add", entity.New().Set("rating", -2.5)), old)
+	require.ErrorAs(t, err, &schemaErrors)
+	assert.True(t, schemaErrors.HasCode(CodeFieldValueMin))
+
+	// A missing existing value is added as zero
+	err = s.ValidateAddedValues(entity.New().Set("$add", entity.New().Set("rating", 6)), entity.New())
+	require.ErrorAs(t, err, &schemaErrors)
+	assert.True(t, schemaErrors.HasCode(CodeFieldValueMax))
+}
+
+func TestSchemaValidateValidationRules(t *testing.T) {
+	s := &Schema{
+		Name:           "post",
+		Namespace:      "posts",
+		LabelFieldName: "title",
+		Fields: []*Field{
+			{Type: TypeString, Name: "title", Validation: &FieldValidation{Min: floatPtr(1)}},
+		},
+	}
+
+	var schemaErrors *SchemaErrors
+	require.ErrorAs(t, s.Validate(), &schemaErrors)
+	assert.True(t, schemaErrors.HasCode(CodeFieldValidationInvalid))
+}
+
+func TestFieldValidationCloneAndMerge(t *testing.T) {
+	field := &Field{Type: TypeString, Name: "name", Validation: &FieldValidation{
+		MinLength: intPtr(1),
+		Pattern:   "^[a-z]+$",
+		Message:   "invalid name",
+	}}
+	require.NoError(t, field.Init())
+
+	clone := field.Clone()
+	assert.Equal(t, field.Validation, clone.Validation)
+	*clone.Validation.MinLength = 2
+	assert.Equal(t, 1, *field.Validation.MinLength)
+	assert.Nil(t, (&Field{}).Clone().Validation)
+
+	merged := &Field{Type: TypeString, Name: "name"}
+	MergeFields(merged, field)
+	assert.Equal(t, field.Validation, merged.Validation)
+	assert.NotSame(t, field.Validation, merged.Validation)
+}
+
+func TestParseValidationTag(t *testing.T) {
+	rules := utils.Must(parseValidationTag("min_length:3, max_length:100,pattern:^[a-z:]+$,email,url,min:-1.5,max:10,"))
+	assert.Equal(t, &FieldValidation{
+		MinLength: intPtr(3),
+		MaxLength: intPtr(100),
+		Min:       floatPtr(-1.5),
+		Max:       floatPtr(10),
+		Pattern:   "^[a-z:]+$",
+		Email:     true,
+		URL:       true,
+	}, rules)
+
+	// The quoted pattern can contain commas
+	rules = utils.Must(parseValidationTag("pattern:'^[a-z]{3,5}$', max_length:5"))
+	assert.Equal(t, &FieldValidation{MaxLength: intPtr(5), Pattern: "^[a-z]{3,5}$"}, rules)
+
+	_, err := parseValidationTag("pattern:'^[a-z]{3,5}$")
+	assert.ErrorContains(t, err, `unterminated quote in "pattern:'^[a-z]{3,5}$"`)
+
+	_, err = parseValidationTag("min_length:abc")
+	assert.ErrorContains(t, err, `invalid min_length value "abc"`)
+
+	_, err = parseValidationTag("max:abc")
+	assert.ErrorContains(t, err, `invalid max value "abc"`)
+
+	_, err = parseValidationTag("required")
+	assert.ErrorContains(t, err, `unknown validation rule "required"`)
+}
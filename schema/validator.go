@@ -0,0 +1,242 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/expr"
+)
+
+// SchemaValidator is a cross-field validation rule of a schema.
+// The expression is evaluated against the merged old and new values of the entity on create and update,
+// the entity is invalid if the expression returns false.
+//
+//	E.g: {"expr": "$args.Data.end_date > $args.Data.start_date", "message": "End date must be after start date", "fields": ["end_date"]}
+type SchemaValidator struct {
+	Expr    string            `json:"expr"`              // validator expression, must return a boolean.
+	Message string            `json:"message,omitempty"` // error message when the validation fails.
+	Fields  []string          `json:"fields,omitempty"`  // fields that the error is attributed to.
+	program *ValidatorProgram `json:"-"`                 // Compiled validator program
+}
+
+// ValidatorArgs holds the arguments of the validator expressions.
+//
+//   - Schema: The schema of the entity.
+//   - Entity: The merged entity, the values of the existing record are overwritten by the new values.
+//   - Old: The existing record on update, nil on create.
+//   - Data: The values of the merged entity, e.g. $args.Data.end_date.
+type ValidatorArgs struct {
+	Schema *Schema
+	Entity *entity.Entity
+	Old    *entity.Entity
+	Data   map[string]any
+}
+
+type ValidatorProgram = expr.Program[ValidatorArgs, bool]
+
+// Clone returns a copy of the validator.
+func (v *SchemaValidator) Clone() *SchemaValidator {
+	if v == nil {
+		return nil
+	}
+
+	clone := *v
+	if v.Fields != nil {
+		clone.Fields = append([]string{}, v.Fields...)
+	}
+
+	return &clone
+}
+
+// compileValidators compiles the validator expressions of the schema.
+func (s *Schema) compileValidators() []*FieldError {
+	var fieldErrors []*FieldError
+	for i, validator := range s.Validators {
+		if validator == nil || strings.TrimSpace(validator.Expr) == "" {
+			fieldErrors = append(fieldErrors, SchemaValidatorCompileError(i, errors.New("expression is required")))
+			continue
+		}
+
+		program, err := expr.Compile[ValidatorArgs, bool](validator.Expr)
+		if err != nil {
+			fieldErrors = append(fieldErrors, SchemaValidatorCompileError(i, err))
+			continue
+		}
+
+		validator.program = program
+	}
+
+	return fieldErrors
+}
+
+// ApplyValidators runs the validators of the schema against the entity.
+// old is the existing record on update and nil on create,
+// its values are overwritten by the values of the entity and the update block $set.
+// The fields of the update block $clear are set to nil and the values of the update block $add
+// are added to the existing values. The fields of the update block $expr are computed by the database
+// and cannot be validated, so they are rejected.
+// Returns a *SchemaErrors containing an error for each failed validator and attributed field, or nil.
+func (s *Schema) ApplyValidators(ctx context.Context, e, old *entity.Entity, configs ...expr.Config) error {
+	if len(s.Validators) == 0 || e == nil {
+		return nil
+	}
+
+	merged, err := s.mergeValidatorEntity(e, old)
+	if err != nil {
+		return err
+	}
+
+	args := ValidatorArgs{
+		Schema: s,
+		Entity: merged,
+		Old:    old,
+		Data:   merged.ToMap(),
+	}
+
+	errs := &SchemaErrors{Schema: s.Name}
+	for i, validator := range s.Validators {
+		// The validators are compiled in Schema.Validate, the validators that are not compiled are compiled on the fly.
+		program := validator.program
+		if program == nil {
+			var err error
+			if program, err = expr.Compile[ValidatorArgs, bool](validator.Expr); err != nil {
+				return SchemaValidatorCompileError(i, err)
+			}
+
+			if program == nil {
+				continue
+			}
+		}
+
+		result, err := program.Run(ctx, args, configs...)
+		if err != nil {
+			return fmt.Errorf("failed to run validator %d of schema %s: %w", i, s.Name, err)
+		}
+
+		if valid, err := result.Value(); err == nil && valid {
+			continue
+		}
+
+		message := validator.Message
+		if message == "" {
+			message = fmt.Sprintf("validator %q failed", validator.Expr)
+		}
+
+		if len(validator.Fields) == 0 {
+			errs.Add(SchemaValidatorFailed("", message))
+			continue
+		}
+
+		for _, fieldName := range validator.Fields {
+			errs.Add(SchemaValidatorFailed(fieldName, message))
+		}
+	}
+
+	if errs.HasErrors() {
+		return errs
+	}
+
+	return nil
+}
+
+// mergeValidatorEntity merges the values of the entity into the existing record.
+// The string values of the time fields are converted to time.Time so they can be compared with the existing values.
+func (s *Schema) mergeValidatorEntity(e, old *entity.Entity) (*entity.Entity, error) {
+	merged := entity.New()
+	if old != nil {
+		for pair := old.First(); pair != nil; pair = pair.Next() {
+			merged.Set(pair.Key, pair.Value)
+		}
+	}
+
+	setValues := func(source *entity.Entity) {
+		for pair := source.First(); pair != nil; pair = pair.Next() {
+			if strings.HasPrefix(pair.Key, "$") {
+				continue
+			}
+
+			value := pair.Value
+			field := s.Field(pair.Key)
+			if strValue, ok := value.(string); ok && field != nil && field.Type == TypeTime {
+				if timeValue, err := StringToFieldValue[any](field, strValue); err == nil {
+					value = timeValue
+				}
+			}
+
+			merged.Set(pair.Key, value)
+		}
+	}
+
+	setValues(e)
+	if setBlock, ok := e.Get("$set").(*entity.Entity); ok {
+		setValues(setBlock)
+	}
+
+	if clearBlock, ok := e.Get("$clear").(*entity.Entity); ok {
+		for pair := clearBlock.First(); pair != nil; pair = pair.Next() {
+			if field := s.Field(pair.Key); field != nil && field.Relation == nil {
+				merged.Set(pair.Key, nil)
+			}
+		}
+	}
+
+	if addBlock, ok := e.Get("$add").(*entity.Entity); ok {
+		for pair := addBlock.First(); pair != nil; pair = pair.Next() {
+			if field := s.Field(pair.Key); field == nil || field.Relation != nil {
+				continue
+			}
+
+			sum, ok := addNumbers(merged.Get(pair.Key), pair.Value)
+			if !ok {
+				return nil, fmt.Errorf("field $add.%s=%v is not a number", pair.Key, pair.Value)
+			}
+
+			merged.Set(pair.Key, sum)
+		}
+	}
+
+	if exprBlock, ok := e.Get("$expr").(*entity.Entity); ok && !exprBlock.Empty() {
+		errs := &SchemaErrors{Schema: s.Name}
+		for _, fieldName := range exprBlock.Keys() {
+			errs.Add(SchemaValidatorFailed(fieldName, "the value of $expr cannot be validated by the schema validators"))
+		}
+
+		return nil, errs
+	}
+
+	return merged, nil
+}
+
+// addNumbers returns the sum of the numbers, a nil value is added as zero.
+// The sum is an int64 if both numbers are integers, otherwise a float64.
+func addNumbers(a, b any) (any, bool) {
+	if a == nil {
+		a = 0
+	}
+
+	x, xok := toFloat(a)
+	y, yok := toFloat(b)
+	if !xok || !yok {
+		return nil, false
+	}
+
+	if isInteger(a) && isInteger(b) {
+		return int64(x) + int64(y), true
+	}
+
+	return x + y, true
+}
+
+func isInteger(value any) bool {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,212 @@
+package schema
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createValidatorSchema(t *testing.T, validators ...*SchemaValidator) *Schema {
+	s := &Schema{
+		Name:           "event",
+		Namespace:      "events",
+		LabelFieldName: "name",
+		Fields: []*Field{
+			{Type: TypeString, Name: "name"},
+			{Type: TypeTime, Name: "start_date", Optional: true},
+			{Type: TypeTime, Name: "end_date", Optional: true},
+			{Type: TypeFloat64, Name: "price"},
+			{Type: TypeFloat64, Name: "discount", Optional: true},
+		},
+		Validators: validators,
+	}
+	require.NoError(t, s.Init(false))
+	return s
+}
+
+func TestSchemaValidatorsCompile(t *testing.T) {
+	s := &Schema{
+		Name:           "event",
+		Namespace:      "events",
+		LabelFieldName: "name",
+		Fields:         []*Field{{Type: TypeString, Name: "name"}},
+		Validators: []*SchemaValidator{
+			{Expr: "$args.Data.name != ''"},
+			{Expr: "((invalid"},
+			{Expr: " "},
+			nil,
+		},
+	}
+
+	var schemaErrors *SchemaErrors
+	require.ErrorAs(t, s.Validate(), &schemaErrors)
+	compileErrors := schemaErrors.ByCode(CodeSchemaValidatorCompileError)
+	require.Len(t, compileErrors, 3)
+	assert.Contains(t, compileErrors[0].Message, "validator at index 1 failed to compile")
+	assert.Equal(t, "validator at index 2 failed to compile: expression is required", compileErrors[1].Message)
+	assert.Equal(t, "validator at index 3 failed to compile: expression is required", compileErrors[2].Message)
+	assert.NotNil(t, s.Validators[0].program)
+}
+
+func TestSchemaApplyValidatorsCreate(t *testing.T) {
+	s := createValidatorSchema(t,
+		&SchemaValidator{
+			Expr:    "$args.Data.end_date == nil || $args.Data.end_date > $args.Data.start_date",
+			Message: "The end date must be after the start date",
+			Fields:  []string{"end_date"},
+		},
+		&SchemaValidator{
+			Expr:   "($args.Data.discount ?? 0) <= $args.Data.price",
+			Fields: []string{"discount", "price"},
+		},
+		&SchemaValidator{Expr: "$args.Old == nil || $args.Data.name == $args.Old.Get('name')"},
+	)
+	ctx := context.Background()
+
+	assert.NoError(t, s.ApplyValidators(ctx, nil, nil))
+	assert.NoError(t, s.ApplyValidators(ctx, entity.New().
+		Set("name", "launch").
+		Set("start_date", "2024-05-01T00:00:00Z").
+		Set("end_date", "2024-05-02T00:00:00Z").
+		Set("price", 10.0).
+		Set("discount", 10), nil))
+	assert.NoError(t, s.ApplyValidators(ctx, entity.New().Set("name", "launch").Set("price", 5), nil))
+
+	err := s.ApplyValidators(ctx, entity.New().
+		Set("name", "launch").
+		Set("start_date", "2024-05-02T00:00:00Z").
+		Set("end_date", "2024-05-01T00:00:00Z").
+		Set("price", 10.0).
+		Set("discount", 20.0), nil)
+	var schemaErrors *SchemaErrors
+	require.ErrorAs(t, err, &schemaErrors)
+	assert.Equal(t, "event", schemaErrors.Schema)
+	assert.Equal(t, []*FieldError{
+		{Code: CodeSchemaValidatorFailed, Field: "end_date", Message: "The end date must be after the start date"},
+		{Code: CodeSchemaValidatorFailed, Field: "discount", Message: `validator "($args.Data.discount ?? 0) <= $args.Data.price" failed`},
+		{Code: CodeSchemaValidatorFailed, Field: "price", Message: `validator "($args.Data.discount ?? 0) <= $args.Data.price" failed`},
+	}, schemaErrors.FieldErrors)
+}
+
+func TestSchemaApplyValidatorsUpdate(t *testing.T) {
+	s := createValidatorSchema(t,
+		&SchemaValidator{
+			Expr:    "($args.Data.discount ?? 0) <= $args.Data.price",
+			Message: "The discount must not be greater than the price",
+			Fields:  []string{"discount"},
+		},
+		&SchemaValidator{
+			Expr:    "$args.Data.end_date == nil || $args.Data.end_date > $args.Data.start_date",
+			Message: "The end date must be after the start date",
+		},
+	)
+	ctx := context.Background()
+	old := entity.New().
+		Set("id", uint64(1)).
+		Set("name", "launch").
+		Set("start_date", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)).
+		Set("end_date", time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)).
+		Set("price", 10.0).
+		Set("discount", 5.0)
+
+	// The new values are merged into the existing record
+	assert.NoError(t, s.ApplyValidators(ctx, entity.New().Set("price", 8.0), old))
+	assert.NoError(t, s.ApplyValidators(ctx, entity.New().Set("end_date", "2024-05-02T00:00:00Z"), old))
+
+	err := s.ApplyValidators(ctx, entity.New().Set("price", 4.0), old)
+	var schemaErrors *SchemaErrors
+	require.ErrorAs(t, err, &schemaErrors)
+	assert.Equal(t, []*FieldError{
+		{Code: CodeSchemaValidatorFailed, Field: "discount", Message: "The discount must not be greater than the price"},
+	}, schemaErrors.FieldErrors)
+
+	// The time strings are compared with the existing time values
+	err = s.ApplyValidators(ctx, entity.New().Set("$set", entity.New().Set("end_date", "2024-04-30T00:00:00Z")), old)
+	require.ErrorAs(t, err, &schemaErrors)
+	assert.Equal(t, []*FieldError{
+		{Code: CodeSchemaValidatorFailed, Message: "The end date must be after the start date"},
+	}, schemaErrors.FieldErrors)
+
+	// The cleared fields are set to nil
+	assert.NoError(t, s.ApplyValidators(ctx, entity.New().
+		Set("price", 1.0).
+		Set("$clear", entity.New().Set("discount", true)), old))
+
+	// The added values are added to the existing values
+	assert.NoError(t, s.ApplyValidators(ctx, entity.New().Set("$add", entity.New().Set("discount", 5)), old))
+	err = s.ApplyValidators(ctx, entity.New().Set("$add", entity.New().Set("discount", 5.5)), old)
+	require.ErrorAs(t, err, &schemaErrors)
+	assert.Equal(t, []*FieldError{
+		{Code: CodeSchemaValidatorFailed, Field: "discount", Message: "The discount must not be greater than the price"},
+	}, schemaErrors.FieldErrors)
+	assert.ErrorContains(t, s.ApplyValidators(ctx, entity.New().Set("$add", entity.New().Set("discount", "5")), old),
+		"field $add.discount=5 is not a number")
+
+	// The values computed by the database cannot be validated
+	err = s.ApplyValidators(ctx, entity.New().Set("$expr", entity.New().Set("discount", "price + 1")), old)
+	require.ErrorAs(t, err, &schemaErrors)
+	assert.Equal(t, []*FieldError{{
+		Code:    CodeSchemaValidatorFailed,
+		Field:   "discount",
+		Message: "the value of $expr cannot be validated by the schema validators",
+	}}, schemaErrors.FieldErrors)
+
+	// The existing record is not changed
+	assert.Equal(t, 10.0, old.Get("price"))
+	assert.Equal(t, 5.0, old.Get("discount"))
+}
+
+func TestSchemaApplyValidatorsError(t *testing.T) {
+	ctx := context.Background()
+
+	// Runtime error
+	s := createValidatorSchema(t, &SchemaValidator{Expr: "$args.Data.price > $args.Data.name"})
+	err := s.ApplyValidators(ctx, entity.New().Set("price", 1.0).Set("name", "launch"), nil)
+	assert.ErrorContains(t, err, "failed to run validator 0 of schema event")
+
+	// The validators that are not compiled are compiled on the fly
+	s = &Schema{Name: "event", Validators: []*SchemaValidator{{Expr: "false"}, {Expr: ""}}}
+	err = s.ApplyValidators(ctx, entity.New(), nil)
+	var schemaErrors *SchemaErrors
+	require.ErrorAs(t, err, &schemaErrors)
+	assert.Len(t, schemaErrors.FieldErrors, 1)
+
+	s = &Schema{Name: "event", Validators: []*SchemaValidator{{Expr: "((invalid"}}}
+	err = s.ApplyValidators(ctx, entity.New(), nil)
+	var fieldError *FieldError
+	require.ErrorAs(t, err, &fieldError)
+	assert.Equal(t, CodeSchemaValidatorCompileError, fieldError.Code)
+}
+
+func TestSchemaValidatorsJSONCloneAndMerge(t *testing.T) {
+	s := utils.Must(NewSchemaFromJSON(`{
+		"name": "event",
+		"namespace": "events",
+		"label_field": "name",
+		"fields": [{"type": "string", "name": "name", "label": "Name"}],
+		"validators": [{"expr": "$args.Data.name != ''", "message": "The name is required", "fields": ["name"]}]
+	}`))
+	require.NoError(t, s.Init(false))
+	assert.Equal(t, []*SchemaValidator{{
+		Expr:    "$args.Data.name != ''",
+		Message: "The name is required",
+		Fields:  []string{"name"},
+		program: s.Validators[0].program,
+	}}, s.Validators)
+
+	clone := s.Clone()
+	assert.Equal(t, s.Validators, clone.Validators)
+	clone.Validators[0].Fields[0] = "title"
+	assert.Equal(t, "name", s.Validators[0].Fields[0])
+
+	target := &Schema{Name: "event"}
+	MergeSchemas(target, s)
+	assert.Equal(t, s.Validators, target.Validators)
+	assert.NotSame(t, s.Validators[0], target.Validators[0])
+	assert.Nil(t, (*SchemaValidator)(nil).Clone())
+}
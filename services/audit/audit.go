@@ -0,0 +1,85 @@
+package auditservice
+
+import (
+	"math"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/errors"
+)
+
+type AppLike interface {
+	DB() db.Client
+	Config() *fs.Config
+}
+
+type AuditService struct {
+	DB        func() db.Client
+	AppConfig func() *fs.Config
+}
+
+func New(app AppLike) *AuditService {
+	return &AuditService{
+		DB:        app.DB,
+		AppConfig: app.Config,
+	}
+}
+
+func (s *AuditService) CreateResource(api *fs.Resource) {
+	api.Group("audit").
+		Add(fs.NewResource("list", s.List, &fs.Meta{Get: "/"}))
+}
+
+// Pagination contains the pagination info and the audit logs of a page.
+type Pagination struct {
+	Total       uint           `json:"total"`
+	PerPage     uint           `json:"per_page"`
+	CurrentPage uint           `json:"current_page"`
+	LastPage    uint           `json:"last_page"`
+	Items       []*fs.AuditLog `json:"items"`
+}
+
+// List returns the audit logs, the newest first.
+//
+//	The logs are filtered with the filter object of the content list, for example:
+//		filter={"schema":"post","record_id":"1","created_at":{"$gte":"2024-01-01"}}
+func (s *AuditService) List(c fs.Context, _ any) (*Pagination, error) {
+	model, err := s.DB().Model("audit_log")
+	if err != nil {
+		return nil, errors.InternalServerError(err.Error())
+	}
+
+	predicates, err := db.CreatePredicatesFromFilterObject(
+		s.DB().SchemaBuilder(),
+		model.Schema(),
+		c.Arg("filter", ""),
+	)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	total, err := db.Builder[*fs.AuditLog](s.DB()).Where(predicates...).Count(c)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	page := uint(max(c.ArgInt("page", 1), 1))
+	limit := uint(max(c.ArgInt("limit", 10), 1))
+	logs, err := db.Builder[*fs.AuditLog](s.DB()).
+		Where(predicates...).
+		Limit(limit).
+		Offset((page-1)*limit).
+		Order(c.Arg("sort", "-created_at"), "-id").
+		Get(c)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	return &Pagination{
+		Total:       uint(total),
+		PerPage:     limit,
+		CurrentPage: page,
+		LastPage:    uint(math.Ceil(float64(total) / float64(limit))),
+		Items:       logs,
+	}, nil
+}
@@ -0,0 +1,229 @@
+package auditservice_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/logger"
+	"github.com/fastschema/fastschema/pkg/entdbadapter"
+	"github.com/fastschema/fastschema/pkg/restfulresolver"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	auditservice "github.com/fastschema/fastschema/services/audit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testApp struct {
+	db     db.Client
+	config *fs.Config
+}
+
+func (a *testApp) DB() db.Client {
+	return a.db
+}
+
+func (a *testApp) Config() *fs.Config {
+	return a.config
+}
+
+func createTestApp(t *testing.T, config *fs.Config) (*testApp, *auditservice.AuditService) {
+	schemaDir := t.TempDir()
+	utils.WriteFile(schemaDir+"/post.json", `{
+		"name": "post",
+		"namespace": "posts",
+		"label_field": "title",
+		"audit": true,
+		"fields": [
+			{ "type": "string", "name": "title", "label": "Title" },
+			{ "type": "string", "name": "password", "label": "Password", "optional": true },
+			{ "type": "string", "name": "token", "label": "Token", "optional": true }
+		]
+	}`)
+	utils.WriteFile(schemaDir+"/note.json", `{
+		"name": "note",
+		"namespace": "notes",
+		"label_field": "title",
+		"fields": [{ "type": "string", "name": "title", "label": "Title" }]
+	}`)
+
+	app := &testApp{config: config}
+	auditService := auditservice.New(app)
+	sb := utils.Must(schema.NewBuilderFromDir(schemaDir, fs.SystemSchemaTypes...))
+	app.db = utils.Must(entdbadapter.NewTestClient(
+		utils.Must(os.MkdirTemp("", "migrations")),
+		sb,
+		func() *db.Hooks {
+			return &db.Hooks{
+				PostDBCreate: []db.PostDBCreate{auditService.CreateHook},
+				PostDBUpdate: []db.PostDBUpdate{auditService.UpdateHook},
+				PostDBDelete: []db.PostDBDelete{auditService.DeleteHook},
+			}
+		},
+	))
+
+	return app, auditService
+}
+
+func TestAuditHooks(t *testing.T) {
+	app, _ := createTestApp(t, &fs.Config{})
+	user := &fs.User{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001"), Username: "editor"}
+	ctx := context.WithValue(context.Background(), "user", user)
+	ctx = context.WithValue(ctx, fs.ContextKeyTraceID, "trace-1")
+	postModel := utils.Must(app.DB().Model("post"))
+	noteModel := utils.Must(app.DB().Model("note"))
+
+	postID := utils.Must(postModel.Mutation().Create(ctx, entity.New().
+		Set("title", "Hello").
+		Set("password", "secret")))
+	_ = utils.Must(noteModel.Mutation().Create(ctx, entity.New().Set("title", "Note")))
+	_ = utils.Must(postModel.Mutation().Where(db.EQ("id", postID)).Update(context.Background(), entity.New().
+		Set("title", "Hello world").
+		Set("password", "changed")))
+	_ = utils.Must(postModel.Mutation().Where(db.EQ("id", postID)).Update(ctx, entity.New().
+		Set("title", "Hello world")))
+	_ = utils.Must(postModel.Mutation().Where(db.EQ("id", postID)).Delete(ctx))
+
+	logs := utils.Must(db.Builder[*fs.AuditLog](app.DB()).Order("created_at", "id").Get(context.Background()))
+	require.Len(t, logs, 3)
+	recordID := fmt.Sprint(postID)
+
+	assert.Equal(t, fs.AuditActionCreate, logs[0].Action)
+	assert.Equal(t, "post", logs[0].Schema)
+	assert.Equal(t, recordID, logs[0].RecordID)
+	assert.Equal(t, user.ID, *logs[0].UserID)
+	assert.Equal(t, "editor", logs[0].Username)
+	assert.Equal(t, "trace-1", logs[0].TraceID)
+	assert.Equal(t, &fs.AuditChange{After: "Hello"}, logs[0].Changes["title"])
+	assert.Equal(t, &fs.AuditChange{After: fs.AuditMaskedValue}, logs[0].Changes["password"])
+	assert.Nil(t, logs[0].Changes["token"])
+
+	assert.Equal(t, fs.AuditActionUpdate, logs[1].Action)
+	assert.Equal(t, "", logs[1].Username)
+	assert.Equal(t, "", logs[1].TraceID)
+	assert.Equal(t, map[string]*fs.AuditChange{
+		"title":    {Before: "Hello", After: "Hello world"},
+		"password": {Before: fs.AuditMaskedValue, After: fs.AuditMaskedValue},
+	}, logs[1].Changes)
+
+	assert.Equal(t, fs.AuditActionDelete, logs[2].Action)
+	assert.Equal(t, recordID, logs[2].RecordID)
+	assert.Equal(t, &fs.AuditChange{Before: "Hello world"}, logs[2].Changes["title"])
+	assert.Equal(t, &fs.AuditChange{Before: fs.AuditMaskedValue}, logs[2].Changes["password"])
+}
+
+func TestAuditHooksTx(t *testing.T) {
+	app, _ := createTestApp(t, &fs.Config{})
+	ctx := context.Background()
+	postID := utils.Must(utils.Must(app.DB().Model("post")).Mutation().Create(ctx, entity.New().Set("title", "Hello")))
+	update := func(tx db.Client, title string) error {
+		postModel := utils.Must(tx.Model("post"))
+		_, err := postModel.Mutation().Where(db.EQ("id", postID)).Update(ctx, entity.New().Set("title", title))
+		return err
+	}
+
+	// The audit logs are rolled back with the transaction of the mutation
+	err := db.WithTx(app.DB(), ctx, func(tx db.Client) error {
+		require.NoError(t, update(tx, "Rolled back"))
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, utils.Must(db.Builder[*fs.AuditLog](app.DB()).Count(ctx)))
+
+	require.NoError(t, db.WithTx(app.DB(), ctx, func(tx db.Client) error {
+		return update(tx, "Committed")
+	}))
+	logs := utils.Must(db.Builder[*fs.AuditLog](app.DB()).Order("id").Get(ctx))
+	require.Len(t, logs, 2)
+	assert.Equal(t, map[string]*fs.AuditChange{
+		"title": {Before: "Hello", After: "Committed"},
+	}, logs[1].Changes)
+}
+
+func TestAuditMaskFields(t *testing.T) {
+	app, _ := createTestApp(t, &fs.Config{AuditConfig: &fs.AuditConfig{MaskFields: []string{"token"}}})
+	postModel := utils.Must(app.DB().Model("post"))
+	_ = utils.Must(postModel.Mutation().Create(context.Background(), entity.New().
+		Set("title", "Hello").
+		Set("password", "secret").
+		Set("token", "abc")))
+
+	log := utils.Must(db.Builder[*fs.AuditLog](app.DB()).First(context.Background()))
+	assert.Equal(t, &fs.AuditChange{After: "secret"}, log.Changes["password"])
+	assert.Equal(t, &fs.AuditChange{After: fs.AuditMaskedValue}, log.Changes["token"])
+}
+
+func TestAuditServiceList(t *testing.T) {
+	app, auditService := createTestApp(t, nil)
+	postModel := utils.Must(app.DB().Model("post"))
+	for _, title := range []string{"First", "Second", "Third"} {
+		_ = utils.Must(postModel.Mutation().Create(context.Background(), entity.New().Set("title", title)))
+	}
+	_ = utils.Must(postModel.Mutation().Where(db.EQ("title", "Second")).Delete(context.Background()))
+
+	resources := fs.NewResourcesManager()
+	api := resources.Group("api")
+	auditService.CreateResource(api)
+	assert.NoError(t, resources.Init())
+	assert.False(t, api.Find("api.audit.list").IsPublic())
+	server := restfulresolver.NewRestfulResolver(&restfulresolver.ResolverConfig{
+		ResourceManager: resources,
+		Logger:          logger.CreateMockLogger(true),
+	}).Server()
+
+	tests := []struct {
+		name         string
+		query        url.Values
+		expectStatus int
+		expectBody   []string
+	}{
+		{
+			name:         "invalid filter",
+			query:        url.Values{"filter": {`{"invalid": 1}`}},
+			expectStatus: 400,
+			expectBody:   []string{`invalid`},
+		},
+		{
+			name:         "all",
+			query:        url.Values{"limit": {"2"}},
+			expectStatus: 200,
+			expectBody: []string{
+				`"total":4`,
+				`"per_page":2`,
+				`"last_page":2`,
+				`"action":"delete"`,
+				`"title":{"before":"Second","after":null}`,
+			},
+		},
+		{
+			name:         "filter",
+			query:        url.Values{"filter": {`{"schema":"post","action":"create"}`}, "sort": {"created_at"}},
+			expectStatus: 200,
+			expectBody: []string{
+				`"total":3`,
+				`"changes":{"title":{"before":null,"after":"First"}}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/audit?"+tt.query.Encode(), nil)
+			resp := utils.Must(server.Test(req))
+			defer func() { assert.NoError(t, resp.Body.Close()) }()
+			response := utils.Must(utils.ReadCloserToString(resp.Body))
+			assert.Equal(t, tt.expectStatus, resp.StatusCode, response)
+			for _, expectBody := range tt.expectBody {
+				assert.Contains(t, response, expectBody)
+			}
+		})
+	}
+}
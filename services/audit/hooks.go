@@ -0,0 +1,234 @@
+package auditservice
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+)
+
+// CreateHook records the created record of an audited schema.
+func (s *AuditService) CreateHook(
+	ctx context.Context,
+	schema *schema.Schema,
+	dataCreate *entity.Entity,
+	id any,
+) error {
+	if !schema.Audit {
+		return nil
+	}
+
+	changes := map[string]*fs.AuditChange{}
+	for _, field := range auditedFields(schema) {
+		if value, ok := dataCreate.Data().Get(field.Name); ok && value != nil {
+			changes[field.Name] = &fs.AuditChange{After: value}
+		}
+	}
+
+	return s.record(ctx, schema, fs.AuditActionCreate, []any{id}, []map[string]*fs.AuditChange{changes})
+}
+
+// UpdateHook records the changed fields of the updated records of an audited schema.
+//
+//	The values after the update are read from the database in the transaction of the mutation,
+//	so that the values of the expressions and the generated values are recorded.
+//	The records without changed fields are not recorded.
+func (s *AuditService) UpdateHook(
+	ctx context.Context,
+	schema *schema.Schema,
+	predicates *[]*db.Predicate,
+	updateData *entity.Entity,
+	originalEntities []*entity.Entity,
+	affected int,
+) error {
+	if !schema.Audit || len(originalEntities) == 0 {
+		return nil
+	}
+
+	model, err := db.TxFromContext(ctx, s.DB()).Model(schema.Name)
+	if err != nil {
+		return err
+	}
+
+	// The updated records are read as stored, like the original records
+	pkName := schema.PrimaryKeyName()
+	ids := utils.Map(originalEntities, func(e *entity.Entity) any {
+		return e.Get(pkName)
+	})
+	updatedEntities, err := model.Query(db.In(pkName, ids)).Get(db.WithRawRead(db.WithPrimary(ctx)))
+	if err != nil {
+		return err
+	}
+
+	recordIDs := []any{}
+	recordChanges := []map[string]*fs.AuditChange{}
+	for _, original := range originalEntities {
+		updatedIndex := slices.IndexFunc(updatedEntities, func(e *entity.Entity) bool {
+			return fmt.Sprint(e.Get(pkName)) == fmt.Sprint(original.Get(pkName))
+		})
+		if updatedIndex < 0 {
+			continue
+		}
+
+		updated := updatedEntities[updatedIndex]
+		changes := map[string]*fs.AuditChange{}
+		for _, field := range auditedFields(schema) {
+			if field.Name == entity.FieldUpdatedAt {
+				continue
+			}
+
+			before, after := original.Get(field.Name), updated.Get(field.Name)
+			if !reflect.DeepEqual(before, after) {
+				changes[field.Name] = &fs.AuditChange{Before: before, After: after}
+			}
+		}
+
+		if len(changes) > 0 {
+			recordIDs = append(recordIDs, original.Get(pkName))
+			recordChanges = append(recordChanges, changes)
+		}
+	}
+
+	return s.record(ctx, schema, fs.AuditActionUpdate, recordIDs, recordChanges)
+}
+
+// DeleteHook records the deleted records of an audited schema.
+func (s *AuditService) DeleteHook(
+	ctx context.Context,
+	schema *schema.Schema,
+	predicates *[]*db.Predicate,
+	originalEntities []*entity.Entity,
+	affected int,
+) error {
+	if !schema.Audit || len(originalEntities) == 0 {
+		return nil
+	}
+
+	pkName := schema.PrimaryKeyName()
+	recordIDs := make([]any, 0, len(originalEntities))
+	recordChanges := make([]map[string]*fs.AuditChange, 0, len(originalEntities))
+	for _, original := range originalEntities {
+		changes := map[string]*fs.AuditChange{}
+		for _, field := range auditedFields(schema) {
+			if value := original.Get(field.Name); value != nil {
+				changes[field.Name] = &fs.AuditChange{Before: value}
+			}
+		}
+
+		recordIDs = append(recordIDs, original.Get(pkName))
+		recordChanges = append(recordChanges, changes)
+	}
+
+	return s.record(ctx, schema, fs.AuditActionDelete, recordIDs, recordChanges)
+}
+
+// record creates the audit logs of the records with the user and the trace ID of the context.
+// The audit logs are written in the transaction of the mutation, so that they are rolled back with it.
+func (s *AuditService) record(
+	ctx context.Context,
+	schema *schema.Schema,
+	action string,
+	recordIDs []any,
+	recordChanges []map[string]*fs.AuditChange,
+) error {
+	if len(recordIDs) == 0 {
+		return nil
+	}
+
+	model, err := db.TxFromContext(ctx, s.DB()).Model("audit_log")
+	if err != nil {
+		return err
+	}
+
+	var auditConfig *fs.AuditConfig
+	if config := s.AppConfig(); config != nil {
+		auditConfig = config.AuditConfig
+	}
+
+	maskFields := auditConfig.GetMaskFields()
+
+	user := contextUser(ctx)
+	traceID := contextTraceID(ctx)
+	now := time.Now()
+	logs := make([]*entity.Entity, 0, len(recordIDs))
+	for i, recordID := range recordIDs {
+		changes := recordChanges[i]
+		for name, change := range changes {
+			if !slices.Contains(maskFields, name) {
+				continue
+			}
+
+			if change.Before != nil {
+				change.Before = fs.AuditMaskedValue
+			}
+
+			if change.After != nil {
+				change.After = fs.AuditMaskedValue
+			}
+		}
+
+		log := entity.New().
+			Set("action", action).
+			Set("schema", schema.Name).
+			Set("record_id", fmt.Sprint(recordID)).
+			Set("trace_id", traceID).
+			Set("changes", changes).
+			Set("created_at", now)
+
+		if user != nil {
+			log.Set("user_id", user.ID).Set("username", user.Username)
+		}
+
+		logs = append(logs, log)
+	}
+
+	if _, err := model.Mutation().CreateMany(ctx, logs); err != nil {
+		return fmt.Errorf("audit %s %s: %w", action, schema.Name, err)
+	}
+
+	return nil
+}
+
+// auditedFields returns the fields of the schema that are stored in the schema table,
+// except the primary key which is the record ID of the audit logs.
+func auditedFields(s *schema.Schema) []*schema.Field {
+	pkName := s.PrimaryKeyName()
+	return utils.Filter(s.Fields, func(f *schema.Field) bool {
+		return !f.Type.IsRelationType() && f.Name != pkName
+	})
+}
+
+// contextUser returns the user of the request that runs the mutation.
+func contextUser(ctx context.Context) *fs.User {
+	if c, ok := ctx.(interface{ User() *fs.User }); ok {
+		return c.User()
+	}
+
+	user, _ := ctx.Value("user").(*fs.User)
+	return user
+}
+
+// contextTraceID returns the trace ID of the request that runs the mutation.
+func contextTraceID(ctx context.Context) string {
+	if traceable, ok := ctx.(fs.Traceable); ok {
+		return traceable.TraceID()
+	}
+
+	if traceID := ctx.Value(fs.ContextKeyTraceID); traceID != nil {
+		return fmt.Sprint(traceID)
+	}
+
+	// The request locals are exposed as context values when the request context is wrapped
+	if traceID := ctx.Value(fs.TraceID); traceID != nil {
+		return fmt.Sprint(traceID)
+	}
+
+	return ""
+}
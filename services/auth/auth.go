@@ -138,11 +138,13 @@ func (as *AuthService) CreateResource(api *fs.Resource, authProviders map[string
 }
 
 func (as *AuthService) AuthUserCan(c fs.Context, user *fs.User, resourceID string) bool {
-	exprConfig := expr.Config{
-		DB: func() expr.DBLike {
-			return as.DB()
-		},
-	}
+	return len(as.AuthUserPermissions(c, user, resourceID)) > 0
+}
+
+// AuthUserPermissions returns the permissions of the user roles that allow access to the resource.
+func (as *AuthService) AuthUserPermissions(c fs.Context, user *fs.User, resourceID string) []*fs.Permission {
+	permissions := []*fs.Permission{}
+	exprConfig := as.exprConfig()
 
 	// Check for all user roles for this action.
 	// If any role has permission value allow, then allow.
@@ -158,13 +160,21 @@ func (as *AuthService) AuthUserCan(c fs.Context, user *fs.User, resourceID strin
 		}
 
 		if err := permission.Check(c, exprConfig); err == nil {
-			return true
+			permissions = append(permissions, permission)
 		} else {
 			c.Logger().Error(err)
 		}
 	}
 
-	return false
+	return permissions
+}
+
+func (as *AuthService) exprConfig() expr.Config {
+	return expr.Config{
+		DB: func() expr.DBLike {
+			return as.DB()
+		},
+	}
 }
 
 func (as *AuthService) GetRolesFromIDs(ids []uuid.UUID) []*fs.Role {
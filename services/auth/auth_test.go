@@ -143,6 +143,35 @@ func createTestApp(t *testing.T) *testApp {
 			}
 		]
 	}`)
+	utils.WriteFile(schemaDir+"/post.json", `{
+		"name": "post",
+		"namespace": "posts",
+		"label_field": "title",
+		"fields": [
+			{ "type": "string", "name": "title", "label": "Title" },
+			{
+				"type": "relation",
+				"name": "author",
+				"label": "Author",
+				"optional": true,
+				"relation": { "schema": "user", "field": "posts", "type": "o2m", "optional": true }
+			}
+		]
+	}`)
+	utils.WriteFile(schemaDir+"/user.json", `{
+		"name": "user",
+		"namespace": "users",
+		"label_field": "username",
+		"fields": [
+			{
+				"type": "relation",
+				"name": "posts",
+				"label": "Posts",
+				"optional": true,
+				"relation": { "schema": "post", "field": "author", "type": "o2m", "owner": true }
+			}
+		]
+	}`)
 	migrationDir := utils.Must(os.MkdirTemp("", "migrations"))
 	sb := utils.Must(schema.NewBuilderFromDir(schemaDir, fs.SystemSchemaTypes...))
 	dbc := utils.Must(entdbadapter.NewTestClient(migrationDir, sb))
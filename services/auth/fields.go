@@ -0,0 +1,90 @@
+package authservice
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/errors"
+	"github.com/fastschema/fastschema/schema"
+)
+
+// FieldsQueryHook rejects the queries that select, filter, sort, group or aggregate
+// the fields that the request is not allowed to read.
+// The queries that do not select any field of the schema are limited to the readable fields,
+// so the fields that are not readable are not loaded.
+func (as *AuthService) FieldsQueryHook(ctx context.Context, option *db.QueryOption) error {
+	if option == nil || option.Schema == nil {
+		return nil
+	}
+
+	fieldPermissions := fs.FieldPermissionsFromContext(ctx, option.Schema.Name)
+	if fieldPermissions == nil {
+		return nil
+	}
+
+	fields := append([]string{option.Column}, option.GroupBy...)
+	for _, order := range option.Order {
+		fields = append(fields, strings.TrimPrefix(order, "-"))
+	}
+
+	for _, aggregation := range option.Aggregations {
+		if aggregation != nil {
+			fields = append(fields, aggregation.Field)
+		}
+	}
+
+	fields = append(fields, db.PredicateFields(option.Having)...)
+	if option.Predicates != nil {
+		fields = append(fields, db.PredicateFields(*option.Predicates)...)
+	}
+
+	if option.Columns != nil {
+		fields = append(fields, *option.Columns...)
+	}
+
+	if field := fieldPermissions.UnreadableField(option.Schema, fields...); field != "" {
+		return errors.Forbidden("field %s is not readable", field)
+	}
+
+	if option.Columns != nil && len(option.Aggregations) == 0 {
+		*option.Columns = selectReadableFields(fieldPermissions, option.Schema, *option.Columns)
+	}
+
+	return nil
+}
+
+// selectReadableFields adds the readable fields of the schema to the selected columns
+// if no field other than the relations is selected and some fields are not readable.
+func selectReadableFields(fieldPermissions *fs.FieldPermissions, s *schema.Schema, columns []string) []string {
+	if slices.ContainsFunc(columns, func(column string) bool {
+		name, _, _ := strings.Cut(column, ".")
+		field := s.Field(name)
+		return field != nil && !field.Type.IsRelationType()
+	}) {
+		return columns
+	}
+
+	readableFields := []string{}
+	hasUnreadableFields := false
+	for _, field := range s.Fields {
+		if field.Type.IsRelationType() {
+			continue
+		}
+
+		if fieldPermissions.UnreadableField(s, field.Name) != "" {
+			hasUnreadableFields = true
+			continue
+		}
+
+		readableFields = append(readableFields, field.Name)
+	}
+
+	if !hasUnreadableFields {
+		return columns
+	}
+
+	return append(columns, readableFields...)
+}
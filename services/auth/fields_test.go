@@ -0,0 +1,129 @@
+package authservice_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/logger"
+	rr "github.com/fastschema/fastschema/pkg/restfulresolver"
+	"github.com/fastschema/fastschema/pkg/utils"
+	as "github.com/fastschema/fastschema/services/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldsQueryHook(t *testing.T) {
+	testApp := createTestApp(t)
+	authService := as.New(testApp)
+	blogSchema := utils.Must(testApp.sb.Schema("blog"))
+	userSchema := utils.Must(testApp.sb.Schema("user"))
+	permission := &fs.Permission{Value: "allow", Fields: `{"read": ["*", "-name"], "write": ["title"]}`}
+	require.NoError(t, permission.Compile())
+	ctx := context.WithValue(context.Background(), fs.FieldPermissionsKey, &fs.FieldPermissions{
+		Schema:      "blog",
+		Permissions: []*fs.Permission{permission},
+	})
+
+	// The readable fields are selected if no field is selected
+	columns := []string{}
+	require.NoError(t, authService.FieldsQueryHook(ctx, &db.QueryOption{Schema: blogSchema, Columns: &columns}))
+	assert.Equal(t, []string{"id", "created_at", "updated_at", "deleted_at"}, columns)
+
+	columns = []string{"id"}
+	require.NoError(t, authService.FieldsQueryHook(ctx, &db.QueryOption{Schema: blogSchema, Columns: &columns}))
+	assert.Equal(t, []string{"id"}, columns)
+
+	columns = []string{}
+	require.NoError(t, authService.FieldsQueryHook(ctx, &db.QueryOption{Schema: userSchema, Columns: &columns}))
+	assert.Empty(t, columns)
+
+	// The aliases, the primary key and the system fields are not checked
+	predicates := []*db.Predicate{db.Null("deleted_at", true), db.Or(db.EQ("id", 1))}
+	assert.NoError(t, authService.FieldsQueryHook(ctx, &db.QueryOption{
+		Schema:       blogSchema,
+		Predicates:   &predicates,
+		Order:        []string{"-count"},
+		Aggregations: []*db.Aggregation{db.AggCount("", "count")},
+		Having:       []*db.Predicate{db.GT("count", 1)},
+	}))
+	assert.NoError(t, authService.FieldsQueryHook(ctx, &db.QueryOption{Query: "SELECT name FROM blogs"}))
+
+	// The fields that are not readable are rejected
+	predicates = []*db.Predicate{db.And(db.EQ("name", "blog"))}
+	columns = []string{"name"}
+	for _, option := range []*db.QueryOption{
+		{Schema: blogSchema, Columns: &columns},
+		{Schema: blogSchema, Predicates: &predicates},
+		{Schema: blogSchema, Order: []string{"-name"}},
+		{Schema: blogSchema, Column: "name"},
+		{Schema: blogSchema, GroupBy: []string{"name"}},
+		{Schema: blogSchema, Aggregations: []*db.Aggregation{db.AggMax("name")}},
+	} {
+		assert.ErrorContains(t, authService.FieldsQueryHook(ctx, option), "field name is not readable")
+	}
+}
+
+func TestAuthorizeFieldPermissions(t *testing.T) {
+	testApp := createTestApp(t)
+	permissionModel := utils.Must(testApp.db.Model("permission"))
+	_ = utils.Must(permissionModel.Create(context.Background(), entity.New().
+		Set("resource", "api.content.blog.fields").
+		Set("value", fs.PermissionTypeAllow.String()).
+		Set("fields", `{"read": ["name"], "write": []}`).
+		Set("role_id", fs.RoleUser.ID),
+	))
+
+	authService := as.New(testApp)
+	resources := fs.NewResourcesManager()
+	resources.Hooks = func() *fs.Hooks {
+		return &fs.Hooks{PreResolve: []fs.Middleware{authService.Authorize}}
+	}
+	resources.Middlewares = append(resources.Middlewares, authService.ParseUser)
+	resources.Group("api", &fs.Meta{Prefix: "/api"}).Group("content").
+		Add(fs.NewResource("fields", func(c fs.Context, _ any) (*fs.Map, error) {
+			fieldPermissions := fs.FieldPermissionsFromContext(c, "blog")
+			if fieldPermissions == nil {
+				return nil, nil
+			}
+
+			return &fs.Map{
+				"read_name":   fieldPermissions.CanRead("name"),
+				"read_title":  fieldPermissions.CanRead("title"),
+				"write_name":  fieldPermissions.CanWrite("name"),
+				"write_title": fieldPermissions.CanWrite("title"),
+			}, nil
+		}, &fs.Meta{Get: "/:schema/fields"}))
+	assert.NoError(t, resources.Init())
+	server := rr.NewRestfulResolver(&rr.ResolverConfig{
+		ResourceManager: resources,
+		Logger:          logger.CreateMockLogger(false),
+	}).Server()
+
+	tests := []struct {
+		name    string
+		token   string
+		expects string
+	}{
+		{name: "root", token: testApp.adminToken, expects: `{"data":null}`},
+		{
+			name:    "fields",
+			token:   testApp.normalUserToken,
+			expects: `{"data":{"read_name":true,"read_title":false,"write_name":false,"write_title":false}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/content/blog/fields", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			resp := utils.Must(server.Test(req))
+			defer func() { assert.NoError(t, resp.Body.Close()) }()
+			assert.Equal(t, 200, resp.StatusCode)
+			assert.Equal(t, tt.expects, utils.Must(utils.ReadCloserToString(resp.Body)))
+		})
+	}
+}
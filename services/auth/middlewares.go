@@ -1,9 +1,12 @@
 package authservice
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/fastschema/fastschema/db"
 	"github.com/fastschema/fastschema/fs"
 	"github.com/fastschema/fastschema/pkg/errors"
 	"github.com/fastschema/fastschema/pkg/utils"
@@ -21,17 +24,86 @@ func (as *AuthService) ParseUser(c fs.Context) error {
 		},
 	)
 
+	var validToken *jwt.Token
 	if err == nil {
 		if claims, ok := jwtToken.Claims.(*fs.UserJwtClaims); ok && jwtToken.Valid {
 			user := claims.User
 			user.Roles = as.GetRolesFromIDs(user.RoleIDs)
 			c.Local("user", user)
+			validToken = jwtToken
 		}
 	}
 
+	tenantID, err := as.ResolveTenant(c, validToken)
+	if err != nil {
+		return err
+	}
+
+	if tenantID != "" {
+		c.Local(db.TenantKey, tenantID)
+	}
+
 	return c.Next()
 }
 
+// ResolveTenant returns the tenant of the request using the tenant config of the app.
+//
+//	The user is bound to the tenants of the tenant claim of the given access token if it is valid,
+//	the claim is either a tenant or the list of the tenants that the user is a member of.
+//	The tenant requested by the header or the subdomain is only honored if the user is bound to it,
+//	otherwise the request is rejected.
+//	Without a requested tenant, the tenant of the claim is used if the user is bound to a single tenant.
+func (as *AuthService) ResolveTenant(c fs.Context, token *jwt.Token) (string, error) {
+	config := as.AppConfig()
+	if config == nil || config.TenantConfig == nil {
+		return "", nil
+	}
+
+	tenantConfig := config.TenantConfig
+	tenantIDs := []string{}
+	if token != nil {
+		claims := jwt.MapClaims{}
+		if _, _, err := jwt.NewParser(jwt.WithJSONNumber()).ParseUnverified(token.Raw, claims); err == nil {
+			switch claim := claims[tenantConfig.GetClaim()].(type) {
+			case nil:
+			case []any:
+				for _, tenantID := range claim {
+					tenantIDs = append(tenantIDs, fmt.Sprintf("%v", tenantID))
+				}
+			default:
+				tenantIDs = append(tenantIDs, fmt.Sprintf("%v", claim))
+			}
+		}
+	}
+
+	requestedTenantID := ""
+	if tenantConfig.Header != "" {
+		requestedTenantID = strings.TrimSpace(c.Header(tenantConfig.Header))
+	}
+
+	if requestedTenantID == "" && tenantConfig.Subdomain != "" {
+		host, _, _ := strings.Cut(c.Header("Host"), ":")
+		subdomain, ok := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(tenantConfig.Subdomain))
+		if ok && subdomain != "" && !strings.Contains(subdomain, ".") {
+			requestedTenantID = subdomain
+		}
+	}
+
+	if requestedTenantID == "" {
+		if len(tenantIDs) == 1 {
+			return tenantIDs[0], nil
+		}
+
+		return "", nil
+	}
+
+	if !slices.Contains(tenantIDs, requestedTenantID) {
+		return "", errors.Forbidden("the user is not a member of the tenant %s", requestedTenantID)
+	}
+
+	return requestedTenantID, nil
+}
+
 func (as *AuthService) Authorize(c fs.Context) error {
 	resource := c.Resource()
 	if resource == nil {
@@ -78,8 +150,13 @@ func (as *AuthService) Authorize(c fs.Context) error {
 		return errors.Forbidden("User is inactive")
 	}
 
-	if as.AuthUserCan(c, user, resourceID) {
-		return nil
+	if permissions := as.AuthUserPermissions(c, user, resourceID); len(permissions) > 0 {
+		as.applyFieldPermissions(c, permissions)
+		if err := as.applyRowFilter(c, permissions); err != nil {
+			return err
+		}
+
+		return as.authorizeNestedWrites(c, user)
 	}
 
 	return utils.If(
@@ -88,3 +165,85 @@ func (as *AuthService) Authorize(c fs.Context) error {
 		errors.Unauthorized("Unauthorized"),
 	)
 }
+
+// applyRowFilter restricts the records of the requested content schema using the permission modifiers.
+// The modifiers are filter templates that are evaluated against the request:
+//
+//	{"owner_id": "$context.User().ID"}
+//
+// The filters of the allowed permissions are combined with $or,
+// a permission without modifier grants access to all the records.
+func (as *AuthService) applyRowFilter(c fs.Context, permissions []*fs.Permission) error {
+	schemaName := contentSchemaName(c)
+	if schemaName == "" {
+		return nil
+	}
+
+	filters := []map[string]any{}
+	for _, permission := range permissions {
+		if !permission.HasModifier() {
+			return nil
+		}
+
+		filter, err := permission.Filter(c, as.exprConfig())
+		if err != nil {
+			c.Logger().Error(err)
+			continue
+		}
+
+		filters = append(filters, filter)
+	}
+
+	if len(filters) == 0 {
+		return errors.Forbidden("Forbidden")
+	}
+
+	sb := as.DB().SchemaBuilder()
+	s, err := sb.Schema(schemaName)
+	if err != nil {
+		return errors.NotFound(err.Error())
+	}
+
+	filterObject := any(filters[0])
+	if len(filters) > 1 {
+		filterObject = map[string]any{"$or": filters}
+	}
+
+	filterJSON, err := json.Marshal(filterObject)
+	if err != nil {
+		return errors.InternalServerError(err.Error())
+	}
+
+	predicates, err := db.CreatePredicatesFromFilterObject(sb, s, string(filterJSON))
+	if err != nil {
+		return errors.InternalServerError(err.Error())
+	}
+
+	c.Local(db.RowFilterKey, &db.RowFilter{Schema: s.Name, Predicates: predicates})
+	return nil
+}
+
+// applyFieldPermissions restricts the fields of the requested content schema
+// that can be read and written using the fields of the permissions.
+func (as *AuthService) applyFieldPermissions(c fs.Context, permissions []*fs.Permission) {
+	schemaName := contentSchemaName(c)
+	if schemaName == "" || !slices.ContainsFunc(permissions, (*fs.Permission).HasFields) {
+		return
+	}
+
+	c.Local(fs.FieldPermissionsKey, &fs.FieldPermissions{
+		Schema:      schemaName,
+		Permissions: permissions,
+	})
+}
+
+// contentSchemaName returns the schema name of the content and realtime content resources,
+// or an empty string for the other resources.
+func contentSchemaName(c fs.Context) string {
+	resourceID := c.Resource().ID()
+	if !strings.HasPrefix(resourceID, "api.content.") && resourceID != "api.realtime.content" {
+		return ""
+	}
+
+	return c.Arg("schema")
+}
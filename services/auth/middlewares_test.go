@@ -2,10 +2,22 @@ package authservice_test
 
 import (
 	"context"
+	"fmt"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/logger"
+	"github.com/fastschema/fastschema/pkg/jwt"
+	rr "github.com/fastschema/fastschema/pkg/restfulresolver"
 	"github.com/fastschema/fastschema/pkg/utils"
+	as "github.com/fastschema/fastschema/services/auth"
+	jwtlib "github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -150,3 +162,328 @@ func TestRoleServiceAuth(t *testing.T) {
 		assert.Equal(t, 200, resp.StatusCode, "Seniority user should have access to content.blog.list")
 	})
 }
+
+type tenantTestApp struct {
+	*testApp
+	tenantConfig *fs.TenantConfig
+}
+
+func (s tenantTestApp) Config() *fs.Config {
+	config := s.testApp.Config()
+	config.TenantConfig = s.tenantConfig
+	return config
+}
+
+type tenantClaims struct {
+	*jwt.AccessTokenClaims
+	TenantID any `json:"tenant_id"`
+}
+
+func TestParseUserTenant(t *testing.T) {
+	testApp := createTestApp(t)
+	tenantApp := &tenantTestApp{
+		testApp: testApp,
+		tenantConfig: &fs.TenantConfig{
+			Header:    "X-Tenant-ID",
+			Subdomain: "example.com",
+		},
+	}
+	authService := as.New(tenantApp)
+	resources := fs.NewResourcesManager()
+	resources.Middlewares = append(resources.Middlewares, authService.ParseUser)
+	resources.Add(fs.NewResource("tenant", func(c fs.Context, _ any) (any, error) {
+		return db.TenantFromContext(c), nil
+	}, &fs.Meta{Public: true}))
+	assert.NoError(t, resources.Init())
+	server := rr.NewRestfulResolver(&rr.ResolverConfig{
+		ResourceManager: resources,
+		Logger:          logger.CreateMockLogger(false),
+	}).Server()
+
+	createTenantToken := func(tenantID any) string {
+		token, _, err := jwt.GenerateAccessToken(
+			jwt.UserToJwtClaims(testApp.normalUser),
+			testApp.Key(), time.Time{},
+			func(claims *jwt.AccessTokenClaims) (jwtlib.Claims, error) {
+				return &tenantClaims{AccessTokenClaims: claims, TenantID: tenantID}, nil
+			},
+		)
+		assert.NoError(t, err)
+		return token
+	}
+
+	tenantToken := createTenantToken("acme")
+	membershipToken := createTenantToken([]string{"acme", "globex"})
+
+	tests := []struct {
+		name    string
+		token   string
+		header  string
+		host    string
+		status  int
+		expects string
+	}{
+		{name: "no tenant", status: 200, expects: `{"data":""}`},
+		{name: "claim", token: tenantToken, status: 200, expects: `{"data":"acme"}`},
+		{name: "claim header", token: tenantToken, header: " acme ", status: 200, expects: `{"data":"acme"}`},
+		{name: "claim subdomain", token: tenantToken, host: "Acme.Example.com:8000", status: 200, expects: `{"data":"acme"}`},
+		{name: "claim other header", token: tenantToken, header: "globex", status: 403},
+		{name: "claim other subdomain", token: tenantToken, host: "globex.example.com", status: 403},
+		{name: "membership", token: membershipToken, status: 200, expects: `{"data":""}`},
+		{name: "membership header", token: membershipToken, header: "globex", host: "acme.example.com", status: 200, expects: `{"data":"globex"}`},
+		{name: "membership subdomain", token: membershipToken, host: "globex.example.com", status: 200, expects: `{"data":"globex"}`},
+		{name: "membership other header", token: membershipToken, header: "initech", status: 403},
+		{name: "token without claim", token: testApp.normalUserToken, header: "globex", status: 403},
+		{name: "invalid token", token: tenantToken + "invalid", header: "acme", status: 403},
+		{name: "header without token", header: "globex", status: 403},
+		{name: "subdomain without token", host: "acme.example.com", status: 403},
+		{name: "nested subdomain", token: tenantToken, host: "api.acme.example.com", status: 200, expects: `{"data":"acme"}`},
+		{name: "other domain", host: "acme.example.org", status: 200, expects: `{"data":""}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/tenant", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			resp := utils.Must(server.Test(req))
+			defer func() { assert.NoError(t, resp.Body.Close()) }()
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.status == 200 {
+				assert.Equal(t, tt.expects, utils.Must(utils.ReadCloserToString(resp.Body)))
+			}
+		})
+	}
+}
+
+func TestAuthorizeRowFilter(t *testing.T) {
+	testApp := createTestApp(t)
+	ctx := context.Background()
+	roleModel := utils.Must(testApp.db.Model("role"))
+	permissionModel := utils.Must(testApp.db.Model("permission"))
+	blogModel := utils.Must(testApp.db.Model("blog"))
+	for _, name := range []string{"normaluser", "other", "public"} {
+		_ = utils.Must(blogModel.Create(ctx, entity.New().Set("name", name)))
+	}
+
+	createRole := func(name string) uuid.UUID {
+		return utils.Must(roleModel.Create(ctx, entity.New().Set("name", name))).(uuid.UUID)
+	}
+	createPermission := func(roleID uuid.UUID, modifier string) {
+		_ = utils.Must(permissionModel.Create(ctx, entity.New().
+			Set("resource", "api.content.blog.rows").
+			Set("value", fs.PermissionTypeAllow.String()).
+			Set("modifier", modifier).
+			Set("role_id", roleID),
+		))
+	}
+	createToken := func(roleIDs ...uuid.UUID) string {
+		token, _, err := jwt.GenerateAccessToken(jwt.UserToJwtClaims(&fs.User{
+			ID:       testApp.normalUser.ID,
+			Username: "normaluser",
+			Active:   true,
+			Roles: utils.Map(roleIDs, func(id uuid.UUID) *fs.Role {
+				return &fs.Role{ID: id}
+			}),
+		}), testApp.Key(), time.Time{}, nil)
+		assert.NoError(t, err)
+		return token
+	}
+
+	editorRoleID := createRole("editor")
+	viewerRoleID := createRole("viewer")
+	createPermission(fs.RoleUser.ID, `{"name": "$context.User().Username"}`)
+	createPermission(fs.RoleGuest.ID, `{"name": "$context.User().Username"}`)
+	createPermission(editorRoleID, `{"name": "other"}`)
+	createPermission(viewerRoleID, "")
+
+	authService := as.New(testApp)
+	resources := fs.NewResourcesManager()
+	resources.Hooks = func() *fs.Hooks {
+		return &fs.Hooks{PreResolve: []fs.Middleware{authService.Authorize}}
+	}
+	resources.Middlewares = append(resources.Middlewares, authService.ParseUser)
+	resources.Group("api", &fs.Meta{Prefix: "/api"}).Group("content").
+		Add(fs.NewResource("rows", func(c fs.Context, _ any) ([]string, error) {
+			blogs, err := db.Builder[*entity.Entity](testApp.db, "blog").Order("id").Get(c)
+			return utils.Map(blogs, func(e *entity.Entity) string {
+				return e.GetString("name")
+			}), err
+		}, &fs.Meta{Get: "/:schema/rows"}))
+	assert.NoError(t, resources.Init())
+	server := rr.NewRestfulResolver(&rr.ResolverConfig{
+		ResourceManager: resources,
+		Logger:          logger.CreateMockLogger(false),
+	}).Server()
+
+	tests := []struct {
+		name    string
+		token   string
+		status  int
+		expects string
+	}{
+		{name: "root", token: testApp.adminToken, status: 200, expects: `{"data":["normaluser","other","public"]}`},
+		{name: "guest", status: 403, expects: `Forbidden`},
+		{name: "modifier", token: createToken(fs.RoleUser.ID), status: 200, expects: `{"data":["normaluser"]}`},
+		{name: "modifiers", token: createToken(fs.RoleUser.ID, editorRoleID), status: 200, expects: `{"data":["normaluser","other"]}`},
+		{name: "no modifier", token: createToken(fs.RoleUser.ID, viewerRoleID), status: 200, expects: `{"data":["normaluser","other","public"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/content/blog/rows", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			resp := utils.Must(server.Test(req))
+			defer func() { assert.NoError(t, resp.Body.Close()) }()
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Contains(t, utils.Must(utils.ReadCloserToString(resp.Body)), tt.expects)
+		})
+	}
+}
+
+func TestAuthorizeNestedWrites(t *testing.T) {
+	testApp := createTestApp(t)
+	ctx := context.Background()
+	roleModel := utils.Must(testApp.db.Model("role"))
+	permissionModel := utils.Must(testApp.db.Model("permission"))
+	createRole := func(name string, resources ...string) uuid.UUID {
+		roleID := utils.Must(roleModel.Create(ctx, entity.New().Set("name", name))).(uuid.UUID)
+		for _, resource := range append([]string{"api.content.post.create"}, resources...) {
+			_ = utils.Must(permissionModel.Create(ctx, entity.New().
+				Set("resource", resource).
+				Set("value", fs.PermissionTypeAllow.String()).
+				Set("role_id", roleID),
+			))
+		}
+		return roleID
+	}
+	createToken := func(roleID uuid.UUID) string {
+		token, _, err := jwt.GenerateAccessToken(jwt.UserToJwtClaims(&fs.User{
+			ID:       testApp.normalUser.ID,
+			Username: "normaluser",
+			Active:   true,
+			Roles:    []*fs.Role{{ID: roleID}},
+		}), testApp.Key(), time.Time{}, nil)
+		assert.NoError(t, err)
+		return token
+	}
+
+	writerRoleID := createRole("writer")
+	creatorRoleID := createRole("creator", "api.content.user.create")
+	editorRoleID := createRole("editor", "api.content.user.create", "api.content.user.update")
+	fieldsRoleID := createRole("fields")
+	filteredRoleID := createRole("filtered")
+	for _, action := range []string{"create", "update"} {
+		_ = utils.Must(permissionModel.Create(ctx, entity.New().
+			Set("resource", "api.content.user."+action).
+			Set("value", fs.PermissionTypeAllow.String()).
+			Set("fields", `{"write": ["username", "provider"]}`).
+			Set("role_id", fieldsRoleID),
+		))
+		_ = utils.Must(permissionModel.Create(ctx, entity.New().
+			Set("resource", "api.content.user."+action).
+			Set("value", fs.PermissionTypeAllow.String()).
+			Set("modifier", `{"id": "$context.User().ID"}`).
+			Set("role_id", filteredRoleID),
+		))
+	}
+
+	authService := as.New(testApp)
+	resources := fs.NewResourcesManager()
+	resources.Hooks = func() *fs.Hooks {
+		return &fs.Hooks{PreResolve: []fs.Middleware{authService.Authorize}}
+	}
+	resources.Middlewares = append(resources.Middlewares, authService.ParseUser)
+	resources.Group("api", &fs.Meta{Prefix: "/api"}).Group("content").
+		Add(fs.NewResource("create", func(c fs.Context, _ any) (any, error) {
+			payload, err := c.Payload()
+			if err != nil {
+				return nil, err
+			}
+
+			return utils.Must(testApp.db.Model(c.Arg("schema"))).Create(c, payload)
+		}, &fs.Meta{Post: "/:schema"}))
+	assert.NoError(t, resources.Init())
+	server := rr.NewRestfulResolver(&rr.ResolverConfig{
+		ResourceManager: resources,
+		Logger:          logger.CreateMockLogger(false),
+	}).Server()
+
+	upsertAuthor := `{"title": "hello", "author": {"$upsert": {"conflict": ["username"], "data": %s}}}`
+	tests := []struct {
+		name    string
+		token   string
+		payload string
+		status  int
+		expects string
+	}{
+		{
+			name:    "no nested write",
+			token:   createToken(writerRoleID),
+			payload: `{"title": "hello"}`,
+			status:  200,
+		},
+		{
+			name:    "no create permission",
+			token:   createToken(writerRoleID),
+			payload: `{"title": "hello", "author": {"$create": {"username": "writer", "provider": "local"}}}`,
+			status:  403,
+			expects: `create user is not allowed`,
+		},
+		{
+			name:    "no update permission",
+			token:   createToken(creatorRoleID),
+			payload: fmt.Sprintf(upsertAuthor, `{"username": "normaluser", "provider": "local", "active": true}`),
+			status:  403,
+			expects: `update user is not allowed`,
+		},
+		{
+			name:    "field not writable",
+			token:   createToken(fieldsRoleID),
+			payload: fmt.Sprintf(upsertAuthor, `{"username": "normaluser", "provider": "local", "active": true}`),
+			status:  403,
+			expects: `field user.active is not writable`,
+		},
+		{
+			name:    "row filter",
+			token:   createToken(filteredRoleID),
+			payload: fmt.Sprintf(upsertAuthor, `{"username": "normaluser", "provider": "local"}`),
+			status:  403,
+			expects: `create user is restricted by a row filter`,
+		},
+		{
+			name:    "allowed",
+			token:   createToken(editorRoleID),
+			payload: fmt.Sprintf(upsertAuthor, `{"username": "normaluser", "provider": "local"}`),
+			status:  200,
+		},
+		{
+			name:    "root",
+			token:   testApp.adminToken,
+			payload: fmt.Sprintf(upsertAuthor, `{"username": "normaluser", "provider": "local", "active": true}`),
+			status:  200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/content/post", strings.NewReader(tt.payload))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			resp := utils.Must(server.Test(req))
+			defer func() { assert.NoError(t, resp.Body.Close()) }()
+			response := utils.Must(utils.ReadCloserToString(resp.Body))
+			assert.Equal(t, tt.status, resp.StatusCode, response)
+			assert.Contains(t, response, tt.expects)
+		})
+	}
+}
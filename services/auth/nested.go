@@ -0,0 +1,129 @@
+package authservice
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/errors"
+	"github.com/fastschema/fastschema/schema"
+)
+
+// nestedWriteResources are the content resources that write the related records
+// of the $create and $upsert blocks of the relation fields.
+var nestedWriteResources = []string{"api.content.create", "api.content.update", "api.content.bulk-update"}
+
+// authorizeNestedWrites authorizes the related records that a content write creates or upserts:
+//
+//	{"author": {"$upsert": {"conflict": ["username"], "data": {"username": "john"}}}}
+//
+// The related records are authorized as if they were written directly:
+// the create permission of the related schema is required, and the update permission for the upserts,
+// the fields of the related records must be writable by these permissions.
+// The row filters only restrict the requested schema, so the related records
+// can not be written if all the permissions of the related schema have a modifier.
+func (as *AuthService) authorizeNestedWrites(c fs.Context, user *fs.User) error {
+	if !slices.Contains(nestedWriteResources, c.Resource().ID()) {
+		return nil
+	}
+
+	// The content resource reports the unknown schemas and the invalid payloads
+	s, err := as.DB().SchemaBuilder().Schema(c.Arg("schema"))
+	if err != nil {
+		return nil
+	}
+
+	payload, err := c.Payload()
+	if err != nil || payload == nil {
+		return nil
+	}
+
+	return as.authorizeNestedRecords(c, user, s, payload)
+}
+
+// authorizeNestedRecords authorizes the nested writes of the relation fields of the record,
+// the nested writes of the related records are authorized too.
+func (as *AuthService) authorizeNestedRecords(c fs.Context, user *fs.User, s *schema.Schema, record *entity.Entity) error {
+	for pair := record.First(); pair != nil; pair = pair.Next() {
+		field := s.Field(pair.Key)
+		nested, ok := pair.Value.(*entity.Entity)
+		if field == nil || !field.Type.IsRelationType() || field.Relation == nil || !ok {
+			continue
+		}
+
+		creates := nestedRecords(nested.Get("$create"))
+		upserts := []*entity.Entity{}
+		if upsert, ok := nested.Get("$upsert").(*entity.Entity); ok {
+			upserts = nestedRecords(upsert.Get("data"))
+		}
+
+		records := append(slices.Clip(creates), upserts...)
+		if len(records) == 0 {
+			continue
+		}
+
+		target, err := as.DB().SchemaBuilder().Schema(field.Relation.TargetSchemaName)
+		if err != nil {
+			return errors.InternalServerError(err.Error())
+		}
+
+		if err := as.authorizeNestedWrite(c, user, target, "create", records); err != nil {
+			return err
+		}
+
+		if err := as.authorizeNestedWrite(c, user, target, "update", upserts); err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			if err := as.authorizeNestedRecords(c, user, target, record); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// authorizeNestedWrite checks that the user is allowed to run the action on the related records of the schema.
+func (as *AuthService) authorizeNestedWrite(
+	c fs.Context,
+	user *fs.User,
+	s *schema.Schema,
+	action string,
+	records []*entity.Entity,
+) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	permissions := as.AuthUserPermissions(c, user, fmt.Sprintf("api.content.%s.%s", s.Name, action))
+	if len(permissions) == 0 {
+		return errors.Forbidden("%s %s is not allowed", action, s.Name)
+	}
+
+	if !slices.ContainsFunc(permissions, func(p *fs.Permission) bool { return !p.HasModifier() }) {
+		return errors.Forbidden("%s %s is restricted by a row filter, the records must be written directly", action, s.Name)
+	}
+
+	fieldPermissions := &fs.FieldPermissions{Schema: s.Name, Permissions: permissions}
+	for _, record := range records {
+		if field := fieldPermissions.UnwritableField(record); field != "" {
+			return errors.Forbidden("field %s.%s is not writable", s.Name, field)
+		}
+	}
+
+	return nil
+}
+
+func nestedRecords(value any) []*entity.Entity {
+	switch v := value.(type) {
+	case *entity.Entity:
+		return []*entity.Entity{v}
+	case []*entity.Entity:
+		return v
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,51 @@
+package contentservice
+
+import (
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/errors"
+)
+
+// Aggregate runs aggregations over the contents that match the filter.
+// The aggregations are given in the format func[:field[:alias]], e.g.
+//
+//	/content/blog/aggregate?group=category&aggregate=count,sum:views:total_views&having={"total_views":{"$gt":100}}
+func (cs *ContentService) Aggregate(c fs.Context, _ any) ([]*entity.Entity, error) {
+	model, err := cs.DB().Model(c.Arg("schema"))
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	predicates, err := db.CreatePredicatesFromFilterObject(
+		cs.DB().SchemaBuilder(),
+		model.Schema(),
+		c.Arg("filter", ""),
+	)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	aggregations, err := db.ParseAggregations(c.Arg("aggregate", "count"))
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	having, err := db.CreateHavingPredicatesFromFilterObject(aggregations, c.Arg("having", ""))
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	records, err := model.Query(predicates...).
+		GroupBy(splitArg(c, "group")...).
+		Having(having...).
+		Order(splitArg(c, "sort")...).
+		Limit(uint(c.ArgInt("limit", 0))).
+		Offset(uint(c.ArgInt("offset", 0))).
+		Aggregate(c, aggregations...)
+	if err != nil {
+		return nil, queryError(err, errors.BadRequest)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,99 @@
+package contentservice_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TestAggregateResponse struct {
+	Data []map[string]any `json:"data"`
+}
+
+func TestContentServiceAggregate(t *testing.T) {
+	cs, server := createContentService(t)
+
+	// Case 1: schema not found
+	req := httptest.NewRequest("GET", "/content/test/aggregate", nil)
+	resp := utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Contains(t, utils.Must(utils.ReadCloserToString(resp.Body)), `"message":"model test not found"`)
+
+	// create 6 blog posts in 2 groups
+	blogModel := utils.Must(cs.DB().Model("blog"))
+	for i := 0; i < 6; i++ {
+		utils.Must(blogModel.CreateFromJSON(context.Background(), fmt.Sprintf(
+			`{"name": "blog %d", "views": %d}`,
+			i%2,
+			(i+1)*10,
+		)))
+	}
+
+	// Case 2: invalid filter
+	req = httptest.NewRequest("GET", "/content/blog/aggregate?filter=invalid", nil)
+	resp = utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 400, resp.StatusCode)
+
+	// Case 3: invalid aggregation
+	req = httptest.NewRequest("GET", "/content/blog/aggregate?aggregate=median:views", nil)
+	resp = utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Contains(t, utils.Must(utils.ReadCloserToString(resp.Body)), `invalid aggregate function`)
+
+	// Case 4: invalid having
+	req = httptest.NewRequest("GET", "/content/blog/aggregate?having="+url.QueryEscape(`{"views": 1}`), nil)
+	resp = utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Contains(t, utils.Must(utils.ReadCloserToString(resp.Body)), `views is not an aggregation alias`)
+
+	// Case 5: group by a non filterable field
+	req = httptest.NewRequest("GET", "/content/blog/aggregate?group=tags", nil)
+	resp = utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 400, resp.StatusCode)
+
+	// Case 6: default count aggregation
+	req = httptest.NewRequest("GET", "/content/blog/aggregate", nil)
+	resp = utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var data TestAggregateResponse
+	assert.NoError(t, json.Unmarshal([]byte(utils.Must(utils.ReadCloserToString(resp.Body))), &data))
+	require.Len(t, data.Data, 1)
+	assert.Equal(t, float64(6), data.Data[0]["count"])
+
+	// Case 7: group, filter, having and sort
+	query := url.Values{}
+	query.Set("group", "name")
+	query.Set("aggregate", "count,sum:views:total,max:views")
+	query.Set("filter", `{"views": {"$gt": 10}}`)
+	query.Set("having", `{"total": {"$gt": 50}}`)
+	query.Set("sort", "-total")
+	req = httptest.NewRequest("GET", "/content/blog/aggregate?"+query.Encode(), nil)
+	resp = utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 200, resp.StatusCode)
+
+	data = TestAggregateResponse{}
+	assert.NoError(t, json.Unmarshal([]byte(utils.Must(utils.ReadCloserToString(resp.Body))), &data))
+	require.Len(t, data.Data, 2)
+	assert.Equal(t, "blog 1", data.Data[0]["name"])
+	assert.Equal(t, float64(3), data.Data[0]["count"])
+	assert.Equal(t, float64(120), data.Data[0]["total"])
+	assert.Equal(t, float64(60), data.Data[0]["max_views"])
+	assert.Equal(t, "blog 0", data.Data[1]["name"])
+	assert.Equal(t, float64(2), data.Data[1]["count"])
+	assert.Equal(t, float64(80), data.Data[1]["total"])
+}
@@ -2,6 +2,7 @@ package contentservice
 
 import (
 	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
 	"github.com/fastschema/fastschema/fs"
 	"github.com/fastschema/fastschema/pkg/errors"
 	"github.com/fastschema/fastschema/schema"
@@ -34,14 +35,48 @@ func (cs *ContentService) CreateResource(api *fs.Resource) {
 		Add(fs.NewResource("list", cs.List, &fs.Meta{
 			Get: "/",
 		})).
+		Add(fs.NewResource("aggregate", cs.Aggregate, &fs.Meta{
+			Get: "/aggregate",
+		})).
+		Add(fs.NewResource("export", cs.Export, &fs.Meta{
+			Get: "/export",
+		})).
+		Add(fs.NewResource("trash", cs.TrashList, &fs.Meta{
+			Get: "/trash",
+		})).
+		Add(fs.NewResource("trash-bulk-restore", cs.TrashBulkRestore, &fs.Meta{
+			Post: "/trash/restore",
+		})).
+		Add(fs.NewResource("trash-restore", cs.TrashRestore, &fs.Meta{
+			Post: "/trash/:id/restore",
+			Args: fs.Args{"id": fs.CreateArg(fs.TypeUint64, "The content ID")},
+		})).
+		Add(fs.NewResource("trash-bulk-purge", cs.TrashBulkPurge, &fs.Meta{
+			Delete: "/trash",
+		})).
+		Add(fs.NewResource("trash-purge", cs.TrashPurge, &fs.Meta{
+			Delete: "/trash/:id",
+			Args:   fs.Args{"id": fs.CreateArg(fs.TypeUint64, "The content ID")},
+		})).
 		Add(fs.NewResource("detail", cs.Detail, &fs.Meta{
 			Get:  "/:id",
 			Args: fs.Args{"id": fs.CreateArg(fs.TypeUint64, "The content ID")},
 		})).
 		Add(fs.NewResource("create", cs.Create, &fs.Meta{Post: "/"})).
+		Add(fs.NewResource("bulk-create", cs.BulkCreate, &fs.Meta{Post: "/bulk"})).
 		Add(fs.NewResource("bulk-update", cs.BulkUpdate, &fs.Meta{
 			Put: "/update",
 		})).
+		Add(fs.NewResource("upsert", cs.Upsert, &fs.Meta{
+			Put: "/upsert",
+			Args: fs.Args{
+				"conflict": fs.CreateArg(fs.TypeString, "The comma separated fields to detect the existing content"),
+				"update": {
+					Type:        fs.TypeString,
+					Description: "The comma separated fields to update if the content exists",
+				},
+			},
+		})).
 		Add(fs.NewResource("update", cs.Update, &fs.Meta{
 			Put:  "/:id",
 			Args: fs.Args{"id": fs.CreateArg(fs.TypeUint64, "The content ID")},
@@ -52,9 +87,26 @@ func (cs *ContentService) CreateResource(api *fs.Resource) {
 		Add(fs.NewResource("delete", cs.Delete, &fs.Meta{
 			Delete: "/:id",
 			Args:   fs.Args{"id": fs.CreateArg(fs.TypeUint64, "The content ID")},
+		})).
+		Add(fs.NewResource("revisions", cs.Revisions, &fs.Meta{
+			Get:  "/:id/revisions",
+			Args: fs.Args{"id": fs.CreateArg(fs.TypeUint64, "The content ID")},
+		})).
+		Add(fs.NewResource("revision", cs.Revision, &fs.Meta{
+			Get:  "/:id/revisions/:rev",
+			Args: revisionArgs,
+		})).
+		Add(fs.NewResource("revision-restore", cs.RevisionRestore, &fs.Meta{
+			Post: "/:id/revisions/:rev/restore",
+			Args: revisionArgs,
 		}))
 }
 
+var revisionArgs = fs.Args{
+	"id":  fs.CreateArg(fs.TypeUint64, "The content ID"),
+	"rev": fs.CreateArg(fs.TypeUint64, "The revision number"),
+}
+
 func parseIDArg(s *schema.Schema, rawID string) (any, error) {
 	if rawID == "" {
 		return nil, errors.BadRequest("missing id")
@@ -71,3 +123,39 @@ func parseIDArg(s *schema.Schema, rawID string) (any, error) {
 
 	return schema.StringToFieldValue[any](idField, rawID)
 }
+
+// checkWritableFields rejects the payloads that contain fields the request is not allowed to write.
+// The payloads are checked as sent by the client, before the setters of the schema are applied.
+func checkWritableFields(c fs.Context, schemaName string, payloads ...*entity.Entity) error {
+	fieldPermissions := fs.FieldPermissionsFromContext(c, schemaName)
+	for _, payload := range payloads {
+		if field := fieldPermissions.UnwritableField(payload); field != "" {
+			return errors.Forbidden("field %s is not writable", field)
+		}
+	}
+
+	return nil
+}
+
+// queryError returns the response error of a failed query.
+// The response errors of the query hooks, e.g. the fields that are not readable, are kept,
+// the other errors are created with the given error constructor.
+func queryError(err error, createError func(msgs ...any) *errors.Error) *errors.Error {
+	var responseError *errors.Error
+	if errors.As(err, &responseError) && responseError.Status != 0 {
+		return responseError
+	}
+
+	return createError(err.Error())
+}
+
+// validationError returns a 422 error containing the per-field errors
+// if the given error is a schema validation error, otherwise nil.
+func validationError(err error) *errors.Error {
+	var batch *schema.SchemaErrors
+	if !errors.As(err, &batch) {
+		return nil
+	}
+
+	return errors.UnprocessableEntity("validation failed").WithData(batch)
+}
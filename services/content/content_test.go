@@ -1,6 +1,8 @@
 package contentservice_test
 
 import (
+	"bytes"
+	"net/http/httptest"
 	"os"
 	"testing"
 
@@ -11,6 +13,7 @@ import (
 	rr "github.com/fastschema/fastschema/pkg/restfulresolver"
 	"github.com/fastschema/fastschema/pkg/utils"
 	"github.com/fastschema/fastschema/schema"
+	as "github.com/fastschema/fastschema/services/auth"
 	cs "github.com/fastschema/fastschema/services/content"
 	"github.com/stretchr/testify/assert"
 )
@@ -25,7 +28,7 @@ func (s testApp) DB() db.Client {
 	return s.db
 }
 
-func createContentService(t *testing.T) (*cs.ContentService, *rr.Server) {
+func createContentService(t *testing.T, middlewares ...fs.Middleware) (*cs.ContentService, *rr.Server) {
 	schemaDir := t.TempDir()
 	utils.WriteFile(schemaDir+"/blog.json", `{
 		"name": "blog",
@@ -42,7 +45,19 @@ func createContentService(t *testing.T) (*cs.ContentService, *rr.Server) {
 				"type": "string",
 				"name": "name",
 				"label": "Name",
-				"sortable": true
+				"sortable": true,
+				"filterable": true,
+				"searchable": true,
+				"validation": {"max_length": 100}
+			},
+			{
+				"type": "int",
+				"name": "views",
+				"label": "Views",
+				"optional": true,
+				"sortable": true,
+				"filterable": true,
+				"validation": {"min": 0}
 			},
 			{
 				"type": "relation",
@@ -88,24 +103,63 @@ func createContentService(t *testing.T) (*cs.ContentService, *rr.Server) {
 			}
 		]
 	}`)
+	utils.WriteFile(schemaDir+"/note.json", `{
+		"name": "note",
+		"namespace": "notes",
+		"label_field": "title",
+		"optimistic_lock": true,
+		"fields": [
+			{
+				"type": "string",
+				"name": "title",
+				"label": "Title"
+			}
+		]
+	}`)
+	utils.WriteFile(schemaDir+"/coupon.json", `{
+		"name": "coupon",
+		"namespace": "coupons",
+		"label_field": "code",
+		"primary_field": "code",
+		"fields": [
+			{
+				"type": "uuid",
+				"name": "code",
+				"label": "Code"
+			}
+		]
+	}`)
 	sb := utils.Must(schema.NewBuilderFromDir(schemaDir, fs.SystemSchemaTypes...))
 	db := utils.Must(entdbadapter.NewTestClient(utils.Must(os.MkdirTemp("", "migrations")), sb))
 	testApp := &testApp{sb: sb, db: db}
 	contentService := cs.New(testApp)
 	testApp.resources = fs.NewResourcesManager()
+	testApp.resources.Middlewares = append(testApp.resources.Middlewares, middlewares...)
 	testApp.resources.Group("content").
 		Add(fs.NewResource("list", contentService.List, &fs.Meta{
 			Get: "/:schema",
 		})).
+		Add(fs.NewResource("aggregate", contentService.Aggregate, &fs.Meta{
+			Get: "/:schema/aggregate",
+		})).
+		Add(fs.NewResource("export", contentService.Export, &fs.Meta{
+			Get: "/:schema/export",
+		})).
 		Add(fs.NewResource("detail", contentService.Detail, &fs.Meta{
 			Get: "/:schema/:id",
 		})).
 		Add(fs.NewResource("create", contentService.Create, &fs.Meta{
 			Post: "/:schema",
 		})).
+		Add(fs.NewResource("bulk-create", contentService.BulkCreate, &fs.Meta{
+			Post: "/:schema/bulk",
+		})).
 		Add(fs.NewResource("bulk-update", contentService.BulkUpdate, &fs.Meta{
 			Put: "/:schema/update",
 		})).
+		Add(fs.NewResource("upsert", contentService.Upsert, &fs.Meta{
+			Put: "/:schema/upsert",
+		})).
 		Add(fs.NewResource("update", contentService.Update, &fs.Meta{
 			Put: "/:schema/:id",
 		})).
@@ -137,10 +191,68 @@ func TestCreateResource(t *testing.T) {
 
 	service.CreateResource(api)
 	assert.NotNil(t, api.Find("api.content.list"))
+	assert.NotNil(t, api.Find("api.content.aggregate"))
+	assert.NotNil(t, api.Find("api.content.export"))
 	assert.NotNil(t, api.Find("api.content.detail"))
 	assert.NotNil(t, api.Find("api.content.create"))
+	assert.NotNil(t, api.Find("api.content.bulk-create"))
 	assert.NotNil(t, api.Find("api.content.bulk-update"))
+	assert.NotNil(t, api.Find("api.content.upsert"))
 	assert.NotNil(t, api.Find("api.content.update"))
 	assert.NotNil(t, api.Find("api.content.bulk-delete"))
 	assert.NotNil(t, api.Find("api.content.delete"))
 }
+
+func TestContentServiceFieldPermissions(t *testing.T) {
+	permission := &fs.Permission{Value: "allow", Fields: `{"read": ["*", "-views"], "write": ["name"]}`}
+	assert.NoError(t, permission.Compile())
+	contentService, server := createContentService(t, func(c fs.Context) error {
+		c.Local(fs.FieldPermissionsKey, &fs.FieldPermissions{
+			Schema:      "blog",
+			Permissions: []*fs.Permission{permission},
+		})
+		return c.Next()
+	})
+	contentService.DB().Config().Hooks = func() *db.Hooks {
+		return &db.Hooks{PreDBQuery: []db.PreDBQuery{(&as.AuthService{}).FieldsQueryHook}}
+	}
+
+	tests := []struct {
+		method  string
+		path    string
+		body    string
+		status  int
+		message string
+	}{
+		{"POST", "/content/blog", `{"name": "blog 1"}`, 200, ""},
+		{"POST", "/content/blog", `{"name": "blog 2", "views": 1}`, 403, "field views is not writable"},
+		{"POST", "/content/blog/bulk", `[{"name": "blog 3"}, {"name": "blog 4", "views": 1}]`, 403, "field views is not writable"},
+		{"PUT", "/content/blog/1", `{"name": "blog 1 updated"}`, 200, ""},
+		{"PUT", "/content/blog/1", `{"$set": {"views": 1}}`, 403, "field views is not writable"},
+		{"PUT", "/content/blog/update?filter={\"id\":1}", `{"$clear": {"views": true}}`, 403, "field views is not writable"},
+		{"PUT", "/content/blog/upsert?conflict=name", `{"name": "blog 1", "views": 1}`, 403, "field views is not writable"},
+		{"POST", "/content/tag", `{"name": "tag 1"}`, 200, ""},
+		{"GET", "/content/blog/1?select=views", "", 403, "field views is not readable"},
+		{"GET", "/content/blog?filter={\"views\":{\"$gt\":0}}", "", 403, "field views is not readable"},
+		{"GET", "/content/blog?sort=-views", "", 403, "field views is not readable"},
+		{"GET", "/content/blog/aggregate?aggregate=sum:views", "", 403, "field views is not readable"},
+		{"GET", "/content/blog/aggregate?group=name", "", 200, ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, bytes.NewReader([]byte(tt.body)))
+		resp := utils.Must(server.Test(req))
+		body := utils.Must(utils.ReadCloserToString(resp.Body))
+		assert.Equal(t, tt.status, resp.StatusCode, tt.path+" "+tt.body+": "+body)
+		if tt.message != "" {
+			assert.Contains(t, body, `"message":"`+tt.message+`"`)
+		}
+	}
+
+	// The fields that are not readable are not selected
+	resp := utils.Must(server.Test(httptest.NewRequest("GET", "/content/blog/1", nil)))
+	body := utils.Must(utils.ReadCloserToString(resp.Body))
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Contains(t, body, `"name":"blog 1 updated"`)
+	assert.NotContains(t, body, `"views"`)
+}
@@ -1,6 +1,7 @@
 package contentservice
 
 import (
+	"github.com/fastschema/fastschema/entity"
 	"github.com/fastschema/fastschema/fs"
 	"github.com/fastschema/fastschema/pkg/errors"
 )
@@ -20,9 +21,44 @@ func (cs *ContentService) Create(c fs.Context, _ any) (any, error) {
 		entity.SetIDField(pkField.Name)
 	}
 
+	if err := checkWritableFields(c, schemaName, entity); err != nil {
+		return nil, err
+	}
+
 	if _, err := model.Create(c, entity); err != nil {
-		return nil, errors.BadRequest(err.Error())
+		if verr := validationError(err); verr != nil {
+			return nil, verr
+		}
+		return nil, queryError(err, errors.BadRequest)
 	}
 
 	return entity.Delete("password"), nil
 }
+
+// BulkCreate creates the contents in a single transaction and returns the created IDs.
+func (cs *ContentService) BulkCreate(c fs.Context, entities []*entity.Entity) ([]any, error) {
+	model, err := cs.DB().Model(c.Arg("schema"))
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	if pkField := model.Schema().PrimaryField(); pkField != nil {
+		for _, entity := range entities {
+			entity.SetIDField(pkField.Name)
+		}
+	}
+
+	if err := checkWritableFields(c, model.Schema().Name, entities...); err != nil {
+		return nil, err
+	}
+
+	ids, err := model.CreateMany(c, entities)
+	if err != nil {
+		if verr := validationError(err); verr != nil {
+			return nil, verr
+		}
+		return nil, queryError(err, errors.BadRequest)
+	}
+
+	return ids, nil
+}
@@ -3,11 +3,15 @@ package contentservice_test
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
 	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -49,6 +53,32 @@ func TestContentServiceCreateNormal(t *testing.T) {
 	assert.Equal(t, uint64(1), blog.ID())
 }
 
+func TestContentServiceCreateValidation(t *testing.T) {
+	cs, server := createContentService(t)
+	req := httptest.NewRequest("POST", "/content/blog", bytes.NewReader([]byte(`{"name": "test blog", "views": -1}`)))
+	resp := utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 422, resp.StatusCode)
+	assert.JSONEq(t, `{
+		"error": {
+			"code": "422",
+			"message": "validation failed",
+			"data": {
+				"schema": "blog",
+				"field_errors": [{
+					"code": "field.value.min",
+					"field": "views",
+					"message": "must be greater than or equal to 0"
+				}]
+			}
+		}
+	}`, utils.Must(utils.ReadCloserToString(resp.Body)))
+
+	// The blog is not created
+	blogModel := utils.Must(cs.DB().Model("blog"))
+	assert.Equal(t, 0, utils.Must(blogModel.Query().Count(context.Background())))
+}
+
 func TestContentServiceCreateUser(t *testing.T) {
 	cs, server := createContentService(t)
 
@@ -71,3 +101,106 @@ func TestContentServiceCreateUser(t *testing.T) {
 	assert.Equal(t, "testuser", user.GetString("username"))
 	assert.NotEqual(t, "testpassword", user.GetString("password"))
 }
+
+func TestContentServiceBulkCreate(t *testing.T) {
+	cs, server := createContentService(t)
+
+	bulkCreate := func(path, body string) (int, string) {
+		req := httptest.NewRequest("POST", path, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		resp := utils.Must(server.Test(req))
+		defer func() { assert.NoError(t, resp.Body.Close()) }()
+		return resp.StatusCode, utils.Must(utils.ReadCloserToString(resp.Body))
+	}
+
+	// Case 1: schema not found
+	status, response := bulkCreate("/content/test/bulk", `[{"name": "test blog"}]`)
+	assert.Equal(t, 400, status)
+	assert.Contains(t, response, `"message":"model test not found"`)
+
+	// Case 2: invalid json
+	status, _ = bulkCreate("/content/blog/bulk", `[{"name": "invalid json"`)
+	assert.Equal(t, 400, status)
+
+	// Case 3: one of the entities is invalid, nothing is created
+	status, response = bulkCreate("/content/blog/bulk", `[{"name": "blog 1"}, {"invalid": "blog 2"}]`)
+	assert.Equal(t, 400, status)
+	assert.Contains(t, response, `entity 1: column error: column blog.invalid not found`)
+
+	// Case 4: success
+	status, response = bulkCreate(
+		"/content/blog/bulk",
+		`[{"name": "blog 1", "views": 1}, {"name": "blog 2"}, {"name": "blog 3", "views": 3}]`,
+	)
+	assert.Equal(t, 200, status)
+	assert.Contains(t, response, `"data":[1,2,3]`)
+
+	blogs := utils.Must(utils.Must(cs.DB().Model("blog")).Query().Order("id").Get(context.Background()))
+	assert.Len(t, blogs, 3)
+	assert.Equal(t, "blog 3", blogs[2].GetString("name"))
+	assert.Equal(t, 3, blogs[2].Get("views"))
+	assert.Nil(t, blogs[1].Get("views"))
+}
+
+func TestContentServiceCreateCustomPrimaryKey(t *testing.T) {
+	cs, server := createContentService(t)
+	hookIDs := []any{}
+	cs.DB().Config().Hooks = func() *db.Hooks {
+		return &db.Hooks{PreDBCreate: []db.PreDBCreate{
+			func(ctx context.Context, schema *schema.Schema, e *entity.Entity) error {
+				hookIDs = append(hookIDs, e.ID())
+				return nil
+			},
+		}}
+	}
+
+	create := func(path, body string) (int, string) {
+		req := httptest.NewRequest("POST", path, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		resp := utils.Must(server.Test(req))
+		defer func() { assert.NoError(t, resp.Body.Close()) }()
+		return resp.StatusCode, utils.Must(utils.ReadCloserToString(resp.Body))
+	}
+
+	// The payloads of the single and the bulk create use the primary key of the schema as their ID
+	codes := []string{uuid.NewString(), uuid.NewString(), uuid.NewString()}
+	status, _ := create("/content/coupon", fmt.Sprintf(`{"code": "%s"}`, codes[0]))
+	assert.Equal(t, 200, status)
+
+	status, response := create("/content/coupon/bulk", fmt.Sprintf(`[{"code": "%s"}, {"code": "%s"}]`, codes[1], codes[2]))
+	assert.Equal(t, 200, status)
+	assert.Contains(t, response, codes[1])
+	assert.Equal(t, []any{codes[0], codes[1], codes[2]}, hookIDs)
+}
+
+func TestContentServiceCreateNested(t *testing.T) {
+	cs, server := createContentService(t)
+
+	// Case 1: create a tag with new blogs
+	req := httptest.NewRequest("POST", "/content/tag", bytes.NewReader([]byte(`{
+		"name": "tag 1",
+		"blogs": {"$create": [{"name": "blog 1"}, {"name": "blog 2"}]}
+	}`)))
+	resp := utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 200, resp.StatusCode)
+	response := utils.Must(utils.ReadCloserToString(resp.Body))
+	assert.Contains(t, response, `"name":"blog 1"`)
+	assert.Contains(t, response, `"name":"blog 2"`)
+
+	blogs := utils.Must(utils.Must(cs.DB().Model("blog")).Query().Select("name", "tags.name").Order("id").Get(context.Background()))
+	assert.Len(t, blogs, 2)
+	for _, blog := range blogs {
+		assert.Equal(t, "tag 1", blog.Get("tags").(*entity.Entity).GetString("name"))
+	}
+
+	// Case 2: the nested records are not created if the content is invalid
+	req = httptest.NewRequest("POST", "/content/tag", bytes.NewReader([]byte(`{
+		"invalid": "tag 2",
+		"blogs": {"$create": [{"name": "blog 3"}]}
+	}`)))
+	resp = utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Equal(t, 2, utils.Must(utils.Must(cs.DB().Model("blog")).Query().Count(context.Background())))
+}
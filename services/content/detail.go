@@ -44,8 +44,7 @@ func (cs *ContentService) Detail(c fs.Context, _ any) (*entity.Entity, error) {
 
 	entity, err := query.First(c)
 	if err != nil {
-		e := utils.If(db.IsNotFound(err), errors.NotFound, errors.InternalServerError)
-		return nil, e(err.Error())
+		return nil, queryError(err, utils.If(db.IsNotFound(err), errors.NotFound, errors.InternalServerError))
 	}
 
 	if schemaName == "user" {
@@ -0,0 +1,75 @@
+package contentservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/errors"
+)
+
+// Export streams the contents that match the filter as newline delimited JSON, one content per line.
+// The contents are read from the database in batches, so that large tables can be exported
+// without loading all the contents in memory. If an error occurs after the response has started,
+// it is written as the last line in the format {"error": {...}}.
+//
+//	/content/blog/export?filter={"views":{"$gt":100}}&select=id,name&sort=-id
+func (cs *ContentService) Export(c fs.Context, _ any) (*fs.HTTPResponse, error) {
+	model, err := cs.DB().Model(c.Arg("schema"))
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	predicates, err := db.CreatePredicatesFromFilterObject(
+		cs.DB().SchemaBuilder(),
+		model.Schema(),
+		c.Arg("filter", ""),
+	)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	columns := []string{}
+	if fields := c.Arg("select", ""); fields != "" {
+		columns = strings.Split(fields, ",")
+	} else if model.Schema().Name == "user" {
+		columns = []string{"roles"}
+	}
+
+	relationOptions, err := db.ParseRelationOptions(c.Arg("select_options", ""))
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	query := model.Query(predicates...).
+		Select(columns...).
+		Limit(uint(c.ArgInt("limit", 0))).
+		Order(c.Arg("sort", model.Schema().PrimaryKeyName()))
+
+	if relationOptions != nil {
+		query = query.WithRelationOptions(relationOptions)
+	}
+
+	return &fs.HTTPResponse{
+		Header: http.Header{
+			"Content-Type":        []string{"application/x-ndjson"},
+			"Content-Disposition": []string{fmt.Sprintf(`attachment; filename="%s.ndjson"`, model.Schema().Name)},
+		},
+		BodyWriter: func(w io.Writer) error {
+			encoder := json.NewEncoder(w)
+			if err := query.Each(c, func(e *entity.Entity) error {
+				return encoder.Encode(e)
+			}); err != nil {
+				_ = encoder.Encode(map[string]any{"error": errors.From(err)})
+				return err
+			}
+
+			return nil
+		},
+	}, nil
+}
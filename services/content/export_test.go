@@ -0,0 +1,59 @@
+package contentservice_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentServiceExport(t *testing.T) {
+	cs, server := createContentService(t)
+
+	// Case 1: schema not found
+	req := httptest.NewRequest("GET", "/content/test/export", nil)
+	resp := utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Contains(t, utils.Must(utils.ReadCloserToString(resp.Body)), `"message":"model test not found"`)
+
+	// Case 2: invalid filter
+	req = httptest.NewRequest("GET", "/content/blog/export?filter=invalid", nil)
+	resp = utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 400, resp.StatusCode)
+
+	blogModel := utils.Must(cs.DB().Model("blog"))
+	for i := 1; i <= 3; i++ {
+		utils.Must(blogModel.CreateFromJSON(context.Background(), fmt.Sprintf(
+			`{"name": "blog %d", "views": %d}`,
+			i,
+			i*10,
+		)))
+	}
+
+	// Case 3: export the filtered contents
+	req = httptest.NewRequest("GET", "/content/blog/export?select=id,name&sort=-id&filter="+url.QueryEscape(`{"views":{"$gte":20}}`), nil)
+	resp = utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="blog.ndjson"`, resp.Header.Get("Content-Disposition"))
+	assert.Equal(t, strings.Join([]string{
+		`{"id":3,"name":"blog 3"}`,
+		`{"id":2,"name":"blog 2"}`,
+		``,
+	}, "\n"), utils.Must(utils.ReadCloserToString(resp.Body)))
+
+	// Case 4: the query error is written as the last line
+	req = httptest.NewRequest("GET", "/content/blog/export?sort=invalid", nil)
+	resp = utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Contains(t, utils.Must(utils.ReadCloserToString(resp.Body)), `{"error":{"code":"500","message":"column blog.invalid not found"}}`)
+}
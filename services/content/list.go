@@ -2,20 +2,28 @@ package contentservice
 
 import (
 	"math"
+	"slices"
 	"strings"
 
 	"github.com/fastschema/fastschema/db"
 	"github.com/fastschema/fastschema/entity"
 	"github.com/fastschema/fastschema/fs"
 	"github.com/fastschema/fastschema/pkg/errors"
+	"github.com/fastschema/fastschema/pkg/utils"
 )
 
 // Pagination is a struct that contains pagination info and the data.
+//
+//	In cursor mode, NextCursor and PrevCursor are set when there are more items
+//	in the corresponding direction, CurrentPage and LastPage are not used
+//	and Total is only set when it is requested.
 type Pagination struct {
 	Total       uint             `json:"total"`
 	PerPage     uint             `json:"per_page"`
 	CurrentPage uint             `json:"current_page"`
 	LastPage    uint             `json:"last_page"`
+	NextCursor  string           `json:"next_cursor,omitempty"`
+	PrevCursor  string           `json:"prev_cursor,omitempty"`
 	Items       []*entity.Entity `json:"items"`
 }
 
@@ -45,10 +53,36 @@ func (cs *ContentService) List(c fs.Context, _ any) (*Pagination, error) {
 		return nil, errors.BadRequest(err.Error())
 	}
 
+	// The search results are sorted by relevance unless a sort is given
+	defaultSort := "-" + model.Schema().PrimaryKeyName()
+	if search := c.Arg("search", ""); search != "" {
+		searchPredicate, err := db.SearchFields(model.Schema(), search)
+		if err != nil {
+			return nil, errors.BadRequest(err.Error())
+		}
+
+		// The search does not match the fields that the request is not allowed to read
+		if fieldPermissions := fs.FieldPermissionsFromContext(c, model.Schema().Name); fieldPermissions != nil {
+			searchPredicate.Or = utils.Filter(searchPredicate.Or, func(p *db.Predicate) bool {
+				return fieldPermissions.UnreadableField(model.Schema(), p.Field) == ""
+			})
+			if len(searchPredicate.Or) == 0 {
+				return nil, errors.Forbidden("the searchable fields are not readable")
+			}
+		}
+
+		predicates = append(predicates, searchPredicate)
+		defaultSort = "-" + db.SearchRelevance
+	}
+
+	if c.Arg("pagination", "") == "cursor" || c.Arg("after", "") != "" || c.Arg("before", "") != "" {
+		return cs.listWithCursor(c, model, predicates)
+	}
+
 	columns := []string{}
 	total, err := model.Query(predicates...).Count(c, &db.QueryOption{})
 	if err != nil {
-		return nil, errors.BadRequest(err.Error())
+		return nil, queryError(err, errors.BadRequest)
 	}
 
 	if fields := c.Arg("select", ""); fields != "" {
@@ -69,7 +103,7 @@ func (cs *ContentService) List(c fs.Context, _ any) (*Pagination, error) {
 		Select(columns...).
 		Limit(uint(c.ArgInt("limit", 10))).
 		Offset((page - 1) * limit).
-		Order(c.Arg("sort", "-"+model.Schema().PrimaryKeyName()))
+		Order(c.Arg("sort", defaultSort))
 
 	// Apply relation options if provided
 	if relationOptions != nil {
@@ -78,8 +112,127 @@ func (cs *ContentService) List(c fs.Context, _ any) (*Pagination, error) {
 
 	records, err := query.Get(c)
 	if err != nil {
-		return nil, errors.InternalServerError(err.Error())
+		return nil, queryError(err, errors.InternalServerError)
 	}
 
 	return NewPagination(uint(total), limit, page, records), nil
 }
+
+// listWithCursor lists the contents using keyset pagination.
+// One extra record is fetched to know if there are more records in the paginating direction.
+func (cs *ContentService) listWithCursor(
+	c fs.Context,
+	model db.Model,
+	predicates []*db.Predicate,
+) (*Pagination, error) {
+	after, before := c.Arg("after", ""), c.Arg("before", "")
+	if after != "" && before != "" {
+		return nil, errors.BadRequest("after and before cannot be used together")
+	}
+
+	s := model.Schema()
+	order := db.KeysetOrder(s, []string{c.Arg("sort", "-"+s.PrimaryKeyName())})
+	if slices.ContainsFunc(order, func(o string) bool {
+		return strings.TrimPrefix(o, "-") == db.SearchRelevance
+	}) {
+		return nil, errors.BadRequest("sorting by relevance is not supported with cursor pagination")
+	}
+
+	for _, encoded := range []string{after, before} {
+		if encoded == "" {
+			continue
+		}
+
+		cursor, err := db.DecodeCursor(encoded)
+		if err != nil {
+			return nil, errors.BadRequest(err.Error())
+		}
+
+		if _, err := cursor.Predicates(s, order, before != "", true); err != nil {
+			return nil, errors.BadRequest(err.Error())
+		}
+	}
+
+	// At least one record is fetched per page so that the cursors can be created
+	pagination := &Pagination{PerPage: uint(max(c.ArgInt("limit", 10), 1))}
+	if c.Arg("total", "") == "true" {
+		total, err := model.Query(predicates...).Count(c, &db.QueryOption{})
+		if err != nil {
+			return nil, queryError(err, errors.BadRequest)
+		}
+		pagination.Total = uint(total)
+	}
+
+	columns := []string{}
+	hiddenColumns := []string{}
+	if fields := c.Arg("select", ""); fields != "" {
+		columns = strings.Split(fields, ",")
+
+		// The keyset columns are required to create the cursors,
+		// those that are not selected are removed from the records afterward.
+		// The primary key is always selected.
+		for _, o := range order {
+			column := strings.TrimPrefix(o, "-")
+			if column != s.PrimaryKeyName() && !slices.Contains(columns, column) {
+				hiddenColumns = append(hiddenColumns, column)
+			}
+		}
+
+		columns = append(columns, hiddenColumns...)
+	} else if s.Name == "user" {
+		columns = []string{"roles"}
+	}
+
+	relationOptions, err := db.ParseRelationOptions(c.Arg("select_options", ""))
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	query := model.Query(predicates...).
+		Select(utils.Unique(columns)...).
+		Limit(pagination.PerPage + 1).
+		Order(order...).
+		After(after).
+		Before(before)
+
+	if relationOptions != nil {
+		query = query.WithRelationOptions(relationOptions)
+	}
+
+	records, err := query.Get(c)
+	if err != nil {
+		return nil, queryError(err, errors.InternalServerError)
+	}
+
+	hasMore := uint(len(records)) > pagination.PerPage
+	if hasMore && before != "" {
+		records = records[1:]
+	} else if hasMore {
+		records = records[:pagination.PerPage]
+	}
+
+	pagination.Items = records
+	if len(records) == 0 {
+		return pagination, nil
+	}
+
+	if hasMore || before != "" {
+		if pagination.NextCursor, err = db.CreateCursor(s, order, records[len(records)-1]); err != nil {
+			return nil, errors.InternalServerError(err.Error())
+		}
+	}
+
+	if (hasMore && before != "") || after != "" {
+		if pagination.PrevCursor, err = db.CreateCursor(s, order, records[0]); err != nil {
+			return nil, errors.InternalServerError(err.Error())
+		}
+	}
+
+	for _, record := range records {
+		for _, column := range hiddenColumns {
+			record.Delete(column)
+		}
+	}
+
+	return pagination, nil
+}
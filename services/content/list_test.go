@@ -24,6 +24,8 @@ type TestPagination struct {
 	PerPage     uint            `json:"per_page"`
 	CurrentPage uint            `json:"current_page"`
 	LastPage    uint            `json:"last_page"`
+	NextCursor  string          `json:"next_cursor"`
+	PrevCursor  string          `json:"prev_cursor"`
 	Items       []*TestListItem `json:"items"`
 }
 
@@ -134,3 +136,203 @@ func TestContentServiceList(t *testing.T) {
 	assert.Contains(t, response, `"roles":`)
 	assert.Contains(t, response, `"created_at":`)
 }
+
+func TestContentServiceListWithCursor(t *testing.T) {
+	cs, server := createContentService(t)
+
+	blogModel := utils.Must(cs.DB().Model("blog"))
+	for i := 0; i < 5; i++ {
+		utils.Must(blogModel.CreateFromJSON(context.Background(), fmt.Sprintf(`{"name": "test blog %d"}`, i+1)))
+	}
+
+	list := func(query string) (int, *TestPagination) {
+		req := httptest.NewRequest("GET", "/content/blog?"+query, nil)
+		resp := utils.Must(server.Test(req))
+		defer func() { assert.NoError(t, resp.Body.Close()) }()
+
+		var data TestResponse
+		response := utils.Must(utils.ReadCloserToString(resp.Body))
+		assert.NoError(t, json.Unmarshal([]byte(response), &data))
+		return resp.StatusCode, data.Data
+	}
+
+	ids := func(p *TestPagination) []float64 {
+		return utils.Map(p.Items, func(item *TestListItem) float64 {
+			return item.ID.(float64)
+		})
+	}
+
+	// Case 1: invalid cursor
+	status, _ := list("after=invalid")
+	assert.Equal(t, 400, status)
+
+	// Case 2: after and before together
+	status, _ = list("after=a&before=b")
+	assert.Equal(t, 400, status)
+
+	// Case 3: first page
+	status, page := list("pagination=cursor&limit=2&total=true&select=name")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, uint(5), page.Total)
+	assert.Equal(t, []float64{5, 4}, ids(page))
+	assert.Equal(t, "test blog 5", page.Items[0].Name)
+	assert.NotEmpty(t, page.NextCursor)
+	assert.Empty(t, page.PrevCursor)
+
+	// Case 4: next page
+	status, page = list("limit=2&after=" + page.NextCursor)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, uint(0), page.Total)
+	assert.Equal(t, []float64{3, 2}, ids(page))
+	assert.NotEmpty(t, page.NextCursor)
+	assert.NotEmpty(t, page.PrevCursor)
+
+	// Case 5: last page
+	prevCursor := page.PrevCursor
+	status, page = list("limit=2&after=" + page.NextCursor)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []float64{1}, ids(page))
+	assert.Empty(t, page.NextCursor)
+	assert.NotEmpty(t, page.PrevCursor)
+
+	// Case 6: previous page
+	status, page = list("limit=2&before=" + prevCursor)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []float64{5, 4}, ids(page))
+	assert.NotEmpty(t, page.NextCursor)
+	assert.Empty(t, page.PrevCursor)
+
+	// Case 7: cursor does not match the sort order
+	status, _ = list("limit=2&sort=name&after=" + page.NextCursor)
+	assert.Equal(t, 400, status)
+}
+
+func TestContentServiceListWithCursorNulls(t *testing.T) {
+	cs, server := createContentService(t)
+
+	blogModel := utils.Must(cs.DB().Model("blog"))
+	for i, views := range []string{"1", "null", "2", "null", "3"} {
+		utils.Must(blogModel.CreateFromJSON(
+			context.Background(),
+			fmt.Sprintf(`{"name": "test blog %d", "views": %s}`, i+1, views),
+		))
+	}
+
+	list := func(query string) (int, []map[string]any, string, string) {
+		req := httptest.NewRequest("GET", "/content/blog?"+query, nil)
+		resp := utils.Must(server.Test(req))
+		defer func() { assert.NoError(t, resp.Body.Close()) }()
+
+		var data struct {
+			Data struct {
+				NextCursor string           `json:"next_cursor"`
+				PrevCursor string           `json:"prev_cursor"`
+				Items      []map[string]any `json:"items"`
+			} `json:"data"`
+		}
+		response := utils.Must(utils.ReadCloserToString(resp.Body))
+		assert.NoError(t, json.Unmarshal([]byte(response), &data))
+		return resp.StatusCode, data.Data.Items, data.Data.NextCursor, data.Data.PrevCursor
+	}
+
+	ids := func(items []map[string]any) []float64 {
+		return utils.Map(items, func(item map[string]any) float64 {
+			return item["id"].(float64)
+		})
+	}
+
+	// Case 1: the NULL values are paginated, the unselected sort column is not returned
+	status, items, nextCursor, _ := list("pagination=cursor&limit=2&sort=views&select=name")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []float64{2, 4}, ids(items))
+	assert.NotContains(t, items[0], "views")
+	assert.Equal(t, "test blog 2", items[0]["name"])
+
+	status, items, nextCursor, _ = list("limit=2&sort=views&select=name&after=" + nextCursor)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []float64{1, 3}, ids(items))
+	assert.NotContains(t, items[0], "views")
+
+	status, items, nextCursor, prevCursor := list("limit=2&sort=views&select=name&after=" + nextCursor)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []float64{5}, ids(items))
+	assert.Empty(t, nextCursor)
+
+	status, items, _, _ = list("limit=2&sort=views&select=name&before=" + prevCursor)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []float64{1, 3}, ids(items))
+
+	// Case 2: descending order, the selected sort column is returned
+	status, items, nextCursor, _ = list("pagination=cursor&limit=3&sort=-views&select=name,views")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []float64{5, 3, 1}, ids(items))
+	assert.Equal(t, float64(3), items[0]["views"])
+
+	status, items, nextCursor, prevCursor = list("limit=3&sort=-views&select=name,views&after=" + nextCursor)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []float64{2, 4}, ids(items))
+	assert.Nil(t, items[0]["views"])
+	assert.Empty(t, nextCursor)
+
+	status, items, _, _ = list("limit=3&sort=-views&select=name,views&before=" + prevCursor)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []float64{5, 3, 1}, ids(items))
+
+	// Case 3: the limit is clamped to one record
+	status, items, nextCursor, _ = list("pagination=cursor&limit=0&sort=views")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []float64{2}, ids(items))
+	assert.NotEmpty(t, nextCursor)
+}
+
+func TestContentServiceListWithSearch(t *testing.T) {
+	cs, server := createContentService(t)
+
+	blogModel := utils.Must(cs.DB().Model("blog"))
+	for _, name := range []string{"hello world", "another blog", "hello hello"} {
+		utils.Must(blogModel.CreateFromJSON(context.Background(), fmt.Sprintf(`{"name": %q}`, name)))
+	}
+
+	list := func(query string) (int, *TestPagination) {
+		req := httptest.NewRequest("GET", "/content/blog?"+query, nil)
+		resp := utils.Must(server.Test(req))
+		defer func() { assert.NoError(t, resp.Body.Close()) }()
+
+		var data TestResponse
+		response := utils.Must(utils.ReadCloserToString(resp.Body))
+		assert.NoError(t, json.Unmarshal([]byte(response), &data))
+		return resp.StatusCode, data.Data
+	}
+
+	ids := func(p *TestPagination) []float64 {
+		return utils.Map(p.Items, func(item *TestListItem) float64 {
+			return item.ID.(float64)
+		})
+	}
+
+	// Case 1: sorted by relevance by default
+	status, page := list("search=hello")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, uint(2), page.Total)
+	assert.Equal(t, []float64{3, 1}, ids(page))
+
+	// Case 2: custom sort
+	status, page = list("search=hello&sort=id")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []float64{1, 3}, ids(page))
+
+	// Case 3: search combined with filter
+	status, page = list(`search=hello&filter={"id":{"$gt":1}}`)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, []float64{3}, ids(page))
+
+	// Case 4: relevance sort is not supported with cursor pagination
+	status, _ = list("search=hello&pagination=cursor&sort=-_relevance")
+	assert.Equal(t, 400, status)
+
+	// Case 5: schema without searchable fields
+	req := httptest.NewRequest("GET", "/content/tag?search=hello", nil)
+	resp := utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 400, resp.StatusCode)
+}
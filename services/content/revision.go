@@ -0,0 +1,360 @@
+package contentservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/errors"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+)
+
+// RevisionChange holds the value of a field in a revision and in the current record.
+type RevisionChange struct {
+	Revision any `json:"revision"`
+	Current  any `json:"current"`
+}
+
+// RevisionDetail contains a revision, the current record and the fields that differ between them.
+//
+//	Current is nil if the record was deleted.
+type RevisionDetail struct {
+	*fs.Revision
+	Current *entity.Entity             `json:"current"`
+	Diff    map[string]*RevisionChange `json:"diff"`
+}
+
+// revisionIgnoredFields are the fields that are managed by the database, they are not restored.
+var revisionIgnoredFields = []string{
+	entity.FieldCreatedAt,
+	entity.FieldUpdatedAt,
+	entity.FieldDeletedAt,
+	entity.FieldVersion,
+	entity.FieldTenantID,
+}
+
+// RevisionUpdateHook keeps the versions of the records of the schemas with revisions before they are updated.
+func (cs *ContentService) RevisionUpdateHook(
+	ctx context.Context,
+	schema *schema.Schema,
+	predicates *[]*db.Predicate,
+	updateData *entity.Entity,
+	originalEntities []*entity.Entity,
+	affected int,
+) error {
+	return cs.createRevisions(ctx, schema, "update", originalEntities)
+}
+
+// RevisionDeleteHook keeps the versions of the records of the schemas with revisions before they are deleted.
+func (cs *ContentService) RevisionDeleteHook(
+	ctx context.Context,
+	schema *schema.Schema,
+	predicates *[]*db.Predicate,
+	originalEntities []*entity.Entity,
+	affected int,
+) error {
+	return cs.createRevisions(ctx, schema, "delete", originalEntities)
+}
+
+// createRevisions creates a revision for each record with the next number of the record revisions.
+// The oldest revisions of the records are deleted if there are more than the schema maximum.
+//
+//	The revisions are written in the transaction of the mutation if the hooks run in one,
+//	otherwise in their own transaction, so that the last number is read and the next one is
+//	inserted atomically. The unique index of the revisions rejects the concurrent duplicates.
+func (cs *ContentService) createRevisions(
+	ctx context.Context,
+	s *schema.Schema,
+	action string,
+	originalEntities []*entity.Entity,
+) error {
+	if s.Revisions == nil || len(originalEntities) == 0 {
+		return nil
+	}
+
+	client := db.TxFromContext(ctx, cs.DB())
+	if !client.IsTx() {
+		return db.WithTx(client, ctx, func(tx db.Client) error {
+			return cs.writeRevisions(ctx, tx, s, action, originalEntities)
+		})
+	}
+
+	return cs.writeRevisions(ctx, client, s, action, originalEntities)
+}
+
+func (cs *ContentService) writeRevisions(
+	ctx context.Context,
+	client db.Client,
+	s *schema.Schema,
+	action string,
+	originalEntities []*entity.Entity,
+) error {
+	pkName := s.PrimaryKeyName()
+	for _, original := range originalEntities {
+		recordID := fmt.Sprint(original.Get(pkName))
+		number, err := lastRevisionNumber(ctx, client, s.Name, recordID)
+		if err != nil {
+			return err
+		}
+
+		data := map[string]any{}
+		for _, field := range s.Fields {
+			if field.Type.IsRelationType() || (s.Name == "user" && field.Name == "password") {
+				continue
+			}
+
+			if value, ok := original.Data().Get(field.Name); ok {
+				data[field.Name] = value
+			}
+		}
+
+		number++
+		if _, err := db.Create[*fs.Revision](ctx, client, entity.New().
+			Set("schema", s.Name).
+			Set("record_id", recordID).
+			Set("number", number).
+			Set("action", action).
+			Set("tenant_id", original.GetString(entity.FieldTenantID)).
+			Set("data", data).
+			Set("created_at", time.Now()),
+		); err != nil {
+			return fmt.Errorf("create revision of %s %s: %w", s.Name, recordID, err)
+		}
+
+		if s.Revisions.Max > 0 && number > uint64(s.Revisions.Max) {
+			if _, err := db.Delete[*fs.Revision](ctx, client, []*db.Predicate{
+				db.EQ("schema", s.Name),
+				db.EQ("record_id", recordID),
+				db.LTE("number", number-uint64(s.Revisions.Max)),
+			}); err != nil {
+				return fmt.Errorf("delete revisions of %s %s: %w", s.Name, recordID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func lastRevisionNumber(ctx context.Context, client db.Client, schemaName, recordID string) (uint64, error) {
+	revision, err := db.Builder[*fs.Revision](client).
+		Where(db.EQ("schema", schemaName), db.EQ("record_id", recordID)).
+		Select("number").
+		Order("-number").
+		First(db.WithPrimary(ctx))
+	if db.IsNotFound(err) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return revision.Number, nil
+}
+
+// revisionPredicates returns the predicates of the revisions of the requested record.
+// The revisions of a tenant schema are scoped to the tenant of the request.
+func (cs *ContentService) revisionPredicates(c fs.Context) (db.Model, any, []*db.Predicate, error) {
+	model, err := cs.DB().Model(c.Arg("schema"))
+	if err != nil {
+		return nil, nil, nil, errors.BadRequest(err.Error())
+	}
+
+	s := model.Schema()
+	if s.Revisions == nil {
+		return nil, nil, nil, errors.BadRequest("schema %s does not have revisions", s.Name)
+	}
+
+	idValue, err := parseIDArg(s, c.Arg("id"))
+	if err != nil {
+		return nil, nil, nil, errors.NotFound(err.Error())
+	}
+
+	predicates := []*db.Predicate{
+		db.EQ("schema", s.Name),
+		db.EQ("record_id", fmt.Sprint(idValue)),
+	}
+
+	if s.Tenant && !db.UseAllTenants(c) {
+		tenantID := db.TenantFromContext(c)
+		if tenantID == "" {
+			return nil, nil, nil, db.ErrTenantRequired
+		}
+
+		predicates = append(predicates, db.EQ("tenant_id", tenantID))
+	}
+
+	// The revisions of the records that the row filter of the request does not match are not accessible
+	if len(db.RowFilterFromContext(c, s.Name)) > 0 {
+		count, err := model.Query(db.EQ(s.PrimaryKeyName(), idValue)).WithTrashed().Count(c)
+		if err != nil {
+			return nil, nil, nil, queryError(err, errors.InternalServerError)
+		}
+
+		if count == 0 {
+			return nil, nil, nil, errors.NotFound("%s %v not found", s.Name, idValue)
+		}
+	}
+
+	return model, idValue, predicates, nil
+}
+
+// stripRevisionData removes the fields that the request is not allowed to read from the revision snapshots.
+func stripRevisionData(c fs.Context, s *schema.Schema, revisions ...*fs.Revision) {
+	fieldPermissions := fs.FieldPermissionsFromContext(c, s.Name)
+	for _, revision := range revisions {
+		for name := range revision.Data {
+			if fieldPermissions.UnreadableField(s, name) != "" {
+				delete(revision.Data, name)
+			}
+		}
+	}
+}
+
+// Revisions returns the revisions of a record, the newest first.
+func (cs *ContentService) Revisions(c fs.Context, _ any) ([]*fs.Revision, error) {
+	model, _, predicates, err := cs.revisionPredicates(c)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := db.Builder[*fs.Revision](cs.DB()).Where(predicates...).Order("-number").Get(c)
+	if err != nil {
+		return nil, queryError(err, errors.InternalServerError)
+	}
+
+	stripRevisionData(c, model.Schema(), revisions...)
+	return revisions, nil
+}
+
+// revision returns the requested revision of a record and the model of the record schema.
+func (cs *ContentService) revision(c fs.Context) (db.Model, any, *fs.Revision, error) {
+	model, idValue, predicates, err := cs.revisionPredicates(c)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	number, err := strconv.ParseUint(c.Arg("rev"), 10, 64)
+	if err != nil {
+		return nil, nil, nil, errors.NotFound("invalid revision: %s", c.Arg("rev"))
+	}
+
+	revision, err := db.Builder[*fs.Revision](cs.DB()).
+		Where(append(predicates, db.EQ("number", number))...).
+		First(c)
+	if err != nil {
+		e := utils.If(db.IsNotFound(err), errors.NotFound, errors.InternalServerError)
+		return nil, nil, nil, e(err.Error())
+	}
+
+	if revision.Data == nil {
+		revision.Data = map[string]any{}
+	}
+
+	return model, idValue, revision, nil
+}
+
+// Revision returns a revision of a record and the fields that differ from the current record.
+func (cs *ContentService) Revision(c fs.Context, _ any) (*RevisionDetail, error) {
+	model, idValue, revision, err := cs.revision(c)
+	if err != nil {
+		return nil, err
+	}
+
+	stripRevisionData(c, model.Schema(), revision)
+	current, err := model.Query(db.EQ(model.Schema().PrimaryKeyName(), idValue)).First(c)
+	if err != nil && !db.IsNotFound(err) {
+		return nil, errors.InternalServerError(err.Error())
+	}
+
+	if current != nil && model.Schema().Name == "user" {
+		current.Delete("password")
+	}
+
+	if fieldPermissions := fs.FieldPermissionsFromContext(c, model.Schema().Name); current != nil && fieldPermissions != nil {
+		fieldPermissions.Strip(model.Schema(), current)
+	}
+
+	detail := &RevisionDetail{
+		Revision: revision,
+		Current:  current,
+		Diff:     map[string]*RevisionChange{},
+	}
+
+	for _, field := range model.Schema().Fields {
+		if field.Type.IsRelationType() || slices.Contains(revisionIgnoredFields, field.Name) {
+			continue
+		}
+
+		revisionValue, inRevision := revision.Data[field.Name]
+		if !inRevision {
+			continue
+		}
+
+		var currentValue any
+		if current != nil {
+			currentValue = current.Get(field.Name)
+		}
+
+		// The values are compared as JSON since the revision values are decoded from JSON
+		revisionJSON, _ := json.Marshal(revisionValue)
+		currentJSON, _ := json.Marshal(currentValue)
+		if string(revisionJSON) != string(currentJSON) {
+			detail.Diff[field.Name] = &RevisionChange{Revision: revisionValue, Current: currentValue}
+		}
+	}
+
+	return detail, nil
+}
+
+// RevisionRestore restores the fields of a record to the values of a revision.
+//
+//	The record is updated with the mutation of the schema, so that the hooks and the realtime events run
+//	and the current version of the record is kept as a new revision.
+//	The relations and the fields managed by the database are not restored.
+func (cs *ContentService) RevisionRestore(c fs.Context, _ any) (*entity.Entity, error) {
+	model, idValue, revision, err := cs.revision(c)
+	if err != nil {
+		return nil, err
+	}
+
+	pkName := model.Schema().PrimaryKeyName()
+	restoreData := entity.New()
+	for name, value := range revision.Data {
+		if name == pkName || slices.Contains(revisionIgnoredFields, name) {
+			continue
+		}
+
+		restoreData.Set(name, value)
+	}
+
+	if err := checkWritableFields(c, model.Schema().Name, restoreData); err != nil {
+		return nil, err
+	}
+
+	affected, err := model.Mutation().Where(db.EQ(pkName, idValue)).Update(c, restoreData)
+	if err != nil {
+		if isValidationError(err) {
+			return nil, errors.BadRequest(err.Error())
+		}
+		return nil, errors.InternalServerError(err.Error())
+	}
+
+	if affected == 0 {
+		return nil, errors.NotFound("%s %v not found, the deleted records cannot be restored", model.Schema().Name, idValue)
+	}
+
+	restored, err := model.Query(db.EQ(pkName, idValue)).First(c)
+	if err != nil {
+		return nil, errors.InternalServerError(err.Error())
+	}
+
+	return restored.Delete("password"), nil
+}
@@ -0,0 +1,254 @@
+package contentservice_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/logger"
+	"github.com/fastschema/fastschema/pkg/entdbadapter"
+	rr "github.com/fastschema/fastschema/pkg/restfulresolver"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	cs "github.com/fastschema/fastschema/services/content"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createRevisionContentService(t *testing.T, middlewares ...fs.Middleware) (*testApp, *rr.Server) {
+	schemaDir := t.TempDir()
+	utils.WriteFile(schemaDir+"/article.json", `{
+		"name": "article",
+		"namespace": "articles",
+		"label_field": "title",
+		"optimistic_lock": true,
+		"revisions": {"max": 2},
+		"fields": [
+			{ "type": "string", "name": "title", "label": "Title" },
+			{ "type": "int", "name": "views", "label": "Views", "optional": true }
+		]
+	}`)
+	utils.WriteFile(schemaDir+"/page.json", `{
+		"name": "page",
+		"namespace": "pages",
+		"label_field": "title",
+		"fields": [{ "type": "string", "name": "title", "label": "Title" }]
+	}`)
+
+	testApp := &testApp{}
+	contentService := cs.New(testApp)
+	testApp.sb = utils.Must(schema.NewBuilderFromDir(schemaDir, fs.SystemSchemaTypes...))
+	testApp.db = utils.Must(entdbadapter.NewTestClient(
+		utils.Must(os.MkdirTemp("", "migrations")),
+		testApp.sb,
+		func() *db.Hooks {
+			return &db.Hooks{
+				PostDBUpdate: []db.PostDBUpdate{contentService.RevisionUpdateHook},
+				PostDBDelete: []db.PostDBDelete{contentService.RevisionDeleteHook},
+			}
+		},
+	))
+
+	testApp.resources = fs.NewResourcesManager()
+	testApp.resources.Middlewares = append(testApp.resources.Middlewares, middlewares...)
+	contentService.CreateResource(testApp.resources.Group("api"))
+	require.NoError(t, testApp.resources.Init())
+	restResolver := rr.NewRestfulResolver(&rr.ResolverConfig{
+		ResourceManager: testApp.resources,
+		Logger:          logger.CreateMockLogger(true),
+	})
+
+	return testApp, restResolver.Server()
+}
+
+func TestContentRevisions(t *testing.T) {
+	testApp, server := createRevisionContentService(t)
+	articleModel := utils.Must(testApp.db.Model("article"))
+	pageModel := utils.Must(testApp.db.Model("page"))
+	articleID := utils.Must(articleModel.Mutation().Create(context.Background(), entity.New().
+		Set("title", "v1").
+		Set("views", 1)))
+	_ = utils.Must(pageModel.Mutation().Create(context.Background(), entity.New().Set("title", "page")))
+	_ = utils.Must(articleModel.Mutation().Where(db.EQ("id", articleID)).Update(context.Background(), entity.New().
+		Set("title", "v2")))
+	_ = utils.Must(articleModel.Mutation().Where(db.EQ("id", articleID)).Update(context.Background(), entity.New().
+		Set("title", "v3").
+		Set("views", 3)))
+
+	request := func(method, path string) (int, string) {
+		path = strings.ReplaceAll(path, ":id", fmt.Sprint(articleID))
+		resp := utils.Must(server.Test(httptest.NewRequest(method, "/api/content"+path, nil)))
+		defer func() { assert.NoError(t, resp.Body.Close()) }()
+		return resp.StatusCode, utils.Must(utils.ReadCloserToString(resp.Body))
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		expectStatus int
+		expectBody   []string
+	}{
+		{
+			name:         "schema without revisions",
+			method:       "GET",
+			path:         "/page/:id/revisions",
+			expectStatus: 400,
+			expectBody:   []string{`schema page does not have revisions`},
+		},
+		{
+			name:         "list",
+			method:       "GET",
+			path:         "/article/:id/revisions",
+			expectStatus: 200,
+			expectBody: []string{
+				`"number":2,"action":"update"`,
+				`"title":"v2","updated_at"`,
+				`"number":1,"action":"update"`,
+				`"title":"v1","version":1,"views":1}`,
+			},
+		},
+		{
+			name:         "invalid revision",
+			method:       "GET",
+			path:         "/article/:id/revisions/invalid",
+			expectStatus: 404,
+			expectBody:   []string{`invalid revision: invalid`},
+		},
+		{
+			name:         "revision not found",
+			method:       "GET",
+			path:         "/article/:id/revisions/10",
+			expectStatus: 404,
+		},
+		{
+			name:         "detail",
+			method:       "GET",
+			path:         "/article/:id/revisions/1",
+			expectStatus: 200,
+			expectBody: []string{
+				`"number":1`,
+				`"title":"v3","views":3`,
+				`"diff":{"title":{"revision":"v1","current":"v3"},"views":{"revision":1,"current":3}}`,
+			},
+		},
+		{
+			name:         "restore",
+			method:       "POST",
+			path:         "/article/:id/revisions/1/restore",
+			expectStatus: 200,
+			expectBody:   []string{`"title":"v1","views":1`, `"version":4`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response := request(tt.method, tt.path)
+			assert.Equal(t, tt.expectStatus, status, response)
+			for _, expectBody := range tt.expectBody {
+				assert.Contains(t, response, expectBody)
+			}
+		})
+	}
+
+	// The restore keeps the replaced version and the oldest revision is deleted
+	revisions := utils.Must(db.Builder[*fs.Revision](testApp.db).Order("number").Get(context.Background()))
+	require.Len(t, revisions, 2)
+	assert.Equal(t, []uint64{2, 3}, utils.Map(revisions, func(r *fs.Revision) uint64 { return r.Number }))
+	assert.Equal(t, "v3", revisions[1].Data["title"])
+
+	// The deleted records keep their revisions but cannot be restored
+	_ = utils.Must(articleModel.Mutation().Where(db.EQ("id", articleID)).Delete(context.Background()))
+	status, response := request("GET", "/article/:id/revisions/4")
+	assert.Equal(t, 200, status, response)
+	assert.Contains(t, response, `"action":"delete"`)
+	assert.Contains(t, response, `"current":null`)
+	assert.Contains(t, response, `"title":{"revision":"v1","current":null}`)
+
+	status, response = request("POST", "/article/:id/revisions/4/restore")
+	assert.Equal(t, 404, status, response)
+	assert.Contains(t, response, `the deleted records cannot be restored`)
+}
+
+func TestContentRevisionsPermissions(t *testing.T) {
+	permission := &fs.Permission{Value: "allow", Fields: `{"read": ["*", "-views"]}`}
+	require.NoError(t, permission.Compile())
+	testApp, server := createRevisionContentService(t, func(c fs.Context) error {
+		c.Local(fs.FieldPermissionsKey, &fs.FieldPermissions{
+			Schema:      "article",
+			Permissions: []*fs.Permission{permission},
+		})
+		c.Local(db.RowFilterKey, &db.RowFilter{
+			Schema:     "article",
+			Predicates: []*db.Predicate{db.EQ("title", "a2")},
+		})
+		return c.Next()
+	})
+
+	ctx := context.Background()
+	articleModel := utils.Must(testApp.db.Model("article"))
+	ids := []any{}
+	for _, title := range []string{"a", "b"} {
+		id := utils.Must(articleModel.Mutation().Create(ctx, entity.New().Set("title", title+"1").Set("views", 1)))
+		_ = utils.Must(articleModel.Mutation().Where(db.EQ("id", id)).Update(ctx, entity.New().Set("title", title+"2")))
+		ids = append(ids, id)
+	}
+
+	request := func(path string) (int, string) {
+		resp := utils.Must(server.Test(httptest.NewRequest("GET", "/api/content/article"+path, nil)))
+		defer func() { assert.NoError(t, resp.Body.Close()) }()
+		return resp.StatusCode, utils.Must(utils.ReadCloserToString(resp.Body))
+	}
+
+	// The unreadable fields are removed from the revision snapshots
+	status, response := request(fmt.Sprintf("/%v/revisions", ids[0]))
+	assert.Equal(t, 200, status, response)
+	assert.Contains(t, response, `"title":"a1"`)
+	assert.NotContains(t, response, `"views"`)
+
+	status, response = request(fmt.Sprintf("/%v/revisions/1", ids[0]))
+	assert.Equal(t, 200, status, response)
+	assert.Contains(t, response, `"diff":{"title":{"revision":"a1","current":"a2"}}`)
+	assert.NotContains(t, response, `"views"`)
+
+	// The revisions of the records that the row filter does not match are not accessible
+	status, _ = request(fmt.Sprintf("/%v/revisions", ids[1]))
+	assert.Equal(t, 404, status)
+	status, _ = request(fmt.Sprintf("/%v/revisions/1", ids[1]))
+	assert.Equal(t, 404, status)
+
+	// The revision numbers are unique per record
+	_, err := db.Create[*fs.Revision](ctx, testApp.db, entity.New().
+		Set("schema", "article").
+		Set("record_id", fmt.Sprint(ids[0])).
+		Set("number", 1).
+		Set("action", "update"))
+	assert.Error(t, err)
+}
+
+func TestContentRevisionsTransaction(t *testing.T) {
+	testApp, _ := createRevisionContentService(t)
+	ctx := context.Background()
+	articleModel := utils.Must(testApp.db.Model("article"))
+	articleID := utils.Must(articleModel.Mutation().Create(ctx, entity.New().Set("title", "v1")))
+
+	// The revisions are written in the transaction of the update, they are rolled back with it
+	err := db.WithTx(testApp.db, ctx, func(tx db.Client) error {
+		model := utils.Must(tx.Model("article"))
+		if _, err := model.Mutation().Where(db.EQ("id", articleID)).Update(ctx, entity.New().Set("title", "v2")); err != nil {
+			return err
+		}
+
+		assert.Equal(t, 1, utils.Must(db.Builder[*fs.Revision](tx).Count(ctx)))
+		return errors.New("rollback")
+	})
+	assert.EqualError(t, err, "rollback")
+	assert.Equal(t, 0, utils.Must(db.Builder[*fs.Revision](testApp.db).Count(ctx)))
+}
@@ -0,0 +1,262 @@
+package contentservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/errors"
+	"github.com/fastschema/fastschema/schema"
+)
+
+// hasTrash reports whether the deleted records of the schema are kept in the trash.
+func (cs *ContentService) hasTrash(s *schema.Schema) bool {
+	return cs.DB().Config().UseSoftDeletes && !s.IsJunctionSchema && s.Field(entity.FieldDeletedAt) != nil
+}
+
+// trashModel returns the model of the requested schema if its deleted records are kept in the trash.
+func (cs *ContentService) trashModel(c fs.Context) (db.Model, error) {
+	model, err := cs.DB().Model(c.Arg("schema"))
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	if !cs.hasTrash(model.Schema()) {
+		return nil, errors.BadRequest("schema %s does not have a trash, soft deletes are not enabled", model.Schema().Name)
+	}
+
+	return model, nil
+}
+
+// trashedIDs returns the IDs of the records in the trash that match the filter argument.
+func (cs *ContentService) trashedIDs(c fs.Context, model db.Model) ([]any, error) {
+	predicates, err := db.CreatePredicatesFromFilterObject(
+		cs.DB().SchemaBuilder(),
+		model.Schema(),
+		c.Arg("filter"),
+	)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	pkName := model.Schema().PrimaryKeyName()
+	records, err := model.Query(predicates...).OnlyTrashed().Select(pkName).Get(c)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	ids := make([]any, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.Get(pkName))
+	}
+
+	return ids, nil
+}
+
+// trashedID returns the ID argument if the record is in the trash.
+func (cs *ContentService) trashedID(c fs.Context, model db.Model) (any, error) {
+	idValue, err := parseIDArg(model.Schema(), c.Arg("id"))
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	pkName := model.Schema().PrimaryKeyName()
+	if _, err := model.Query(db.EQ(pkName, idValue)).OnlyTrashed().Select(pkName).Only(c); err != nil {
+		if db.IsNotFound(err) {
+			return nil, errors.NotFound("%s %v not found in the trash", model.Schema().Name, idValue)
+		}
+
+		return nil, errors.InternalServerError(err.Error())
+	}
+
+	return idValue, nil
+}
+
+// TrashList returns the records in the trash, the filter and the pagination arguments are the same as the list.
+func (cs *ContentService) TrashList(c fs.Context, _ any) (*Pagination, error) {
+	model, err := cs.trashModel(c)
+	if err != nil {
+		return nil, err
+	}
+
+	predicates, err := db.CreatePredicatesFromFilterObject(
+		cs.DB().SchemaBuilder(),
+		model.Schema(),
+		c.Arg("filter", ""),
+	)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	total, err := model.Query(predicates...).OnlyTrashed().Count(c, &db.QueryOption{})
+	if err != nil {
+		return nil, queryError(err, errors.BadRequest)
+	}
+
+	page := uint(max(c.ArgInt("page", 1), 1))
+	limit := uint(max(c.ArgInt("limit", 10), 1))
+	records, err := model.Query(predicates...).
+		OnlyTrashed().
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Order(c.Arg("sort", "-"+model.Schema().PrimaryKeyName())).
+		Get(c)
+	if err != nil {
+		return nil, queryError(err, errors.InternalServerError)
+	}
+
+	for _, record := range records {
+		record.Delete("password")
+	}
+
+	return NewPagination(uint(total), limit, page, records), nil
+}
+
+// TrashRestore restores a record from the trash.
+func (cs *ContentService) TrashRestore(c fs.Context, _ any) (*entity.Entity, error) {
+	model, err := cs.trashModel(c)
+	if err != nil {
+		return nil, err
+	}
+
+	idValue, err := cs.trashedID(c, model)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := cs.restore(c, model.Schema(), []any{idValue}); err != nil {
+		return nil, err
+	}
+
+	restored, err := model.Query(db.EQ(model.Schema().PrimaryKeyName(), idValue)).First(db.WithPrimary(c))
+	if err != nil {
+		return nil, errors.InternalServerError(err.Error())
+	}
+
+	return restored.Delete("password"), nil
+}
+
+// TrashBulkRestore restores the records in the trash that match the filter.
+func (cs *ContentService) TrashBulkRestore(c fs.Context, _ any) (int, error) {
+	model, err := cs.trashModel(c)
+	if err != nil {
+		return 0, err
+	}
+
+	ids, err := cs.trashedIDs(c, model)
+	if err != nil {
+		return 0, err
+	}
+
+	return cs.restore(c, model.Schema(), ids)
+}
+
+// TrashPurge permanently deletes a record from the trash.
+func (cs *ContentService) TrashPurge(c fs.Context, _ any) (any, error) {
+	model, err := cs.trashModel(c)
+	if err != nil {
+		return nil, err
+	}
+
+	idValue, err := cs.trashedID(c, model)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := cs.purge(c, model, []any{idValue}); err != nil {
+		return nil, errors.InternalServerError(err.Error())
+	}
+
+	return entity.New(idValue), nil
+}
+
+// TrashBulkPurge permanently deletes the records in the trash that match the filter.
+func (cs *ContentService) TrashBulkPurge(c fs.Context, _ any) (int, error) {
+	model, err := cs.trashModel(c)
+	if err != nil {
+		return 0, err
+	}
+
+	ids, err := cs.trashedIDs(c, model)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := cs.purge(c, model, ids)
+	if err != nil {
+		return 0, errors.InternalServerError(err.Error())
+	}
+
+	return affected, nil
+}
+
+// PurgeTrash permanently deletes the records of all the schemas that were moved to the trash before the given time.
+//
+//	It is run periodically when the trash retention is set.
+func (cs *ContentService) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	ctx = db.WithAllTenants(ctx)
+	purged := 0
+	for _, s := range cs.DB().SchemaBuilder().Schemas() {
+		if !cs.hasTrash(s) {
+			continue
+		}
+
+		model, err := cs.DB().Model(s.Name)
+		if err != nil {
+			return purged, err
+		}
+
+		pkName := s.PrimaryKeyName()
+		records, err := model.Query(db.LT(entity.FieldDeletedAt, before)).OnlyTrashed().Select(pkName).Get(ctx)
+		if err != nil {
+			return purged, fmt.Errorf("purge trash of %s: %w", s.Name, err)
+		}
+
+		ids := make([]any, 0, len(records))
+		for _, record := range records {
+			ids = append(ids, record.Get(pkName))
+		}
+
+		affected, err := cs.purge(ctx, model, ids)
+		if err != nil {
+			return purged, fmt.Errorf("purge trash of %s: %w", s.Name, err)
+		}
+
+		purged += affected
+	}
+
+	return purged, nil
+}
+
+// restore clears the deleted_at of the records and of their m2m junction rows.
+func (cs *ContentService) restore(ctx context.Context, s *schema.Schema, ids []any) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	model, err := cs.DB().Model(s.Name)
+	if err != nil {
+		return 0, errors.BadRequest(err.Error())
+	}
+
+	affected, err := model.Mutation().Where(db.In(s.PrimaryKeyName(), ids)).Restore(ctx)
+	if err != nil {
+		return 0, queryError(err, errors.InternalServerError)
+	}
+
+	return affected, nil
+}
+
+// purge permanently deletes the records, the junction rows are deleted by the foreign keys.
+func (cs *ContentService) purge(ctx context.Context, model db.Model, ids []any) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	return model.Mutation().
+		Where(db.In(model.Schema().PrimaryKeyName(), ids)).
+		Delete(db.WithForceDelete(ctx))
+}
@@ -0,0 +1,290 @@
+package contentservice_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/logger"
+	"github.com/fastschema/fastschema/pkg/entdbadapter"
+	rr "github.com/fastschema/fastschema/pkg/restfulresolver"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	cs "github.com/fastschema/fastschema/services/content"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTrashContentService(t *testing.T, useSoftDeletes bool) (*testApp, *cs.ContentService, *rr.Server) {
+	schemaDir := t.TempDir()
+	utils.WriteFile(schemaDir+"/post.json", `{
+		"name": "post",
+		"namespace": "posts",
+		"label_field": "title",
+		"fields": [
+			{
+				"type": "uint64",
+				"name": "id",
+				"label": "ID",
+				"db": {"attr": "UNSIGNED", "key": "PRIMARY", "increment": true}
+			},
+			{ "type": "string", "name": "title", "label": "Title", "filterable": true },
+			{
+				"type": "relation",
+				"name": "tags",
+				"label": "Tags",
+				"optional": true,
+				"relation": { "schema": "tag", "field": "posts", "type": "m2m", "owner": true, "optional": true }
+			}
+		]
+	}`)
+	utils.WriteFile(schemaDir+"/tag.json", `{
+		"name": "tag",
+		"namespace": "tags",
+		"label_field": "name",
+		"fields": [
+			{
+				"type": "uint64",
+				"name": "id",
+				"label": "ID",
+				"db": {"attr": "UNSIGNED", "key": "PRIMARY", "increment": true}
+			},
+			{ "type": "string", "name": "name", "label": "Name" },
+			{
+				"type": "relation",
+				"name": "posts",
+				"label": "Posts",
+				"optional": true,
+				"relation": { "schema": "post", "field": "tags", "type": "m2m", "optional": true }
+			}
+		]
+	}`)
+
+	testApp := &testApp{}
+	contentService := cs.New(testApp)
+	testApp.sb = utils.Must(schema.NewBuilderFromDir(schemaDir, fs.SystemSchemaTypes...))
+	testApp.db = utils.Must(entdbadapter.NewClient(&db.Config{
+		Driver:         "sqlite",
+		Name:           ":memory:_" + utils.RandomString(10),
+		MigrationDir:   utils.Must(os.MkdirTemp("", "migrations")),
+		UseSoftDeletes: useSoftDeletes,
+	}, testApp.sb))
+
+	testApp.resources = fs.NewResourcesManager()
+	contentService.CreateResource(testApp.resources.Group("api"))
+	require.NoError(t, testApp.resources.Init())
+	restResolver := rr.NewRestfulResolver(&rr.ResolverConfig{
+		ResourceManager: testApp.resources,
+		Logger:          logger.CreateMockLogger(true),
+	})
+
+	return testApp, contentService, restResolver.Server()
+}
+
+func TestContentTrashDisabled(t *testing.T) {
+	_, _, server := createTrashContentService(t, false)
+	resp := utils.Must(server.Test(httptest.NewRequest("GET", "/api/content/post/trash", nil)))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	response := utils.Must(utils.ReadCloserToString(resp.Body))
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Contains(t, response, `schema post does not have a trash`)
+}
+
+func TestContentTrash(t *testing.T) {
+	testApp, _, server := createTrashContentService(t, true)
+	ctx := context.Background()
+	tagModel := utils.Must(testApp.db.Model("tag"))
+	postModel := utils.Must(testApp.db.Model("post"))
+	tagID := utils.Must(tagModel.Create(ctx, entity.New().Set("name", "go")))
+	for _, title := range []string{"first", "second", "third", "fourth"} {
+		_ = utils.Must(postModel.Create(ctx, entity.New().
+			Set("title", title).
+			Set("tags", []*entity.Entity{entity.New(tagID)})))
+	}
+
+	_ = utils.Must(postModel.Mutation().Where(db.In("title", []any{"first", "second", "third"})).Delete(ctx))
+	junctionModel := utils.Must(testApp.db.Model("posts_tags"))
+	assert.Equal(t, 1, utils.Must(junctionModel.Query().Count(ctx)))
+
+	request := func(method, path string, query url.Values) (int, string) {
+		resp := utils.Must(server.Test(httptest.NewRequest(method, "/api/content/post"+path+"?"+query.Encode(), nil)))
+		defer func() { assert.NoError(t, resp.Body.Close()) }()
+		return resp.StatusCode, utils.Must(utils.ReadCloserToString(resp.Body))
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		query        url.Values
+		expectStatus int
+		expectBody   []string
+	}{
+		{
+			name:         "list",
+			method:       "GET",
+			path:         "/trash",
+			query:        url.Values{"limit": {"2"}},
+			expectStatus: 200,
+			expectBody:   []string{`"total":3`, `"last_page":2`, `"title":"third"`, `"title":"second"`},
+		},
+		{
+			name:         "list filter",
+			method:       "GET",
+			path:         "/trash",
+			query:        url.Values{"filter": {`{"title":"first"}`}},
+			expectStatus: 200,
+			expectBody:   []string{`"total":1`, `"title":"first"`},
+		},
+		{
+			name:         "restore not in trash",
+			method:       "POST",
+			path:         "/trash/4/restore",
+			expectStatus: 404,
+			expectBody:   []string{`post 4 not found in the trash`},
+		},
+		{
+			name:         "restore",
+			method:       "POST",
+			path:         "/trash/1/restore",
+			expectStatus: 200,
+			expectBody:   []string{`"id":1`, `"title":"first"`},
+		},
+		{
+			name:         "purge not in trash",
+			method:       "DELETE",
+			path:         "/trash/1",
+			expectStatus: 404,
+		},
+		{
+			name:         "purge",
+			method:       "DELETE",
+			path:         "/trash/2",
+			expectStatus: 200,
+			expectBody:   []string{`"id":2`},
+		},
+		{
+			name:         "bulk restore",
+			method:       "POST",
+			path:         "/trash/restore",
+			query:        url.Values{"filter": {`{"title":{"$in":["third","fourth"]}}`}},
+			expectStatus: 200,
+			expectBody:   []string{`"data":1`},
+		},
+		{
+			name:         "empty trash",
+			method:       "GET",
+			path:         "/trash",
+			expectStatus: 200,
+			expectBody:   []string{`"total":0`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response := request(tt.method, tt.path, tt.query)
+			assert.Equal(t, tt.expectStatus, status, response)
+			for _, expectBody := range tt.expectBody {
+				assert.Contains(t, response, expectBody)
+			}
+		})
+	}
+
+	// The restored posts are linked to the tag again, the purged post and its junction row are deleted
+	posts := utils.Must(postModel.Query().WithTrashed().Select("title").Order("id").Get(ctx))
+	assert.Equal(t, []any{"first", "third", "fourth"}, utils.Map(posts, func(e *entity.Entity) any {
+		return e.Get("title")
+	}))
+	assert.Equal(t, 3, utils.Must(junctionModel.Query().Count(ctx)))
+	assert.Equal(t, 3, utils.Must(junctionModel.Query().WithTrashed().Count(ctx)))
+	tag := utils.Must(tagModel.Query(db.EQ("id", tagID)).Select("posts").First(ctx))
+	assert.Len(t, tag.Get("posts"), 3)
+
+	_ = utils.Must(postModel.Mutation().Where(db.EQ("title", "fourth")).Delete(ctx))
+	status, response := request("DELETE", "/trash", nil)
+	assert.Equal(t, 200, status, response)
+	assert.Contains(t, response, `"data":1`)
+	assert.Equal(t, 2, utils.Must(postModel.Query().WithTrashed().Count(ctx)))
+}
+
+func TestContentTrashHooks(t *testing.T) {
+	testApp, _, server := createTrashContentService(t, true)
+	ctx := context.Background()
+	postModel := utils.Must(testApp.db.Model("post"))
+	postID := utils.Must(postModel.Create(ctx, entity.New().Set("title", "first")))
+
+	calls := []string{}
+	var restoredOriginals []*entity.Entity
+	testApp.db.Config().Hooks = func() *db.Hooks {
+		return &db.Hooks{
+			PreDBUpdate: []db.PreDBUpdate{func(
+				ctx context.Context,
+				schema *schema.Schema,
+				predicates *[]*db.Predicate,
+				updateData *entity.Entity,
+			) error {
+				calls = append(calls, "pre update")
+				return nil
+			}},
+			PostDBUpdate: []db.PostDBUpdate{func(
+				ctx context.Context,
+				schema *schema.Schema,
+				predicates *[]*db.Predicate,
+				updateData *entity.Entity,
+				originalEntities []*entity.Entity,
+				affected int,
+			) error {
+				calls = append(calls, "post update")
+				restoredOriginals = originalEntities
+				return nil
+			}},
+			PostDBDelete: []db.PostDBDelete{func(
+				ctx context.Context,
+				schema *schema.Schema,
+				predicates *[]*db.Predicate,
+				originalEntities []*entity.Entity,
+				affected int,
+			) error {
+				calls = append(calls, "post delete")
+				return nil
+			}},
+		}
+	}
+
+	// The soft delete runs the delete hooks only
+	_ = utils.Must(postModel.Mutation().Where(db.EQ("id", postID)).Delete(ctx))
+	assert.Equal(t, []string{"post delete"}, calls)
+
+	// The restore runs the update hooks with the trashed record as the original entity
+	calls = []string{}
+	resp := utils.Must(server.Test(httptest.NewRequest("POST", "/api/content/post/trash/1/restore", nil)))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, []string{"pre update", "post update"}, calls)
+	require.Len(t, restoredOriginals, 1)
+	assert.Equal(t, "first", restoredOriginals[0].Get("title"))
+	assert.NotNil(t, restoredOriginals[0].Get(entity.FieldDeletedAt))
+}
+
+func TestContentPurgeTrash(t *testing.T) {
+	testApp, contentService, _ := createTrashContentService(t, true)
+	ctx := context.Background()
+	postModel := utils.Must(testApp.db.Model("post"))
+	for _, title := range []string{"first", "second"} {
+		_ = utils.Must(postModel.Create(ctx, entity.New().Set("title", title)))
+	}
+
+	_ = utils.Must(postModel.Mutation().Where(db.EQ("title", "first")).Delete(ctx))
+	purged := utils.Must(contentService.PurgeTrash(ctx, time.Now().Add(-time.Hour)))
+	assert.Equal(t, 0, purged)
+
+	purged = utils.Must(contentService.PurgeTrash(ctx, time.Now().Add(time.Hour)))
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, 1, utils.Must(postModel.Query().WithTrashed().Count(ctx)))
+}
@@ -1,12 +1,15 @@
 package contentservice
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/fastschema/fastschema/db"
 	"github.com/fastschema/fastschema/entity"
 	"github.com/fastschema/fastschema/fs"
 	"github.com/fastschema/fastschema/pkg/errors"
+	"github.com/fastschema/fastschema/schema"
 )
 
 // isValidationError checks if the error is a client input validation error
@@ -20,6 +23,34 @@ func isValidationError(err error) bool {
 	return strings.Contains(msg, "column") && strings.Contains(msg, "not found")
 }
 
+// setExpectedVersion sets the version of the If-Match header to the entity of a schema with optimistic lock.
+// The header takes precedence over the version field of the payload.
+func setExpectedVersion(c fs.Context, s *schema.Schema, e *entity.Entity) error {
+	ifMatch := strings.TrimSpace(c.Header("If-Match"))
+	if !s.OptimisticLock || ifMatch == "" {
+		return nil
+	}
+
+	version, err := strconv.ParseUint(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid If-Match header: %s", ifMatch)
+	}
+
+	e.Set(entity.FieldVersion, version)
+	return nil
+}
+
+// incrementVersion sets the version of the updated entity if the update was checked against a version.
+func incrementVersion(s *schema.Schema, e *entity.Entity) {
+	if !s.OptimisticLock {
+		return
+	}
+
+	if version, err := e.GetUint64(entity.FieldVersion, false); err == nil {
+		e.Set(entity.FieldVersion, version+1)
+	}
+}
+
 func (cs *ContentService) Update(c fs.Context, _ any) (*entity.Entity, error) {
 	schemaName := c.Arg("schema")
 	model, err := cs.DB().Model(schemaName)
@@ -39,7 +70,24 @@ func (cs *ContentService) Update(c fs.Context, _ any) (*entity.Entity, error) {
 	}
 
 	entity.SetIDField(pkName)
+	if err := checkWritableFields(c, schemaName, entity); err != nil {
+		return nil, err
+	}
+
+	if err := setExpectedVersion(c, model.Schema(), entity); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
 	if _, err := model.Mutation().Where(db.EQ(pkName, idValue)).Update(c, entity); err != nil {
+		if errors.Is(err, db.ErrVersionConflict) {
+			return nil, err
+		}
+		if db.IsNotFound(err) {
+			return nil, errors.NotFound("%s %v not found", schemaName, idValue)
+		}
+		if verr := validationError(err); verr != nil {
+			return nil, verr
+		}
 		if isValidationError(err) {
 			return nil, errors.BadRequest(err.Error())
 		}
@@ -50,6 +98,7 @@ func (cs *ContentService) Update(c fs.Context, _ any) (*entity.Entity, error) {
 		return nil, errors.InternalServerError(err.Error())
 	}
 
+	incrementVersion(model.Schema(), entity)
 	return entity.Delete("password"), nil
 }
 
@@ -76,8 +125,15 @@ func (cs *ContentService) BulkUpdate(c fs.Context, _ any) (int, error) {
 		return 0, nil
 	}
 
+	if err := checkWritableFields(c, model.Schema().Name, entity); err != nil {
+		return 0, err
+	}
+
 	updatedCount, err := model.Mutation().Where(predicates...).Update(c, entity)
 	if err != nil {
+		if verr := validationError(err); verr != nil {
+			return 0, verr
+		}
 		if isValidationError(err) {
 			return 0, errors.BadRequest(err.Error())
 		}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/fastschema/fastschema/db"
@@ -92,6 +93,48 @@ func TestContentServiceUpdate(t *testing.T) {
 	assert.NotEqual(t, user.GetString("password"), userUpdated.GetString("password"))
 }
 
+func TestContentServiceUpdateValidation(t *testing.T) {
+	cs, server := createContentService(t)
+	blogModel := utils.Must(cs.DB().Model("blog"))
+	blogID := utils.Must(blogModel.CreateFromJSON(context.Background(), `{"name": "test blog"}`))
+
+	req := httptest.NewRequest(
+		"PUT",
+		fmt.Sprintf("/content/blog/%v", blogID),
+		bytes.NewReader([]byte(`{"name": "`+strings.Repeat("a", 101)+`", "views": -1}`)),
+	)
+	resp := utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 422, resp.StatusCode)
+	body := utils.Must(utils.ReadCloserToString(resp.Body))
+	assert.Contains(t, body, `{"code":"field.value.max_length","field":"name","message":"must be at most 100 characters"}`)
+	assert.Contains(t, body, `{"code":"field.value.min","field":"views","message":"must be greater than or equal to 0"}`)
+
+	// The blog is not updated
+	blog := utils.Must(blogModel.Query(db.EQ("id", blogID)).First(context.Background()))
+	assert.Equal(t, "test blog", blog.GetString("name"))
+
+	// The added values are validated against the existing values
+	update := func(body string) (int, string) {
+		req := httptest.NewRequest("PUT", fmt.Sprintf("/content/blog/%v", blogID), bytes.NewReader([]byte(body)))
+		resp := utils.Must(server.Test(req))
+		defer func() { assert.NoError(t, resp.Body.Close()) }()
+		return resp.StatusCode, utils.Must(utils.ReadCloserToString(resp.Body))
+	}
+
+	status, body := update(`{"$add": {"views": 2}}`)
+	assert.Equal(t, 200, status, body)
+
+	status, body = update(`{"$add": {"views": -3}}`)
+	assert.Equal(t, 422, status)
+	assert.Contains(t, body, `{"code":"field.value.min","field":"views","message":"must be greater than or equal to 0"}`)
+
+	status, _ = update(`{"$add": {"views": -2}}`)
+	assert.Equal(t, 200, status)
+	blog = utils.Must(blogModel.Query(db.EQ("id", blogID)).First(context.Background()))
+	assert.Equal(t, 0, blog.Get("views"))
+}
+
 func TestContentServiceBulkUpdate(t *testing.T) {
 	cs, server := createContentService(t)
 	// Case 1: schema not found
@@ -167,3 +210,62 @@ func TestContentServiceBulkUpdate(t *testing.T) {
 		`"data":0`,
 	)
 }
+
+func TestContentServiceUpdateOptimisticLock(t *testing.T) {
+	cs, server := createContentService(t)
+	noteModel := utils.Must(cs.DB().Model("note"))
+	noteID := utils.Must(noteModel.CreateFromJSON(context.Background(), `{"title": "note"}`))
+	update := func(body, ifMatch string) (int, string) {
+		req := httptest.NewRequest("PUT", fmt.Sprintf("/content/note/%v", noteID), bytes.NewReader([]byte(body)))
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		resp := utils.Must(server.Test(req))
+		defer func() { assert.NoError(t, resp.Body.Close()) }()
+		return resp.StatusCode, utils.Must(utils.ReadCloserToString(resp.Body))
+	}
+	version := func() uint64 {
+		note := utils.Must(noteModel.Query(db.EQ("id", noteID)).First(context.Background()))
+		return utils.Must(note.GetUint64("version", false))
+	}
+	assert.Equal(t, uint64(1), version())
+
+	// Case 1: update with the expected version in the payload
+	status, body := update(`{"title": "editor 1", "version": 1}`, "")
+	assert.Equal(t, 200, status)
+	assert.Contains(t, body, `"version":2`)
+	assert.Equal(t, uint64(2), version())
+
+	// Case 2: update with a stale version returns a conflict
+	status, body = update(`{"title": "editor 2", "version": 1}`, "")
+	assert.Equal(t, 409, status)
+	assert.Contains(t, body, "version conflict")
+	assert.Equal(t, "editor 1", utils.Must(noteModel.Query(db.EQ("id", noteID)).First(context.Background())).GetString("title"))
+
+	// Case 3: the If-Match header takes precedence over the payload
+	status, _ = update(`{"title": "editor 2", "version": 1}`, `"2"`)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, uint64(3), version())
+
+	status, _ = update(`{"title": "editor 3"}`, `W/"2"`)
+	assert.Equal(t, 409, status)
+
+	// Case 4: invalid If-Match header
+	status, body = update(`{"title": "editor 3"}`, "invalid")
+	assert.Equal(t, 400, status)
+	assert.Contains(t, body, "invalid If-Match header")
+
+	// Case 5: update without version still increments the version
+	status, _ = update(`{"title": "editor 3"}`, "")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, uint64(4), version())
+}
+
+func TestContentServiceUpdateOptimisticLockNotFound(t *testing.T) {
+	_, server := createContentService(t)
+	req := httptest.NewRequest("PUT", "/content/note/1000", bytes.NewReader([]byte(`{"title": "note"}`)))
+	req.Header.Set("If-Match", `"1"`)
+	resp := utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 404, resp.StatusCode)
+}
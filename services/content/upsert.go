@@ -0,0 +1,59 @@
+package contentservice
+
+import (
+	"strings"
+
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/errors"
+	"github.com/fastschema/fastschema/pkg/utils"
+)
+
+// Upsert creates the content or updates the existing content that has the same conflict field values.
+func (cs *ContentService) Upsert(c fs.Context, _ any) (*entity.Entity, error) {
+	model, err := cs.DB().Model(c.Arg("schema"))
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	conflictColumns := splitArg(c, "conflict")
+	if len(conflictColumns) == 0 {
+		return nil, errors.BadRequest("conflict fields are required")
+	}
+
+	entity, err := c.Payload()
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	if err := checkWritableFields(c, model.Schema().Name, entity); err != nil {
+		return nil, err
+	}
+
+	entity.SetIDField(model.Schema().PrimaryKeyName())
+	if err := setExpectedVersion(c, model.Schema(), entity); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	if _, err := model.Mutation().Upsert(
+		c,
+		entity,
+		conflictColumns,
+		splitArg(c, "update"),
+	); err != nil {
+		if verr := validationError(err); verr != nil {
+			return nil, verr
+		}
+		return nil, queryError(err, errors.BadRequest)
+	}
+
+	return entity.Delete("password"), nil
+}
+
+// splitArg splits the comma separated values of the argument and removes the empty ones.
+func splitArg(c fs.Context, name string) []string {
+	return utils.Filter(
+		utils.Map(strings.Split(c.Arg(name, ""), ","), strings.TrimSpace),
+		func(s string) bool { return s != "" },
+	)
+}
@@ -0,0 +1,125 @@
+package contentservice_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentServiceUpsert(t *testing.T) {
+	cs, server := createContentService(t)
+
+	upsert := func(path, body string) (int, string) {
+		req := httptest.NewRequest("PUT", path, bytes.NewReader([]byte(body)))
+		resp := utils.Must(server.Test(req))
+		defer func() { assert.NoError(t, resp.Body.Close()) }()
+		return resp.StatusCode, utils.Must(utils.ReadCloserToString(resp.Body))
+	}
+
+	// Case 1: schema not found
+	status, response := upsert("/content/test/upsert?conflict=id", `{"name": "test blog"}`)
+	assert.Equal(t, 400, status)
+	assert.Contains(t, response, `"message":"model test not found"`)
+
+	// Case 2: missing conflict fields
+	status, response = upsert("/content/blog/upsert", `{"name": "test blog"}`)
+	assert.Equal(t, 400, status)
+	assert.Contains(t, response, `"message":"conflict fields are required"`)
+
+	// Case 3: invalid json
+	status, _ = upsert("/content/blog/upsert?conflict=id", `{"name": "invalid json"`)
+	assert.Equal(t, 400, status)
+
+	// Case 4: conflict fields are not unique
+	status, response = upsert("/content/blog/upsert?conflict=name", `{"name": "test blog"}`)
+	assert.Equal(t, 400, status)
+	assert.Contains(t, response, "must be the primary key or a unique key of blog")
+
+	// Case 5: create
+	status, response = upsert("/content/blog/upsert?conflict=id", `{"id": 10, "name": "test blog", "views": 1}`)
+	assert.Equal(t, 200, status)
+	assert.Contains(t, response, `"id":10`)
+
+	// Case 6: update the given fields only
+	status, response = upsert("/content/blog/upsert?conflict=id&update=views", `{"id": 10, "name": "new name", "views": 2}`)
+	assert.Equal(t, 200, status)
+	assert.Contains(t, response, `"id":10`)
+
+	blog := utils.Must(utils.Must(cs.DB().Model("blog")).Query(db.EQ("id", 10)).First(context.Background()))
+	assert.Equal(t, "test blog", blog.GetString("name"))
+	assert.Equal(t, 2, blog.Get("views"))
+
+	// Case 7: upsert by a unique field, the password is not returned
+	status, response = upsert(
+		"/content/user/upsert?conflict=username",
+		`{"username": "testuser", "password": "testpassword", "provider": "local"}`,
+	)
+	assert.Equal(t, 200, status)
+	assert.Contains(t, response, `"username":"testuser"`)
+	assert.NotContains(t, response, "testpassword")
+
+	status, _ = upsert("/content/user/upsert?conflict=username", `{"username": "testuser", "provider": "github"}`)
+	assert.Equal(t, 200, status)
+
+	users := utils.Must(utils.Must(cs.DB().Model("user")).Query(db.EQ("username", "testuser")).Get(context.Background()))
+	assert.Len(t, users, 1)
+	assert.Equal(t, "github", users[0].GetString("provider"))
+}
+
+func TestContentServiceUpsertOptimisticLock(t *testing.T) {
+	cs, server := createContentService(t)
+	noteID := uuid.NewString()
+	upsert := func(id, body, ifMatch string) (int, string) {
+		body = fmt.Sprintf(body, id)
+		req := httptest.NewRequest("PUT", "/content/note/upsert?conflict=id", bytes.NewReader([]byte(body)))
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		resp := utils.Must(server.Test(req))
+		defer func() { assert.NoError(t, resp.Body.Close()) }()
+		return resp.StatusCode, utils.Must(utils.ReadCloserToString(resp.Body))
+	}
+	note := func() *entity.Entity {
+		return utils.Must(utils.Must(cs.DB().Model("note")).Query(db.EQ("id", noteID)).First(context.Background()))
+	}
+
+	// Case 1: insert starts at version 1
+	status, response := upsert(noteID, `{"id": "%s", "title": "note"}`, "")
+	assert.Equal(t, 200, status)
+	assert.Contains(t, response, `"version":1`)
+	assert.Equal(t, uint64(1), utils.Must(note().GetUint64("version", false)))
+
+	// Case 2: the payload version is the expected version, it does not overwrite the column
+	status, response = upsert(noteID, `{"id": "%s", "title": "editor 1", "version": 1}`, "")
+	assert.Equal(t, 200, status)
+	assert.Contains(t, response, `"version":2`)
+	assert.Equal(t, uint64(2), utils.Must(note().GetUint64("version", false)))
+
+	status, _ = upsert(noteID, `{"id": "%s", "title": "editor 1", "version": 5}`, "")
+	assert.Equal(t, 409, status)
+	assert.Equal(t, uint64(2), utils.Must(note().GetUint64("version", false)))
+
+	// Case 3: stale If-Match returns a conflict
+	status, response = upsert(noteID, `{"id": "%s", "title": "editor 2"}`, `"1"`)
+	assert.Equal(t, 409, status)
+	assert.Contains(t, response, "version conflict")
+	assert.Equal(t, "editor 1", note().GetString("title"))
+
+	// Case 4: matching If-Match updates the record
+	status, _ = upsert(noteID, `{"id": "%s", "title": "editor 2"}`, `"2"`)
+	assert.Equal(t, 200, status)
+	assert.Equal(t, uint64(3), utils.Must(note().GetUint64("version", false)))
+	assert.Equal(t, "editor 2", note().GetString("title"))
+
+	// Case 5: expected version on a missing record returns a conflict
+	status, _ = upsert(uuid.NewString(), `{"id": "%s", "title": "new"}`, `"1"`)
+	assert.Equal(t, 409, status)
+}
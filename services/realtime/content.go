@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -165,6 +166,9 @@ type WSContentSerializer struct {
 	name       string
 	fields     string
 	predicates []*db.Predicate
+	rowFilter  []*db.Predicate
+	// fieldPermissions removes the fields that the client is not allowed to read
+	fieldPermissions *fs.FieldPermissions
 }
 
 type WSContentSerializeData struct {
@@ -190,6 +194,8 @@ func (tc *WSContentSerializer) Serialize(data any) (msg []byte, err error) {
 			return nil, nil
 		}
 
+		tc.stripFields(content)
+
 		return json.Marshal(WSContentSerializeData{
 			Event: WSContentEventCreate,
 			Data:  content,
@@ -216,6 +222,8 @@ func (tc *WSContentSerializer) Serialize(data any) (msg []byte, err error) {
 			return nil, nil
 		}
 
+		tc.stripFields(contents...)
+
 		sd := WSContentSerializeData{
 			Event: WSContentEventUpdate,
 			Data:  contents,
@@ -230,17 +238,28 @@ func (tc *WSContentSerializer) Serialize(data any) (msg []byte, err error) {
 
 	realtimeDelete, ok := data.(*RealtimeDeleteData)
 	if ok {
-		if len(realtimeDelete.OriginalEntities) == 0 {
+		deletedEntities, err := tc.deletedEntities(realtimeDelete.OriginalEntities)
+		if err != nil {
+			return nil, fmt.Errorf("realtime.content: %w", err)
+		}
+
+		if len(deletedEntities) == 0 {
 			return nil, nil
 		}
 
+		// The deleted entities are shared by all the clients
+		if tc.fieldPermissions != nil {
+			deletedEntities = utils.Map(deletedEntities, (*entity.Entity).Clone)
+			tc.stripFields(deletedEntities...)
+		}
+
 		sd := WSContentSerializeData{
 			Event: WSContentEventDelete,
-			Data:  realtimeDelete.OriginalEntities,
+			Data:  deletedEntities,
 		}
 
 		if tc.id > 0 {
-			sd.Data = realtimeDelete.OriginalEntities[0]
+			sd.Data = deletedEntities[0]
 		}
 
 		return json.Marshal(sd)
@@ -249,6 +268,50 @@ func (tc *WSContentSerializer) Serialize(data any) (msg []byte, err error) {
 	return nil, nil
 }
 
+// stripFields removes the fields that the client is not allowed to read.
+func (tc *WSContentSerializer) stripFields(entities ...*entity.Entity) {
+	if tc.fieldPermissions != nil {
+		tc.fieldPermissions.Strip(tc.schema, entities...)
+	}
+}
+
+// deletedEntities returns the deleted entities that match the row filter of the client.
+// The trashed rows are matched against the row filter, the hard deleted rows are
+// no longer available so they are not sent to the clients that have a row filter.
+func (tc *WSContentSerializer) deletedEntities(originalEntities []*entity.Entity) ([]*entity.Entity, error) {
+	if len(tc.rowFilter) == 0 || len(originalEntities) == 0 {
+		return originalEntities, nil
+	}
+
+	client := tc.db()
+	if !client.Config().UseSoftDeletes {
+		return nil, nil
+	}
+
+	model, err := client.Model(tc.schema.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	deletedIDs := utils.Map(originalEntities, func(e *entity.Entity) any {
+		return e.ID()
+	})
+
+	predicates := append([]*db.Predicate{db.In("id", deletedIDs)}, tc.rowFilter...)
+	visibleEntities, err := model.Query(predicates...).WithTrashed().Select("id").Get(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	visibleIDs := utils.Map(visibleEntities, func(e *entity.Entity) string {
+		return fmt.Sprint(e.ID())
+	})
+
+	return utils.Filter(originalEntities, func(e *entity.Entity) bool {
+		return slices.Contains(visibleIDs, fmt.Sprint(e.ID()))
+	}), nil
+}
+
 func (rs *RealtimeService) createContentSerializer(c fs.Context) (*WSContentSerializer, error) {
 	schemaName := c.Arg("schema")
 	event := c.Arg("event", "*")
@@ -283,13 +346,33 @@ func (rs *RealtimeService) createContentSerializer(c fs.Context) (*WSContentSeri
 		}
 	}
 
+	fieldPermissions, _ := c.Local(fs.FieldPermissionsKey).(*fs.FieldPermissions)
+	if fieldPermissions != nil && fieldPermissions.Schema != schema.Name {
+		fieldPermissions = nil
+	}
+
+	// The client filter and selection must not use the fields that the client is not allowed to read.
+	selectedFields := append(db.PredicateFields(predicates), strings.Split(fields, ",")...)
+	if field := fieldPermissions.UnreadableField(schema, selectedFields...); field != "" {
+		return nil, fmt.Errorf("realtime.content: field %s is not readable", field)
+	}
+
+	// The row filter of the permission modifiers is enforced on top of the client filter.
+	var rowFilter []*db.Predicate
+	if filter, ok := c.Local(db.RowFilterKey).(*db.RowFilter); ok && filter.Schema == schema.Name {
+		rowFilter = filter.Predicates
+		predicates = append(predicates, rowFilter...)
+	}
+
 	return &WSContentSerializer{
-		db:         rs.DB,
-		schema:     schema,
-		event:      contentEvent,
-		id:         id,
-		name:       strings.Join(topicParts, "."),
-		fields:     fields,
-		predicates: predicates,
+		db:               rs.DB,
+		schema:           schema,
+		event:            contentEvent,
+		id:               id,
+		name:             strings.Join(topicParts, "."),
+		fields:           fields,
+		predicates:       predicates,
+		rowFilter:        rowFilter,
+		fieldPermissions: fieldPermissions,
 	}, nil
 }
@@ -26,13 +26,14 @@ func (rs *RoleService) Export(c fs.Context, _ any) (*fs.RolePermissionSettingsCo
 			continue
 		}
 
-		// Only include resource, value, and modifier fields for export
+		// Only include resource, value, modifier and fields for export
 		exportPerms := make([]*fs.Permission, len(role.Permissions))
 		for i, perm := range role.Permissions {
 			exportPerms[i] = &fs.Permission{
 				Resource: perm.Resource,
 				Value:    perm.Value,
 				Modifier: perm.Modifier,
+				Fields:   perm.Fields,
 			}
 		}
 
@@ -69,7 +69,8 @@ func updateRolePermissions(
 	for _, permission := range updated {
 		permissionEntity := entity.New().
 			Set("value", permission.Value).
-			Set("modifier", permission.Modifier)
+			Set("modifier", permission.Modifier).
+			Set("fields", permission.Fields)
 		if _, err := db.Update[*fs.Permission](ctx, tx, permissionEntity, []*db.Predicate{
 			db.EQ("role_id", existingRole.ID),
 			db.EQ("resource", permission.Resource),
@@ -84,6 +85,7 @@ func updateRolePermissions(
 			Set("resource", permission.Resource).
 			Set("value", permission.Value).
 			Set("modifier", permission.Modifier).
+			Set("fields", permission.Fields).
 			Set("role_id", existingRole.ID)
 
 		if _, err := db.Create[*fs.Permission](ctx, tx, permissionEntity); err != nil {
@@ -130,6 +132,7 @@ func getPermissionsUpdate(
 				Resource: permission.GetString("resource"),
 				Value:    value,
 				Modifier: permission.GetString("modifier", ""),
+				Fields:   permission.GetString("fields", ""),
 			})
 		}
 	}
@@ -1,6 +1,7 @@
 package schemaservice
 
 import (
+	stderrors "errors"
 	"fmt"
 	"os"
 	"path"
@@ -114,6 +115,10 @@ func (su *SchemaUpdate) update() (err error) {
 
 	su.newSchemaBuilder, err = schema.NewBuilderFromDir(su.newSchemaBuilderDir, su.systemSchemas...)
 	if err != nil {
+		var batch *schema.BuilderErrors
+		if stderrors.As(err, &batch) {
+			return errors.UnprocessableEntity("schema validation failed").WithData(batch)
+		}
 		return err
 	}
 
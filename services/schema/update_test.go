@@ -334,6 +334,54 @@ func TestSchemaServiceUpdate(t *testing.T) {
 	assert.True(t, fieldBlogs.Relation.Optional)
 }
 
+func TestSchemaServiceUpdateValidators(t *testing.T) {
+	testApp, _, server := createUpdateTest(t)
+
+	// Case 1: invalid validator expression
+	blogJSON := strings.ReplaceAll(
+		testBlogJSON,
+		`"fields": [`,
+		`"validators": [{"expr": "((invalid"}], "fields": [`,
+	)
+	req := httptest.NewRequest(
+		"PUT", "/schema/blog",
+		bytes.NewReader([]byte(fmt.Sprintf(`{"schema":%s}`, blogJSON))),
+	)
+	resp := utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 422, resp.StatusCode)
+	response := utils.Must(utils.ReadCloserToString(resp.Body))
+	assert.Contains(t, response, `schema validation failed`)
+	assert.Contains(t, response, `"code":"schema.validator.compile_error"`)
+	assert.Contains(t, response, `"schema":"blog"`)
+	assert.Empty(t, testApp.Schema("blog").Validators)
+
+	// Case 2: success
+	blogJSON = strings.ReplaceAll(
+		testBlogJSON,
+		`"fields": [`,
+		`"validators": [{
+			"expr": "$args.Data.name != 'invalid'",
+			"message": "The name is not allowed",
+			"fields": ["name"]
+		}], "fields": [`,
+	)
+	req = httptest.NewRequest(
+		"PUT", "/schema/blog",
+		bytes.NewReader([]byte(fmt.Sprintf(`{"schema":%s}`, blogJSON))),
+	)
+	resp = utils.Must(server.Test(req))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 200, resp.StatusCode)
+	response = utils.Must(utils.ReadCloserToString(resp.Body))
+	assert.Contains(t, response, `"validators":[{"expr":"$args.Data.name != 'invalid'","message":"The name is not allowed","fields":["name"]}]`)
+
+	validators := testApp.Schema("blog").Validators
+	assert.Len(t, validators, 1)
+	assert.Equal(t, "The name is not allowed", validators[0].Message)
+	assert.Equal(t, []string{"name"}, validators[0].Fields)
+}
+
 // Case 8: rename normal field
 func TestSchemaServiceUpdateRenameNormalField(t *testing.T) {
 	checkMigration := false
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/fastschema/fastschema/fs"
+	auditservice "github.com/fastschema/fastschema/services/audit"
 	authservice "github.com/fastschema/fastschema/services/auth"
 	contentservice "github.com/fastschema/fastschema/services/content"
 	fileservice "github.com/fastschema/fastschema/services/file"
@@ -20,6 +21,7 @@ type Content = contentservice.ContentService
 type Tool = toolservice.ToolService
 type Auth = authservice.AuthService
 type Realtime = realtimeservice.RealtimeService
+type Audit = auditservice.AuditService
 
 type Services struct {
 	file     *File
@@ -29,10 +31,11 @@ type Services struct {
 	tool     *Tool
 	auth     *Auth
 	realtime *Realtime
+	audit    *Audit
 }
 
 type ServiceType interface {
-	File | Role | Schema | Content | Tool | Auth | Realtime
+	File | Role | Schema | Content | Tool | Auth | Realtime | Audit
 }
 
 type ServicesProvider interface {
@@ -63,6 +66,8 @@ func Get[T ServiceType](app any) (*T, error) {
 		return any(services.Schema()).(*T), nil
 	case toolservice.ToolService:
 		return any(services.Tool()).(*T), nil
+	case auditservice.AuditService:
+		return any(services.Audit()).(*T), nil
 	}
 
 	return nil, errors.New("service not found")
@@ -77,6 +82,7 @@ func New(app fs.App) *Services {
 		tool:     toolservice.New(app),
 		auth:     authservice.New(app),
 		realtime: realtimeservice.New(app),
+		audit:    auditservice.New(app),
 	}
 }
 
@@ -107,3 +113,7 @@ func (s *Services) Auth() *authservice.AuthService {
 func (s *Services) Realtime() *realtimeservice.RealtimeService {
 	return s.realtime
 }
+
+func (s *Services) Audit() *auditservice.AuditService {
+	return s.audit
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/fastschema/fastschema/pkg/utils"
 	"github.com/fastschema/fastschema/schema"
 	"github.com/fastschema/fastschema/services"
+	auditservice "github.com/fastschema/fastschema/services/audit"
 	authservice "github.com/fastschema/fastschema/services/auth"
 	contentservice "github.com/fastschema/fastschema/services/content"
 	fileservice "github.com/fastschema/fastschema/services/file"
@@ -71,6 +72,7 @@ func TestNew(t *testing.T) {
 	assert.NotNil(t, s.Tool())
 	assert.NotNil(t, s.Auth())
 	assert.NotNil(t, s.Realtime())
+	assert.NotNil(t, s.Audit())
 }
 func TestGet(t *testing.T) {
 	// app does not implement ServicesProvider
@@ -104,5 +106,8 @@ func TestGet(t *testing.T) {
 
 		tool := utils.Must(services.Get[toolservice.ToolService](app))
 		assert.NotNil(t, tool)
+
+		audit := utils.Must(services.Get[auditservice.AuditService](app))
+		assert.NotNil(t, audit)
 	}
 }
@@ -0,0 +1,73 @@
+package toolservice
+
+import (
+	"strings"
+
+	"entgo.io/ent/dialect"
+	"github.com/fastschema/fastschema/db"
+	"github.com/fastschema/fastschema/entity"
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/errors"
+)
+
+// ExplainData contains the statement of a content list query and its execution plan.
+type ExplainData struct {
+	SQL  string           `json:"sql"`
+	Args []any            `json:"args"`
+	Plan []*entity.Entity `json:"plan"`
+}
+
+// Explain returns the statement of a content list query and the plan of the database to run it.
+//
+//	The query is built from the same arguments as the content list: schema, filter, select, sort, limit and page.
+//	The statement is not run, only the EXPLAIN statement of the dialect is run:
+//		- sqlite: EXPLAIN QUERY PLAN
+//		- mysql, postgres: EXPLAIN
+func (s *ToolService) Explain(c fs.Context, _ any) (*ExplainData, error) {
+	model, err := s.DB().Model(c.Arg("schema"))
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	predicates, err := db.CreatePredicatesFromFilterObject(
+		s.DB().SchemaBuilder(),
+		model.Schema(),
+		c.Arg("filter", ""),
+	)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	columns := []string{}
+	if fields := c.Arg("select", ""); fields != "" {
+		columns = strings.Split(fields, ",")
+	}
+
+	page := uint(max(c.ArgInt("page", 1), 1))
+	limit := uint(max(c.ArgInt("limit", 10), 1))
+	statement, args, err := model.Query(predicates...).
+		Select(columns...).
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Order(c.Arg("sort", "-"+model.Schema().PrimaryKeyName())).
+		SQL(c)
+	if err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	explain := "EXPLAIN "
+	if s.DB().Dialect() == dialect.SQLite {
+		explain = "EXPLAIN QUERY PLAN "
+	}
+
+	plan, err := s.DB().Query(c, explain+statement, args...)
+	if err != nil {
+		return nil, errors.InternalServerError(err.Error())
+	}
+
+	return &ExplainData{
+		SQL:  statement,
+		Args: args,
+		Plan: plan,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package toolservice_test
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/logger"
+	"github.com/fastschema/fastschema/pkg/entdbadapter"
+	"github.com/fastschema/fastschema/pkg/restfulresolver"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	toolservice "github.com/fastschema/fastschema/services/tool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolServiceExplain(t *testing.T) {
+	sb := utils.Must(schema.NewBuilderFromDir(t.TempDir(), fs.SystemSchemaTypes...))
+	db := utils.Must(entdbadapter.NewTestClient(utils.Must(os.MkdirTemp("", "migrations")), sb))
+	toolService := toolservice.New(&testApp{sb: sb, db: db})
+
+	resources := fs.NewResourcesManager()
+	resources.Group("tool").
+		Add(fs.NewResource("explain", toolService.Explain, &fs.Meta{
+			Get: "/explain",
+		}))
+
+	assert.NoError(t, resources.Init())
+	restResolver := restfulresolver.NewRestfulResolver(&restfulresolver.ResolverConfig{
+		ResourceManager: resources,
+		Logger:          logger.CreateMockLogger(true),
+	})
+	server := restResolver.Server()
+
+	tests := []struct {
+		name         string
+		query        url.Values
+		expectStatus int
+		expectBody   []string
+	}{
+		{
+			name:         "invalid schema",
+			query:        url.Values{"schema": {"invalid"}},
+			expectStatus: 400,
+			expectBody:   []string{`model invalid not found`},
+		},
+		{
+			name:         "invalid filter",
+			query:        url.Values{"schema": {"user"}, "filter": {`{"invalid": 1}`}},
+			expectStatus: 400,
+			expectBody:   []string{`invalid`},
+		},
+		{
+			name:         "invalid sort",
+			query:        url.Values{"schema": {"user"}, "sort": {"invalid"}},
+			expectStatus: 400,
+			expectBody:   []string{`invalid`},
+		},
+		{
+			name: "explain",
+			query: url.Values{
+				"schema": {"user"},
+				"filter": {`{"username": {"$like": "%admin%"}}`},
+				"select": {"username,email"},
+				"sort":   {"-username"},
+				"limit":  {"5"},
+				"page":   {"2"},
+			},
+			expectStatus: 200,
+			expectBody: []string{
+				"SELECT `users`.`id`, `users`.`username`, `users`.`email` FROM `users` " +
+					"WHERE `users`.`username` LIKE ? " +
+					"ORDER BY `users`.`username` DESC LIMIT 5 OFFSET 5",
+				`"args":["%admin%"]`,
+				`"plan":[{`,
+				`"detail":"SCAN users`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/tool/explain?"+tt.query.Encode(), nil)
+			resp := utils.Must(server.Test(req))
+			defer func() { assert.NoError(t, resp.Body.Close()) }()
+			response := utils.Must(utils.ReadCloserToString(resp.Body))
+			assert.Equal(t, tt.expectStatus, resp.StatusCode, response)
+			for _, expectBody := range tt.expectBody {
+				assert.Contains(t, response, expectBody)
+			}
+		})
+	}
+
+	api := fs.NewResourcesManager().Group("api")
+	toolService.CreateResource(api)
+	explain := api.Find("api.tool.explain")
+	assert.NotNil(t, explain)
+	assert.False(t, explain.IsPublic())
+}
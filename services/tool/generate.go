@@ -0,0 +1,88 @@
+package toolservice
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/pkg/codegen"
+	"github.com/fastschema/fastschema/pkg/errors"
+	"github.com/fastschema/fastschema/schema"
+)
+
+// GenerateGo generates the Go code of the schemas of the schema directory to the output directory.
+// Generating the code again replaces the generated files and removes the files of the deleted schemas.
+func GenerateGo(schemasDir, outDir string, config *codegen.GoConfig) error {
+	sb, err := schema.NewBuilderFromDir(schemasDir, fs.SystemSchemaTypes...)
+	if err != nil {
+		return fmt.Errorf("failed to load schemas: %w", err)
+	}
+
+	files, err := codegen.GenerateGo(sb, config)
+	if err != nil {
+		return err
+	}
+
+	if err := codegen.WriteFiles(outDir, files); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	fmt.Printf("Generated Go files in %s:\n", outDir)
+	for _, name := range names {
+		fmt.Printf("  %s\n", name)
+	}
+
+	return nil
+}
+
+// TypeScript returns the TypeScript types of the schemas and the fetch client of the resources.
+func (s *ToolService) TypeScript(c fs.Context, _ any) (*fs.HTTPResponse, error) {
+	content, err := codegen.GenerateTS(&codegen.TSConfig{
+		SchemaBuilder: s.DB().SchemaBuilder(),
+		Resources:     s.Resources(),
+	})
+	if err != nil {
+		return nil, errors.InternalServerError(err.Error())
+	}
+
+	header := make(http.Header)
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	header.Set("Content-Disposition", `attachment; filename="fastschema.ts"`)
+
+	return &fs.HTTPResponse{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       content,
+	}, nil
+}
+
+// GenerateTS writes the TypeScript types of the schemas and the fetch client of the resources to the output file.
+func GenerateTS(sb *schema.Builder, resources *fs.ResourcesManager, outFile string) error {
+	content, err := codegen.GenerateTS(&codegen.TSConfig{
+		SchemaBuilder: sb,
+		Resources:     resources,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outFile), os.ModePerm); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(outFile, content, 0o644); err != nil {
+		return err
+	}
+
+	fmt.Printf("Generated TypeScript file: %s\n", outFile)
+	return nil
+}
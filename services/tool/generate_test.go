@@ -0,0 +1,76 @@
+package toolservice_test
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastschema/fastschema/fs"
+	"github.com/fastschema/fastschema/logger"
+	"github.com/fastschema/fastschema/pkg/codegen"
+	"github.com/fastschema/fastschema/pkg/entdbadapter"
+	"github.com/fastschema/fastschema/pkg/restfulresolver"
+	"github.com/fastschema/fastschema/pkg/utils"
+	"github.com/fastschema/fastschema/schema"
+	toolservice "github.com/fastschema/fastschema/services/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateGo(t *testing.T) {
+	schemaDir := t.TempDir()
+	outDir := t.TempDir()
+	utils.WriteFile(schemaDir+"/blog.json", `{
+		"name": "blog",
+		"namespace": "blogs",
+		"label_field": "title",
+		"fields": [{ "type": "string", "name": "title", "label": "Title" }]
+	}`)
+
+	require.NoError(t, toolservice.GenerateGo(schemaDir, outDir, &codegen.GoConfig{Package: "models"}))
+	assert.True(t, utils.IsFileExists(filepath.Join(outDir, "blog.go")))
+	assert.True(t, utils.IsFileExists(filepath.Join(outDir, "blog", "blog.go")))
+
+	utils.WriteFile(schemaDir+"/blog.json", `{invalid`)
+	assert.Error(t, toolservice.GenerateGo(schemaDir, outDir, nil))
+}
+
+func TestGenerateTS(t *testing.T) {
+	sb := utils.Must(schema.NewBuilderFromDir(t.TempDir(), fs.SystemSchemaTypes...))
+	outFile := filepath.Join(t.TempDir(), "client", "fastschema.ts")
+	resources := fs.NewResourcesManager()
+	resources.Group("api").Add(fs.Get("ping", func(c fs.Context, _ any) (string, error) {
+		return "pong", nil
+	}))
+	require.NoError(t, resources.Init())
+
+	require.NoError(t, toolservice.GenerateTS(sb, resources, outFile))
+	content := string(utils.Must(os.ReadFile(outFile)))
+	assert.Contains(t, content, codegen.Header)
+	assert.Contains(t, content, "export interface User {")
+	assert.Contains(t, content, `ping: (query?: Query) => request<string>("GET", "/api/ping", { query }),`)
+}
+
+func TestToolServiceTypeScript(t *testing.T) {
+	sb := utils.Must(schema.NewBuilderFromDir(t.TempDir(), fs.SystemSchemaTypes...))
+	db := utils.Must(entdbadapter.NewTestClient(utils.Must(os.MkdirTemp("", "migrations")), sb))
+	resources := fs.NewResourcesManager()
+	toolService := toolservice.New(&testApp{sb: sb, db: db, resources: resources})
+	toolService.CreateResource(resources.Group("api"))
+	require.NoError(t, resources.Init())
+
+	server := restfulresolver.NewRestfulResolver(&restfulresolver.ResolverConfig{
+		ResourceManager: resources,
+		Logger:          logger.CreateMockLogger(true),
+	}).Server()
+
+	resp := utils.Must(server.Test(httptest.NewRequest("GET", "/api/tool/typescript", nil)))
+	defer func() { assert.NoError(t, resp.Body.Close()) }()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, `attachment; filename="fastschema.ts"`, resp.Header.Get("Content-Disposition"))
+	response := utils.Must(utils.ReadCloserToString(resp.Body))
+	assert.Contains(t, response, "export interface Role {")
+	assert.Contains(t, response, `typescript: (query?: Query) => send("GET", "/api/tool/typescript", { query }),`)
+	assert.Contains(t, response, `stats: (query?: Query) => request<StatsData>("GET", "/api/tool/stats", { query }),`)
+}
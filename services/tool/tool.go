@@ -7,21 +7,26 @@ import (
 
 type AppLike interface {
 	DB() db.Client
+	Resources() *fs.ResourcesManager
 }
 
 type ToolService struct {
-	DB func() db.Client
+	DB        func() db.Client
+	Resources func() *fs.ResourcesManager
 }
 
 func New(app AppLike) *ToolService {
 	return &ToolService{
-		DB: app.DB,
+		DB:        app.DB,
+		Resources: app.Resources,
 	}
 }
 
 func (s *ToolService) CreateResource(api *fs.Resource) {
 	api.Group("tool").
-		Add(fs.NewResource("stats", s.Stats, &fs.Meta{Get: "/stats", Public: true}))
+		Add(fs.NewResource("stats", s.Stats, &fs.Meta{Get: "/stats", Public: true})).
+		Add(fs.NewResource("explain", s.Explain, &fs.Meta{Get: "/explain"})).
+		Add(fs.NewResource("typescript", s.TypeScript, &fs.Meta{Get: "/typescript"}))
 }
 
 type StatsData struct {
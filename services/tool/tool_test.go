@@ -17,14 +17,19 @@ import (
 )
 
 type testApp struct {
-	sb *schema.Builder
-	db db.Client
+	sb        *schema.Builder
+	db        db.Client
+	resources *fs.ResourcesManager
 }
 
 func (s testApp) DB() db.Client {
 	return s.db
 }
 
+func (s testApp) Resources() *fs.ResourcesManager {
+	return s.resources
+}
+
 func TestToolServiceError(t *testing.T) {
 	sb := &schema.Builder{}
 	db := utils.Must(entdbadapter.NewTestClient(utils.Must(os.MkdirTemp("", "migrations")), sb))
@@ -74,7 +79,7 @@ func TestToolService(t *testing.T) {
 	defer func() { assert.NoError(t, resp.Body.Close()) }()
 	assert.Equal(t, 200, resp.StatusCode)
 	response := utils.Must(utils.ReadCloserToString(resp.Body))
-	assert.Contains(t, response, `"totalSchemas":7`)
+	assert.Contains(t, response, `"totalSchemas":9`)
 	assert.Contains(t, response, `"totalUsers":0`)
 	assert.Contains(t, response, `"totalFiles":0`)
 